@@ -6,13 +6,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"jollfi-gaming-api/internal/breaker"
 	"jollfi-gaming-api/internal/config"
 	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/matchmaking"
+	"jollfi-gaming-api/internal/models"
 	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/screening"
 	"jollfi-gaming-api/internal/service"
+	"jollfi-gaming-api/internal/webhook"
 )
 
 func main() {
@@ -27,6 +33,122 @@ func main() {
 
 	gameService := service.NewGameService(suiClient, mongoClient)
 
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	gameService.OnDependencyRecovered(service.DependencyMongo, mongoClient.EnsureIndexes)
+	gameService.OnDependencyRecovered(service.DependencySui, suiClient.ValidatePoolConfig)
+	gameService.CheckDependencies(ctx)
+	if cfg.DependencyCheckInterval > 0 {
+		go gameService.RunDependencyMonitor(ctx, time.Duration(cfg.DependencyCheckInterval)*time.Second)
+	}
+	if cfg.SuiRPCHealthInterval > 0 {
+		go suiClient.MonitorEndpoints(ctx, time.Duration(cfg.SuiRPCHealthInterval)*time.Second)
+	}
+	if cfg.ChallengeSweepInterval > 0 {
+		go gameService.RunChallengeSweeper(ctx, time.Duration(cfg.ChallengeSweepInterval)*time.Second)
+	}
+	gameService.ConfigureMatchmaking(matchmaking.Config{
+		InitialWindow: cfg.MatchmakingInitialWindow,
+		WidenBy:       cfg.MatchmakingWidenBy,
+		WidenEvery:    time.Duration(cfg.MatchmakingWidenEvery) * time.Second,
+		MaxWindow:     cfg.MatchmakingMaxWindow,
+		TicketTTL:     time.Duration(cfg.MatchmakingTicketTTL) * time.Second,
+	}, matchmaking.SystemClock{})
+	if cfg.MatchmakingInterval > 0 {
+		go gameService.RunMatchmaker(ctx, time.Duration(cfg.MatchmakingInterval)*time.Second)
+	}
+	if cfg.TournamentInterval > 0 {
+		go gameService.RunTournamentWorker(ctx, time.Duration(cfg.TournamentInterval)*time.Second)
+	}
+
+	gameService.ConfigureWebhooks(webhook.Config{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		BaseDelay:   time.Duration(cfg.WebhookBaseDelay) * time.Second,
+		MaxDelay:    time.Duration(cfg.WebhookMaxDelay) * time.Second,
+		Timeout:     time.Duration(cfg.WebhookTimeout) * time.Second,
+	}, &http.Client{})
+	if cfg.WebhookDispatchInterval > 0 {
+		go gameService.RunWebhookDispatcher(ctx, time.Duration(cfg.WebhookDispatchInterval)*time.Second)
+	}
+	if cfg.ConfirmationInterval > 0 {
+		go gameService.RunConfirmationWorker(ctx, time.Duration(cfg.ConfirmationInterval)*time.Second)
+	}
+
+	gameService.ConfigureStream(cfg.StreamReplayBuffer)
+	gameService.ConfigureLive(cfg.LiveMaxPerGame)
+	gameService.ConfigurePool(cfg.PoolID)
+	gameService.ConfigureRateLimit(cfg.RateLimitBackend)
+
+	walletLease := service.DefaultWalletLeaseConfig()
+	walletLease.Name = "wallet:" + suiClient.GetAddress()
+	walletLease.TTL = time.Duration(cfg.WalletLeaseTTL) * time.Second
+	walletLease.Wait = time.Duration(cfg.WalletLeaseWait) * time.Second
+	gameService.ConfigureWalletLease(walletLease)
+
+	gameService.ConfigureResponsibleGaming(service.ResponsibleGamingConfig{
+		Limits: models.StakeLimits{
+			MaxStake: uint64(cfg.StakeLimitMax),
+			Daily:    uint64(cfg.StakeLimitDaily),
+			Weekly:   uint64(cfg.StakeLimitWeekly),
+			Monthly:  uint64(cfg.StakeLimitMonthly),
+			NetLoss:  uint64(cfg.StakeLimitNetLoss),
+		},
+		LoosenDelay: time.Duration(cfg.StakeLimitLoosenDelay) * time.Hour,
+	})
+
+	maxScores, _ := cfg.PayoutMaxScoresByType()
+	gameService.ConfigurePayoutGuard(service.PayoutGuardConfig{
+		MaxScore:        uint64(cfg.PayoutMaxScore),
+		MaxScores:       maxScores,
+		MinGameDuration: time.Duration(cfg.PayoutMinGameSeconds) * time.Second,
+		DailyCap:        uint64(cfg.PayoutDailyCapPerServer),
+	})
+	gameService.ConfigurePayoutApproval(service.PayoutApprovalConfig{
+		Threshold: uint64(cfg.PayoutApprovalThreshold),
+		Required:  cfg.PayoutApprovalsRequired,
+		TTL:       time.Duration(cfg.PayoutApprovalTTL) * time.Hour,
+	})
+	if cfg.PayoutExpiryInterval > 0 {
+		go gameService.RunPayoutExpiry(ctx, time.Duration(cfg.PayoutExpiryInterval)*time.Second)
+	}
+
+	gameService.ConfigureRefunds(service.RefundConfig{
+		Draws:      cfg.RefundDraws,
+		StuckAfter: time.Duration(cfg.StuckGameTimeout) * time.Minute,
+	})
+	if cfg.TimeoutSettlementInterval > 0 && cfg.TimeoutSettlementAfter > 0 {
+		interval := time.Duration(cfg.TimeoutSettlementInterval) * time.Second
+		timeoutSettlement := service.DefaultTimeoutSettlementConfig()
+		timeoutSettlement.After = time.Duration(cfg.TimeoutSettlementAfter) * time.Minute
+		timeoutSettlement.Policy = cfg.TimeoutSettlementPolicy
+		timeoutSettlement.LeaseTTL = 3 * interval
+		gameService.ConfigureTimeoutSettlement(timeoutSettlement)
+		go gameService.RunTimeoutSettlement(ctx, interval)
+	}
+
+	if cfg.ScreeningFile != "" {
+		provider, err := screening.NewFileProvider(cfg.ScreeningFile)
+		if err != nil {
+			log.Fatalf("❌ Failed to load SCREENING_FILE: %v", err)
+		}
+		log.Printf("🛡️  Screening %d addresses from %s", provider.Len(), cfg.ScreeningFile)
+		gameService.ConfigureScreening(provider)
+	}
+
+	gameService.ConfigureKillSwitch(service.KillSwitchConfig{
+		CacheTTL:      time.Duration(cfg.KillSwitchCacheTTL) * time.Second,
+		MinGasBalance: uint64(cfg.GasMinBalance),
+	})
+	gameService.ConfigureReadiness(service.ReadinessConfig{
+		CacheTTL:         time.Duration(cfg.ReadinessCacheTTL) * time.Second,
+		Timeout:          time.Duration(cfg.ReadinessTimeout) * time.Second,
+		MaxCheckpointAge: time.Duration(cfg.ReadinessMaxCheckpointAge) * time.Second,
+		MinGasBalance:    uint64(cfg.GasMinBalance),
+	})
+	if cfg.GasMinBalance > 0 && cfg.GasCheckInterval > 0 {
+		go gameService.RunGasMonitor(ctx, time.Duration(cfg.GasCheckInterval)*time.Second)
+	}
+
 	router := routes.SetupRoutes(gameService, cfg)
 
 	// Start server
@@ -40,36 +162,41 @@ func initializeDependencies(cfg *config.Config) (*data.SuiClient, *data.MongoCli
 		PoolID:     cfg.PoolID,
 	}
 
-	suiClient, err := data.NewSuiClient(cfg.SuiNetworkURL, cfg.SuiPrivateKey, suiConfig)
+	rpcURLs := cfg.RPCURLs()
+	suiClient, err := data.NewSuiClient(rpcURLs[0], cfg.SuiPrivateKey, suiConfig)
 	if err != nil {
 		log.Fatalf("Failed to initialize Sui client: %v", err)
 	}
+	rpcConfig := data.DefaultRPCConfig()
+	rpcConfig.Timeout = time.Duration(cfg.SuiRPCTimeout) * time.Second
+	rpcConfig.MaxRetries = cfg.SuiRPCMaxRetries
+	rpcConfig.RetryBaseDelay = time.Duration(cfg.SuiRPCRetryBaseMs) * time.Millisecond
+	rpcConfig.MaxCheckpointLag = uint64(cfg.SuiRPCMaxCheckpointLag)
+	rpcConfig.Breaker = breaker.Config{
+		FailureThreshold: cfg.SuiBreakerFailures,
+		OpenTimeout:      time.Duration(cfg.SuiBreakerOpenSeconds) * time.Second,
+		HalfOpenProbes:   cfg.SuiBreakerHalfOpenProbes,
+	}
+	suiClient.ConfigureRPC(rpcConfig, rpcURLs...)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Unreachable dependencies are not fatal: the server starts degraded and
+	// the dependency monitor brings them in when they answer.
 	if err := suiClient.HealthCheck(ctx); err != nil {
-		log.Fatalf("Sui client health check failed: %v", err)
+		log.Printf("⚠️  Sui RPC not reachable yet, starting degraded: %v", err)
+	} else {
+		log.Println("✅ Sui client connected successfully")
+		// A reachable node that cannot show us the pool means the configuration is wrong
+		if err := suiClient.ValidatePoolConfig(ctx); err != nil {
+			log.Fatalf("❌ Stake pool check failed for SUI_POOL_ID=%q, SUI_PACKAGE_ID=%q, SUI_MODULE_NAME=%q: %v", cfg.PoolID, cfg.PackageID, cfg.ModuleName, err)
+		}
+		log.Println("✅ Stake pool configuration verified")
 	}
-	log.Println("✅ Sui client connected successfully")
 
 	mongoClient := data.NewMongoClient(cfg.MongoURI, cfg.MongoDatabase)
-
-	const maxRetries = 5
-	const retryDelay = 2 * time.Second
-	for i := 0; i < maxRetries; i++ {
-		ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Second)
-		err := mongoClient.Ping(ctx2)
-		cancel2()
-		if err == nil {
-			log.Println("✅ MongoDB connected successfully")
-			return suiClient, mongoClient
-		}
-		log.Printf("MongoDB ping attempt %d failed: %v", i+1, err)
-		time.Sleep(retryDelay)
-	}
-	log.Fatalf("MongoDB ping failed after %d attempts: %v", maxRetries, err)
-	return nil, nil
+	return suiClient, mongoClient
 }
 
 func startServer(router http.Handler, cfg *config.Config) {
@@ -114,7 +241,8 @@ func logServerInfo(cfg *config.Config) {
 	log.Printf("📦 Package ID: %s", cfg.PackageID)
 	log.Printf("🏊 Pool ID: %s", cfg.PoolID)
 	log.Printf("🔧 Module Name: %s", cfg.ModuleName)
-	log.Printf("📊 Rate Limit: %d req/min", cfg.RateLimit)
+	log.Printf("📊 Rate Limit: %d req/min, writes %d, admin %d (%s, by %s)",
+		cfg.RateLimit, cfg.RateLimitWrites, cfg.RateLimitAdmin, cfg.RateLimitBackend, strings.Join(cfg.RateLimitKeys(), ","))
 	log.Printf("🔒 CORS Enabled: %t", cfg.EnableCORS)
 	log.Printf("📝 Logging Enabled: %t", cfg.EnableLogging)
 
@@ -123,6 +251,11 @@ func logServerInfo(cfg *config.Config) {
 	} else {
 		log.Println("🔑 API Key Authentication: Disabled")
 	}
+	if cfg.AdminAPIKey != "" {
+		log.Println("🛡️  Admin API: Enabled")
+	} else {
+		log.Println("🛡️  Admin API: Disabled")
+	}
 
 	log.Println("🚀 ================================")
 	log.Printf("🚀 Server running on http://localhost:%s", cfg.Port)
@@ -135,5 +268,30 @@ func logServerInfo(cfg *config.Config) {
 	log.Println("   POST /api/v1/games/pay_winner")
 	log.Println("   GET  /api/v1/games/stakes/:address")
 	log.Println("   GET  /api/v1/games/history/:address")
+	log.Println("   GET  /api/v1/games/stream?address=")
+	log.Println("   GET  /api/v1/games/live/:id (WebSocket)")
+	log.Println("   GET  /api/v1/players/:address")
+	log.Println("   GET  /api/v1/players/:address/rating")
+	log.Println("   GET  /api/v1/leaderboard")
+	log.Println("   GET  /api/v1/pool")
+	log.Println("   POST /api/v1/challenges")
+	log.Println("   GET  /api/v1/challenges")
+	log.Println("   POST /api/v1/challenges/:id/accept")
+	log.Println("   POST /api/v1/challenges/:id/cancel")
+	log.Println("   POST /api/v1/matchmaking/queue")
+	log.Println("   GET  /api/v1/matchmaking/queue/:id")
+	log.Println("   POST /api/v1/matchmaking/queue/:id/leave")
+	log.Println("   GET  /admin/wallet/balance")
+	log.Println("   GET  /admin/wallet/coins")
+	log.Println("   GET  /admin/pool")
+	log.Println("   GET  /admin/transactions/pending")
+	log.Println("   GET  /admin/collections/stats")
+	log.Println("   POST /admin/games/cleanup")
+	log.Println("   GET  /admin/audit")
+	log.Println("   GET|POST /admin/kill-switch")
+	log.Println("   GET|POST /admin/webhooks")
+	log.Println("   DELETE   /admin/webhooks/:id")
+	log.Println("   GET  /admin/webhooks/deliveries")
+	log.Println("   POST /admin/webhooks/deliveries/:id/redeliver")
 	log.Println("🚀 ================================")
 }
@@ -0,0 +1,169 @@
+// Package breaker implements a circuit breaker for calls to a remote
+// dependency. After enough consecutive failures the breaker opens and fails
+// calls immediately; once the open timeout passes it lets a few probe calls
+// through (half-open) and closes again if they succeed.
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	Closed   State = "closed"
+	Open     State = "open"
+	HalfOpen State = "half_open"
+)
+
+var ErrOpen = errors.New("circuit breaker is open")
+
+type Config struct {
+	// FailureThreshold is how many consecutive failures open the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before probing.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is how many calls may run while half-open; that many
+	// successes close the breaker.
+	HalfOpenProbes int
+	// Now replaces the wall clock, for tests.
+	Now func() time.Time
+}
+
+func DefaultConfig() Config {
+	return Config{FailureThreshold: 5, OpenTimeout: 30 * time.Second, HalfOpenProbes: 1}
+}
+
+// Status is a snapshot of a breaker for health reporting.
+type Status struct {
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+type Breaker struct {
+	mu        sync.Mutex
+	config    Config
+	state     State
+	failures  int
+	openedAt  time.Time
+	inFlight  int
+	successes int
+	lastError string
+}
+
+func New(config Config) *Breaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultConfig().FailureThreshold
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = 1
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &Breaker{config: config, state: Closed}
+}
+
+// Allow reports whether a call may proceed, returning ErrOpen if not. Every
+// allowed call must be followed by Success, Failure or Ignore.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open {
+		if b.config.Now().Sub(b.openedAt) < b.config.OpenTimeout {
+			return ErrOpen
+		}
+		b.state = HalfOpen
+		b.inFlight = 0
+		b.successes = 0
+	}
+	if b.state == HalfOpen {
+		if b.inFlight >= b.config.HalfOpenProbes {
+			return ErrOpen
+		}
+		b.inFlight++
+	}
+	return nil
+}
+
+// Success records a call that reached a healthy dependency.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	if b.state != HalfOpen {
+		return
+	}
+	if b.inFlight > 0 {
+		b.inFlight--
+	}
+	b.successes++
+	if b.successes >= b.config.HalfOpenProbes {
+		b.state = Closed
+		b.lastError = ""
+	}
+}
+
+// Ignore ends an allowed call that says nothing about the dependency's
+// health, such as one cancelled by its caller.
+func (b *Breaker) Ignore() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == HalfOpen && b.inFlight > 0 {
+		b.inFlight--
+	}
+}
+
+// Failure records a call that failed because of the dependency. A failed
+// probe reopens the breaker straight away.
+func (b *Breaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if err != nil {
+		b.lastError = err.Error()
+	}
+	switch b.state {
+	case HalfOpen:
+		b.trip()
+	case Closed:
+		if b.failures >= b.config.FailureThreshold {
+			b.trip()
+		}
+	}
+}
+
+func (b *Breaker) State() State {
+	return b.Status().State
+}
+
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := Status{State: b.state, ConsecutiveFailures: b.failures, LastError: b.lastError}
+	if b.state == Open && b.config.Now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		// Report what the next call will see
+		status.State = HalfOpen
+	}
+	if b.state != Closed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// trip must be called with b.mu held.
+func (b *Breaker) trip() {
+	b.state = Open
+	b.openedAt = b.config.Now()
+	b.inFlight = 0
+	b.successes = 0
+}
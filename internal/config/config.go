@@ -4,8 +4,10 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -26,6 +28,91 @@ type Config struct {
 	EnableCORS    bool
 	EnableLogging bool
 	RateLimit     int
+
+	RateLimitBackend string   // memory or mongo
+	RateLimitWrites  int      // per minute, POST routes under /api/v1
+	RateLimitAdmin   int      // per minute, /admin
+	RateLimitKeyBy   []string // api_key, address and/or ip, in order
+	TrustedProxies   []string // IPs or CIDRs allowed to set X-Forwarded-For
+
+	ChallengeSweepInterval int // seconds
+
+	MatchmakingInterval      int // seconds
+	MatchmakingInitialWindow float64
+	MatchmakingWidenBy       float64
+	MatchmakingWidenEvery    int // seconds
+	MatchmakingMaxWindow     float64
+	MatchmakingTicketTTL     int // seconds
+
+	TournamentInterval int // seconds
+
+	AdminAPIKey string
+	AdminKeys   []string // name:key
+
+	ConfirmationInterval    int // seconds
+	WebhookDispatchInterval int // seconds
+	WebhookMaxAttempts      int
+	WebhookBaseDelay        int // seconds
+	WebhookMaxDelay         int // seconds
+	WebhookTimeout          int // seconds
+
+	StreamHeartbeatInterval int // seconds
+	StreamReplayBuffer      int
+
+	LiveMaxConnections          int
+	LiveMaxConnectionsPerClient int
+	LiveMaxPerGame              int
+
+	KillSwitchCacheTTL   int // seconds
+	KillSwitchRetryAfter int // seconds
+	GasMinBalance        int // MIST; 0 disables the auto pause
+	GasCheckInterval     int // seconds
+
+	SuiRPCTimeout            int // seconds, per attempt
+	SuiRPCMaxRetries         int
+	SuiRPCRetryBaseMs        int
+	SuiBreakerFailures       int
+	SuiBreakerOpenSeconds    int
+	SuiBreakerHalfOpenProbes int
+
+	SuiRPCURLs             []string // defaults to SuiNetworkURL
+	SuiRPCHealthInterval   int      // seconds
+	SuiRPCMaxCheckpointLag int
+
+	ReadinessCacheTTL         int // seconds
+	ReadinessTimeout          int // seconds, per dependency
+	ReadinessMaxCheckpointAge int // seconds
+
+	DependencyCheckInterval int // seconds
+
+	WalletLeaseTTL  int // seconds
+	WalletLeaseWait int // seconds
+
+	StakeLimitMax         int
+	StakeLimitDaily       int
+	StakeLimitWeekly      int
+	StakeLimitMonthly     int
+	StakeLimitNetLoss     int
+	StakeLimitLoosenDelay int // hours
+
+	ScreeningFile string
+
+	PayoutMaxScore          int
+	PayoutMaxScores         []string // game_type:max
+	PayoutMinGameSeconds    int
+	PayoutDailyCapPerServer int
+
+	PayoutApprovalThreshold int
+	PayoutApprovalsRequired int
+	PayoutApprovalTTL       int // hours
+	PayoutExpiryInterval    int // seconds
+
+	RefundDraws      bool
+	StuckGameTimeout int // minutes
+
+	TimeoutSettlementInterval int // seconds
+	TimeoutSettlementAfter    int // minutes
+	TimeoutSettlementPolicy   string
 }
 
 func LoadConfig() *Config {
@@ -62,7 +149,147 @@ func LoadConfig() *Config {
 		EnableCORS:    getEnvBool("ENABLE_CORS", true),
 		EnableLogging: getEnvBool("ENABLE_LOGGING", true),
 		RateLimit:     getEnvInt("RATE_LIMIT", 100),
+
+		RateLimitBackend: getEnv("RATE_LIMIT_BACKEND", "memory"),
+		RateLimitWrites:  getEnvInt("RATE_LIMIT_WRITES", 20),
+		RateLimitAdmin:   getEnvInt("RATE_LIMIT_ADMIN", 60),
+		RateLimitKeyBy:   getEnvList("RATE_LIMIT_KEY_BY"),
+		TrustedProxies:   getEnvList("TRUSTED_PROXIES"),
+
+		ChallengeSweepInterval: getEnvInt("CHALLENGE_SWEEP_INTERVAL", 60),
+
+		MatchmakingInterval:      getEnvInt("MATCHMAKING_INTERVAL", 5),
+		MatchmakingInitialWindow: getEnvFloat("MATCHMAKING_INITIAL_WINDOW", 100),
+		MatchmakingWidenBy:       getEnvFloat("MATCHMAKING_WIDEN_BY", 50),
+		MatchmakingWidenEvery:    getEnvInt("MATCHMAKING_WIDEN_EVERY", 10),
+		MatchmakingMaxWindow:     getEnvFloat("MATCHMAKING_MAX_WINDOW", 600),
+		MatchmakingTicketTTL:     getEnvInt("MATCHMAKING_TICKET_TTL", 600),
+
+		TournamentInterval: getEnvInt("TOURNAMENT_INTERVAL", 5),
+
+		AdminAPIKey: getEnv("ADMIN_API_KEY", ""),
+		AdminKeys:   getEnvList("ADMIN_KEYS"),
+
+		ConfirmationInterval:    getEnvInt("CONFIRMATION_INTERVAL", 15),
+		WebhookDispatchInterval: getEnvInt("WEBHOOK_DISPATCH_INTERVAL", 5),
+		WebhookMaxAttempts:      getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookBaseDelay:        getEnvInt("WEBHOOK_BASE_DELAY", 30),
+		WebhookMaxDelay:         getEnvInt("WEBHOOK_MAX_DELAY", 21600),
+		WebhookTimeout:          getEnvInt("WEBHOOK_TIMEOUT", 10),
+
+		StreamHeartbeatInterval: getEnvInt("STREAM_HEARTBEAT_INTERVAL", 15),
+		StreamReplayBuffer:      getEnvInt("STREAM_REPLAY_BUFFER", 256),
+
+		LiveMaxConnections:          getEnvInt("LIVE_MAX_CONNECTIONS", 2000),
+		LiveMaxConnectionsPerClient: getEnvInt("LIVE_MAX_CONNECTIONS_PER_CLIENT", 10),
+		LiveMaxPerGame:              getEnvInt("LIVE_MAX_PER_GAME", 200),
+
+		KillSwitchCacheTTL:   getEnvInt("KILL_SWITCH_CACHE_TTL", 5),
+		KillSwitchRetryAfter: getEnvInt("KILL_SWITCH_RETRY_AFTER", 300),
+		GasMinBalance:        getEnvInt("GAS_MIN_BALANCE", 0),
+		GasCheckInterval:     getEnvInt("GAS_CHECK_INTERVAL", 60),
+
+		SuiRPCTimeout:            getEnvInt("SUI_RPC_TIMEOUT", 10),
+		SuiRPCMaxRetries:         getEnvInt("SUI_RPC_MAX_RETRIES", 2),
+		SuiRPCRetryBaseMs:        getEnvInt("SUI_RPC_RETRY_BASE_MS", 200),
+		SuiBreakerFailures:       getEnvInt("SUI_BREAKER_FAILURES", 5),
+		SuiBreakerOpenSeconds:    getEnvInt("SUI_BREAKER_OPEN_SECONDS", 30),
+		SuiBreakerHalfOpenProbes: getEnvInt("SUI_BREAKER_HALF_OPEN_PROBES", 1),
+
+		SuiRPCURLs:             getEnvList("SUI_RPC_URLS"),
+		SuiRPCHealthInterval:   getEnvInt("SUI_RPC_HEALTH_INTERVAL", 15),
+		SuiRPCMaxCheckpointLag: getEnvInt("SUI_RPC_MAX_CHECKPOINT_LAG", 20),
+
+		ReadinessCacheTTL:         getEnvInt("READINESS_CACHE_TTL", 2),
+		ReadinessTimeout:          getEnvInt("READINESS_TIMEOUT", 3),
+		ReadinessMaxCheckpointAge: getEnvInt("READINESS_MAX_CHECKPOINT_AGE", 60),
+
+		DependencyCheckInterval: getEnvInt("DEPENDENCY_CHECK_INTERVAL", 5),
+
+		WalletLeaseTTL:  getEnvInt("WALLET_LEASE_TTL", 30),
+		WalletLeaseWait: getEnvInt("WALLET_LEASE_WAIT", 20),
+
+		StakeLimitMax:         getEnvInt("STAKE_LIMIT_MAX", 0),
+		StakeLimitDaily:       getEnvInt("STAKE_LIMIT_DAILY", 0),
+		StakeLimitWeekly:      getEnvInt("STAKE_LIMIT_WEEKLY", 0),
+		StakeLimitMonthly:     getEnvInt("STAKE_LIMIT_MONTHLY", 0),
+		StakeLimitNetLoss:     getEnvInt("STAKE_LIMIT_NET_LOSS", 0),
+		StakeLimitLoosenDelay: getEnvInt("STAKE_LIMIT_LOOSEN_DELAY", 24),
+
+		ScreeningFile: getEnv("SCREENING_FILE", ""),
+
+		PayoutMaxScore:          getEnvInt("PAYOUT_MAX_SCORE", 0),
+		PayoutMaxScores:         getEnvList("PAYOUT_MAX_SCORES"),
+		PayoutMinGameSeconds:    getEnvInt("PAYOUT_MIN_GAME_SECONDS", 0),
+		PayoutDailyCapPerServer: getEnvInt("PAYOUT_DAILY_CAP_PER_SERVER", 0),
+
+		PayoutApprovalThreshold: getEnvInt("PAYOUT_APPROVAL_THRESHOLD", 0),
+		PayoutApprovalsRequired: getEnvInt("PAYOUT_APPROVALS_REQUIRED", 2),
+		PayoutApprovalTTL:       getEnvInt("PAYOUT_APPROVAL_TTL", 24),
+		PayoutExpiryInterval:    getEnvInt("PAYOUT_EXPIRY_INTERVAL", 60),
+
+		RefundDraws:      getEnvBool("REFUND_DRAWS", false),
+		StuckGameTimeout: getEnvInt("STUCK_GAME_TIMEOUT", 60),
+
+		TimeoutSettlementInterval: getEnvInt("TIMEOUT_SETTLEMENT_INTERVAL", 60),
+		TimeoutSettlementAfter:    getEnvInt("TIMEOUT_SETTLEMENT_AFTER", 0),
+		TimeoutSettlementPolicy:   getEnv("TIMEOUT_SETTLEMENT_POLICY", "refund"),
+	}
+}
+
+// RateLimitKeys returns what callers are identified by for rate limiting,
+// most specific first.
+func (c *Config) RateLimitKeys() []string {
+	if len(c.RateLimitKeyBy) > 0 {
+		return c.RateLimitKeyBy
 	}
+	return []string{"address", "ip"}
+}
+
+// RPCURLs returns the Sui RPC endpoints in order of preference.
+func (c *Config) RPCURLs() []string {
+	if len(c.SuiRPCURLs) > 0 {
+		return c.SuiRPCURLs
+	}
+	return []string{c.SuiNetworkURL}
+}
+
+// PayoutMaxScoresByType parses PAYOUT_MAX_SCORES, a list of game_type:max
+// pairs.
+func (c *Config) PayoutMaxScoresByType() (map[string]uint64, error) {
+	scores := make(map[string]uint64, len(c.PayoutMaxScores))
+	for _, pair := range c.PayoutMaxScores {
+		gameType, max, ok := strings.Cut(pair, ":")
+		value, err := strconv.ParseUint(strings.TrimSpace(max), 10, 64)
+		if !ok || err != nil || strings.TrimSpace(gameType) == "" {
+			return nil, fmt.Errorf("PAYOUT_MAX_SCORES: %q is not game_type:max", pair)
+		}
+		scores[strings.TrimSpace(gameType)] = value
+	}
+	return scores, nil
+}
+
+// AdminKeysByName parses ADMIN_KEYS, a list of name:key pairs. Names and keys
+// must be unique, and no key may equal ADMIN_API_KEY.
+func (c *Config) AdminKeysByName() (map[string]string, error) {
+	keys := make(map[string]string, len(c.AdminKeys))
+	seen := make(map[string]bool, len(c.AdminKeys))
+	for _, pair := range c.AdminKeys {
+		name, key, ok := strings.Cut(pair, ":")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !ok || name == "" || key == "" {
+			return nil, fmt.Errorf("ADMIN_KEYS: entries must be name:key")
+		}
+		if _, exists := keys[name]; exists {
+			return nil, fmt.Errorf("ADMIN_KEYS: %q is listed twice", name)
+		}
+		if seen[key] || key == c.AdminAPIKey {
+			return nil, fmt.Errorf("ADMIN_KEYS: the key for %q is not unique", name)
+		}
+		keys[name] = key
+		seen[key] = true
+	}
+	return keys, nil
 }
 
 func (c *Config) ValidateConfig() error {
@@ -79,6 +306,31 @@ func (c *Config) ValidateConfig() error {
 			return fmt.Errorf("required environment variable %s is not set", key)
 		}
 	}
+	if _, err := c.PayoutMaxScoresByType(); err != nil {
+		return err
+	}
+	for _, proxy := range c.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("TRUSTED_PROXIES entry %q is not an IP or CIDR", proxy)
+			}
+		}
+	}
+	if c.TimeoutSettlementPolicy != "refund" && c.TimeoutSettlementPolicy != "award_reporter" {
+		return fmt.Errorf("TIMEOUT_SETTLEMENT_POLICY must be refund or award_reporter, got %q", c.TimeoutSettlementPolicy)
+	}
+	adminKeys, err := c.AdminKeysByName()
+	if err != nil {
+		return err
+	}
+	if c.PayoutApprovalThreshold > 0 {
+		if c.PayoutApprovalsRequired < 1 {
+			return fmt.Errorf("PAYOUT_APPROVALS_REQUIRED must be at least 1")
+		}
+		if len(adminKeys) < c.PayoutApprovalsRequired {
+			return fmt.Errorf("PAYOUT_APPROVALS_REQUIRED is %d but ADMIN_KEYS names %d admins", c.PayoutApprovalsRequired, len(adminKeys))
+		}
+	}
 	return nil
 }
 
@@ -113,6 +365,27 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvList splits a comma-separated variable, dropping empty entries.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		f, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
 func generateRandomSecret() string {
 	b := make([]byte, 32)
 	_, err := rand.Read(b)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -19,6 +20,7 @@ type MongoClient struct {
 	client   *mongo.Client
 	database *mongo.Database
 	dbName   string
+	indexed  atomic.Bool
 }
 
 type Game struct {
@@ -28,14 +30,28 @@ type Game struct {
 	RequesterCoinID   string             `bson:"requester_coin_id" json:"requester_coin_id"`
 	AccepterCoinID    string             `bson:"accepter_coin_id" json:"accepter_coin_id"`
 	StakeAmount       uint64             `bson:"stake_amount" json:"stake_amount"`
+	GameType          string             `bson:"game_type,omitempty" json:"game_type,omitempty"`
 	Status            string             `bson:"status" json:"status"`
 	RequesterScore    *uint64            `bson:"requester_score,omitempty" json:"requester_score,omitempty"`
 	AccepterScore     *uint64            `bson:"accepter_score,omitempty" json:"accepter_score,omitempty"`
 	Winner            string             `bson:"winner,omitempty" json:"winner,omitempty"`
 	TransactionDigest string             `bson:"transaction_digest,omitempty" json:"transaction_digest,omitempty"`
+	RefundReason      string             `bson:"refund_reason,omitempty" json:"refund_reason,omitempty"`
+	RequesterReport   *ScoreReport       `bson:"requester_report,omitempty" json:"requester_report,omitempty"`
+	AccepterReport    *ScoreReport       `bson:"accepter_report,omitempty" json:"accepter_report,omitempty"`
 	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt         time.Time          `bson:"updated_at" json:"updated_at"`
-	CompletedAt       *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	// StakedAt is when the stake landed on chain; a challenge is created
+	// long before it is staked.
+	StakedAt    *time.Time `bson:"staked_at,omitempty" json:"staked_at,omitempty"`
+	CompletedAt *time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+}
+
+// ScoreReport is the score a player reported for their own side of a game.
+type ScoreReport struct {
+	Score      uint64    `bson:"score" json:"score"`
+	ReportedAt time.Time `bson:"reported_at" json:"reported_at"`
 }
 
 type User struct {
@@ -63,6 +79,9 @@ type Transaction struct {
 	ConfirmedAt *time.Time         `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
 }
 
+// NewMongoClient connects to MongoDB. An unreachable server is not fatal: the
+// driver keeps reconnecting in the background, and indexes are created by
+// EnsureIndexes once it answers. Only an unusable URI is fatal.
 func NewMongoClient(mongoURI string, dbName string) *MongoClient {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -79,26 +98,40 @@ func NewMongoClient(mongoURI string, dbName string) *MongoClient {
 		log.Fatalf("Failed to connect to MongoDB: %v", err)
 	}
 
-	// Test connection
-	if err = client.Ping(ctx, readpref.Primary()); err != nil {
-		log.Fatalf("Failed to ping MongoDB: %v", err)
-	}
-
-	log.Println("✅ Connected to MongoDB")
-
 	mongoClient := &MongoClient{
 		client:   client,
 		database: client.Database(dbName),
 		dbName:   dbName,
 	}
 
-	if err := mongoClient.createIndexes(ctx); err != nil {
+	// Test connection
+	if err = client.Ping(ctx, readpref.Primary()); err != nil {
+		log.Printf("⚠️  MongoDB not reachable yet, starting degraded: %v", err)
+		return mongoClient
+	}
+
+	log.Println("✅ Connected to MongoDB")
+
+	if err := mongoClient.EnsureIndexes(ctx); err != nil {
 		log.Printf("Warning: Failed to create indexes: %v", err)
 	}
 
 	return mongoClient
 }
 
+// EnsureIndexes creates the collection indexes the first time it succeeds and
+// is a no-op afterwards.
+func (m *MongoClient) EnsureIndexes(ctx context.Context) error {
+	if m.indexed.Load() {
+		return nil
+	}
+	if err := m.createIndexes(ctx); err != nil {
+		return err
+	}
+	m.indexed.Store(true)
+	return nil
+}
+
 func (m *MongoClient) GetDatabase(name string) interfaces.MongoDatabaseInterface {
 	database := m.client.Database(name)
 	return &MongoDatabase{database: database}
@@ -212,6 +245,7 @@ func (m *MongoClient) UpdateGame(ctx context.Context, gameID string, updates int
 			"winner":             u.Winner,
 			"transaction_digest": u.TransactionDigest,
 			"completed_at":       u.CompletedAt,
+			"expires_at":         u.ExpiresAt,
 		}
 	default:
 		return fmt.Errorf("unsupported update type: %T", updates)
@@ -234,6 +268,39 @@ func (m *MongoClient) UpdateGame(ctx context.Context, gameID string, updates int
 	return nil
 }
 
+// TransitionGame applies updates only while the game is still in fromStatus and
+// reports whether it did, so concurrent state changes cannot both succeed.
+func (m *MongoClient) TransitionGame(ctx context.Context, gameID, fromStatus string, updates interface{}) (bool, error) {
+	collection := m.database.Collection("games")
+
+	objectID, err := primitive.ObjectIDFromHex(gameID)
+	if err != nil {
+		return false, fmt.Errorf("invalid game ID format: %v", err)
+	}
+
+	var updateDoc bson.M
+	switch u := updates.(type) {
+	case bson.M:
+		updateDoc = u
+	case map[string]interface{}:
+		updateDoc = bson.M(u)
+	default:
+		return false, fmt.Errorf("unsupported update type: %T", updates)
+	}
+
+	updateDoc["updated_at"] = time.Now()
+
+	filter := bson.M{"_id": objectID, "status": fromStatus}
+	update := bson.M{"$set": updateDoc}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to transition game: %v", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
 func (m *MongoClient) GetGamesByStatus(ctx context.Context, status string) ([]interface{}, error) {
 	collection := m.database.Collection("games")
 
@@ -609,7 +676,7 @@ func (m *MongoClient) UpdateTransactionStatus(ctx context.Context, txDigest stri
 	return nil
 }
 
-func (m *MongoClient) GetPendingTransactions(ctx context.Context) ([]Transaction, error) {
+func (m *MongoClient) GetPendingTransactions(ctx context.Context) ([]interface{}, error) {
 	collection := m.database.Collection("transactions")
 
 	filter := bson.M{"status": "pending"}
@@ -621,7 +688,7 @@ func (m *MongoClient) GetPendingTransactions(ctx context.Context) ([]Transaction
 	}
 	defer cursor.Close(ctx)
 
-	var transactions []Transaction
+	var transactions []interface{}
 	for cursor.Next(ctx) {
 		var tx Transaction
 		if err := cursor.Decode(&tx); err != nil {
@@ -707,6 +774,224 @@ func (m *MongoClient) createIndexes(ctx context.Context) error {
 		return fmt.Errorf("failed to create transactions indexes: %v", err)
 	}
 
+	// Leaderboards are written by the game service, which uses the jollfi_games database
+	leaderboardsCollection := m.client.Database("jollfi_games").Collection("leaderboards")
+	leaderboardsIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "period", Value: 1}, {Key: "period_key", Value: 1}, {Key: "address", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "period", Value: 1}, {Key: "period_key", Value: 1}, {Key: "wins", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "period", Value: 1}, {Key: "period_key", Value: 1}, {Key: "profit", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "period", Value: 1}, {Key: "period_key", Value: 1}, {Key: "volume", Value: -1}},
+		},
+	}
+
+	if _, err := leaderboardsCollection.Indexes().CreateMany(ctx, leaderboardsIndexes); err != nil {
+		return fmt.Errorf("failed to create leaderboards indexes: %v", err)
+	}
+
+	// Each game type's leaderboards are kept apart from the overall ones
+	gameTypeLeaderboardsCollection := m.client.Database("jollfi_games").Collection("game_type_leaderboards")
+	gameTypeLeaderboardsIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "game_type", Value: 1}, {Key: "period", Value: 1}, {Key: "period_key", Value: 1}, {Key: "address", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "game_type", Value: 1}, {Key: "period", Value: 1}, {Key: "period_key", Value: 1}, {Key: "wins", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "game_type", Value: 1}, {Key: "period", Value: 1}, {Key: "period_key", Value: 1}, {Key: "profit", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "game_type", Value: 1}, {Key: "period", Value: 1}, {Key: "period_key", Value: 1}, {Key: "volume", Value: -1}},
+		},
+	}
+
+	if _, err := gameTypeLeaderboardsCollection.Indexes().CreateMany(ctx, gameTypeLeaderboardsIndexes); err != nil {
+		return fmt.Errorf("failed to create game type leaderboards indexes: %v", err)
+	}
+
+	// Ratings are upserted by address, so concurrent first games must collide
+	ratingUsersCollection := m.client.Database("jollfi_games").Collection("users")
+	ratingUsersIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "address", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "rating.rating", Value: -1}},
+		},
+	}
+
+	if _, err := ratingUsersCollection.Indexes().CreateMany(ctx, ratingUsersIndexes); err != nil {
+		return fmt.Errorf("failed to create rating users indexes: %v", err)
+	}
+
+	ratingHistoryCollection := m.client.Database("jollfi_games").Collection("rating_history")
+	ratingHistoryIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "address", Value: 1}, {Key: "timestamp", Value: -1}},
+		},
+	}
+
+	if _, err := ratingHistoryCollection.Indexes().CreateMany(ctx, ratingHistoryIndexes); err != nil {
+		return fmt.Errorf("failed to create rating history indexes: %v", err)
+	}
+
+	queueCollection := m.client.Database("jollfi_games").Collection("matchmaking_queue")
+	queueIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "stake_amount", Value: 1}, {Key: "joined_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "address", Value: 1}, {Key: "status", Value: 1}},
+		},
+		// One live ticket per address, so concurrent joins cannot queue a
+		// player twice. Partial indexes cannot filter with $in before
+		// MongoDB 6.0, so live tickets carry a flag instead.
+		{
+			Keys:    bson.D{{Key: "address", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"live": true}),
+		},
+	}
+
+	if _, err := queueCollection.Indexes().CreateMany(ctx, queueIndexes); err != nil {
+		return fmt.Errorf("failed to create matchmaking queue indexes: %v", err)
+	}
+
+	deliveriesCollection := m.client.Database("jollfi_games").Collection("webhook_deliveries")
+	deliveriesIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "endpoint_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+	}
+
+	if _, err := deliveriesCollection.Indexes().CreateMany(ctx, deliveriesIndexes); err != nil {
+		return fmt.Errorf("failed to create webhook delivery indexes: %v", err)
+	}
+
+	auditCollection := m.client.Database("jollfi_games").Collection("admin_audit_log")
+	auditIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "action", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	if _, err := auditCollection.Indexes().CreateMany(ctx, auditIndexes); err != nil {
+		return fmt.Errorf("failed to create admin audit indexes: %v", err)
+	}
+
+	heldPayoutsCollection := m.client.Database("jollfi_games").Collection("held_payouts")
+	heldPayoutsIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	if _, err := heldPayoutsCollection.Indexes().CreateMany(ctx, heldPayoutsIndexes); err != nil {
+		return fmt.Errorf("failed to create held payout indexes: %v", err)
+	}
+
+	timeoutSettlementsCollection := m.client.Database("jollfi_games").Collection("timeout_settlements")
+	timeoutSettlementsIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "game_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	if _, err := timeoutSettlementsCollection.Indexes().CreateMany(ctx, timeoutSettlementsIndexes); err != nil {
+		return fmt.Errorf("failed to create timeout settlement indexes: %v", err)
+	}
+
+	// Pending payouts are listed by status and swept once they expire
+	pendingPayoutsCollection := m.client.Database("jollfi_games").Collection("pending_payouts")
+	pendingPayoutsIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}},
+		},
+	}
+
+	if _, err := pendingPayoutsCollection.Indexes().CreateMany(ctx, pendingPayoutsIndexes); err != nil {
+		return fmt.Errorf("failed to create pending payout indexes: %v", err)
+	}
+
+	tournamentsCollection := m.client.Database("jollfi_games").Collection("tournaments")
+	tournamentsIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+	}
+
+	if _, err := tournamentsCollection.Indexes().CreateOne(ctx, tournamentsIndex); err != nil {
+		return fmt.Errorf("failed to create tournament indexes: %v", err)
+	}
+
+	tournamentEntriesCollection := m.client.Database("jollfi_games").Collection("tournament_entries")
+	tournamentEntriesIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "tournament_id", Value: 1}, {Key: "seed", Value: 1}},
+	}
+
+	if _, err := tournamentEntriesCollection.Indexes().CreateOne(ctx, tournamentEntriesIndex); err != nil {
+		return fmt.Errorf("failed to create tournament entry indexes: %v", err)
+	}
+
+	// Settled games are matched back to their tournament match by game_id
+	tournamentMatchesCollection := m.client.Database("jollfi_games").Collection("tournament_matches")
+	tournamentMatchesIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tournament_id", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "game_id", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	}
+
+	if _, err := tournamentMatchesCollection.Indexes().CreateMany(ctx, tournamentMatchesIndexes); err != nil {
+		return fmt.Errorf("failed to create tournament match indexes: %v", err)
+	}
+
+	// The payout guard totals each game server's payouts over the last day
+	payoutsByServerCollection := m.client.Database("jollfi_games").Collection("pay_winners")
+	payoutsByServerIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "game_server", Value: 1}, {Key: "timestamp", Value: -1}},
+	}
+
+	if _, err := payoutsByServerCollection.Indexes().CreateOne(ctx, payoutsByServerIndex); err != nil {
+		return fmt.Errorf("failed to create payout indexes: %v", err)
+	}
+
+	rateLimitCollection := m.client.Database("jollfi_games").Collection("rate_limits")
+	rateLimitIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+
+	if _, err := rateLimitCollection.Indexes().CreateOne(ctx, rateLimitIndex); err != nil {
+		return fmt.Errorf("failed to create rate limit indexes: %v", err)
+	}
+
 	return nil
 }
 
@@ -51,14 +51,18 @@ func (mc *MongoCollection) FindOne(ctx context.Context, filter interface{}) inte
 	return mc.collection.FindOne(ctx, filter)
 }
 
-func (mc *MongoCollection) UpdateOne(ctx context.Context, filter, update interface{}) (*mongo.UpdateResult, error) {
-	return mc.collection.UpdateOne(ctx, filter, update)
+func (mc *MongoCollection) UpdateOne(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return mc.collection.UpdateOne(ctx, filter, update, opts...)
 }
 
 func (mc *MongoCollection) DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error) {
 	return mc.collection.DeleteOne(ctx, filter)
 }
 
+func (mc *MongoCollection) CountDocuments(ctx context.Context, filter interface{}) (int64, error) {
+	return mc.collection.CountDocuments(ctx, filter)
+}
+
 func (md *MongoDatabase) Collection(name string) interfaces.MongoCollectionInterface {
 	collection := md.database.Collection(name)
 	return &MongoCollection{collection: collection}
@@ -0,0 +1,129 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"jollfi-gaming-api/internal/models"
+)
+
+// GetPool fetches the configured pool and decodes it, checking that it is an
+// object of the configured package and module.
+func (s *SuiClient) GetPool(ctx context.Context) (*models.StakePool, error) {
+	raw, err := s.GetStakePool(s.config.PoolID)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := DecodeStakePool(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !IsModuleType(pool.Type, s.config.PackageID, s.config.ModuleName) {
+		return nil, fmt.Errorf("object %s has type %s, not a type from %s::%s", pool.ObjectID, pool.Type, s.config.PackageID, s.config.ModuleName)
+	}
+	return pool, nil
+}
+
+// DecodeStakePool reads a sui_getObject result, requested with showContent
+// and showType, into a StakePool. Numeric Move fields may arrive as strings
+// or numbers; Balance<T> and Coin<T> fields are both accepted for balance.
+func DecodeStakePool(object map[string]interface{}) (*models.StakePool, error) {
+	if lookupErr, ok := object["error"]; ok {
+		return nil, fmt.Errorf("pool object lookup failed: %v", lookupErr)
+	}
+	data, ok := object["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("pool object response has no data")
+	}
+	content, ok := data["content"].(map[string]interface{})
+	if !ok || content["dataType"] != "moveObject" {
+		return nil, fmt.Errorf("pool object %v is not a Move object", data["objectId"])
+	}
+	fields, ok := content["fields"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("pool object %v has no fields", data["objectId"])
+	}
+
+	pool := &models.StakePool{Fields: fields}
+	pool.ObjectID, _ = data["objectId"].(string)
+	if pool.Type, _ = data["type"].(string); pool.Type == "" {
+		pool.Type, _ = content["type"].(string)
+	}
+
+	var err error
+	if pool.Version, err = moveUint(data["version"]); err != nil {
+		return nil, fmt.Errorf("pool version: %v", err)
+	}
+	if pool.Balance, err = moveUint(fields["balance"]); err != nil {
+		return nil, fmt.Errorf("pool balance: %v", err)
+	}
+	if pool.FeeBps, err = moveUint(fields["fee_bps"]); err != nil {
+		return nil, fmt.Errorf("pool fee_bps: %v", err)
+	}
+	if pool.TotalGames, err = moveUint(fields["total_games"]); err != nil {
+		return nil, fmt.Errorf("pool total_games: %v", err)
+	}
+	if admin, ok := fields["admin"]; ok {
+		if pool.Admin, ok = admin.(string); !ok {
+			return nil, fmt.Errorf("pool admin: expected an address, got %T", admin)
+		}
+	}
+	if paused, ok := fields["paused"]; ok {
+		if pool.Paused, ok = paused.(bool); !ok {
+			return nil, fmt.Errorf("pool paused: expected a bool, got %T", paused)
+		}
+	}
+	return pool, nil
+}
+
+// IsModuleType reports whether a Move type string such as
+// "0x2a::game::StakePool<0x2::sui::SUI>" is declared in packageID::module.
+// Addresses are compared in their normalised 32-byte form.
+func IsModuleType(moveType, packageID, module string) bool {
+	parts := strings.SplitN(moveType, "::", 3)
+	if len(parts) < 3 {
+		return false
+	}
+	return normalizeSuiAddress(parts[0]) == normalizeSuiAddress(packageID) && parts[1] == module
+}
+
+func normalizeSuiAddress(address string) string {
+	hexPart := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	if len(hexPart) < 64 {
+		hexPart = strings.Repeat("0", 64-len(hexPart)) + hexPart
+	}
+	return "0x" + hexPart
+}
+
+// moveUint decodes a Move integer field. Missing fields decode as zero.
+func moveUint(v interface{}) (uint64, error) {
+	switch value := v.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		return strconv.ParseUint(value, 10, 64)
+	case float64:
+		if value < 0 || value != float64(uint64(value)) {
+			return 0, fmt.Errorf("expected an unsigned integer, got %v", value)
+		}
+		return uint64(value), nil
+	case json.Number:
+		return strconv.ParseUint(value.String(), 10, 64)
+	case map[string]interface{}:
+		// Nested structs such as Coin<T> or Balance<T> wrapped in fields
+		if fields, ok := value["fields"].(map[string]interface{}); ok {
+			if inner, ok := fields["balance"]; ok {
+				return moveUint(inner)
+			}
+			if inner, ok := fields["value"]; ok {
+				return moveUint(inner)
+			}
+		}
+		return 0, fmt.Errorf("unrecognised struct %v", value)
+	default:
+		return 0, fmt.Errorf("expected an unsigned integer, got %T", v)
+	}
+}
@@ -7,20 +7,30 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
+
+	"jollfi-gaming-api/internal/breaker"
+	"jollfi-gaming-api/internal/lease"
+	"jollfi-gaming-api/internal/models"
 )
 
 type SuiClient struct {
-	rpcURL     string
 	privateKey ed25519.PrivateKey
 	publicKey  ed25519.PublicKey
 	address    string
 	config     *Config
 	httpClient *http.Client
+	rpc        RPCConfig
+
+	endpointsMu sync.RWMutex
+	endpoints   []*rpcEndpoint
+	pinned      *rpcEndpoint
 }
 
 type Config struct {
@@ -48,6 +58,10 @@ type RPCError struct {
 	Message string `json:"message"`
 }
 
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error [%d]: %s", e.Code, e.Message)
+}
+
 type TransactionBlockResponse struct {
 	Digest  string                 `json:"digest"`
 	Effects map[string]interface{} `json:"effects,omitempty"`
@@ -105,17 +119,101 @@ func NewSuiClient(rpcURL string, privateKeyStr string, cfg *Config) (*SuiClient,
 	// Generate Sui address
 	address := fmt.Sprintf("0x%x", publicKey[:20])
 
-	return &SuiClient{
-		rpcURL:     rpcURL,
+	client := &SuiClient{
 		privateKey: privateKey,
 		publicKey:  publicKey,
 		address:    address,
 		config:     cfg,
 		httpClient: &http.Client{Timeout: 30 * time.Second},
-	}, nil
+		rpc:        DefaultRPCConfig(),
+		endpoints:  newRPCEndpoints([]string{rpcURL}, DefaultRPCConfig().Breaker),
+	}
+	client.pinned = client.endpoints[0]
+	return client, nil
 }
 
+// makeRPCCall sends one JSON-RPC request. Read-only methods go to the best
+// scoring endpoint and fail over to the others, with jittered backoff once
+// every endpoint has been tried. Anything that may submit a transaction goes
+// once to the pinned endpoint so it is never executed twice. Transactions are
+// only sent if the fence on ctx, if any, still holds.
 func (s *SuiClient) makeRPCCall(ctx context.Context, method string, params []interface{}) (*RPCResponse, error) {
+	if !readOnlyMethods[method] {
+		if method == "sui_executeTransactionBlock" {
+			if err := lease.CheckFence(ctx); err != nil {
+				return nil, fmt.Errorf("sui rpc %s: %w", method, err)
+			}
+		}
+		return s.callEndpoint(ctx, s.pinnedEndpoint(), method, params)
+	}
+
+	ranked := s.rankedEndpoints()
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("sui rpc %s: %w", method, breaker.ErrOpen)
+	}
+	attempts := 1 + s.rpc.MaxRetries
+	if attempts < len(ranked) {
+		attempts = len(ranked)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt >= len(ranked) {
+			if err := sleepContext(ctx, s.rpc.retryDelay(attempt-len(ranked)+1)); err != nil {
+				return nil, lastErr
+			}
+		}
+		rpcResp, err := s.callEndpoint(ctx, ranked[attempt%len(ranked)], method, params)
+		if err == nil {
+			return rpcResp, nil
+		}
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) || ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// callEndpoint makes one attempt against an endpoint through its circuit
+// breaker and records the outcome in its health score.
+func (s *SuiClient) callEndpoint(ctx context.Context, endpoint *rpcEndpoint, method string, params []interface{}) (*RPCResponse, error) {
+	if err := endpoint.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("sui rpc %s: %w", method, err)
+	}
+
+	start := time.Now()
+	rpcResp, err := s.rpcAttempt(ctx, endpoint.url, method, params)
+	switch {
+	case err == nil:
+		endpoint.breaker.Success()
+		endpoint.record(time.Since(start), false)
+		if rpcResp.Error != nil {
+			// The node is up; the request itself was rejected
+			return nil, rpcResp.Error
+		}
+		return rpcResp, nil
+	case ctx.Err() != nil:
+		endpoint.breaker.Ignore()
+		return nil, err
+	default:
+		endpoint.breaker.Failure(err)
+		endpoint.record(0, true)
+		return nil, err
+	}
+}
+
+// rpcAttempt makes a single request. Errors returned here mean the node could
+// not be reached or did not answer properly; RPC errors are left in the
+// response.
+func (s *SuiClient) rpcAttempt(ctx context.Context, url, method string, params []interface{}) (*RPCResponse, error) {
+	if s.rpc.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.rpc.Timeout)
+		defer cancel()
+	}
+
 	reqBody := RPCRequest{
 		JSONRPC: "2.0",
 		ID:      1,
@@ -128,7 +226,7 @@ func (s *SuiClient) makeRPCCall(ctx context.Context, method string, params []int
 		return nil, fmt.Errorf("failed to marshal request: %v", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", s.rpcURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
@@ -137,7 +235,7 @@ func (s *SuiClient) makeRPCCall(ctx context.Context, method string, params []int
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %v", err)
+		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -145,15 +243,15 @@ func (s *SuiClient) makeRPCCall(ctx context.Context, method string, params []int
 		}
 	}(resp.Body)
 
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("rpc node returned HTTP %d", resp.StatusCode)
+	}
+
 	var rpcResp RPCResponse
 	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %v", err)
 	}
 
-	if rpcResp.Error != nil {
-		return nil, fmt.Errorf("RPC error [%d]: %s", rpcResp.Error.Code, rpcResp.Error.Message)
-	}
-
 	return &rpcResp, nil
 }
 
@@ -216,7 +314,7 @@ func (s *SuiClient) ExternalStake(requesterCoinID, accepterCoinID string, amount
 
 	coins, err := s.GetCoins(ctx, "0x2::sui::SUI")
 	if err != nil {
-		return "", fmt.Errorf("failed to get coins for gas: %v", err)
+		return "", fmt.Errorf("failed to get coins for gas: %w", err)
 	}
 
 	var gasCoin string
@@ -308,7 +406,7 @@ func (s *SuiClient) ExternalPayWinner(requesterAddress, accepterAddress string,
 
 	execResp, err := s.makeRPCCall(ctx, "sui_executeTransactionBlock", execParams)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute transaction: %v", err)
+		return "", fmt.Errorf("failed to execute transaction: %w", err)
 	}
 
 	var txResult TransactionBlockResponse
@@ -342,6 +440,35 @@ func (s *SuiClient) ExternalPayWinner(requesterAddress, accepterAddress string,
 	return txResult.Digest, nil
 }
 
+// ExternalRefund returns both players' stakes of a game through the
+// contract's refund entry function.
+func (s *SuiClient) ExternalRefund(requesterAddress, accepterAddress string, stakeAmount uint64, ctx context.Context) (string, error) {
+	coins, err := s.GetCoins(ctx, "0x2::sui::SUI")
+	if err != nil {
+		return "", fmt.Errorf("failed to get coins: %v", err)
+	}
+	if len(coins) == 0 {
+		return "", fmt.Errorf("no SUI coins available for gas")
+	}
+	moveCallReq := MoveCallRequest{
+		Signer:          s.address,
+		PackageObjectId: s.config.PackageID,
+		Module:          s.config.ModuleName,
+		Function:        "external_refund",
+		TypeArguments:   []string{},
+		Arguments: []interface{}{
+			s.config.PoolID,
+			requesterAddress,
+			accepterAddress,
+			fmt.Sprintf("%d", stakeAmount),
+		},
+		Gas:       coins[0]["coinObjectId"].(string),
+		GasBudget: "10000000",
+	}
+
+	return s.executeTransaction(ctx, moveCallReq, "ExternalGameRefunded")
+}
+
 func (s *SuiClient) ExecuteTransactionBlock(ctx context.Context, txBytes []byte) (string, error) {
 	txBytesStr := base64.StdEncoding.EncodeToString(txBytes)
 
@@ -361,7 +488,7 @@ func (s *SuiClient) ExecuteTransactionBlock(ctx context.Context, txBytes []byte)
 
 	execResp, err := s.makeRPCCall(ctx, "sui_executeTransactionBlock", execParams)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute transaction: %v", err)
+		return "", fmt.Errorf("failed to execute transaction: %w", err)
 	}
 
 	var txResult TransactionBlockResponse
@@ -671,22 +798,47 @@ func (s *SuiClient) ExternalStakeEnhanced(requesterAddress, accepterAddress stri
 }
 
 func (s *SuiClient) executeTransaction(ctx context.Context, moveCallReq MoveCallRequest, expectedEventSuffix string) (string, error) {
-	params := []interface{}{moveCallReq}
-	resp, err := s.makeRPCCall(ctx, "unsafe_moveCall", params)
+	txResult, err := s.buildAndExecute(ctx, "unsafe_moveCall", []interface{}{moveCallReq})
 	if err != nil {
-		return "", fmt.Errorf("failed to build transaction: %v", err)
+		return "", err
+	}
+
+	eventType := fmt.Sprintf("%s::%s::%s", s.config.PackageID, s.config.ModuleName, expectedEventSuffix)
+	eventFound := false
+	for _, event := range txResult.Events {
+		if event.Type == eventType {
+			eventFound = true
+			fmt.Printf("✅ Event emitted: %s\n", string(event.ParsedJson))
+			break
+		}
+	}
+
+	if !eventFound {
+		fmt.Printf("⚠️  Expected event %s not found in transaction events\n", eventType)
+	}
+
+	return txResult.Digest, nil
+}
+
+// buildAndExecute builds a transaction with one of the unsafe_ builder
+// methods, signs it with the operator key and executes it, failing if the
+// transaction did not succeed.
+func (s *SuiClient) buildAndExecute(ctx context.Context, method string, params []interface{}) (*TransactionBlockResponse, error) {
+	resp, err := s.makeRPCCall(ctx, method, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transaction: %w", err)
 	}
 
 	var txBytes struct {
 		TxBytes string `json:"txBytes"`
 	}
 	if err := json.Unmarshal(resp.Result, &txBytes); err != nil {
-		return "", fmt.Errorf("failed to parse transaction bytes: %v", err)
+		return nil, fmt.Errorf("failed to parse transaction bytes: %v", err)
 	}
 
 	signature, err := s.signTransaction(txBytes.TxBytes)
 	if err != nil {
-		return "", fmt.Errorf("failed to sign transaction: %v", err)
+		return nil, fmt.Errorf("failed to sign transaction: %v", err)
 	}
 
 	execParams := []interface{}{
@@ -700,36 +852,22 @@ func (s *SuiClient) executeTransaction(ctx context.Context, moveCallReq MoveCall
 
 	execResp, err := s.makeRPCCall(ctx, "sui_executeTransactionBlock", execParams)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute transaction: %v", err)
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
 	}
 	var txResult TransactionBlockResponse
 	if err := json.Unmarshal(execResp.Result, &txResult); err != nil {
-		return "", fmt.Errorf("failed to parse execution result: %v", err)
+		return nil, fmt.Errorf("failed to parse execution result: %v", err)
 	}
 
 	if effects, ok := txResult.Effects["status"]; ok {
 		if status, ok := effects.(map[string]interface{}); ok {
 			if status["status"] != "success" {
-				return "", fmt.Errorf("transaction failed with status: %v", status)
+				return nil, fmt.Errorf("transaction failed with status: %v", status)
 			}
 		}
 	}
 
-	eventType := fmt.Sprintf("%s::%s::%s", s.config.PackageID, s.config.ModuleName, expectedEventSuffix)
-	eventFound := false
-	for _, event := range txResult.Events {
-		if event.Type == eventType {
-			eventFound = true
-			fmt.Printf("✅ Event emitted: %s\n", string(event.ParsedJson))
-			break
-		}
-	}
-
-	if !eventFound {
-		fmt.Printf("⚠️  Expected event %s not found in transaction events\n", eventType)
-	}
-
-	return txResult.Digest, nil
+	return &txResult, nil
 }
 
 func (s *SuiClient) GetAddress() string {
@@ -759,6 +897,9 @@ func (s *SuiClient) GetStakePool(poolID string) (map[string]interface{}, error)
 		return nil, fmt.Errorf("failed to parse stake pool response: %v", err)
 	}
 
+	if lookupErr, ok := result["error"]; ok {
+		return nil, fmt.Errorf("stake pool not found: %v", lookupErr)
+	}
 	if data, ok := result["data"]; ok {
 		if dataMap, ok := data.(map[string]interface{}); ok {
 			if dataMap["objectId"] == nil {
@@ -780,8 +921,7 @@ func (s *SuiClient) ValidatePoolConfig(ctx context.Context) error {
 	if s.config.ModuleName == "" {
 		return fmt.Errorf("module name not configured")
 	}
-	_, err := s.GetStakePool(s.config.PoolID)
-	if err != nil {
+	if _, err := s.GetPool(ctx); err != nil {
 		return fmt.Errorf("invalid pool configuration: %v", err)
 	}
 
@@ -958,6 +1098,41 @@ func (s *SuiClient) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// LatestCheckpoint returns the newest checkpoint the node knows of and when it
+// was created.
+func (s *SuiClient) LatestCheckpoint(ctx context.Context) (*models.Checkpoint, error) {
+	resp, err := s.makeRPCCall(ctx, "sui_getLatestCheckpointSequenceNumber", []interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest checkpoint: %v", err)
+	}
+	var sequence string
+	if err := json.Unmarshal(resp.Result, &sequence); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint sequence number: %v", err)
+	}
+
+	resp, err = s.makeRPCCall(ctx, "sui_getCheckpoint", []interface{}{sequence})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint %s: %v", sequence, err)
+	}
+	var result struct {
+		SequenceNumber string `json:"sequenceNumber"`
+		TimestampMs    string `json:"timestampMs"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %v", err)
+	}
+	number, err := strconv.ParseUint(result.SequenceNumber, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint sequence number %q", result.SequenceNumber)
+	}
+	timestampMs, err := strconv.ParseInt(result.TimestampMs, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint timestamp %q", result.TimestampMs)
+	}
+
+	return &models.Checkpoint{SequenceNumber: number, Timestamp: time.UnixMilli(timestampMs).UTC()}, nil
+}
+
 func (s *SuiClient) GetNetworkInfo(ctx context.Context) (map[string]interface{}, error) {
 	resp, err := s.makeRPCCall(ctx, "rpc.discover", []interface{}{})
 	if err != nil {
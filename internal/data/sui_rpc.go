@@ -0,0 +1,326 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"jollfi-gaming-api/internal/breaker"
+	"jollfi-gaming-api/internal/models"
+)
+
+// readOnlyMethods are safe to retry and fail over: repeating them cannot
+// submit a transaction twice.
+var readOnlyMethods = map[string]bool{
+	"sui_getObject":                         true,
+	"sui_getTransactionBlock":               true,
+	"sui_getLatestCheckpointSequenceNumber": true,
+	"sui_getCheckpoint":                     true,
+	"sui_dryRunTransactionBlock":            true,
+	"suix_getBalance":                       true,
+	"suix_getCoins":                         true,
+	"suix_getOwnedObjects":                  true,
+	"rpc.discover":                          true,
+}
+
+const (
+	// Score weights: a fully failing endpoint costs as much as one second of
+	// latency, and each checkpoint behind the highest one as much as 100ms.
+	errorRateWeight     = 1000
+	checkpointLagWeight = 100
+
+	// scoreSmoothing is the weight of the newest sample in the moving
+	// averages.
+	scoreSmoothing = 0.2
+)
+
+// RPCConfig controls timeouts, retries, circuit breakers and health scoring
+// for calls to the Sui nodes.
+type RPCConfig struct {
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// MaxRetries is how many times a failed read is retried. Reads are always
+	// offered to every available endpoint once.
+	MaxRetries int
+	// RetryBaseDelay and RetryMaxDelay bound the jittered exponential backoff
+	// between retries.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// MaxCheckpointLag is how many checkpoints an endpoint may trail the
+	// highest one seen before it is unhealthy; zero disables the check.
+	MaxCheckpointLag uint64
+	// Breaker configures one circuit breaker per endpoint.
+	Breaker breaker.Config
+}
+
+func DefaultRPCConfig() RPCConfig {
+	return RPCConfig{
+		Timeout:          10 * time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   200 * time.Millisecond,
+		RetryMaxDelay:    2 * time.Second,
+		MaxCheckpointLag: 20,
+		Breaker:          breaker.DefaultConfig(),
+	}
+}
+
+// rpcEndpoint is one Sui node with its breaker and running health figures.
+type rpcEndpoint struct {
+	url     string
+	breaker *breaker.Breaker
+
+	mu         sync.Mutex
+	latency    time.Duration
+	errorRate  float64
+	checkpoint uint64
+}
+
+func newRPCEndpoints(urls []string, cfg breaker.Config) []*rpcEndpoint {
+	endpoints := make([]*rpcEndpoint, 0, len(urls))
+	for _, u := range urls {
+		endpoints = append(endpoints, &rpcEndpoint{url: u, breaker: breaker.New(cfg)})
+	}
+	return endpoints
+}
+
+// record folds one call into the moving averages. Latency only counts
+// successful calls.
+func (e *rpcEndpoint) record(latency time.Duration, failed bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	sample := 0.0
+	if failed {
+		sample = 1
+	}
+	e.errorRate += scoreSmoothing * (sample - e.errorRate)
+	if failed {
+		return
+	}
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency += time.Duration(scoreSmoothing * float64(latency-e.latency))
+	}
+}
+
+func (e *rpcEndpoint) setCheckpoint(checkpoint uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.checkpoint = checkpoint
+}
+
+// ConfigureRPC replaces the RPC settings and resets the circuit breakers and
+// health scores. If urls are given they replace the endpoint list; the first
+// one is pinned for transactions until health checks say otherwise.
+func (s *SuiClient) ConfigureRPC(cfg RPCConfig, urls ...string) {
+	s.endpointsMu.Lock()
+	defer s.endpointsMu.Unlock()
+
+	if len(urls) == 0 {
+		for _, endpoint := range s.endpoints {
+			urls = append(urls, endpoint.url)
+		}
+	}
+	s.rpc = cfg
+	s.endpoints = newRPCEndpoints(urls, cfg.Breaker)
+	s.pinned = s.endpoints[0]
+}
+
+// RPCStatus reports every endpoint's breaker and health score, in the
+// configured order.
+func (s *SuiClient) RPCStatus() []models.RPCEndpointStatus {
+	return s.endpointStatuses()
+}
+
+// RefreshEndpoints asks every endpoint for its latest checkpoint, updates
+// their scores and re-pins transactions if the pinned endpoint became
+// unhealthy. Endpoints whose breaker is open are skipped until it lets a probe
+// through.
+func (s *SuiClient) RefreshEndpoints(ctx context.Context) {
+	s.endpointsMu.RLock()
+	endpoints := s.endpoints
+	s.endpointsMu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint *rpcEndpoint) {
+			defer wg.Done()
+			resp, err := s.callEndpoint(ctx, endpoint, "sui_getLatestCheckpointSequenceNumber", []interface{}{})
+			if err != nil {
+				return
+			}
+			var sequence string
+			if err := json.Unmarshal(resp.Result, &sequence); err != nil {
+				return
+			}
+			if checkpoint, err := strconv.ParseUint(sequence, 10, 64); err == nil {
+				endpoint.setCheckpoint(checkpoint)
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+	s.pinnedEndpoint()
+}
+
+// MonitorEndpoints refreshes endpoint health every interval until ctx is
+// cancelled.
+func (s *SuiClient) MonitorEndpoints(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.RefreshEndpoints(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RefreshEndpoints(ctx)
+		}
+	}
+}
+
+// pinnedEndpoint returns the endpoint transactions are sent to. It stays on
+// the same endpoint while that one is healthy, and otherwise moves to the
+// best scoring healthy one. The statuses are read under the same lock that
+// moves the pin, so the choice cannot be made on a list ConfigureRPC replaced
+// or undone by a concurrent re-pin.
+func (s *SuiClient) pinnedEndpoint() *rpcEndpoint {
+	s.endpointsMu.Lock()
+	defer s.endpointsMu.Unlock()
+
+	statuses := s.endpointStatusesLocked()
+
+	best := -1
+	for i, status := range statuses {
+		if s.endpoints[i] == s.pinned && status.Healthy {
+			return s.pinned
+		}
+		if status.Healthy && (best < 0 || status.Score < statuses[best].Score) {
+			best = i
+		}
+	}
+	if best < 0 {
+		// Nothing better to move to
+		return s.pinned
+	}
+	if s.pinned != s.endpoints[best] {
+		log.Printf("⚠️  Sui RPC transactions re-pinned to %s", redactURL(s.endpoints[best].url))
+	}
+	s.pinned = s.endpoints[best]
+	return s.pinned
+}
+
+// rankedEndpoints returns the endpoints a read may try, healthy ones first
+// and then by score. Endpoints whose breaker is open are left out.
+func (s *SuiClient) rankedEndpoints() []*rpcEndpoint {
+	s.endpointsMu.RLock()
+	defer s.endpointsMu.RUnlock()
+
+	statuses := s.endpointStatusesLocked()
+
+	var order []int
+	for i, status := range statuses {
+		if status.Breaker != string(breaker.Open) {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := statuses[order[a]], statuses[order[b]]
+		if x.Healthy != y.Healthy {
+			return x.Healthy
+		}
+		return x.Score < y.Score
+	})
+	ranked := make([]*rpcEndpoint, len(order))
+	for i, index := range order {
+		ranked[i] = s.endpoints[index]
+	}
+	return ranked
+}
+
+func (s *SuiClient) endpointStatuses() []models.RPCEndpointStatus {
+	s.endpointsMu.RLock()
+	defer s.endpointsMu.RUnlock()
+	return s.endpointStatusesLocked()
+}
+
+// endpointStatusesLocked scores every endpoint. The caller holds endpointsMu.
+func (s *SuiClient) endpointStatusesLocked() []models.RPCEndpointStatus {
+	var highest uint64
+	for _, endpoint := range s.endpoints {
+		endpoint.mu.Lock()
+		if endpoint.checkpoint > highest {
+			highest = endpoint.checkpoint
+		}
+		endpoint.mu.Unlock()
+	}
+
+	statuses := make([]models.RPCEndpointStatus, len(s.endpoints))
+	for i, endpoint := range s.endpoints {
+		breakerStatus := endpoint.breaker.Status()
+		endpoint.mu.Lock()
+		status := models.RPCEndpointStatus{
+			URL:                 redactURL(endpoint.url),
+			Pinned:              endpoint == s.pinned,
+			LatencyMs:           float64(endpoint.latency) / float64(time.Millisecond),
+			ErrorRate:           endpoint.errorRate,
+			Checkpoint:          endpoint.checkpoint,
+			Breaker:             string(breakerStatus.State),
+			ConsecutiveFailures: breakerStatus.ConsecutiveFailures,
+			OpenedAt:            breakerStatus.OpenedAt,
+			LastError:           breakerStatus.LastError,
+		}
+		endpoint.mu.Unlock()
+
+		if status.Checkpoint > 0 {
+			status.CheckpointLag = highest - status.Checkpoint
+		}
+		status.Score = status.LatencyMs + errorRateWeight*status.ErrorRate + checkpointLagWeight*float64(status.CheckpointLag)
+		status.Healthy = breakerStatus.State == breaker.Closed &&
+			(s.rpc.MaxCheckpointLag == 0 || status.CheckpointLag <= s.rpc.MaxCheckpointLag)
+		statuses[i] = status
+	}
+	return statuses
+}
+
+// retryDelay returns a random delay up to base*2^(attempt-1), capped at the
+// maximum ("full jitter"), so replicas retrying together spread out.
+func (c RPCConfig) retryDelay(attempt int) time.Duration {
+	if c.RetryBaseDelay <= 0 {
+		return 0
+	}
+	delay := c.RetryBaseDelay << (attempt - 1)
+	if c.RetryMaxDelay > 0 && (delay > c.RetryMaxDelay || delay <= 0) {
+		delay = c.RetryMaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// redactURL keeps only the scheme and host, since provider URLs often carry
+// API keys in the path or query.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "redacted"
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,48 @@
+package request
+
+type AdminCleanupRequest struct {
+	OlderThanDays int `json:"older_than_days" binding:"required,min=1"`
+}
+
+type AdminAuditQuery struct {
+	Action string `form:"action"`
+	Actor  string `form:"actor"`
+	Limit  int    `form:"limit"`
+}
+
+type KillSwitchRequest struct {
+	Paused *bool  `json:"paused" binding:"required"`
+	Reason string `json:"reason"` // required when pausing
+}
+
+type BlocklistEntryRequest struct {
+	Address string `json:"address" binding:"required"`
+	Reason  string `json:"reason"`
+}
+
+type BlocklistQuery struct {
+	Limit int `form:"limit"`
+}
+
+type HeldPayoutQuery struct {
+	Status string `form:"status"`
+	Limit  int    `form:"limit"`
+}
+
+type PendingPayoutQuery struct {
+	Status string `form:"status"`
+	Limit  int    `form:"limit"`
+}
+
+type ReviewPayoutRequest struct {
+	Note string `json:"note"` // required when rejecting
+}
+
+type RefundGameRequest struct {
+	Reason string `json:"reason"` // defaults to abandoned
+}
+
+type TimeoutSettlementQuery struct {
+	GameID string `form:"game_id"`
+	Limit  int    `form:"limit"`
+}
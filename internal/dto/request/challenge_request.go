@@ -0,0 +1,22 @@
+package request
+
+// The player fields below are set by the route from the player's token, never
+// from the body.
+
+type CreateChallengeRequest struct {
+	RequesterAddress string `json:"-"`
+	RequesterCoinID  string `json:"requester_coin_id" binding:"required"`
+	StakeAmount      uint64 `json:"stake_amount" binding:"required,min=1"`
+	GameType         string `json:"game_type,omitempty"`
+	AccepterAddress  string `json:"accepter_address,omitempty"` // optional target opponent
+	ExpiresIn        int64  `json:"expires_in,omitempty"`       // seconds
+}
+
+type AcceptChallengeRequest struct {
+	AccepterAddress string `json:"-"`
+	AccepterCoinID  string `json:"accepter_coin_id" binding:"required"`
+}
+
+type CancelChallengeRequest struct {
+	RequesterAddress string `json:"-"`
+}
@@ -0,0 +1,10 @@
+package request
+
+// GameTypeRequest sets the rules of a game type. The name comes from the path.
+type GameTypeRequest struct {
+	ScoreDirection string `json:"score_direction,omitempty"`
+	TieHandling    string `json:"tie_handling,omitempty"`
+	MinStake       uint64 `json:"min_stake,omitempty"`
+	MaxStake       uint64 `json:"max_stake,omitempty"`
+	MaxScore       uint64 `json:"max_score,omitempty"`
+}
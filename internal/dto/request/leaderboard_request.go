@@ -0,0 +1,11 @@
+package request
+
+type LeaderboardQuery struct {
+	Metric  string `form:"metric"`
+	Period  string `form:"period"`
+	Page    int    `form:"page"`
+	Limit   int    `form:"limit"`
+	Address string `form:"address"`
+	// GameType ranks games of one type only.
+	GameType string `form:"game_type"`
+}
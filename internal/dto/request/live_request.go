@@ -0,0 +1,8 @@
+package request
+
+// MatchScoreUpdate is the frame the game server sends on a live match channel.
+type MatchScoreUpdate struct {
+	Type           string `json:"type"`
+	RequesterScore uint64 `json:"requester_score"`
+	AccepterScore  uint64 `json:"accepter_score"`
+}
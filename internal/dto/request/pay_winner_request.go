@@ -1,11 +1,26 @@
 package request
 
 type PayWinnerRequest struct {
-	RequesterAddress string `bson:"requester_address"`
-	AccepterAddress  string `bson:"accepter_address"`
-	RequesterScore   uint64 `bson:"requester_score"`
-	AccepterScore    uint64 `bson:"accepter_score"`
-	StakeAmount      uint64 `bson:"stake_amount"`
-	Timestamp        int64  `bson:"timestamp"`
-	TransactionHash  string `bson:"transaction_hash,omitempty"`
+	GameID           string `json:"game_id,omitempty" bson:"game_id,omitempty"`
+	RequesterAddress string `json:"requester_address" bson:"requester_address"`
+	AccepterAddress  string `json:"accepter_address" bson:"accepter_address"`
+	RequesterScore   uint64 `json:"requester_score" bson:"requester_score"`
+	AccepterScore    uint64 `json:"accepter_score" bson:"accepter_score"`
+	StakeAmount      uint64 `json:"stake_amount" bson:"stake_amount"`
+	Timestamp        int64  `json:"timestamp,omitempty" bson:"timestamp"`
+	// GameType must match the type the game was staked under, if given.
+	GameType        string `json:"game_type,omitempty" bson:"game_type,omitempty"`
+	TransactionHash string `json:"transaction_hash,omitempty" bson:"transaction_hash,omitempty"`
+	// GameServer identifies the caller reporting the result. It is set by the
+	// route from the caller's credentials, never from the body.
+	GameServer string `json:"-" bson:"game_server,omitempty"`
+}
+
+// ScoreReportRequest is a player's own score for a staked game, used to
+// settle it if the game server never reports the result.
+type ScoreReportRequest struct {
+	Score uint64 `json:"score"`
+	// Address is the reporting player. It is set by the route from the
+	// player's token, never from the body.
+	Address string `json:"-"`
 }
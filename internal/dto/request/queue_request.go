@@ -0,0 +1,15 @@
+package request
+
+// Address is set by the route from the player's token, never from the body.
+type JoinQueueRequest struct {
+	Address      string  `json:"-"`
+	CoinID       string  `json:"coin_id" binding:"required"`
+	StakeAmount  uint64  `json:"stake_amount" binding:"required,min=1"`
+	GameType     string  `json:"game_type,omitempty"`     // only tickets of the same type are paired
+	RatingWindow float64 `json:"rating_window,omitempty"` // optional initial rating distance
+}
+
+// Address is set by the route from the player's token, never from the body.
+type LeaveQueueRequest struct {
+	Address string `json:"-"`
+}
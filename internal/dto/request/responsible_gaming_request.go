@@ -0,0 +1,16 @@
+package request
+
+// SetStakeLimitsRequest changes a player's own limits. Omitted fields keep
+// their current value; zero removes a limit.
+type SetStakeLimitsRequest struct {
+	MaxStake *uint64 `json:"max_stake"`
+	Daily    *uint64 `json:"daily"`
+	Weekly   *uint64 `json:"weekly"`
+	Monthly  *uint64 `json:"monthly"`
+	NetLoss  *uint64 `json:"net_loss"`
+}
+
+// BreakRequest starts a cooling-off period or a self-exclusion.
+type BreakRequest struct {
+	Days int `json:"days" binding:"required,min=1"`
+}
@@ -6,4 +6,5 @@ type StakeRequest struct {
 	RequesterAddress string `json:"requester_address" binding:"required"`
 	AccepterAddress  string `json:"accepter_address" binding:"required"`
 	StakeAmount      uint64 `json:"stake_amount" binding:"required,min=1"`
+	GameType         string `json:"game_type,omitempty"`
 }
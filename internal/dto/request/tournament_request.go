@@ -0,0 +1,27 @@
+package request
+
+type CreateTournamentRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Format     string `json:"format" binding:"required"` // single_elimination or round_robin
+	EntryStake uint64 `json:"entry_stake" binding:"required,min=1"`
+	Capacity   int    `json:"capacity" binding:"required,min=2"`
+	GameType   string `json:"game_type,omitempty"`
+}
+
+type TournamentQuery struct {
+	Status string `form:"status"`
+	Limit  int    `form:"limit"`
+}
+
+// TournamentEntryRequest registers a player, or stages the coin for their
+// next match. Address is set by the route from the player's token, never from
+// the body.
+type TournamentEntryRequest struct {
+	Address string `json:"-"`
+	CoinID  string `json:"coin_id" binding:"required"`
+}
+
+type TournamentResultRequest struct {
+	RequesterScore uint64 `json:"requester_score"`
+	AccepterScore  uint64 `json:"accepter_score"`
+}
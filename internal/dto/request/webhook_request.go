@@ -0,0 +1,14 @@
+package request
+
+type RegisterWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events,omitempty"` // empty subscribes to every event
+	Secret      string   `json:"secret,omitempty"` // generated when empty
+	Description string   `json:"description,omitempty"`
+}
+
+type WebhookDeliveryQuery struct {
+	Status     string `form:"status"`
+	EndpointID string `form:"endpoint_id"`
+	Limit      int    `form:"limit"`
+}
@@ -0,0 +1,122 @@
+package response
+
+import (
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/models"
+)
+
+type AdminBalanceResponse struct {
+	Success  bool   `json:"success"`
+	CoinType string `json:"coin_type"`
+	Balance  uint64 `json:"balance"`
+	Error    string `json:"error,omitempty"`
+}
+
+type AdminCoinsResponse struct {
+	Success  bool                     `json:"success"`
+	CoinType string                   `json:"coin_type"`
+	Coins    []map[string]interface{} `json:"coins"`
+	Count    int                      `json:"count"`
+	Error    string                   `json:"error,omitempty"`
+}
+
+type AdminPoolResponse struct {
+	Success bool                   `json:"success"`
+	PoolID  string                 `json:"pool_id"`
+	Pool    map[string]interface{} `json:"pool,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+type AdminTransactionsResponse struct {
+	Success      bool               `json:"success"`
+	Transactions []data.Transaction `json:"transactions"`
+	Count        int                `json:"count"`
+	Error        string             `json:"error,omitempty"`
+}
+
+type AdminCollectionStatsResponse struct {
+	Success     bool                   `json:"success"`
+	Collections map[string]interface{} `json:"collections"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+type AdminCleanupResponse struct {
+	Success       bool   `json:"success"`
+	OlderThanDays int    `json:"older_than_days"`
+	Deleted       int64  `json:"deleted"`
+	Error         string `json:"error,omitempty"`
+}
+
+type AdminAuditLogResponse struct {
+	Success bool                     `json:"success"`
+	Entries []models.AdminAuditEntry `json:"entries"`
+	Count   int                      `json:"count"`
+	Error   string                   `json:"error,omitempty"`
+}
+
+type KillSwitchResponse struct {
+	Success    bool               `json:"success"`
+	KillSwitch *models.KillSwitch `json:"kill_switch,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
+
+type BlocklistEntryResponse struct {
+	Success bool                   `json:"success"`
+	Entry   *models.BlocklistEntry `json:"entry,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+type BlocklistResponse struct {
+	Success bool                    `json:"success"`
+	Entries []models.BlocklistEntry `json:"entries"`
+	Count   int                     `json:"count"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+// BlocklistImportResponse reports a CSV import. Invalid lists the addresses
+// that were skipped and why.
+type BlocklistImportResponse struct {
+	Success  bool     `json:"success"`
+	Imported int      `json:"imported"`
+	Invalid  []string `json:"invalid,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+type HeldPayoutResponse struct {
+	Success bool               `json:"success"`
+	Payout  *models.HeldPayout `json:"payout,omitempty"`
+	Error   string             `json:"error,omitempty"`
+}
+
+type RefundGameResponse struct {
+	Success bool       `json:"success"`
+	Game    *data.Game `json:"game,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+type TimeoutSettlementListResponse struct {
+	Success     bool                       `json:"success"`
+	Settlements []models.TimeoutSettlement `json:"settlements"`
+	Count       int                        `json:"count"`
+	Error       string                     `json:"error,omitempty"`
+}
+
+type HeldPayoutListResponse struct {
+	Success bool                `json:"success"`
+	Payouts []models.HeldPayout `json:"payouts"`
+	Count   int                 `json:"count"`
+	Error   string              `json:"error,omitempty"`
+}
+
+type PendingPayoutResponse struct {
+	Success bool                  `json:"success"`
+	Payout  *models.PendingPayout `json:"payout,omitempty"`
+	Error   string                `json:"error,omitempty"`
+}
+
+type PendingPayoutListResponse struct {
+	Success bool                   `json:"success"`
+	Payouts []models.PendingPayout `json:"payouts"`
+	Count   int                    `json:"count"`
+	Error   string                 `json:"error,omitempty"`
+}
@@ -0,0 +1,16 @@
+package response
+
+import "jollfi-gaming-api/internal/models"
+
+type ChallengeResponse struct {
+	Success   bool              `json:"success"`
+	Challenge *models.Challenge `json:"challenge,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+type ChallengeListResponse struct {
+	Success    bool               `json:"success"`
+	Challenges []models.Challenge `json:"challenges"`
+	Count      int                `json:"count"`
+	Error      string             `json:"error,omitempty"`
+}
@@ -0,0 +1,16 @@
+package response
+
+import "jollfi-gaming-api/internal/models"
+
+type GameTypeResponse struct {
+	Success  bool             `json:"success"`
+	GameType *models.GameType `json:"game_type,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+type GameTypeListResponse struct {
+	Success   bool              `json:"success"`
+	GameTypes []models.GameType `json:"game_types"`
+	Count     int               `json:"count"`
+	Error     string            `json:"error,omitempty"`
+}
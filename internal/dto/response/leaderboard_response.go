@@ -0,0 +1,17 @@
+package response
+
+import "jollfi-gaming-api/internal/models"
+
+type LeaderboardResponse struct {
+	Success   bool                      `json:"success"`
+	Metric    string                    `json:"metric,omitempty"`
+	Period    string                    `json:"period,omitempty"`
+	PeriodKey string                    `json:"period_key,omitempty"`
+	GameType  string                    `json:"game_type,omitempty"`
+	Page      int                       `json:"page,omitempty"`
+	Limit     int                       `json:"limit,omitempty"`
+	Total     int64                     `json:"total"`
+	Entries   []models.LeaderboardEntry `json:"entries"`
+	Player    *models.LeaderboardEntry  `json:"player,omitempty"`
+	Error     string                    `json:"error,omitempty"`
+}
@@ -1,12 +1,25 @@
 package response
 
-import "jollfi-gaming-api/internal/models"
+import (
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/models"
+)
 
 type PayWinnerResponse struct {
-	Success           bool   `json:"success"`
-	TransactionDigest string `json:"transaction_digest,omitempty"`
-	Message           string `json:"message,omitempty"`
-	Error             string `json:"error,omitempty"`
+	Success           bool     `json:"success"`
+	TransactionDigest string   `json:"transaction_digest,omitempty"`
+	Message           string   `json:"message,omitempty"`
+	Status            string   `json:"status,omitempty"`
+	HeldPayoutID      string   `json:"held_payout_id,omitempty"`
+	PendingPayoutID   string   `json:"pending_payout_id,omitempty"`
+	Violations        []string `json:"violations,omitempty"`
+	Error             string   `json:"error,omitempty"`
+}
+
+type ScoreReportResponse struct {
+	Success bool       `json:"success"`
+	Game    *data.Game `json:"game,omitempty"`
+	Error   string     `json:"error,omitempty"`
 }
 
 type GameHistoryResponse struct {
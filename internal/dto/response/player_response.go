@@ -0,0 +1,17 @@
+package response
+
+import "jollfi-gaming-api/internal/models"
+
+type PlayerProfileResponse struct {
+	Success bool                  `json:"success"`
+	Profile *models.PlayerProfile `json:"profile,omitempty"`
+	Error   string                `json:"error,omitempty"`
+}
+
+type PlayerRatingResponse struct {
+	Success bool                        `json:"success"`
+	Address string                      `json:"address,omitempty"`
+	Rating  *models.PlayerRating        `json:"rating,omitempty"`
+	History []models.RatingHistoryEntry `json:"history,omitempty"`
+	Error   string                      `json:"error,omitempty"`
+}
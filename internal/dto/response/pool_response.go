@@ -0,0 +1,9 @@
+package response
+
+import "jollfi-gaming-api/internal/models"
+
+type PoolResponse struct {
+	Success bool              `json:"success"`
+	Pool    *models.StakePool `json:"pool,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
@@ -0,0 +1,9 @@
+package response
+
+import "jollfi-gaming-api/internal/models"
+
+type QueueTicketResponse struct {
+	Success bool                `json:"success"`
+	Ticket  *models.QueueTicket `json:"ticket,omitempty"`
+	Error   string              `json:"error,omitempty"`
+}
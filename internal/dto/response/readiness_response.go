@@ -0,0 +1,18 @@
+package response
+
+import "time"
+
+// DependencyStatus is the outcome of one readiness check.
+type DependencyStatus struct {
+	Status    string                 `json:"status"`
+	LatencyMs float64                `json:"latency_ms"`
+	Error     string                 `json:"error,omitempty"`
+	Detail    map[string]interface{} `json:"detail,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Success   bool                        `json:"success"`
+	Status    string                      `json:"status"`
+	Checks    map[string]DependencyStatus `json:"checks"`
+	CheckedAt time.Time                   `json:"checked_at"`
+}
@@ -0,0 +1,14 @@
+package response
+
+import "jollfi-gaming-api/internal/models"
+
+// PlayerLimitsResponse shows a player's own controls, the limits actually
+// enforced (the tighter of theirs and the operator's) and their usage.
+type PlayerLimitsResponse struct {
+	Success   bool                      `json:"success"`
+	Address   string                    `json:"address,omitempty"`
+	Controls  *models.ResponsibleGaming `json:"controls,omitempty"`
+	Effective *models.StakeLimits       `json:"effective,omitempty"`
+	Usage     *models.StakeUsage        `json:"usage,omitempty"`
+	Error     string                    `json:"error,omitempty"`
+}
@@ -4,9 +4,12 @@ import "jollfi-gaming-api/internal/models"
 
 type StakeResponse struct {
 	Success           bool   `json:"success"`
+	GameID            string `json:"game_id,omitempty"`
 	TransactionDigest string `json:"transaction_digest,omitempty"`
 	Message           string `json:"message,omitempty"`
 	Error             string `json:"error,omitempty"`
+	// Code says which responsible gaming control refused the stake.
+	Code string `json:"code,omitempty"`
 }
 
 type StakeHistoryResponse struct {
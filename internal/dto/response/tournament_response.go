@@ -0,0 +1,20 @@
+package response
+
+import "jollfi-gaming-api/internal/models"
+
+type TournamentResponse struct {
+	Success    bool                        `json:"success"`
+	Tournament *models.Tournament          `json:"tournament,omitempty"`
+	Players    []models.TournamentEntry    `json:"players,omitempty"`
+	Matches    []models.TournamentMatch    `json:"matches,omitempty"`
+	Standings  []models.TournamentStanding `json:"standings,omitempty"`
+	PrizePool  uint64                      `json:"prize_pool,omitempty"` // total staked across matches so far
+	Error      string                      `json:"error,omitempty"`
+}
+
+type TournamentListResponse struct {
+	Success     bool                `json:"success"`
+	Tournaments []models.Tournament `json:"tournaments"`
+	Count       int                 `json:"count"`
+	Error       string              `json:"error,omitempty"`
+}
@@ -0,0 +1,29 @@
+package response
+
+import "jollfi-gaming-api/internal/models"
+
+type WebhookResponse struct {
+	Success bool                    `json:"success"`
+	Webhook *models.WebhookEndpoint `json:"webhook,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+type WebhookListResponse struct {
+	Success  bool                     `json:"success"`
+	Webhooks []models.WebhookEndpoint `json:"webhooks"`
+	Count    int                      `json:"count"`
+	Error    string                   `json:"error,omitempty"`
+}
+
+type WebhookDeliveryResponse struct {
+	Success  bool                    `json:"success"`
+	Delivery *models.WebhookDelivery `json:"delivery,omitempty"`
+	Error    string                  `json:"error,omitempty"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Success    bool                     `json:"success"`
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+	Count      int                      `json:"count"`
+	Error      string                   `json:"error,omitempty"`
+}
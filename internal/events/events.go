@@ -0,0 +1,80 @@
+// Package events carries domain events out of the service layer to the
+// subsystems that react to them, such as webhook delivery. Publishing is
+// synchronous, so handlers must not block.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StakeCompleted       = "stake.completed"
+	GameSettled          = "game.settled"
+	GameRefunded         = "game.refunded"
+	TransactionConfirmed = "transaction.confirmed"
+	TransactionFailed    = "transaction.failed"
+)
+
+// Types lists every event type that can be published.
+var Types = []string{StakeCompleted, GameSettled, GameRefunded, TransactionConfirmed, TransactionFailed}
+
+// Known reports whether eventType is one of Types.
+func Known(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a single occurrence, serialised as-is into webhook bodies.
+type Event struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	GameID    string                 `json:"game_id,omitempty"`
+	Addresses []string               `json:"addresses,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// Handler receives published events.
+type Handler func(Event)
+
+// Bus fans events out to every subscribed handler.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish stamps the event with an ID and time when missing and hands it to
+// every handler in subscription order.
+func (b *Bus) Publish(event Event) {
+	if event.ID == "" {
+		event.ID = primitive.NewObjectID().Hex()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.handlers...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -17,8 +17,9 @@ type MongoCollectionInterface interface {
 	InsertOne(ctx context.Context, document interface{}) (*mongo.InsertOneResult, error)
 	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (MongoCursorInterface, error)
 	FindOne(ctx context.Context, filter interface{}) interface{}
-	UpdateOne(ctx context.Context, filter, update interface{}) (*mongo.UpdateResult, error)
+	UpdateOne(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error)
+	CountDocuments(ctx context.Context, filter interface{}) (int64, error)
 }
 
 type MongoDatabaseInterface interface {
@@ -33,9 +34,16 @@ type MongoClientInterface interface {
 	CreateGame(ctx context.Context, game interface{}) (string, error)
 	GetGame(ctx context.Context, gameID string) (interface{}, error)
 	UpdateGame(ctx context.Context, gameID string, updates interface{}) error
+	TransitionGame(ctx context.Context, gameID, fromStatus string, updates interface{}) (bool, error)
 	GetGamesByStatus(ctx context.Context, status string) ([]interface{}, error)
 	GetGamesByAddress(ctx context.Context, address string) ([]interface{}, error)
 
 	CreateTransaction(ctx context.Context, transaction interface{}) (string, error)
 	GetTransactionsByGameID(ctx context.Context, gameID string) ([]interface{}, error)
+	UpdateTransactionStatus(ctx context.Context, txDigest string, status string, blockHeight *uint64) error
+	GetPendingTransactions(ctx context.Context) ([]interface{}, error)
+
+	GetUserStats(ctx context.Context, address string) (map[string]interface{}, error)
+	GetCollectionStats(ctx context.Context) (map[string]interface{}, error)
+	CleanupOldGames(ctx context.Context, olderThanDays int) (int64, error)
 }
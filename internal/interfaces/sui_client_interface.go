@@ -1,13 +1,24 @@
 package interfaces
 
-import "context"
+import (
+	"context"
+
+	"jollfi-gaming-api/internal/models"
+)
 
 type SuiClientInterface interface {
 	ExternalStake(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error)
 	ExternalPayWinner(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error)
+	ExternalRefund(requesterAddress, accepterAddress string, stakeAmount uint64, ctx context.Context) (string, error)
 	ExecuteTransactionBlock(ctx context.Context, txBytes []byte) (string, error)
 	GetTransactionBlock(ctx context.Context, digest string) (interface{}, error)
 	BuildTransactionBlock(ctx context.Context, params interface{}) ([]byte, error)
 	GetBalance(ctx context.Context) (uint64, error)
 	GetCoins(ctx context.Context, coinType string) ([]map[string]interface{}, error)
+	GetAllCoins(ctx context.Context, coinType string) ([]map[string]interface{}, error)
+	GetTotalBalance(ctx context.Context, coinType string) (uint64, error)
+	GetStakePool(poolID string) (map[string]interface{}, error)
+	GetPool(ctx context.Context) (*models.StakePool, error)
+	LatestCheckpoint(ctx context.Context) (*models.Checkpoint, error)
+	RPCStatus() []models.RPCEndpointStatus
 }
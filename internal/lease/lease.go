@@ -0,0 +1,249 @@
+// Package lease coordinates replicas through time-limited leases stored in
+// Mongo. Every acquisition increments the lease's fencing token, so a holder
+// that stalled past its expiry can tell, before acting, that someone else has
+// held the lease since: its token no longer matches.
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/interfaces"
+)
+
+// Collection is where leases are kept, one document per lease name.
+const Collection = "leases"
+
+var (
+	// ErrHeld means another holder has an unexpired lease.
+	ErrHeld = errors.New("lease is held by another replica")
+	// ErrLost means the lease was taken over since it was granted.
+	ErrLost = errors.New("lease was lost to another replica")
+)
+
+type Config struct {
+	// TTL is how long a grant lasts unless renewed.
+	TTL time.Duration
+	// RetryInterval is how often Acquire retries while the lease is held.
+	RetryInterval time.Duration
+	// Now replaces the wall clock, for tests.
+	Now func() time.Time
+}
+
+func DefaultConfig() Config {
+	return Config{TTL: 30 * time.Second, RetryInterval: 100 * time.Millisecond}
+}
+
+// Grant is one holder's hold on a lease. Token identifies it: it is larger
+// than the token of every earlier grant of the same lease.
+type Grant struct {
+	Name      string    `bson:"_id" json:"name"`
+	Holder    string    `bson:"holder" json:"holder"`
+	Token     int64     `bson:"token" json:"token"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// Lease is a named lease as seen by one holder.
+type Lease struct {
+	collection interfaces.MongoCollectionInterface
+	name       string
+	holder     string
+	config     Config
+}
+
+func New(collection interfaces.MongoCollectionInterface, name, holder string, config Config) *Lease {
+	if config.TTL <= 0 {
+		config.TTL = DefaultConfig().TTL
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = DefaultConfig().RetryInterval
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &Lease{collection: collection, name: name, holder: holder, config: config}
+}
+
+func (l *Lease) Name() string {
+	return l.name
+}
+
+// TryAcquire takes the lease if it is free or expired, and returns ErrHeld
+// otherwise. The conditional upsert is atomic: when two replicas race for a
+// missing lease, the second insert fails on the duplicate _id.
+func (l *Lease) TryAcquire(ctx context.Context) (*Grant, error) {
+	now := l.config.Now()
+	expiresAt := l.expiry(now)
+	_, err := l.collection.UpdateOne(ctx,
+		bson.M{"_id": l.name, "expires_at": bson.M{"$lte": now}},
+		bson.M{
+			"$set": bson.M{"holder": l.holder, "expires_at": expiresAt, "acquired_at": now},
+			"$inc": bson.M{"token": 1},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrHeld
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lease %s: %v", l.name, err)
+	}
+
+	grant, err := l.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if grant == nil || grant.Holder != l.holder || !grant.ExpiresAt.Equal(expiresAt) {
+		// Expired and taken over between the update and the read
+		return nil, ErrHeld
+	}
+	return grant, nil
+}
+
+// Acquire waits for the lease until ctx is done.
+func (l *Lease) Acquire(ctx context.Context) (*Grant, error) {
+	for {
+		grant, err := l.TryAcquire(ctx)
+		if err != ErrHeld {
+			return grant, err
+		}
+		timer := time.NewTimer(l.config.RetryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("timed out waiting for lease %s: %w", l.name, ErrHeld)
+		case <-timer.C:
+		}
+	}
+}
+
+// Renew extends grant by another TTL. It fails with ErrLost if the lease has
+// been granted to anyone since, which also makes it the fencing check: a
+// holder renews right before acting on the lease.
+func (l *Lease) Renew(ctx context.Context, grant *Grant) error {
+	expiresAt := l.expiry(l.config.Now())
+	result, err := l.collection.UpdateOne(ctx,
+		bson.M{"_id": l.name, "holder": l.holder, "token": grant.Token},
+		bson.M{"$set": bson.M{"expires_at": expiresAt}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to renew lease %s: %v", l.name, err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrLost
+	}
+	grant.ExpiresAt = expiresAt
+	return nil
+}
+
+// Release gives the lease up early. Releasing a lost grant does nothing.
+func (l *Lease) Release(ctx context.Context, grant *Grant) error {
+	_, err := l.collection.UpdateOne(ctx,
+		bson.M{"_id": l.name, "holder": l.holder, "token": grant.Token},
+		bson.M{"$set": bson.M{"expires_at": l.config.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release lease %s: %v", l.name, err)
+	}
+	return nil
+}
+
+// Current returns the latest grant of the lease, or nil if it was never
+// taken.
+func (l *Lease) Current(ctx context.Context) (*Grant, error) {
+	cursor, err := l.collection.Find(ctx, bson.M{"_id": l.name}, options.Find().SetLimit(1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lease %s: %v", l.name, err)
+	}
+	defer cursor.Close(ctx)
+	if !cursor.Next(ctx) {
+		return nil, nil
+	}
+	var grant Grant
+	if err := cursor.Decode(&grant); err != nil {
+		return nil, fmt.Errorf("failed to decode lease %s: %v", l.name, err)
+	}
+	return &grant, nil
+}
+
+// expiry is rounded to what Mongo stores, so a grant read back compares equal.
+func (l *Lease) expiry(now time.Time) time.Time {
+	return now.Add(l.config.TTL).Truncate(time.Millisecond)
+}
+
+// Leader keeps one holder in charge of a periodic job across replicas. The
+// leader renews its grant on every Hold; the others take the lease over once
+// it expires.
+type Leader struct {
+	lease *Lease
+	mu    sync.Mutex
+	grant *Grant
+}
+
+func NewLeader(lease *Lease) *Leader {
+	return &Leader{lease: lease}
+}
+
+// Hold reports whether this holder leads, renewing its grant or taking the
+// lease if it is free. A leader calls it again right before each action, so
+// it stops as soon as it loses the lease.
+func (l *Leader) Hold(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.grant != nil {
+		err := l.lease.Renew(ctx, l.grant)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, ErrLost) {
+			return false, err
+		}
+		l.grant = nil
+	}
+	grant, err := l.lease.TryAcquire(ctx)
+	if errors.Is(err, ErrHeld) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	l.grant = grant
+	return true, nil
+}
+
+// Resign gives the lease up, if held, so another holder can lead straight
+// away.
+func (l *Leader) Resign(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.grant == nil {
+		return nil
+	}
+	grant := l.grant
+	l.grant = nil
+	return l.lease.Release(ctx, grant)
+}
+
+type fenceKey struct{}
+
+// WithFence attaches a check that must pass right before an irreversible
+// action is taken with ctx, typically renewing the lease the action is
+// performed under.
+func WithFence(ctx context.Context, check func(ctx context.Context) error) context.Context {
+	return context.WithValue(ctx, fenceKey{}, check)
+}
+
+// CheckFence runs the check attached by WithFence, if any.
+func CheckFence(ctx context.Context) error {
+	check, ok := ctx.Value(fenceKey{}).(func(ctx context.Context) error)
+	if !ok {
+		return nil
+	}
+	return check(ctx)
+}
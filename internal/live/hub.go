@@ -0,0 +1,211 @@
+// Package live runs one broadcast room per game for players, the game server
+// and spectators watching a match. It is transport agnostic: clients read
+// messages from a channel and the WebSocket plumbing lives in the routes.
+package live
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"jollfi-gaming-api/internal/events"
+)
+
+const (
+	RolePlayer     = "player"
+	RoleGameServer = "game_server"
+	RoleSpectator  = "spectator"
+
+	MessageState    = "state"
+	MessageScore    = "score"
+	MessagePresence = "presence"
+	MessageError    = "error"
+
+	// clientBuffer is how many messages may queue for a client before it is
+	// considered too slow and disconnected.
+	clientBuffer = 32
+)
+
+var ErrRoomFull = errors.New("match room is full")
+
+// Message is one frame sent to the clients in a room. Event types from the
+// events package are forwarded under their own names.
+type Message struct {
+	Type      string                 `json:"type"`
+	GameID    string                 `json:"game_id"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// Client is one connection in a room.
+type Client struct {
+	GameID  string
+	Role    string
+	Address string
+	// C delivers the room's messages.
+	C <-chan Message
+	// Done is closed when the client leaves or is disconnected for falling
+	// behind.
+	Done <-chan struct{}
+
+	hub  *Hub
+	ch   chan Message
+	done chan struct{}
+	once sync.Once
+}
+
+// Close removes the client from its room. It is safe to call more than once.
+func (c *Client) Close() {
+	c.hub.leave(c)
+}
+
+// Send queues a message for this client only.
+func (c *Client) Send(msg Message) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	c.hub.deliver(c, msg)
+}
+
+type room struct {
+	clients map[*Client]struct{}
+	score   map[string]interface{}
+}
+
+// Hub holds the open rooms, keyed by game ID.
+type Hub struct {
+	mu         sync.Mutex
+	rooms      map[string]*room
+	maxPerRoom int
+}
+
+// NewHub returns a hub that admits at most maxPerRoom clients per game; zero
+// leaves rooms unbounded.
+func NewHub(maxPerRoom int) *Hub {
+	return &Hub{rooms: make(map[string]*room), maxPerRoom: maxPerRoom}
+}
+
+// Join adds a client to the game's room.
+func (h *Hub) Join(gameID, role, address string) (*Client, error) {
+	ch := make(chan Message, clientBuffer)
+	done := make(chan struct{})
+	client := &Client{GameID: gameID, Role: role, Address: address, C: ch, Done: done, hub: h, ch: ch, done: done}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.rooms[gameID]
+	if !ok {
+		r = &room{clients: make(map[*Client]struct{})}
+		h.rooms[gameID] = r
+	}
+	if h.maxPerRoom > 0 && len(r.clients) >= h.maxPerRoom {
+		return nil, ErrRoomFull
+	}
+	r.clients[client] = struct{}{}
+	h.broadcastPresence(gameID, r)
+	return client, nil
+}
+
+// Broadcast sends msg to every client in the game's room. Score messages are
+// also remembered so late joiners can be told the current score.
+func (h *Hub) Broadcast(msg Message) {
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.rooms[msg.GameID]
+	if !ok {
+		return
+	}
+	if msg.Type == MessageScore {
+		r.score = msg.Data
+	}
+	for client := range r.clients {
+		h.deliver(client, msg)
+	}
+}
+
+// Score returns the last score broadcast for the game, if any.
+func (h *Hub) Score(gameID string) map[string]interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r, ok := h.rooms[gameID]; ok {
+		return r.score
+	}
+	return nil
+}
+
+// Publish forwards an event to the room of the game it concerns.
+func (h *Hub) Publish(event events.Event) {
+	if event.GameID == "" {
+		return
+	}
+	h.Broadcast(Message{
+		Type:      event.Type,
+		GameID:    event.GameID,
+		Data:      event.Data,
+		Timestamp: event.CreatedAt,
+	})
+}
+
+// Connections returns the number of clients in the game's room.
+func (h *Hub) Connections(gameID string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r, ok := h.rooms[gameID]; ok {
+		return len(r.clients)
+	}
+	return 0
+}
+
+func (h *Hub) leave(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.disconnect(client)
+}
+
+// deliver must be called with h.mu held. A client whose queue is full is
+// disconnected rather than blocking the room.
+func (h *Hub) deliver(client *Client, msg Message) {
+	select {
+	case client.ch <- msg:
+	default:
+		h.disconnect(client)
+	}
+}
+
+// disconnect must be called with h.mu held.
+func (h *Hub) disconnect(client *Client) {
+	client.once.Do(func() { close(client.done) })
+	r, ok := h.rooms[client.GameID]
+	if !ok {
+		return
+	}
+	if _, member := r.clients[client]; !member {
+		return
+	}
+	delete(r.clients, client)
+	if len(r.clients) == 0 {
+		delete(h.rooms, client.GameID)
+		return
+	}
+	h.broadcastPresence(client.GameID, r)
+}
+
+// broadcastPresence must be called with h.mu held.
+func (h *Hub) broadcastPresence(gameID string, r *room) {
+	counts := map[string]interface{}{RolePlayer: 0, RoleGameServer: 0, RoleSpectator: 0}
+	for client := range r.clients {
+		counts[client.Role] = counts[client.Role].(int) + 1
+	}
+	msg := Message{Type: MessagePresence, GameID: gameID, Data: counts, Timestamp: time.Now()}
+	for client := range r.clients {
+		select {
+		case client.ch <- msg:
+		default:
+		}
+	}
+}
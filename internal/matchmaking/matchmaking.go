@@ -0,0 +1,117 @@
+// Package matchmaking pairs queued players of the same stake tier whose ratings
+// fall within each other's search window. Windows widen the longer a player
+// waits. The engine is pure; persistence and staking live in the service layer.
+package matchmaking
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"jollfi-gaming-api/internal/models"
+)
+
+// Clock abstracts time so the pairing loop can be driven by tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// Config controls how rating windows grow over wait time.
+type Config struct {
+	// InitialWindow is the rating distance accepted straight away when the
+	// ticket does not ask for its own window.
+	InitialWindow float64
+	// WidenBy is added to a window every WidenEvery of waiting.
+	WidenBy    float64
+	WidenEvery time.Duration
+	// MaxWindow caps widening; zero leaves it uncapped.
+	MaxWindow float64
+	// TicketTTL is how long a ticket may wait before it expires.
+	TicketTTL time.Duration
+}
+
+// DefaultConfig returns the settings used when none are configured.
+func DefaultConfig() Config {
+	return Config{
+		InitialWindow: 100,
+		WidenBy:       50,
+		WidenEvery:    10 * time.Second,
+		MaxWindow:     600,
+		TicketTTL:     10 * time.Minute,
+	}
+}
+
+// Match is a pair of tickets; First joined the queue earlier and becomes the
+// requester of the staked game.
+type Match struct {
+	First  models.QueueTicket
+	Second models.QueueTicket
+}
+
+// Window returns the rating distance the ticket accepts at now.
+func (c Config) Window(ticket models.QueueTicket, now time.Time) float64 {
+	window := ticket.RatingWindow
+	if window <= 0 {
+		window = c.InitialWindow
+	}
+	if c.WidenEvery > 0 && now.After(ticket.JoinedAt) {
+		steps := math.Floor(float64(now.Sub(ticket.JoinedAt)) / float64(c.WidenEvery))
+		window += steps * c.WidenBy
+	}
+	if c.MaxWindow > 0 && window > c.MaxWindow {
+		window = c.MaxWindow
+	}
+	return window
+}
+
+// Expired reports whether the ticket has waited longer than TicketTTL.
+func (c Config) Expired(ticket models.QueueTicket, now time.Time) bool {
+	return c.TicketTTL > 0 && now.Sub(ticket.JoinedAt) >= c.TicketTTL
+}
+
+// FindMatches pairs waiting tickets greedily, longest waiting first. Each
+// ticket is paired with the closest rated ticket of the same stake tier and
+// game type that both windows accept.
+func (c Config) FindMatches(tickets []models.QueueTicket, now time.Time) []Match {
+	queue := make([]models.QueueTicket, 0, len(tickets))
+	for _, ticket := range tickets {
+		if ticket.Status == models.QueueStatusWaiting && !c.Expired(ticket, now) {
+			queue = append(queue, ticket)
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].JoinedAt.Before(queue[j].JoinedAt)
+	})
+
+	matched := make([]bool, len(queue))
+	var matches []Match
+	for i := range queue {
+		if matched[i] {
+			continue
+		}
+		best := -1
+		bestDistance := math.Inf(1)
+		for j := i + 1; j < len(queue); j++ {
+			if matched[j] || queue[j].StakeAmount != queue[i].StakeAmount || queue[j].GameType != queue[i].GameType || queue[j].Address == queue[i].Address {
+				continue
+			}
+			distance := math.Abs(queue[i].Rating - queue[j].Rating)
+			if distance > c.Window(queue[i], now) || distance > c.Window(queue[j], now) {
+				continue
+			}
+			if distance < bestDistance {
+				best, bestDistance = j, distance
+			}
+		}
+		if best >= 0 {
+			matched[i], matched[best] = true, true
+			matches = append(matches, Match{First: queue[i], Second: queue[best]})
+		}
+	}
+	return matches
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	RolePlayer     = "player"
+	RoleGameServer = "game_server"
+
+	claimsKey = "claims"
+)
+
+// Claims identify the caller of a JWT-authenticated request. Subject is the
+// player's Sui address, or the game server's name.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// ParseToken verifies an HS256 token signed with secret and returns its
+// claims. Tokens must carry an expiry.
+func ParseToken(secret, token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// SignToken issues an HS256 token for claims.
+func SignToken(secret string, claims *Claims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
+// JWTMiddleware authenticates requests that present a bearer token, either in
+// the Authorization header or, for WebSocket clients that cannot set headers,
+// in the token query parameter. Requests without a token continue anonymously;
+// an invalid token is rejected.
+func JWTMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" {
+			token = c.Query("token")
+		}
+		if token == "" {
+			c.Next()
+			return
+		}
+		claims, err := ParseToken(secret, token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Invalid token",
+			})
+			c.Abort()
+			return
+		}
+		c.Set(claimsKey, claims)
+		c.Next()
+	}
+}
+
+// ClaimsFrom returns the claims JWTMiddleware attached to the request.
+func ClaimsFrom(c *gin.Context) (*Claims, bool) {
+	value, exists := c.Get(claimsKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*Claims)
+	return claims, ok
+}
+
+// ConnectionLimitMiddleware caps the long-lived connections open at once, in
+// total and per client IP. Excess requests get 503.
+func ConnectionLimitMiddleware(maxTotal, maxPerClient int) gin.HandlerFunc {
+	var (
+		mu      sync.Mutex
+		total   int
+		clients = make(map[string]int)
+	)
+	acquire := func(ip string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if (maxTotal > 0 && total >= maxTotal) || (maxPerClient > 0 && clients[ip] >= maxPerClient) {
+			return false
+		}
+		total++
+		clients[ip]++
+		return true
+	}
+	release := func(ip string) {
+		mu.Lock()
+		defer mu.Unlock()
+		total--
+		if clients[ip]--; clients[ip] <= 0 {
+			delete(clients, ip)
+		}
+	}
+
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		if !acquire(ip) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"success": false,
+				"error":   "Too many open connections",
+			})
+			c.Abort()
+			return
+		}
+		defer release(ip)
+		c.Next()
+	}
+}
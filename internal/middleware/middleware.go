@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"sync"
@@ -11,9 +12,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"golang.org/x/time/rate"
 
 	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/ratelimit"
 )
 
 func CORSMiddleware() gin.HandlerFunc {
@@ -75,15 +76,33 @@ func RecoveryMiddleware() gin.HandlerFunc {
 	})
 }
 
-func HealthCheckHandler() gin.HandlerFunc {
+// HealthCheck reports one dependency for the health endpoint: its name, the
+// detail to show and whether it is degraded.
+type HealthCheck func() (name string, detail interface{}, degraded bool)
+
+// HealthCheckHandler always answers 200 so orchestrator probes keep passing;
+// a degraded dependency only changes the reported status to "degraded".
+func HealthCheckHandler(checks ...HealthCheck) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
+		body := gin.H{
 			"success":   true,
 			"status":    "healthy",
 			"service":   "jollfi-gaming-api",
 			"timestamp": time.Now().Unix(),
 			"version":   "1.0.0",
-		})
+		}
+		if len(checks) > 0 {
+			dependencies := gin.H{}
+			for _, check := range checks {
+				name, detail, degraded := check()
+				dependencies[name] = detail
+				if degraded {
+					body["status"] = "degraded"
+				}
+			}
+			body["dependencies"] = dependencies
+		}
+		c.JSON(http.StatusOK, body)
 	}
 }
 
@@ -118,54 +137,61 @@ func APIKeyMiddleware(apiKey string) gin.HandlerFunc {
 	}
 }
 
-func RateLimitMiddleware(requestsPerMinute int) gin.HandlerFunc {
-	type client struct {
-		limiter  *rate.Limiter
-		lastSeen time.Time
-	}
-	var (
-		mu      sync.Mutex
-		clients = make(map[string]*client)
-	)
-	go func() {
-		for {
-			time.Sleep(time.Minute * 3)
-			mu.Lock()
-			for ip, client := range clients {
-				if time.Since(client.lastSeen) > time.Minute*3 {
-					delete(clients, ip)
-				}
-			}
-			mu.Unlock()
-		}
-	}()
+// adminIdentityKey holds the name of the named admin key a request
+// authenticated with.
+const adminIdentityKey = "admin_identity"
+
+// AdminAuthMiddleware guards the admin routes with their own key, sent in the
+// X-Admin-Key header. The key is either the shared adminKey or one of the
+// namedKeys, a map of admin name to key; a named key also identifies its
+// holder (see AdminIdentity). Without any configured key every admin request
+// is refused.
+func AdminAuthMiddleware(adminKey string, namedKeys map[string]string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if requestsPerMinute <= 0 {
-			c.Next()
+		if adminKey == "" && len(namedKeys) == 0 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Admin API is not enabled",
+			})
+			c.Abort()
 			return
 		}
-		ip := c.ClientIP()
-		mu.Lock()
-		if _, found := clients[ip]; !found {
-			clients[ip] = &client{
-				limiter: rate.NewLimiter(rate.Limit(requestsPerMinute)/60, requestsPerMinute),
+		providedKey := c.GetHeader("X-Admin-Key")
+		for name, key := range namedKeys {
+			if subtle.ConstantTimeCompare([]byte(providedKey), []byte(key)) == 1 {
+				c.Set(adminIdentityKey, name)
+				c.Next()
+				return
 			}
 		}
-		clients[ip].lastSeen = time.Now()
-		if !clients[ip].limiter.Allow() {
-			mu.Unlock()
-			c.JSON(http.StatusTooManyRequests, gin.H{
+		if adminKey == "" || subtle.ConstantTimeCompare([]byte(providedKey), []byte(adminKey)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
-				"error":   "Rate limit exceeded",
+				"error":   "Invalid admin key",
 			})
 			c.Abort()
 			return
 		}
-		mu.Unlock()
 		c.Next()
 	}
 }
 
+// AdminIdentity returns the admin a request authenticated as, if it used a
+// named key. Requests made with the shared key have no verified identity.
+func AdminIdentity(c *gin.Context) (string, bool) {
+	name := c.GetString(adminIdentityKey)
+	return name, name != ""
+}
+
+// RateLimitMiddleware limits each client IP to requestsPerMinute, counted in
+// this process only.
+func RateLimitMiddleware(requestsPerMinute int) gin.HandlerFunc {
+	policy := RateLimitPolicy{Name: "default", Limit: ratelimit.PerMinute(requestsPerMinute)}
+	return RateLimit(ratelimit.NewMemoryStore(), RateLimitKey("", "", RateLimitKeyIP), func(*gin.Context) (RateLimitPolicy, bool) {
+		return policy, true
+	})
+}
+
 func SecurityHeadersMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("X-Content-Type-Options", "nosniff")
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/ratelimit"
+)
+
+const (
+	RateLimitKeyAPIKey  = "api_key"
+	RateLimitKeyAddress = "address"
+	RateLimitKeyIP      = "ip"
+)
+
+// RateLimitPolicy is the budget for one group of routes. Budgets of different
+// policies are counted separately.
+type RateLimitPolicy struct {
+	Name  string
+	Limit ratelimit.Limit
+}
+
+// RateLimitKeyFunc identifies the caller a request is counted against.
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitKey identifies the caller by the first of keyBy it can find:
+// "api_key" (X-API-Key header or api_key query matching apiKey), "address"
+// (the subject of a valid player bearer token) or "ip". The client IP is the
+// fallback when none match. The limiter runs before APIKeyMiddleware, so a key
+// other than apiKey is ignored rather than letting callers pick their own
+// bucket. API keys are hashed so they are not stored in the clear.
+func RateLimitKey(jwtSecret, apiKey string, keyBy ...string) RateLimitKeyFunc {
+	return func(c *gin.Context) string {
+		for _, kind := range keyBy {
+			switch kind {
+			case RateLimitKeyAPIKey:
+				key := c.GetHeader("X-API-Key")
+				if key == "" {
+					key = c.Query("api_key")
+				}
+				if apiKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+					sum := sha256.Sum256([]byte(key))
+					return RateLimitKeyAPIKey + ":" + hex.EncodeToString(sum[:8])
+				}
+			case RateLimitKeyAddress:
+				if address := playerAddress(c, jwtSecret); address != "" {
+					return RateLimitKeyAddress + ":" + strings.ToLower(address)
+				}
+			case RateLimitKeyIP:
+				return RateLimitKeyIP + ":" + c.ClientIP()
+			}
+		}
+		return RateLimitKeyIP + ":" + c.ClientIP()
+	}
+}
+
+// playerAddress returns the player address of an already verified token, or
+// verifies the bearer token itself. The limiter runs before JWTMiddleware, and
+// an unverified address would let callers pick their own bucket.
+func playerAddress(c *gin.Context, jwtSecret string) string {
+	if claims, ok := ClaimsFrom(c); ok && claims.Role == RolePlayer {
+		return claims.Subject
+	}
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" || jwtSecret == "" {
+		return ""
+	}
+	claims, err := ParseToken(jwtSecret, token)
+	if err != nil || claims.Role != RolePlayer {
+		return ""
+	}
+	return claims.Subject
+}
+
+// RateLimit counts each request against the policy policyFor picks for it and
+// answers 429 once the caller's budget is spent. Responses carry
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset (seconds), and a
+// denied one Retry-After. Requests without a policy are not limited. If the
+// store fails the request is let through: an outage of the shared store
+// should not take the API down with it.
+func RateLimit(store ratelimit.Store, keyFunc RateLimitKeyFunc, policyFor func(c *gin.Context) (RateLimitPolicy, bool)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, ok := policyFor(c)
+		if !ok || policy.Limit.Unlimited() {
+			c.Next()
+			return
+		}
+		result, err := store.Take(c.Request.Context(), policy.Name+":"+keyFunc(c), policy.Limit)
+		if err != nil {
+			log.Printf("⚠️  Rate limit store unavailable, allowing request: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", strconv.Itoa(policy.Limit.Requests)+";w="+strconv.Itoa(ceilSeconds(policy.Limit.Window)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Max(1, float64(ceilSeconds(result.RetryAfter))))))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"error":   "Rate limit exceeded",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -3,9 +3,14 @@ package mocks
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/data"
 	"jollfi-gaming-api/internal/interfaces"
 )
 
@@ -15,21 +20,30 @@ var _ interfaces.MongoCollectionInterface = (*MockCollection)(nil)
 var _ interfaces.MongoCursorInterface = (*MockCursor)(nil)
 
 type MockMongoClient struct {
+	mu           sync.RWMutex
 	databases    map[string]*MockDatabase
 	closed       bool
+	pingErr      error
 	games        map[string]interface{}
 	transactions map[string]interface{}
 	users        map[string]interface{}
 }
 
 type MockDatabase struct {
+	mu          sync.Mutex
 	Name        string
 	collections map[string]*MockCollection
 }
 
 type MockCollection struct {
+	mu        sync.RWMutex
 	Name      string
 	documents []interface{}
+	// insertsLeft counts the inserts allowed before insertErr is returned;
+	// it is ignored while insertErr is nil.
+	insertsLeft int
+	insertErr   error
+	findErr     error
 }
 
 type MockCursor struct {
@@ -49,14 +63,16 @@ func (c *MockCursor) Decode(v interface{}) error {
 	if c.position >= len(c.documents) {
 		return fmt.Errorf("no more documents")
 	}
+	doc := c.documents[c.position]
 	// Move to next position after decode
 	c.position++
-	return nil
+	return decodeDocument(doc, v)
 }
 
 func (c *MockCursor) All(ctx context.Context, results interface{}) error {
-	// For testing, we'll return empty results
-	return nil
+	remaining := c.documents[c.position:]
+	c.position = len(c.documents)
+	return decodeAll(remaining, results)
 }
 
 func (c *MockCursor) Close(ctx context.Context) error {
@@ -76,6 +92,8 @@ func NewMockMongoClient() *MockMongoClient {
 
 // Basic MongoDB client methods
 func (m *MockMongoClient) GetDatabase(name string) interfaces.MongoDatabaseInterface {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.closed {
 		return nil
 	}
@@ -91,29 +109,55 @@ func (m *MockMongoClient) GetDatabase(name string) interfaces.MongoDatabaseInter
 }
 
 func (m *MockMongoClient) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.closed = true
 	m.databases = make(map[string]*MockDatabase)
 }
 
 func (m *MockMongoClient) Ping(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return fmt.Errorf("client is closed")
 	}
-	return nil
+	return m.pingErr
+}
+
+// SetPingError makes Ping fail with err, simulating an unreachable server
+// without losing data; nil restores it.
+func (m *MockMongoClient) SetPingError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pingErr = err
 }
 
 // Game-related methods
 func (m *MockMongoClient) CreateGame(ctx context.Context, game interface{}) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.closed {
 		return "", fmt.Errorf("client is closed")
 	}
 
-	gameID := primitive.NewObjectID().Hex()
+	oid := primitive.NewObjectID()
+	switch g := game.(type) {
+	case data.Game:
+		g.ID = oid
+		game = g
+	case *data.Game:
+		copied := *g
+		copied.ID = oid
+		game = copied
+	}
+	gameID := oid.Hex()
 	m.games[gameID] = game
 	return gameID, nil
 }
 
 func (m *MockMongoClient) GetGame(ctx context.Context, gameID string) (interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
@@ -125,57 +169,110 @@ func (m *MockMongoClient) GetGame(ctx context.Context, gameID string) (interface
 }
 
 func (m *MockMongoClient) UpdateGame(ctx context.Context, gameID string, updates interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.closed {
 		return fmt.Errorf("client is closed")
 	}
 
-	if _, exists := m.games[gameID]; !exists {
+	existing, exists := m.games[gameID]
+	if !exists {
 		return fmt.Errorf("game not found")
 	}
 
-	// In a real mock, you'd merge the updates with existing game
-	m.games[gameID] = updates
+	switch u := updates.(type) {
+	case data.Game:
+		if current, ok := existing.(data.Game); ok {
+			u.ID = current.ID
+			u.CreatedAt = current.CreatedAt
+		}
+		m.games[gameID] = u
+	default:
+		m.games[gameID] = mergeDocument(existing, updates)
+	}
 	return nil
 }
 
+func (m *MockMongoClient) TransitionGame(ctx context.Context, gameID, fromStatus string, updates interface{}) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return false, fmt.Errorf("client is closed")
+	}
+
+	existing, exists := m.games[gameID]
+	if !exists || !matchesFilter(toDocument(existing), bson.M{"status": fromStatus}) {
+		return false, nil
+	}
+	m.games[gameID] = mergeDocument(existing, updates)
+	return true, nil
+}
+
 func (m *MockMongoClient) GetGamesByStatus(ctx context.Context, status string) ([]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
 
 	var games []interface{}
 	for _, game := range m.games {
-		// In a real implementation, you'd check the status field
-		games = append(games, game)
+		if matchesFilter(toDocument(game), bson.M{"status": status}) {
+			games = append(games, game)
+		}
 	}
 	return games, nil
 }
 
 func (m *MockMongoClient) GetGamesByAddress(ctx context.Context, address string) ([]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
 
+	filter := bson.M{
+		"$or": []bson.M{
+			{"requester_address": address},
+			{"accepter_address": address},
+		},
+	}
 	var games []interface{}
 	for _, game := range m.games {
-		// In a real implementation, you'd check address fields
-		games = append(games, game)
+		if matchesFilter(toDocument(game), filter) {
+			games = append(games, game)
+		}
 	}
+	sortDocuments(games, bson.D{{Key: "created_at", Value: -1}})
 	return games, nil
 }
 
 // Transaction-related methods
 func (m *MockMongoClient) CreateTransaction(ctx context.Context, transaction interface{}) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.closed {
 		return "", fmt.Errorf("client is closed")
 	}
 
-	txID := primitive.NewObjectID().Hex()
+	oid := primitive.NewObjectID()
+	switch tx := transaction.(type) {
+	case data.Transaction:
+		tx.ID = oid
+		transaction = tx
+	case *data.Transaction:
+		copied := *tx
+		copied.ID = oid
+		transaction = copied
+	}
+	txID := oid.Hex()
 	m.transactions[txID] = transaction
 	return txID, nil
 }
 
 func (m *MockMongoClient) GetTransactionsByGameID(ctx context.Context, gameID string) ([]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
@@ -189,29 +286,48 @@ func (m *MockMongoClient) GetTransactionsByGameID(ctx context.Context, gameID st
 }
 
 func (m *MockMongoClient) UpdateTransactionStatus(ctx context.Context, txDigest string, status string, blockHeight *uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.closed {
 		return fmt.Errorf("client is closed")
 	}
 
-	// Mock implementation - in reality you'd find and update the transaction
+	updates := bson.M{"status": status}
+	if status == "confirmed" {
+		updates["confirmed_at"] = time.Now()
+		if blockHeight != nil {
+			updates["block_height"] = *blockHeight
+		}
+	}
+	for id, tx := range m.transactions {
+		if matchesFilter(toDocument(tx), bson.M{"tx_digest": txDigest}) {
+			m.transactions[id] = mergeDocument(tx, updates)
+		}
+	}
 	return nil
 }
 
 func (m *MockMongoClient) GetPendingTransactions(ctx context.Context) ([]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
 
-	var pendingTxs []interface{}
+	pendingTxs := []interface{}{}
 	for _, tx := range m.transactions {
-		// In a real implementation, you'd filter by status
-		pendingTxs = append(pendingTxs, tx)
+		if matchesFilter(toDocument(tx), bson.M{"status": "pending"}) {
+			pendingTxs = append(pendingTxs, tx)
+		}
 	}
+	sortDocuments(pendingTxs, bson.D{{Key: "created_at", Value: 1}})
 	return pendingTxs, nil
 }
 
 // User-related methods
 func (m *MockMongoClient) CreateUser(ctx context.Context, user interface{}) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.closed {
 		return "", fmt.Errorf("client is closed")
 	}
@@ -222,6 +338,8 @@ func (m *MockMongoClient) CreateUser(ctx context.Context, user interface{}) (str
 }
 
 func (m *MockMongoClient) GetUser(ctx context.Context, address string) (interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
@@ -234,6 +352,8 @@ func (m *MockMongoClient) GetUser(ctx context.Context, address string) (interfac
 }
 
 func (m *MockMongoClient) UpdateUser(ctx context.Context, address string, updates interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.closed {
 		return fmt.Errorf("client is closed")
 	}
@@ -243,6 +363,8 @@ func (m *MockMongoClient) UpdateUser(ctx context.Context, address string, update
 }
 
 func (m *MockMongoClient) UpdateUserLastSeen(ctx context.Context, address string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.closed {
 		return fmt.Errorf("client is closed")
 	}
@@ -253,6 +375,8 @@ func (m *MockMongoClient) UpdateUserLastSeen(ctx context.Context, address string
 
 // Statistics and utility methods
 func (m *MockMongoClient) GetActiveGames(ctx context.Context) ([]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
@@ -265,6 +389,8 @@ func (m *MockMongoClient) GetActiveGames(ctx context.Context) ([]interface{}, er
 }
 
 func (m *MockMongoClient) GetGameStats(ctx context.Context) (map[string]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
@@ -281,20 +407,62 @@ func (m *MockMongoClient) GetGameStats(ctx context.Context) (map[string]interfac
 }
 
 func (m *MockMongoClient) GetUserStats(ctx context.Context, address string) (map[string]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
 
-	stats := map[string]interface{}{
-		"total_games": 0,
-		"total_wins":  0,
-		"total_stake": uint64(0),
-		"win_rate":    0.0,
+	// Mirrors the $match/$group pipeline of the real client
+	filter := bson.M{
+		"$or": []bson.M{
+			{"requester_address": address},
+			{"accepter_address": address},
+		},
+	}
+	stats := make(map[string]interface{})
+	byStatus := make(map[string]map[string]interface{})
+	var totalGames, totalWins int64
+	var totalStake uint64
+	for _, game := range m.games {
+		doc := toDocument(game)
+		if !matchesFilter(doc, filter) {
+			continue
+		}
+		status, _ := doc["status"].(string)
+		group, ok := byStatus[status]
+		if !ok {
+			group = map[string]interface{}{"count": int64(0), "total_stake": uint64(0), "wins": int64(0)}
+			byStatus[status] = group
+		}
+		stake, _ := toFloat(doc["stake_amount"])
+		group["count"] = group["count"].(int64) + 1
+		group["total_stake"] = group["total_stake"].(uint64) + uint64(stake)
+		totalGames++
+		totalStake += uint64(stake)
+		if doc["winner"] == address {
+			group["wins"] = group["wins"].(int64) + 1
+			totalWins++
+		}
+	}
+	for status, group := range byStatus {
+		stats[status] = group
+	}
+
+	stats["total_games"] = totalGames
+	stats["total_wins"] = totalWins
+	stats["total_stake"] = totalStake
+	if totalGames > 0 {
+		stats["win_rate"] = float64(totalWins) / float64(totalGames)
+	} else {
+		stats["win_rate"] = 0.0
 	}
 	return stats, nil
 }
 
 func (m *MockMongoClient) GetCollectionStats(ctx context.Context) (map[string]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
@@ -312,16 +480,29 @@ func (m *MockMongoClient) HealthCheck(ctx context.Context) error {
 }
 
 func (m *MockMongoClient) CleanupOldGames(ctx context.Context, olderThanDays int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.closed {
 		return 0, fmt.Errorf("client is closed")
 	}
 
-	// Mock implementation - return 0 deleted
-	return 0, nil
+	cutoff := time.Now().AddDate(0, 0, -olderThanDays)
+	var deleted int64
+	for id, raw := range m.games {
+		game, ok := raw.(data.Game)
+		if !ok || game.Status != "completed" || game.CompletedAt == nil || !game.CompletedAt.Before(cutoff) {
+			continue
+		}
+		delete(m.games, id)
+		deleted++
+	}
+	return deleted, nil
 }
 
 // MockDatabase implementations
 func (d *MockDatabase) Collection(name string) interfaces.MongoCollectionInterface {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	if coll, exists := d.collections[name]; exists {
 		return coll
 	}
@@ -333,8 +514,34 @@ func (d *MockDatabase) Collection(name string) interfaces.MongoCollectionInterfa
 	return coll
 }
 
+// FailInsertsAfter lets the next n inserts through and fails every insert
+// after them with err, simulating a write failure partway through a batch;
+// a nil err restores normal inserts.
+func (c *MockCollection) FailInsertsAfter(n int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.insertsLeft = n
+	c.insertErr = err
+}
+
+// FailFinds makes every Find on the collection fail with err, simulating a
+// read that times out; nil restores it.
+func (c *MockCollection) FailFinds(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.findErr = err
+}
+
 // MockCollection implementations
 func (c *MockCollection) InsertOne(ctx context.Context, document interface{}) (*mongo.InsertOneResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.insertErr != nil {
+		if c.insertsLeft == 0 {
+			return nil, c.insertErr
+		}
+		c.insertsLeft--
+	}
 	c.documents = append(c.documents, document)
 	return &mongo.InsertOneResult{
 		InsertedID: primitive.NewObjectID(),
@@ -342,30 +549,108 @@ func (c *MockCollection) InsertOne(ctx context.Context, document interface{}) (*
 }
 
 func (c *MockCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (interfaces.MongoCursorInterface, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.findErr != nil {
+		return nil, c.findErr
+	}
+
+	var matched []interface{}
+	for _, doc := range c.documents {
+		if matchesFilter(toDocument(doc), filter) {
+			matched = append(matched, doc)
+		}
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Sort != nil {
+			sortDocuments(matched, opt.Sort)
+		}
+		if opt.Skip != nil {
+			skip := int(*opt.Skip)
+			if skip > len(matched) {
+				skip = len(matched)
+			}
+			matched = matched[skip:]
+		}
+		if opt.Limit != nil && *opt.Limit > 0 && int(*opt.Limit) < len(matched) {
+			matched = matched[:*opt.Limit]
+		}
+	}
+
 	return &MockCursor{
-		documents: c.documents,
+		documents: matched,
 		position:  0,
 	}, nil
 }
 
 func (c *MockCollection) FindOne(ctx context.Context, filter interface{}) interface{} {
-	if len(c.documents) > 0 {
-		return c.documents[0]
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, doc := range c.documents {
+		if matchesFilter(toDocument(doc), filter) {
+			return doc
+		}
 	}
 	return nil
 }
 
-func (c *MockCollection) UpdateOne(ctx context.Context, filter, update interface{}) (*mongo.UpdateResult, error) {
-	return &mongo.UpdateResult{
-		MatchedCount:  1,
-		ModifiedCount: 1,
-	}, nil
+func (c *MockCollection) UpdateOne(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, doc := range c.documents {
+		if matchesFilter(toDocument(doc), filter) {
+			c.documents[i] = mergeDocument(doc, update)
+			return &mongo.UpdateResult{
+				MatchedCount:  1,
+				ModifiedCount: 1,
+			}, nil
+		}
+	}
+
+	for _, opt := range opts {
+		if opt != nil && opt.Upsert != nil && *opt.Upsert {
+			doc := upsertDocument(filter, update)
+			for _, existing := range c.documents {
+				if compareValues(toDocument(existing)["_id"], doc["_id"]) == 0 {
+					// Like MongoDB, an upsert whose filter missed the
+					// existing _id fails instead of inserting a duplicate
+					return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}}}
+				}
+			}
+			c.documents = append(c.documents, doc)
+			return &mongo.UpdateResult{
+				UpsertedCount: 1,
+				UpsertedID:    doc["_id"],
+			}, nil
+		}
+	}
+	return &mongo.UpdateResult{}, nil
 }
 
 func (c *MockCollection) DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error) {
-	if len(c.documents) > 0 {
-		c.documents = c.documents[1:]
-		return &mongo.DeleteResult{DeletedCount: 1}, nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, doc := range c.documents {
+		if matchesFilter(toDocument(doc), filter) {
+			c.documents = append(c.documents[:i], c.documents[i+1:]...)
+			return &mongo.DeleteResult{DeletedCount: 1}, nil
+		}
 	}
 	return &mongo.DeleteResult{DeletedCount: 0}, nil
 }
+
+func (c *MockCollection) CountDocuments(ctx context.Context, filter interface{}) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var count int64
+	for _, doc := range c.documents {
+		if matchesFilter(toDocument(doc), filter) {
+			count++
+		}
+	}
+	return count, nil
+}
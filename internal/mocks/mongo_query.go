@@ -0,0 +1,404 @@
+package mocks
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// toDocument normalises any document (struct, map, bson.M) into a bson.M by
+// round-tripping it through the bson codec, the same way the driver would.
+func toDocument(v interface{}) bson.M {
+	if v == nil {
+		return bson.M{}
+	}
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return bson.M{}
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return bson.M{}
+	}
+	return doc
+}
+
+// decodeDocument decodes a stored document into the caller supplied value.
+func decodeDocument(doc interface{}, v interface{}) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mock document: %v", err)
+	}
+	return bson.Unmarshal(raw, v)
+}
+
+// decodeAll decodes a slice of stored documents into a pointer to a slice.
+func decodeAll(docs []interface{}, results interface{}) error {
+	raw, err := bson.Marshal(bson.M{"items": docs})
+	if err != nil {
+		return fmt.Errorf("failed to marshal mock documents: %v", err)
+	}
+
+	resultsVal := reflect.ValueOf(results)
+	if resultsVal.Kind() != reflect.Ptr || resultsVal.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results argument must be a pointer to a slice")
+	}
+
+	wrapper := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: "Items",
+		Type: resultsVal.Elem().Type(),
+		Tag:  `bson:"items"`,
+	}}))
+	if err := bson.Unmarshal(raw, wrapper.Interface()); err != nil {
+		return err
+	}
+	items := wrapper.Elem().Field(0)
+	if items.IsNil() {
+		items = reflect.MakeSlice(resultsVal.Elem().Type(), 0, 0)
+	}
+	resultsVal.Elem().Set(items)
+	return nil
+}
+
+// matchesFilter implements the subset of the MongoDB query language used by
+// the service layer: equality, $or/$and and the common comparison operators.
+func matchesFilter(doc bson.M, filter interface{}) bool {
+	f := toDocument(filter)
+	for key, cond := range f {
+		switch key {
+		case "$or":
+			if !matchAny(doc, cond) {
+				return false
+			}
+		case "$and":
+			for _, sub := range toSlice(cond) {
+				if !matchesFilter(doc, sub) {
+					return false
+				}
+			}
+		default:
+			if !matchField(lookup(doc, key), cond) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchAny(doc bson.M, cond interface{}) bool {
+	for _, sub := range toSlice(cond) {
+		if matchesFilter(doc, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchField(value interface{}, cond interface{}) bool {
+	if pattern, ok := cond.(primitive.Regex); ok {
+		return matchRegex(value, pattern)
+	}
+	ops, isOps := cond.(bson.M)
+	if !isOps || !hasOperators(ops) {
+		return compareValues(value, cond) == 0
+	}
+	for op, arg := range ops {
+		switch op {
+		case "$eq":
+			if compareValues(value, arg) != 0 {
+				return false
+			}
+		case "$ne":
+			if compareValues(value, arg) == 0 {
+				return false
+			}
+		case "$gt":
+			if value == nil || compareValues(value, arg) <= 0 {
+				return false
+			}
+		case "$gte":
+			if value == nil || compareValues(value, arg) < 0 {
+				return false
+			}
+		case "$lt":
+			if value == nil || compareValues(value, arg) >= 0 {
+				return false
+			}
+		case "$lte":
+			if value == nil || compareValues(value, arg) > 0 {
+				return false
+			}
+		case "$in":
+			found := false
+			for _, candidate := range toSlice(arg) {
+				if compareValues(value, candidate) == 0 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		case "$nin":
+			for _, candidate := range toSlice(arg) {
+				if compareValues(value, candidate) == 0 {
+					return false
+				}
+			}
+		case "$exists":
+			exists, _ := arg.(bool)
+			if (value != nil) != exists {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchRegex(value interface{}, pattern primitive.Regex) bool {
+	text, ok := value.(string)
+	if !ok {
+		return false
+	}
+	expr := pattern.Pattern
+	if strings.Contains(pattern.Options, "i") {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	return err == nil && re.MatchString(text)
+}
+
+func hasOperators(m bson.M) bool {
+	for key := range m {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+func lookup(doc bson.M, path string) interface{} {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(bson.M)
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+func toSlice(v interface{}) []interface{} {
+	switch s := v.(type) {
+	case bson.A:
+		return s
+	case []interface{}:
+		return s
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+	out := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+// compareValues orders two bson values. Numbers are compared numerically
+// regardless of their concrete type; mismatched types never compare equal.
+func compareValues(a, b interface{}) int {
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			}
+			return 0
+		}
+	}
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv)
+		}
+	case primitive.ObjectID:
+		if bv, ok := b.(primitive.ObjectID); ok {
+			return strings.Compare(av.Hex(), bv.Hex())
+		}
+	case bool:
+		if bv, ok := b.(bool); ok && av == bv {
+			return 0
+		}
+	case nil:
+		if b == nil {
+			return 0
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return 0
+	}
+	return 2
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case primitive.DateTime:
+		return float64(n), true
+	case time.Time:
+		return float64(primitive.NewDateTimeFromTime(n)), true
+	}
+	return 0, false
+}
+
+// sortDocuments applies a FindOptions sort specification (bson.D or bson.M).
+func sortDocuments(docs []interface{}, spec interface{}) {
+	var keys bson.D
+	switch s := spec.(type) {
+	case bson.D:
+		keys = s
+	case bson.M:
+		for k, v := range s {
+			keys = append(keys, bson.E{Key: k, Value: v})
+		}
+	case map[string]interface{}:
+		for k, v := range s {
+			keys = append(keys, bson.E{Key: k, Value: v})
+		}
+	default:
+		return
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		di, dj := toDocument(docs[i]), toDocument(docs[j])
+		for _, key := range keys {
+			direction, _ := toFloat(key.Value)
+			cmp := compareValues(lookup(di, key.Key), lookup(dj, key.Key))
+			if cmp == 0 || cmp == 2 {
+				continue
+			}
+			if direction < 0 {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+// mergeDocument applies a plain field map or a $set/$inc update document to a
+// stored document and decodes the result back into the stored type.
+func mergeDocument(existing interface{}, updates interface{}) interface{} {
+	doc := toDocument(existing)
+	update := toDocument(updates)
+
+	if !hasOperators(update) {
+		update = bson.M{"$set": update}
+	}
+	if set, ok := update["$set"].(bson.M); ok {
+		for k, v := range set {
+			doc[k] = v
+		}
+	}
+	if inc, ok := update["$inc"].(bson.M); ok {
+		for k, v := range inc {
+			current, _ := toFloat(doc[k])
+			delta, _ := toFloat(v)
+			if isInteger(doc[k]) && isInteger(v) {
+				doc[k] = int64(current) + int64(delta)
+			} else {
+				doc[k] = current + delta
+			}
+		}
+	}
+
+	if min, ok := update["$min"].(bson.M); ok {
+		for k, v := range min {
+			if current, exists := doc[k]; !exists || compareValues(v, current) < 0 {
+				doc[k] = v
+			}
+		}
+	}
+	if max, ok := update["$max"].(bson.M); ok {
+		for k, v := range max {
+			if current, exists := doc[k]; !exists || compareValues(v, current) > 0 {
+				doc[k] = v
+			}
+		}
+	}
+	if push, ok := update["$push"].(bson.M); ok {
+		for k, v := range push {
+			doc[k] = append(toSlice(doc[k]), v)
+		}
+	}
+	if unset, ok := update["$unset"].(bson.M); ok {
+		for k := range unset {
+			delete(doc, k)
+		}
+	}
+
+	if existing == nil {
+		return doc
+	}
+	target := reflect.New(reflect.TypeOf(existing))
+	if err := decodeDocument(doc, target.Interface()); err != nil {
+		return doc
+	}
+	return target.Elem().Interface()
+}
+
+// upsertDocument builds the document inserted by an upsert: the equality
+// fields of the filter plus the update, including any $setOnInsert fields.
+func upsertDocument(filter interface{}, update interface{}) bson.M {
+	doc := bson.M{"_id": primitive.NewObjectID()}
+	for k, v := range toDocument(filter) {
+		if strings.HasPrefix(k, "$") {
+			continue
+		}
+		if ops, ok := v.(bson.M); ok && hasOperators(ops) {
+			continue
+		}
+		doc[k] = v
+	}
+	updateDoc := toDocument(update)
+	if setOnInsert, ok := updateDoc["$setOnInsert"].(bson.M); ok {
+		for k, v := range setOnInsert {
+			doc[k] = v
+		}
+	}
+	return mergeDocument(doc, updateDoc).(bson.M)
+}
+
+func isInteger(v interface{}) bool {
+	switch v.(type) {
+	case nil, int, int32, int64, uint, uint32, uint64:
+		return true
+	}
+	return false
+}
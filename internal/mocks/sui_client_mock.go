@@ -3,7 +3,11 @@ package mocks
 import (
 	"context"
 	"fmt"
+	"jollfi-gaming-api/internal/breaker"
+	"jollfi-gaming-api/internal/data"
 	"jollfi-gaming-api/internal/interfaces"
+	"jollfi-gaming-api/internal/models"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -20,8 +24,10 @@ type MockSuiClient struct {
 
 	ExternalStakeFunc     func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error)
 	ExternalPayWinnerFunc func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error)
+	ExternalRefundFunc    func(requesterAddress, accepterAddress string, stakeAmount uint64, ctx context.Context) (string, error)
 	GetBalanceFunc        func(ctx context.Context) (uint64, error)
 	GetCoinsFunc          func(ctx context.Context, coinType string) ([]map[string]interface{}, error)
+	LatestCheckpointFunc  func(ctx context.Context) (*models.Checkpoint, error)
 }
 
 func NewMockSuiClient() *MockSuiClient {
@@ -164,6 +170,29 @@ func (m *MockSuiClient) ExternalPayWinner(requesterAddress, accepterAddress stri
 	return digest, nil
 }
 
+func (m *MockSuiClient) ExternalRefund(requesterAddress, accepterAddress string, stakeAmount uint64, ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("mock external refund: %w", err)
+	}
+	if m.shouldFail {
+		return "", fmt.Errorf("mock external refund failed")
+	}
+	if m.ExternalRefundFunc != nil {
+		return m.ExternalRefundFunc(requesterAddress, accepterAddress, stakeAmount, ctx)
+	}
+
+	digest := fmt.Sprintf("mock_refund_tx_%s_%s_%d", requesterAddress, accepterAddress, stakeAmount)
+	m.AddTransaction(digest, map[string]interface{}{
+		"type":             "external_refund",
+		"requesterAddress": requesterAddress,
+		"accepterAddress":  accepterAddress,
+		"stakeAmount":      stakeAmount,
+		"status":           "success",
+		"timestamp":        time.Now().Unix(),
+	})
+	return digest, nil
+}
+
 func (m *MockSuiClient) ExecuteTransactionBlock(ctx context.Context, txBytes []byte) (string, error) {
 	if err := ctx.Err(); err != nil {
 		return "", fmt.Errorf("mock execute transaction block: %w", err)
@@ -286,6 +315,98 @@ func (m *MockSuiClient) GetCoins(ctx context.Context, coinType string) ([]map[st
 	return m.coins, nil
 }
 
+func (m *MockSuiClient) GetAllCoins(ctx context.Context, coinType string) ([]map[string]interface{}, error) {
+	return m.GetCoins(ctx, coinType)
+}
+
+func (m *MockSuiClient) GetTotalBalance(ctx context.Context, coinType string) (uint64, error) {
+	coins, err := m.GetAllCoins(ctx, coinType)
+	if err != nil {
+		return 0, err
+	}
+
+	var total uint64
+	for _, coin := range coins {
+		if balanceStr, ok := coin["balance"].(string); ok {
+			balance, err := strconv.ParseUint(balanceStr, 10, 64)
+			if err != nil {
+				continue
+			}
+			total += balance
+		}
+	}
+	return total, nil
+}
+
+// GetStakePool returns the "stake_pool" custom response when one is set, and
+// otherwise a minimal pool object with the requested ID.
+func (m *MockSuiClient) GetStakePool(poolID string) (map[string]interface{}, error) {
+	if poolID == "" {
+		return nil, fmt.Errorf("pool ID is required")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.shouldFail {
+		return nil, fmt.Errorf("mock get stake pool failed")
+	}
+	if pool, ok := m.responses["stake_pool"].(map[string]interface{}); ok {
+		return pool, nil
+	}
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"objectId": poolID,
+			"version":  "1",
+			"type":     "0xmock::pool::StakePool",
+			"content": map[string]interface{}{
+				"dataType": "moveObject",
+				"type":     "0xmock::pool::StakePool",
+				"fields": map[string]interface{}{
+					"id":          map[string]interface{}{"id": poolID},
+					"balance":     "0",
+					"fee_bps":     "1000",
+					"admin":       "0xmock_admin",
+					"paused":      false,
+					"total_games": "0",
+				},
+			},
+		},
+	}, nil
+}
+
+// GetPool decodes the mock pool object, so a "stake_pool" custom response
+// exercises the real decoder.
+func (m *MockSuiClient) GetPool(ctx context.Context) (*models.StakePool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("mock get pool: %w", err)
+	}
+	raw, err := m.GetStakePool("0xmock_pool")
+	if err != nil {
+		return nil, err
+	}
+	return data.DecodeStakePool(raw)
+}
+
+// LatestCheckpoint reports a checkpoint created just now unless
+// LatestCheckpointFunc is set.
+func (m *MockSuiClient) LatestCheckpoint(ctx context.Context) (*models.Checkpoint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("mock latest checkpoint: %w", err)
+	}
+	if m.shouldFail {
+		return nil, fmt.Errorf("mock latest checkpoint failed")
+	}
+	if m.LatestCheckpointFunc != nil {
+		return m.LatestCheckpointFunc(ctx)
+	}
+	return &models.Checkpoint{SequenceNumber: 1000, Timestamp: time.Now()}, nil
+}
+
+// RPCStatus reports a single healthy endpoint.
+func (m *MockSuiClient) RPCStatus() []models.RPCEndpointStatus {
+	return []models.RPCEndpointStatus{{URL: "mock://sui", Healthy: true, Pinned: true, Breaker: string(breaker.Closed)}}
+}
+
 func (m *MockSuiClient) GetMockTransaction(digest string) (interface{}, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AdminAuditEntry records one request made to the admin API. Action is the
+// route pattern, such as "POST /admin/games/cleanup"; Params holds the path
+// and query parameters it was called with.
+type AdminAuditEntry struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Action     string             `bson:"action" json:"action"`
+	Actor      string             `bson:"actor,omitempty" json:"actor,omitempty"`
+	RemoteIP   string             `bson:"remote_ip" json:"remote_ip"`
+	RequestID  string             `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	Params     map[string]string  `bson:"params,omitempty" json:"params,omitempty"`
+	StatusCode int                `bson:"status_code" json:"status_code"`
+	DurationMs int64              `bson:"duration_ms" json:"duration_ms"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
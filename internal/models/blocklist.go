@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// BlocklistEntry bars an address from staking and from receiving payouts. The
+// address is stored lowercased as the document ID.
+type BlocklistEntry struct {
+	Address   string    `bson:"_id" json:"address"`
+	Reason    string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	Source    string    `bson:"source" json:"source"`
+	AddedBy   string    `bson:"added_by,omitempty" json:"added_by,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
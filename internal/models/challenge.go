@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Challenge is the public view of a game that is still waiting for, or has
+// just found, an opponent. An empty AccepterAddress means anyone may accept.
+type Challenge struct {
+	ID                string    `json:"id"`
+	RequesterAddress  string    `json:"requester_address"`
+	AccepterAddress   string    `json:"accepter_address,omitempty"`
+	StakeAmount       uint64    `json:"stake_amount"`
+	GameType          string    `json:"game_type,omitempty"`
+	Status            string    `json:"status"`
+	TransactionDigest string    `json:"transaction_digest,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// DependencyState is whether a backing service was reachable at the last
+// check, and since when.
+type DependencyState struct {
+	Available bool      `json:"available"`
+	Since     time.Time `json:"since"`
+	CheckedAt time.Time `json:"checked_at"`
+	LastError string    `json:"last_error,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	ScoreDirectionHigher = "higher"
+	ScoreDirectionLower  = "lower"
+
+	TieHandlingDraw   = "draw"
+	TieHandlingReject = "reject"
+	TieHandlingRefund = "refund"
+)
+
+// GameType holds the rules of one game stakes and payouts can be played
+// under. The name is stored lowercased as the document ID. Zero limits are
+// off.
+type GameType struct {
+	Name string `bson:"_id" json:"name"`
+	// ScoreDirection says whether the higher or the lower score wins.
+	ScoreDirection string `bson:"score_direction" json:"score_direction"`
+	// TieHandling settles equal scores as a draw, refunds both stakes, or
+	// refuses the result.
+	TieHandling string    `bson:"tie_handling" json:"tie_handling"`
+	MinStake    uint64    `bson:"min_stake,omitempty" json:"min_stake,omitempty"`
+	MaxStake    uint64    `bson:"max_stake,omitempty" json:"max_stake,omitempty"`
+	MaxScore    uint64    `bson:"max_score,omitempty" json:"max_score,omitempty"`
+	UpdatedBy   string    `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HeldPayout is a payout the payout guard refused to send. It waits in
+// held_for_review until an admin approves it, which sends it unchanged, or
+// rejects it.
+type HeldPayout struct {
+	ID                primitive.ObjectID `bson:"_id" json:"id"`
+	GameID            string             `bson:"game_id,omitempty" json:"game_id,omitempty"`
+	GameType          string             `bson:"game_type,omitempty" json:"game_type,omitempty"`
+	RequesterAddress  string             `bson:"requester_address" json:"requester_address"`
+	AccepterAddress   string             `bson:"accepter_address" json:"accepter_address"`
+	RequesterScore    uint64             `bson:"requester_score" json:"requester_score"`
+	AccepterScore     uint64             `bson:"accepter_score" json:"accepter_score"`
+	StakeAmount       uint64             `bson:"stake_amount" json:"stake_amount"`
+	GameServer        string             `bson:"game_server,omitempty" json:"game_server,omitempty"`
+	Violations        []string           `bson:"violations" json:"violations"`
+	Status            string             `bson:"status" json:"status"`
+	LastError         string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	ReviewedBy        string             `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewNote        string             `bson:"review_note,omitempty" json:"review_note,omitempty"`
+	ReviewedAt        *time.Time         `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	TransactionDigest string             `bson:"transaction_digest,omitempty" json:"transaction_digest,omitempty"`
+	PendingPayoutID   string             `bson:"pending_payout_id,omitempty" json:"pending_payout_id,omitempty"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+}
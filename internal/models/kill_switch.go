@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+const (
+	KillSwitchSourceAdmin      = "admin"
+	KillSwitchSourceGasMonitor = "gas_monitor"
+)
+
+// KillSwitch is the global pause flag. While Paused, no new stakes or payouts
+// are submitted; reads keep working.
+type KillSwitch struct {
+	ID        string    `bson:"_id" json:"-"`
+	Paused    bool      `bson:"paused" json:"paused"`
+	Reason    string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	Source    string    `bson:"source,omitempty" json:"source,omitempty"`
+	UpdatedBy string    `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// LeaderboardEntry is one player's materialized totals for a leaderboard
+// period, e.g. period "week" and period_key "2026-W42", overall or for one
+// game type.
+type LeaderboardEntry struct {
+	Period    string    `bson:"period" json:"-"`
+	PeriodKey string    `bson:"period_key" json:"-"`
+	GameType  string    `bson:"game_type,omitempty" json:"-"`
+	Address   string    `bson:"address" json:"address"`
+	Rank      int64     `bson:"-" json:"rank"`
+	Games     int64     `bson:"games" json:"games"`
+	Wins      int64     `bson:"wins" json:"wins"`
+	Losses    int64     `bson:"losses" json:"losses"`
+	Draws     int64     `bson:"draws" json:"draws"`
+	Profit    int64     `bson:"profit" json:"profit"` // MIST
+	Volume    int64     `bson:"volume" json:"volume"` // MIST
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -1,6 +1,8 @@
 package models
 
 type PayWinner struct {
+	GameID           string `bson:"game_id,omitempty"`
+	GameType         string `bson:"game_type,omitempty"`
 	RequesterAddress string `bson:"requester_address"`
 	AccepterAddress  string `bson:"accepter_address"`
 	RequesterScore   uint64 `bson:"requester_score"`
@@ -13,4 +15,7 @@ type PayWinner struct {
 	Timestamp        int64  `bson:"timestamp"`
 	TransactionHash  string `bson:"transaction_hash,omitempty"`
 	StakeAmount      uint64 `bson:"stake_amount"`
+	GameServer       string `bson:"game_server,omitempty"`
+	// Refunded marks a draw settled by refunding both stakes.
+	Refunded bool `bson:"refunded,omitempty"`
 }
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PendingPayout is a payout whose stake is large enough that it needs
+// approval from several admins before it is sent. It expires if it does not
+// collect them in time.
+type PendingPayout struct {
+	ID                primitive.ObjectID `bson:"_id" json:"id"`
+	GameID            string             `bson:"game_id,omitempty" json:"game_id,omitempty"`
+	GameType          string             `bson:"game_type,omitempty" json:"game_type,omitempty"`
+	RequesterAddress  string             `bson:"requester_address" json:"requester_address"`
+	AccepterAddress   string             `bson:"accepter_address" json:"accepter_address"`
+	RequesterScore    uint64             `bson:"requester_score" json:"requester_score"`
+	AccepterScore     uint64             `bson:"accepter_score" json:"accepter_score"`
+	StakeAmount       uint64             `bson:"stake_amount" json:"stake_amount"`
+	GameServer        string             `bson:"game_server,omitempty" json:"game_server,omitempty"`
+	Required          int                `bson:"required" json:"required"`
+	Approvals         []PayoutApproval   `bson:"approvals" json:"approvals"`
+	Status            string             `bson:"status" json:"status"`
+	LastError         string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	TransactionDigest string             `bson:"transaction_digest,omitempty" json:"transaction_digest,omitempty"`
+	ExpiresAt         time.Time          `bson:"expires_at" json:"expires_at"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// PayoutApproval records one admin's sign-off on a pending payout.
+type PayoutApproval struct {
+	Admin      string    `bson:"admin" json:"admin"`
+	ApprovedAt time.Time `bson:"approved_at" json:"approved_at"`
+}
+
+// ApprovedBy reports whether admin has already approved the payout.
+func (p PendingPayout) ApprovedBy(admin string) bool {
+	for _, approval := range p.Approvals {
+		if approval.Admin == admin {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package models
+
+type PlayerProfile struct {
+	Address          string                 `json:"address"`
+	Stats            map[string]interface{} `json:"stats"`
+	GamesSettled     int                    `json:"games_settled"`
+	Wins             int                    `json:"wins"`
+	Losses           int                    `json:"losses"`
+	Draws            int                    `json:"draws"`
+	NetProfit        int64                  `json:"net_profit"` // MIST
+	CurrentWinStreak int                    `json:"current_win_streak"`
+	LongestWinStreak int                    `json:"longest_win_streak"`
+	HeadToHead       []HeadToHeadRecord     `json:"head_to_head"`
+	FirstSeen        int64                  `json:"first_seen,omitempty"`
+	LastSeen         int64                  `json:"last_seen,omitempty"`
+}
+
+type HeadToHeadRecord struct {
+	Opponent   string `json:"opponent"`
+	Games      int    `json:"games"`
+	Wins       int    `json:"wins"`
+	Losses     int    `json:"losses"`
+	Draws      int    `json:"draws"`
+	NetProfit  int64  `json:"net_profit"` // MIST
+	LastPlayed int64  `json:"last_played"`
+}
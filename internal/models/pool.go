@@ -0,0 +1,16 @@
+package models
+
+// StakePool is the on-chain pool object with its Move struct fields decoded.
+// Fields keeps every field as returned by the node, including any not
+// decoded here.
+type StakePool struct {
+	ObjectID   string                 `json:"object_id"`
+	Type       string                 `json:"type"`
+	Version    uint64                 `json:"version"`
+	Balance    uint64                 `json:"balance"`
+	FeeBps     uint64                 `json:"fee_bps"`
+	Admin      string                 `json:"admin,omitempty"`
+	Paused     bool                   `json:"paused"`
+	TotalGames uint64                 `json:"total_games"`
+	Fields     map[string]interface{} `json:"fields"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	QueueStatusWaiting   = "waiting"
+	QueueStatusMatching  = "matching"
+	QueueStatusMatched   = "matched"
+	QueueStatusCancelled = "cancelled"
+	QueueStatusExpired   = "expired"
+	QueueStatusFailed    = "failed"
+)
+
+// QueueTicket is one player's place in the matchmaking queue for a stake tier.
+type QueueTicket struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Address           string             `bson:"address" json:"address"`
+	CoinID            string             `bson:"coin_id" json:"coin_id"`
+	StakeAmount       uint64             `bson:"stake_amount" json:"stake_amount"`
+	GameType          string             `bson:"game_type,omitempty" json:"game_type,omitempty"`
+	Rating            float64            `bson:"rating" json:"rating"`
+	RatingWindow      float64            `bson:"rating_window,omitempty" json:"rating_window,omitempty"`
+	Status            string             `bson:"status" json:"status"`
+	Opponent          string             `bson:"opponent,omitempty" json:"opponent,omitempty"`
+	GameID            string             `bson:"game_id,omitempty" json:"game_id,omitempty"`
+	TransactionDigest string             `bson:"transaction_digest,omitempty" json:"transaction_digest,omitempty"`
+	Error             string             `bson:"error,omitempty" json:"error,omitempty"`
+	JoinedAt          time.Time          `bson:"joined_at" json:"joined_at"`
+	MatchedAt         *time.Time         `bson:"matched_at,omitempty" json:"matched_at,omitempty"`
+	// Live is set while the ticket is waiting or matching and backs the
+	// unique index that keeps one live ticket per address.
+	Live bool `bson:"live,omitempty" json:"-"`
+}
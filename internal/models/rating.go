@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// PlayerRating is the Glicko-2 state stored on a player's users document.
+// Games doubles as the document version for optimistic updates.
+type PlayerRating struct {
+	Rating     float64   `bson:"rating" json:"rating"`
+	Deviation  float64   `bson:"deviation" json:"deviation"`
+	Volatility float64   `bson:"volatility" json:"volatility"`
+	Games      int64     `bson:"games" json:"games"`
+	UpdatedAt  time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// RatingHistoryEntry records how one settled game moved a player's rating.
+type RatingHistoryEntry struct {
+	Address         string  `bson:"address" json:"-"`
+	GameID          string  `bson:"game_id,omitempty" json:"game_id,omitempty"`
+	Opponent        string  `bson:"opponent" json:"opponent"`
+	OpponentRating  float64 `bson:"opponent_rating" json:"opponent_rating"`
+	Result          string  `bson:"result" json:"result"` // win, loss or draw
+	RatingBefore    float64 `bson:"rating_before" json:"rating_before"`
+	RatingAfter     float64 `bson:"rating_after" json:"rating_after"`
+	DeviationBefore float64 `bson:"deviation_before" json:"deviation_before"`
+	DeviationAfter  float64 `bson:"deviation_after" json:"deviation_after"`
+	VolatilityAfter float64 `bson:"volatility_after" json:"volatility_after"`
+	Timestamp       int64   `bson:"timestamp" json:"timestamp"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// StakeLimits cap what a player may stake, in the units of stake_amount. Zero
+// means no limit. Daily, Weekly and Monthly cap the total staked over the
+// last 24 hours, 7 days and 30 days; NetLoss caps losses minus winnings over
+// the last 30 days.
+type StakeLimits struct {
+	MaxStake uint64 `bson:"max_stake" json:"max_stake"`
+	Daily    uint64 `bson:"daily" json:"daily"`
+	Weekly   uint64 `bson:"weekly" json:"weekly"`
+	Monthly  uint64 `bson:"monthly" json:"monthly"`
+	NetLoss  uint64 `bson:"net_loss" json:"net_loss"`
+}
+
+// PendingStakeLimits are limits a player loosened. They replace the current
+// limits at EffectiveAt.
+type PendingStakeLimits struct {
+	Limits      StakeLimits `bson:"limits" json:"limits"`
+	EffectiveAt time.Time   `bson:"effective_at" json:"effective_at"`
+}
+
+// ResponsibleGaming holds the controls a player set for themselves, stored on
+// their users document. No stakes are accepted before CoolingOffUntil or
+// ExcludedUntil.
+type ResponsibleGaming struct {
+	Limits          StakeLimits         `bson:"limits" json:"limits"`
+	Pending         *PendingStakeLimits `bson:"pending,omitempty" json:"pending,omitempty"`
+	CoolingOffUntil *time.Time          `bson:"cooling_off_until,omitempty" json:"cooling_off_until,omitempty"`
+	ExcludedUntil   *time.Time          `bson:"excluded_until,omitempty" json:"excluded_until,omitempty"`
+	UpdatedAt       time.Time           `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// StakeUsage is what currently counts against a player's limits. NetLoss is
+// negative while the player is ahead.
+type StakeUsage struct {
+	Daily   uint64 `json:"daily"`
+	Weekly  uint64 `json:"weekly"`
+	Monthly uint64 `json:"monthly"`
+	NetLoss int64  `json:"net_loss"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// RPCEndpointStatus describes one Sui RPC endpoint, its circuit breaker and
+// its health score. A lower score is better.
+type RPCEndpointStatus struct {
+	URL                 string     `json:"url"`
+	Healthy             bool       `json:"healthy"`
+	Pinned              bool       `json:"pinned"`
+	Score               float64    `json:"score"`
+	LatencyMs           float64    `json:"latency_ms"`
+	ErrorRate           float64    `json:"error_rate"`
+	Checkpoint          uint64     `json:"checkpoint,omitempty"`
+	CheckpointLag       uint64     `json:"checkpoint_lag"`
+	Breaker             string     `json:"breaker"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// Checkpoint is a Sui checkpoint's sequence number and creation time.
+type Checkpoint struct {
+	SequenceNumber uint64    `json:"sequence_number"`
+	Timestamp      time.Time `json:"timestamp"`
+}
@@ -7,6 +7,7 @@ type Stake struct {
 	RequesterAddress string `bson:"requester_address" json:"requester_address"`
 	AccepterAddress  string `bson:"accepter_address" json:"accepter_address"`
 	StakeAmount      uint64 `bson:"stake_amount" json:"stake_amount"`
+	GameType         string `bson:"game_type,omitempty" json:"game_type,omitempty"`
 	Status           string `bson:"status" json:"status"`
 	Timestamp        int64  `bson:"timestamp" json:"timestamp"`
 	TransactionHash  string `bson:"transaction_hash,omitempty" json:"transaction_hash,omitempty"`
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TimeoutSettlement records what the timeout scheduler did with a game left
+// in staked past the deadline, and why.
+type TimeoutSettlement struct {
+	ID                primitive.ObjectID `bson:"_id" json:"id"`
+	GameID            string             `bson:"game_id" json:"game_id"`
+	Policy            string             `bson:"policy" json:"policy"`
+	Action            string             `bson:"action" json:"action"`
+	Reason            string             `bson:"reason" json:"reason"`
+	RequesterAddress  string             `bson:"requester_address" json:"requester_address"`
+	AccepterAddress   string             `bson:"accepter_address" json:"accepter_address"`
+	StakeAmount       uint64             `bson:"stake_amount" json:"stake_amount"`
+	Winner            string             `bson:"winner,omitempty" json:"winner,omitempty"`
+	Status            string             `bson:"status" json:"status"`
+	TransactionDigest string             `bson:"transaction_digest,omitempty" json:"transaction_digest,omitempty"`
+	Error             string             `bson:"error,omitempty" json:"error,omitempty"`
+	StakedAt          time.Time          `bson:"staked_at" json:"staked_at"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	TournamentFormatSingleElimination = "single_elimination"
+	TournamentFormatRoundRobin        = "round_robin"
+
+	TournamentStatusRegistering = "registering"
+	TournamentStatusInProgress  = "in_progress"
+	TournamentStatusCompleted   = "completed"
+	TournamentStatusCancelled   = "cancelled"
+
+	// TournamentMatchWaiting is a single elimination match whose players are
+	// not both known yet.
+	TournamentMatchWaiting = "waiting"
+	// TournamentMatchReady has both players and waits for each to have a coin
+	// staged for its stake.
+	TournamentMatchReady    = "ready"
+	TournamentMatchStaking  = "staking"
+	TournamentMatchStaked   = "staked"
+	TournamentMatchComplete = "completed"
+	// TournamentMatchBye advances its only player without a game.
+	TournamentMatchBye = "bye"
+)
+
+// Tournament is a bracket of 1v1 games between registered players. Every
+// match is staked at EntryStake and paid out like any other game.
+type Tournament struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Format      string             `bson:"format" json:"format"`
+	GameType    string             `bson:"game_type,omitempty" json:"game_type,omitempty"`
+	EntryStake  uint64             `bson:"entry_stake" json:"entry_stake"`
+	Capacity    int                `bson:"capacity" json:"capacity"`
+	Registered  int                `bson:"registered" json:"registered"`
+	Status      string             `bson:"status" json:"status"`
+	Winner      string             `bson:"winner,omitempty" json:"winner,omitempty"`
+	CreatedBy   string             `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt   *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// TournamentEntry is one registered player. CoinID is the coin staged for
+// the player's next match; it is used up when that match is staked.
+type TournamentEntry struct {
+	ID           string    `bson:"_id" json:"-"`
+	TournamentID string    `bson:"tournament_id" json:"-"`
+	Address      string    `bson:"address" json:"address"`
+	CoinID       string    `bson:"coin_id,omitempty" json:"coin_id,omitempty"`
+	Seed         int       `bson:"seed,omitempty" json:"seed,omitempty"`
+	Rating       float64   `bson:"rating,omitempty" json:"rating,omitempty"`
+	RegisteredAt time.Time `bson:"registered_at" json:"registered_at"`
+}
+
+// TournamentMatch is one game of the bracket, numbered from 1 in the order
+// it is played. In single elimination its winner moves on to NextMatch, on
+// the NextSlot side.
+type TournamentMatch struct {
+	ID                primitive.ObjectID `bson:"_id" json:"-"`
+	TournamentID      string             `bson:"tournament_id" json:"-"`
+	Number            int                `bson:"number" json:"number"`
+	Round             int                `bson:"round" json:"round"`
+	RequesterAddress  string             `bson:"requester_address,omitempty" json:"requester_address,omitempty"`
+	AccepterAddress   string             `bson:"accepter_address,omitempty" json:"accepter_address,omitempty"`
+	NextMatch         int                `bson:"next_match,omitempty" json:"next_match,omitempty"`
+	NextSlot          string             `bson:"next_slot,omitempty" json:"next_slot,omitempty"`
+	Status            string             `bson:"status" json:"status"`
+	GameID            string             `bson:"game_id,omitempty" json:"game_id,omitempty"`
+	StakeDigest       string             `bson:"stake_digest,omitempty" json:"stake_digest,omitempty"`
+	RequesterScore    *uint64            `bson:"requester_score,omitempty" json:"requester_score,omitempty"`
+	AccepterScore     *uint64            `bson:"accepter_score,omitempty" json:"accepter_score,omitempty"`
+	Winner            string             `bson:"winner,omitempty" json:"winner,omitempty"`
+	TransactionDigest string             `bson:"transaction_digest,omitempty" json:"transaction_digest,omitempty"`
+	LastError         string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	// AdvancePending marks a completed match whose winner the tournament
+	// worker has not moved on yet.
+	AdvancePending bool `bson:"advance_pending,omitempty" json:"-"`
+}
+
+// TournamentStanding totals one player's completed matches. Wins score 3
+// points and draws 1.
+type TournamentStanding struct {
+	Address      string `json:"address"`
+	Played       int    `json:"played"`
+	Wins         int    `json:"wins"`
+	Draws        int    `json:"draws"`
+	Losses       int    `json:"losses"`
+	Points       int    `json:"points"`
+	ScoreFor     uint64 `json:"score_for"`
+	ScoreAgainst uint64 `json:"score_against"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DeliveryStatusPending    = "pending"
+	DeliveryStatusDelivered  = "delivered"
+	DeliveryStatusDeadLetter = "dead_letter"
+)
+
+// WebhookEndpoint is a registered receiver of event notifications. An empty
+// Events list subscribes to every event type.
+type WebhookEndpoint struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	URL         string             `bson:"url" json:"url"`
+	Secret      string             `bson:"secret" json:"secret,omitempty"`
+	Events      []string           `bson:"events" json:"events"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Active      bool               `bson:"active" json:"active"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Accepts reports whether the endpoint wants events of the given type.
+func (e WebhookEndpoint) Accepts(eventType string) bool {
+	if !e.Active {
+		return false
+	}
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, t := range e.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event queued for one endpoint, with the outcome of
+// its latest attempt. Payload is the exact body sent on every attempt.
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EndpointID     primitive.ObjectID `bson:"endpoint_id" json:"endpoint_id"`
+	URL            string             `bson:"url" json:"url"`
+	EventID        string             `bson:"event_id" json:"event_id"`
+	EventType      string             `bson:"event_type" json:"event_type"`
+	Payload        string             `bson:"payload" json:"payload"`
+	Status         string             `bson:"status" json:"status"`
+	Attempts       int                `bson:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LastAttemptAt  *time.Time         `bson:"last_attempt_at,omitempty" json:"last_attempt_at,omitempty"`
+	LastStatusCode int                `bson:"last_status_code,omitempty" json:"last_status_code,omitempty"`
+	LastError      string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	DeliveredAt    *time.Time         `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
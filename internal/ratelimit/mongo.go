@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/interfaces"
+)
+
+// MongoCollection is where MongoStore keeps its counters. A TTL index on
+// expires_at clears old windows.
+const MongoCollection = "rate_limits"
+
+// MongoStore shares budgets across replicas with a sliding window counter:
+// each key has one counter per fixed window, and a request is measured
+// against the current window plus the previous one weighted by how much of it
+// still overlaps the sliding window.
+type MongoStore struct {
+	collection interfaces.MongoCollectionInterface
+	now        func() time.Time
+}
+
+type windowCounter struct {
+	ID    string `bson:"_id"`
+	Count int    `bson:"count"`
+}
+
+func NewMongoStore(collection interfaces.MongoCollectionInterface) *MongoStore {
+	return NewMongoStoreWithClock(collection, time.Now)
+}
+
+// NewMongoStoreWithClock uses now instead of the wall clock, for tests.
+func NewMongoStoreWithClock(collection interfaces.MongoCollectionInterface, now func() time.Time) *MongoStore {
+	return &MongoStore{collection: collection, now: now}
+}
+
+// Take counts the request first so concurrent replicas see each other, then
+// takes it back if it does not fit; denied requests do not use up budget.
+func (m *MongoStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+	now := m.now()
+	start := now.Truncate(limit.Window)
+	currentID := windowID(key, start)
+	previousID := windowID(key, start.Add(-limit.Window))
+
+	_, err := m.collection.UpdateOne(ctx,
+		bson.M{"_id": currentID},
+		bson.M{
+			"$inc": bson.M{"count": 1},
+			"$setOnInsert": bson.M{
+				"key":          key,
+				"window_start": start,
+				"expires_at":   start.Add(2 * limit.Window),
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to count request: %v", err)
+	}
+
+	cursor, err := m.collection.Find(ctx, bson.M{"_id": bson.M{"$in": []string{currentID, previousID}}})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read rate limit windows: %v", err)
+	}
+	var counters []windowCounter
+	if err := cursor.All(ctx, &counters); err != nil {
+		return Result{}, fmt.Errorf("failed to decode rate limit windows: %v", err)
+	}
+	var current, previous int
+	for _, counter := range counters {
+		if counter.ID == currentID {
+			current = counter.Count
+		} else {
+			previous = counter.Count
+		}
+	}
+
+	elapsed := now.Sub(start)
+	overlap := 1 - float64(elapsed)/float64(limit.Window)
+	used := float64(previous)*overlap + float64(current)
+	windowLeft := limit.Window - elapsed
+	result := Result{
+		Allowed:   used <= float64(limit.Requests),
+		Limit:     limit.Requests,
+		Remaining: int(math.Max(0, math.Floor(float64(limit.Requests)-used))),
+		// The current window's count fades out over the next window
+		Reset: windowLeft + limit.Window,
+	}
+	if result.Allowed {
+		return result, nil
+	}
+
+	if _, err := m.collection.UpdateOne(ctx, bson.M{"_id": currentID}, bson.M{"$inc": bson.M{"count": -1}}); err != nil {
+		return Result{}, fmt.Errorf("failed to release denied request: %v", err)
+	}
+	result.RetryAfter = retryAfter(limit, elapsed, previous, current-1)
+	return result, nil
+}
+
+// retryAfter is how long until one more request fits, given the counts
+// without the denied request.
+func retryAfter(limit Limit, elapsed time.Duration, previous, current int) time.Duration {
+	window := limit.Window.Seconds()
+	free := float64(limit.Requests - current - 1)
+	if free >= 0 && previous > 0 {
+		// Fits once enough of the previous window has slid out
+		fadeBy := window * (1 - free/float64(previous))
+		return secondsDuration(fadeBy - elapsed.Seconds())
+	}
+	// Wait for the next window, where the current count becomes the previous
+	fadeBy := window * (1 - float64(limit.Requests-1)/float64(current))
+	return limit.Window - elapsed + secondsDuration(fadeBy)
+}
+
+func windowID(key string, start time.Time) string {
+	return fmt.Sprintf("%s|%d", key, start.Unix())
+}
@@ -0,0 +1,123 @@
+// Package ratelimit counts requests against per-key budgets. A Store decides
+// whether one more request fits; the in-memory store suits a single replica,
+// the Mongo store shares budgets across replicas.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limit allows Requests per Window.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// PerMinute is a limit of n requests a minute.
+func PerMinute(n int) Limit {
+	return Limit{Requests: n, Window: time.Minute}
+}
+
+// Unlimited reports whether the limit lets everything through.
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Window <= 0
+}
+
+// Result is the outcome of one Take.
+type Result struct {
+	Allowed bool
+	Limit   int
+	// Remaining is how many more requests fit right now.
+	Remaining int
+	// Reset is how long until the full budget is available again.
+	Reset time.Duration
+	// RetryAfter is how long a denied caller should wait; zero when allowed.
+	RetryAfter time.Duration
+}
+
+// Store counts a request for key against limit.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// MemoryStore keeps a token bucket per key in process memory. Each bucket
+// holds limit.Requests tokens and refills over limit.Window.
+type MemoryStore struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	limit    Limit
+	lastSeen time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithClock(time.Now)
+}
+
+// NewMemoryStoreWithClock uses now instead of the wall clock, for tests.
+func NewMemoryStoreWithClock(now func() time.Time) *MemoryStore {
+	return &MemoryStore{now: now, buckets: make(map[string]*bucket)}
+}
+
+func (m *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+	now := m.now()
+	perSecond := float64(limit.Requests) / limit.Window.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now, limit.Window)
+	b, found := m.buckets[key]
+	if !found || b.limit != limit {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(perSecond), limit.Requests), limit: limit}
+		m.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	allowed := b.limiter.AllowN(now, 1)
+	tokens := b.limiter.TokensAt(now)
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     secondsDuration((float64(limit.Requests) - tokens) / perSecond),
+	}
+	if !allowed {
+		result.RetryAfter = secondsDuration((1 - tokens) / perSecond)
+	}
+	return result, nil
+}
+
+// sweep drops buckets idle for longer than a window. They have refilled by
+// then, so dropping them loses nothing.
+func (m *MemoryStore) sweep(now time.Time, window time.Duration) {
+	if now.Sub(m.lastSweep) < window {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if now.Sub(b.lastSeen) > b.limit.Window {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,147 @@
+// Package rating implements the Glicko-2 rating system
+// (http://www.glicko.net/glicko/glicko2.pdf). Each settled game is treated as
+// its own rating period.
+package rating
+
+import "math"
+
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+
+	// Tau constrains how much volatility can change per period; Glickman
+	// suggests values between 0.3 and 1.2.
+	Tau = 0.5
+
+	// scale converts between the Glicko and Glicko-2 scales.
+	scale           = 173.7178
+	convergenceTols = 0.000001
+)
+
+// Game outcomes from a player's point of view.
+const (
+	Loss = 0.0
+	Draw = 0.5
+	Win  = 1.0
+)
+
+// Rating is a player's Glicko-2 state on the familiar Glicko scale.
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+// Result is one game against an opponent, scored Win, Draw or Loss.
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// Default is the rating every new player starts from.
+func Default() Rating {
+	return Rating{
+		Rating:     DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+// Update returns the player's rating after one rating period containing the
+// given results. With no results only the deviation grows.
+func Update(player Rating, results []Result) Rating {
+	mu, phi := toGlicko2(player)
+	sigma := player.Volatility
+
+	if len(results) == 0 {
+		phiStar := math.Sqrt(phi*phi + sigma*sigma)
+		return fromGlicko2(mu, phiStar, sigma)
+	}
+
+	var vInv, deltaSum float64
+	for _, result := range results {
+		muJ, phiJ := toGlicko2(result.Opponent)
+		g := gFactor(phiJ)
+		e := expected(mu, muJ, g)
+		vInv += g * g * e * (1 - e)
+		deltaSum += g * (result.Score - e)
+	}
+	v := 1 / vInv
+	delta := v * deltaSum
+
+	sigmaPrime := newVolatility(phi, sigma, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigmaPrime*sigmaPrime)
+	phiPrime := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	muPrime := mu + phiPrime*phiPrime*deltaSum
+
+	return fromGlicko2(muPrime, phiPrime, sigmaPrime)
+}
+
+// ExpectedScore is the probability that a beats b, counting a draw as half a
+// win. Matchmaking can use it to judge how even a pairing is.
+func ExpectedScore(a, b Rating) float64 {
+	muA, phiA := toGlicko2(a)
+	muB, phiB := toGlicko2(b)
+	return expected(muA, muB, gFactor(math.Sqrt(phiA*phiA+phiB*phiB)))
+}
+
+func toGlicko2(r Rating) (mu, phi float64) {
+	return (r.Rating - DefaultRating) / scale, r.Deviation / scale
+}
+
+func fromGlicko2(mu, phi, sigma float64) Rating {
+	deviation := phi * scale
+	if deviation > DefaultDeviation {
+		deviation = DefaultDeviation
+	}
+	return Rating{
+		Rating:     mu*scale + DefaultRating,
+		Deviation:  deviation,
+		Volatility: sigma,
+	}
+}
+
+func gFactor(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expected(mu, muJ, g float64) float64 {
+	return 1 / (1 + math.Exp(-g*(mu-muJ)))
+}
+
+// newVolatility solves for the new volatility with the Illinois algorithm
+// (step 5 of the paper).
+func newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-d)/(2*d*d) - (x-a)/(Tau*Tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*Tau) < 0 {
+			k++
+		}
+		B = a - k*Tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergenceTols {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
@@ -0,0 +1,397 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/service"
+)
+
+const (
+	// adminActorHeader optionally names the operator making an admin request,
+	// for the audit log, when they use the shared admin key. A named key's
+	// holder is recorded instead.
+	adminActorHeader = "X-Admin-Actor"
+	// adminAuditBodyKey carries request body fields a handler wants recorded
+	// alongside the path and query parameters.
+	adminAuditBodyKey = "admin_audit_body"
+)
+
+func setupAdminRoutes(r *gin.Engine, gameService service.GameServiceInterface, cfg *config.Config) {
+	dependencyRetryAfter := time.Duration(cfg.DependencyCheckInterval) * time.Second
+	needsSui := requireDependencies(gameService, dependencyRetryAfter, service.DependencySui)
+	namedKeys, _ := cfg.AdminKeysByName()
+	admin := r.Group("/admin")
+	admin.Use(middleware.AdminAuthMiddleware(cfg.AdminAPIKey, namedKeys), auditAdminActions(gameService))
+	{
+		admin.GET("/wallet/balance", needsSui, handleAdminBalance(gameService))
+		admin.GET("/wallet/coins", needsSui, handleAdminCoins(gameService))
+		admin.GET("/pool", needsSui, handleAdminPool(gameService))
+		admin.GET("/transactions/pending", handleAdminPendingTransactions(gameService))
+		admin.GET("/collections/stats", handleAdminCollectionStats(gameService))
+		admin.POST("/games/cleanup", handleAdminCleanupGames(gameService))
+		admin.POST("/games/:id/refund", needsSui, handleRefundGame(gameService))
+		admin.GET("/games/timeouts", handleListTimeoutSettlements(gameService))
+		admin.GET("/audit", handleAdminAuditLog(gameService))
+		admin.GET("/kill-switch", handleGetKillSwitch(gameService))
+		admin.POST("/kill-switch", handleSetKillSwitch(gameService))
+
+		blocklist := admin.Group("/blocklist")
+		{
+			blocklist.GET("", handleListBlocklist(gameService))
+			blocklist.POST("", handleAddBlocklistEntry(gameService))
+			blocklist.POST("/import", handleImportBlocklist(gameService))
+			blocklist.DELETE("/:address", handleRemoveBlocklistEntry(gameService))
+		}
+
+		gameTypes := admin.Group("/game-types")
+		{
+			gameTypes.PUT("/:name", handleUpsertGameType(gameService))
+			gameTypes.DELETE("/:name", handleDeleteGameType(gameService))
+		}
+
+		heldPayouts := admin.Group("/payouts/held")
+		{
+			heldPayouts.GET("", handleListHeldPayouts(gameService))
+			heldPayouts.POST("/:id/approve", needsSui, handleApproveHeldPayout(gameService))
+			heldPayouts.POST("/:id/reject", handleRejectHeldPayout(gameService))
+		}
+
+		pendingPayouts := admin.Group("/payouts/pending")
+		{
+			pendingPayouts.GET("", handleListPendingPayouts(gameService))
+			pendingPayouts.POST("/:id/approve", needsSui, handleApprovePendingPayout(gameService))
+		}
+
+		tournaments := admin.Group("/tournaments")
+		{
+			tournaments.POST("", handleCreateTournament(gameService))
+			tournaments.POST("/:id/start", handleStartTournament(gameService))
+			tournaments.POST("/:id/cancel", handleCancelTournament(gameService))
+		}
+
+		webhooks := admin.Group("/webhooks")
+		{
+			webhooks.POST("", handleRegisterWebhook(gameService))
+			webhooks.GET("", handleListWebhooks(gameService))
+			webhooks.DELETE("/:id", handleDeleteWebhook(gameService))
+			webhooks.GET("/deliveries", handleListWebhookDeliveries(gameService))
+			webhooks.POST("/deliveries/:id/redeliver", handleRedeliverWebhook(gameService))
+		}
+	}
+}
+
+// auditAdminActions writes every authenticated admin request to the audit log
+// once it has been handled, including the status it was answered with.
+func auditAdminActions(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		params := make(map[string]string)
+		for _, p := range c.Params {
+			params[p.Key] = p.Value
+		}
+		for key, values := range c.Request.URL.Query() {
+			if len(values) > 0 {
+				params[key] = values[0]
+			}
+		}
+		if body, ok := c.Get(adminAuditBodyKey); ok {
+			for key, value := range body.(map[string]string) {
+				params[key] = value
+			}
+		}
+
+		action := c.FullPath()
+		if action == "" {
+			action = c.Request.URL.Path
+		}
+		gameService.RecordAdminAction(&models.AdminAuditEntry{
+			Action:     c.Request.Method + " " + action,
+			Actor:      adminActor(c),
+			RemoteIP:   c.ClientIP(),
+			RequestID:  c.GetString("RequestID"),
+			Params:     params,
+			StatusCode: c.Writer.Status(),
+			DurationMs: time.Since(start).Milliseconds(),
+			CreatedAt:  start,
+		})
+	}
+}
+
+// adminActor names who made an admin request: the holder of the named key it
+// used, else whoever the X-Admin-Actor header claims.
+func adminActor(c *gin.Context) string {
+	if name, ok := middleware.AdminIdentity(c); ok {
+		return name
+	}
+	return c.GetHeader(adminActorHeader)
+}
+
+// @Summary Operator wallet balance
+// @Description Total balance of the operator wallet's coins
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param coin_type query string false "Coin type (default 0x2::sui::SUI)"
+// @Success 200 {object} response.AdminBalanceResponse
+// @Failure 502 {object} response.AdminBalanceResponse
+// @Router /admin/wallet/balance [get]
+func handleAdminBalance(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.GetOperatorBalance(c.Query("coin_type"))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Operator coin inventory
+// @Description Every coin object of a type held by the operator wallet
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param coin_type query string false "Coin type (default 0x2::sui::SUI)"
+// @Success 200 {object} response.AdminCoinsResponse
+// @Failure 502 {object} response.AdminCoinsResponse
+// @Router /admin/wallet/coins [get]
+func handleAdminCoins(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.GetOperatorCoins(c.Query("coin_type"))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Stake pool object
+// @Description Raw on-chain state of the configured stake pool
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} response.AdminPoolResponse
+// @Failure 502 {object} response.AdminPoolResponse
+// @Failure 503 {object} response.AdminPoolResponse
+// @Router /admin/pool [get]
+func handleAdminPool(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.GetStakePoolState()
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Pending transactions
+// @Description Stake and payout transactions not yet confirmed on chain
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} response.AdminTransactionsResponse
+// @Router /admin/transactions/pending [get]
+func handleAdminPendingTransactions(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.ListPendingTransactions()
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Collection counts
+// @Description Document counts for the games, users and transactions collections
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} response.AdminCollectionStatsResponse
+// @Router /admin/collections/stats [get]
+func handleAdminCollectionStats(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.GetCollectionStats()
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Clean up old games
+// @Description Deletes completed games that finished more than older_than_days ago
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param cleanup body request.AdminCleanupRequest true "Cleanup"
+// @Success 200 {object} response.AdminCleanupResponse
+// @Failure 400 {object} response.AdminCleanupResponse
+// @Router /admin/games/cleanup [post]
+func handleAdminCleanupGames(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.AdminCleanupRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.AdminCleanupResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		c.Set(adminAuditBodyKey, map[string]string{"older_than_days": strconv.Itoa(req.OlderThanDays)})
+		resp, err := gameService.CleanupOldGames(req.OlderThanDays)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Admin audit log
+// @Description Admin API requests, newest first
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param action query string false "Action, e.g. POST /admin/games/cleanup"
+// @Param actor query string false "Actor"
+// @Param limit query int false "Maximum entries (default 50, max 500)"
+// @Success 200 {object} response.AdminAuditLogResponse
+// @Router /admin/audit [get]
+func handleAdminAuditLog(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query request.AdminAuditQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, response.AdminAuditLogResponse{
+				Success: false,
+				Error:   "Invalid query: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.ListAdminAuditLog(&query)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Register a webhook
+// @Description Registers an endpoint for signed event notifications; the signing secret is only returned here
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param webhook body request.RegisterWebhookRequest true "Webhook"
+// @Success 200 {object} response.WebhookResponse
+// @Failure 400 {object} response.WebhookResponse
+// @Router /admin/webhooks [post]
+func handleRegisterWebhook(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.RegisterWebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.WebhookResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.RegisterWebhook(&req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary List webhooks
+// @Description Lists registered webhook endpoints without their secrets
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} response.WebhookListResponse
+// @Router /admin/webhooks [get]
+func handleListWebhooks(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.ListWebhooks()
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Delete a webhook
+// @Description Removes a webhook endpoint; its pending deliveries are dead-lettered on their next attempt
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} response.WebhookResponse
+// @Failure 404 {object} response.WebhookResponse
+// @Router /admin/webhooks/{id} [delete]
+func handleDeleteWebhook(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.DeleteWebhook(c.Param("id"))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary List webhook deliveries
+// @Description Returns the delivery log, newest first
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param status query string false "pending, delivered or dead_letter"
+// @Param endpoint_id query string false "Webhook ID"
+// @Param limit query int false "Maximum entries (default 50, max 200)"
+// @Success 200 {object} response.WebhookDeliveryListResponse
+// @Failure 400 {object} response.WebhookDeliveryListResponse
+// @Router /admin/webhooks/deliveries [get]
+func handleListWebhookDeliveries(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query request.WebhookDeliveryQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, response.WebhookDeliveryListResponse{
+				Success: false,
+				Error:   "Invalid query: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.ListWebhookDeliveries(&query)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Redeliver a webhook
+// @Description Resets a delivery's retry budget and sends it again immediately
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} response.WebhookDeliveryResponse
+// @Failure 404 {object} response.WebhookDeliveryResponse
+// @Router /admin/webhooks/deliveries/{id}/redeliver [post]
+func handleRedeliverWebhook(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.RedeliverWebhook(c.Param("id"))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
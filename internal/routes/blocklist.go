@@ -0,0 +1,138 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/screening"
+	"jollfi-gaming-api/internal/service"
+)
+
+// maxBlocklistImportBytes caps the size of a CSV import.
+const maxBlocklistImportBytes = 8 << 20
+
+// @Summary List blocked addresses
+// @Description Blocklist entries, newest first
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param limit query int false "Maximum entries (default 100, max 1000)"
+// @Success 200 {object} response.BlocklistResponse
+// @Router /admin/blocklist [get]
+func handleListBlocklist(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query request.BlocklistQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, response.BlocklistResponse{
+				Success: false,
+				Error:   "Invalid query: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.ListBlocklist(&query)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Block an address
+// @Description Bars an address from staking and from receiving payouts
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param entry body request.BlocklistEntryRequest true "Address and reason"
+// @Success 200 {object} response.BlocklistEntryResponse
+// @Failure 400 {object} response.BlocklistEntryResponse
+// @Router /admin/blocklist [post]
+func handleAddBlocklistEntry(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.BlocklistEntryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.BlocklistEntryResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		if err := validateSuiAddress(req.Address); err != nil {
+			c.JSON(http.StatusBadRequest, response.BlocklistEntryResponse{
+				Success: false,
+				Error:   "Invalid address format: " + err.Error(),
+			})
+			return
+		}
+		c.Set(adminAuditBodyKey, map[string]string{"address": req.Address, "reason": req.Reason})
+		resp, err := gameService.AddBlocklistEntry(&req, adminActor(c))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Import blocked addresses
+// @Description Blocks every address in a CSV body of address,reason lines. A header row and lines starting with # are skipped; invalid addresses are reported and skipped
+// @Accept text/csv
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} response.BlocklistImportResponse
+// @Failure 400 {object} response.BlocklistImportResponse
+// @Router /admin/blocklist/import [post]
+func handleImportBlocklist(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entries, err := screening.ParseCSV(http.MaxBytesReader(c.Writer, c.Request.Body, maxBlocklistImportBytes))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.BlocklistImportResponse{
+				Success: false,
+				Error:   "Invalid CSV: " + err.Error(),
+			})
+			return
+		}
+		valid := make([]screening.Entry, 0, len(entries))
+		var invalid []string
+		for _, entry := range entries {
+			if err := validateSuiAddress(entry.Address); err != nil {
+				invalid = append(invalid, entry.Address+": "+err.Error())
+				continue
+			}
+			valid = append(valid, entry)
+		}
+		c.Set(adminAuditBodyKey, map[string]string{
+			"entries": strconv.Itoa(len(valid)),
+			"invalid": strconv.Itoa(len(invalid)),
+		})
+
+		resp, err := gameService.ImportBlocklist(valid, adminActor(c))
+		resp.Invalid = invalid
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Unblock an address
+// @Description Removes an address from the blocklist. Addresses flagged by the screening provider stay blocked
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param address path string true "Sui address"
+// @Success 200 {object} response.BlocklistEntryResponse
+// @Failure 404 {object} response.BlocklistEntryResponse
+// @Router /admin/blocklist/{address} [delete]
+func handleRemoveBlocklistEntry(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.RemoveBlocklistEntry(c.Param("address"))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,206 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/live"
+	"jollfi-gaming-api/internal/service"
+)
+
+// @Summary Open a challenge
+// @Description Opens a challenge for the player in the token with a stake amount, optionally reserved for one opponent
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer player token"
+// @Param challenge body request.CreateChallengeRequest true "Challenge request"
+// @Success 200 {object} response.ChallengeResponse
+// @Failure 400 {object} response.ChallengeResponse
+// @Failure 401 {object} response.ChallengeResponse
+// @Failure 403 {object} response.ChallengeResponse
+// @Router /challenges [post]
+func handleCreateChallenge(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.CreateChallengeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.ChallengeResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		req.RequesterAddress = playerAddress(c)
+		if err := validateCreateChallengeRequest(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.ChallengeResponse{
+				Success: false,
+				Error:   "Validation failed: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.CreateChallenge(&req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary List open challenges
+// @Description Lists unexpired open challenges, including those created by or reserved for the given address
+// @Produce json
+// @Param address query string false "Sui address"
+// @Success 200 {object} response.ChallengeListResponse
+// @Failure 400 {object} response.ChallengeListResponse
+// @Router /challenges [get]
+func handleListChallenges(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address := c.Query("address")
+		if address != "" {
+			if err := validateSuiAddress(address); err != nil {
+				c.JSON(http.StatusBadRequest, response.ChallengeListResponse{
+					Success: false,
+					Error:   "Invalid address format: " + err.Error(),
+				})
+				return
+			}
+		}
+		resp, err := gameService.ListChallenges(address)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Accept a challenge
+// @Description Accepts an open challenge for the player in the token and stakes both coins on the Sui blockchain
+// @Accept json
+// @Produce json
+// @Param id path string true "Challenge ID"
+// @Param Authorization header string true "Bearer player token"
+// @Param accept body request.AcceptChallengeRequest true "Accept request"
+// @Success 200 {object} response.StakeResponse
+// @Failure 400 {object} response.StakeResponse
+// @Failure 401 {object} response.StakeResponse
+// @Failure 403 {object} response.StakeResponse
+// @Failure 404 {object} response.StakeResponse
+// @Failure 409 {object} response.StakeResponse
+// @Router /challenges/{id}/accept [post]
+func handleAcceptChallenge(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.AcceptChallengeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.StakeResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		req.AccepterAddress = playerAddress(c)
+		if err := validateSuiAddress(req.AccepterAddress); err != nil {
+			c.JSON(http.StatusBadRequest, response.StakeResponse{
+				Success: false,
+				Error:   "Validation failed: invalid player address: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.AcceptChallenge(c.Param("id"), &req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Cancel a challenge
+// @Description Cancels a pending challenge; only its requester may cancel it
+// @Produce json
+// @Param id path string true "Challenge ID"
+// @Param Authorization header string true "Bearer player token"
+// @Success 200 {object} response.ChallengeResponse
+// @Failure 401 {object} response.ChallengeResponse
+// @Failure 403 {object} response.ChallengeResponse
+// @Failure 404 {object} response.ChallengeResponse
+// @Failure 409 {object} response.ChallengeResponse
+// @Router /challenges/{id}/cancel [post]
+func handleCancelChallenge(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := request.CancelChallengeRequest{RequesterAddress: playerAddress(c)}
+		resp, err := gameService.CancelChallenge(c.Param("id"), &req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func validateCreateChallengeRequest(req *request.CreateChallengeRequest) error {
+	if err := validateSuiAddress(req.RequesterAddress); err != nil {
+		return fmt.Errorf("invalid player address: %v", err)
+	}
+	if req.AccepterAddress != "" {
+		if err := validateSuiAddress(req.AccepterAddress); err != nil {
+			return fmt.Errorf("invalid accepter_address: %v", err)
+		}
+		if strings.EqualFold(req.AccepterAddress, req.RequesterAddress) {
+			return fmt.Errorf("requester and accepter addresses cannot be the same")
+		}
+	}
+	if req.ExpiresIn < 0 {
+		return fmt.Errorf("expires_in must not be negative")
+	}
+	return nil
+}
+
+// serviceErrorStatus maps the service's sentinel errors to HTTP statuses.
+func serviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrPayoutHeld), errors.Is(err, service.ErrPayoutPendingApproval):
+		return http.StatusAccepted
+	case errors.Is(err, service.ErrInvalidChallenge), errors.Is(err, service.ErrInvalidTicket),
+		errors.Is(err, service.ErrInvalidWebhook), errors.Is(err, service.ErrInvalidStream),
+		errors.Is(err, service.ErrInvalidAdminRequest), errors.Is(err, service.ErrInvalidLimits),
+		errors.Is(err, service.ErrInvalidPayoutReview), errors.Is(err, service.ErrInvalidTournament),
+		errors.Is(err, service.ErrInvalidGameType), errors.Is(err, service.ErrUnknownGameType),
+		errors.Is(err, service.ErrGameRuleViolation), errors.Is(err, service.ErrPayoutMismatch):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrChallengeForbidden), errors.Is(err, service.ErrTicketForbidden),
+		errors.Is(err, service.ErrMatchForbidden), errors.Is(err, service.ErrStakeRefused),
+		errors.Is(err, service.ErrAddressBlocked), errors.Is(err, service.ErrApproverUnverified):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrChallengeNotFound), errors.Is(err, service.ErrTicketNotFound),
+		errors.Is(err, service.ErrWebhookNotFound), errors.Is(err, service.ErrDeliveryNotFound),
+		errors.Is(err, service.ErrMatchNotFound), errors.Is(err, service.ErrBlocklistEntryNotFound),
+		errors.Is(err, service.ErrHeldPayoutNotFound), errors.Is(err, service.ErrPendingPayoutNotFound),
+		errors.Is(err, service.ErrTournamentNotFound), errors.Is(err, service.ErrTournamentMatchNotFound),
+		errors.Is(err, service.ErrNotRegistered), errors.Is(err, service.ErrGameTypeNotFound),
+		errors.Is(err, service.ErrGameNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrChallengeNotOpen), errors.Is(err, service.ErrTicketNotWaiting),
+		errors.Is(err, service.ErrAlreadyQueued), errors.Is(err, service.ErrMatchNotLive),
+		errors.Is(err, service.ErrHeldPayoutNotPending), errors.Is(err, service.ErrPendingPayoutNotPending),
+		errors.Is(err, service.ErrPendingPayoutExpired), errors.Is(err, service.ErrAlreadyApproved),
+		errors.Is(err, service.ErrTournamentNotOpen), errors.Is(err, service.ErrTournamentFull),
+		errors.Is(err, service.ErrAlreadyRegistered), errors.Is(err, service.ErrTournamentNotRunning),
+		errors.Is(err, service.ErrTournamentMatchNotStaked), errors.Is(err, service.ErrGameNotRefundable),
+		errors.Is(err, service.ErrRefundTooEarly), errors.Is(err, service.ErrGameNotStaked):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrChainUnavailable):
+		return http.StatusBadGateway
+	case errors.Is(err, live.ErrRoomFull), errors.Is(err, service.ErrPoolNotConfigured),
+		errors.Is(err, service.ErrPaused), errors.Is(err, service.ErrWalletBusy),
+		errors.Is(err, service.ErrScreeningUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
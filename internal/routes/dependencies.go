@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/service"
+)
+
+// requireDependencies answers 503 with Retry-After while any of the named
+// dependencies was unreachable at the last check, instead of letting the
+// request wait for a Mongo or RPC timeout.
+func requireDependencies(gameService service.GameServiceInterface, retryAfter time.Duration, names ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, name := range names {
+			if gameService.DependencyAvailable(name) {
+				continue
+			}
+			if retryAfter > 0 {
+				c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+			}
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"success":    false,
+				"error":      "Service temporarily unavailable",
+				"dependency": name,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/service"
+)
+
+// @Summary List game types
+// @Description Game types stakes and payouts can be played under, with their rules
+// @Produce json
+// @Success 200 {object} response.GameTypeListResponse
+// @Router /game-types [get]
+func handleListGameTypes(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.ListGameTypes()
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Set a game type
+// @Description Creates a game type or replaces its rules
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param name path string true "Game type name"
+// @Param rules body request.GameTypeRequest true "Rules"
+// @Success 200 {object} response.GameTypeResponse
+// @Failure 400 {object} response.GameTypeResponse
+// @Router /admin/game-types/{name} [put]
+func handleUpsertGameType(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.GameTypeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.GameTypeResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		c.Set(adminAuditBodyKey, map[string]string{
+			"score_direction": req.ScoreDirection,
+			"tie_handling":    req.TieHandling,
+			"min_stake":       strconv.FormatUint(req.MinStake, 10),
+			"max_stake":       strconv.FormatUint(req.MaxStake, 10),
+			"max_score":       strconv.FormatUint(req.MaxScore, 10),
+		})
+		resp, err := gameService.UpsertGameType(c.Param("name"), &req, adminActor(c))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Delete a game type
+// @Description Removes a game type. Games staked under it cannot be paid out until it is set again
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param name path string true "Game type name"
+// @Success 200 {object} response.GameTypeResponse
+// @Failure 404 {object} response.GameTypeResponse
+// @Router /admin/game-types/{name} [delete]
+func handleDeleteGameType(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.DeleteGameType(c.Param("name"))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
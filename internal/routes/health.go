@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/service"
+)
+
+// suiRPCHealthCheck reports the Sui RPC endpoints. The chain counts as
+// degraded while no endpoint is healthy.
+func suiRPCHealthCheck(gameService service.GameServiceInterface) middleware.HealthCheck {
+	return func() (string, interface{}, bool) {
+		endpoints := gameService.RPCStatus()
+		degraded := true
+		for _, endpoint := range endpoints {
+			if endpoint.Healthy {
+				degraded = false
+			}
+		}
+		return "sui_rpc", endpoints, degraded
+	}
+}
+
+// connectionsHealthCheck reports the dependency monitor's view of Mongo and
+// Sui. The service is degraded while either is unreachable.
+func connectionsHealthCheck(gameService service.GameServiceInterface) middleware.HealthCheck {
+	return func() (string, interface{}, bool) {
+		states := gameService.DependencyStates()
+		degraded := false
+		for _, state := range states {
+			if !state.Available {
+				degraded = true
+			}
+		}
+		return "connections", states, degraded
+	}
+}
+
+// @Summary Liveness probe
+// @Description Reports that the process is up and serving requests; no dependencies are checked
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /livez [get]
+func handleLivez() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"success":   true,
+			"status":    "alive",
+			"timestamp": time.Now().Unix(),
+		})
+	}
+}
+
+// @Summary Readiness probe
+// @Description Mongo ping, Sui checkpoint freshness, operator gas balance and stake pool reachability, each with its status and latency. Results are cached briefly
+// @Produce json
+// @Success 200 {object} response.ReadinessResponse
+// @Failure 503 {object} response.ReadinessResponse
+// @Router /readyz [get]
+func handleReadyz(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result := gameService.Readiness()
+		status := http.StatusOK
+		if !result.Success {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, result)
+	}
+}
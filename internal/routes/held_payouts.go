@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/service"
+)
+
+// @Summary List held payouts
+// @Description Payouts the payout guard parked, newest first
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param status query string false "held_for_review (default), approved or rejected"
+// @Param limit query int false "Maximum entries (default 50, max 500)"
+// @Success 200 {object} response.HeldPayoutListResponse
+// @Router /admin/payouts/held [get]
+func handleListHeldPayouts(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query request.HeldPayoutQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, response.HeldPayoutListResponse{
+				Success: false,
+				Error:   "Invalid query: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.ListHeldPayouts(&query)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Approve a held payout
+// @Description Sends a held payout with the scores as reported. The kill switch and address screening still apply
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param id path string true "Held payout ID"
+// @Param review body request.ReviewPayoutRequest false "Review note"
+// @Success 200 {object} response.HeldPayoutResponse
+// @Failure 404 {object} response.HeldPayoutResponse
+// @Failure 409 {object} response.HeldPayoutResponse
+// @Router /admin/payouts/held/{id}/approve [post]
+func handleApproveHeldPayout(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return handleReviewPayout(gameService.ApproveHeldPayout)
+}
+
+// @Summary Reject a held payout
+// @Description Drops a held payout. Its game returns to staked so the correct result can be reported
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param id path string true "Held payout ID"
+// @Param review body request.ReviewPayoutRequest true "Why it was rejected"
+// @Success 200 {object} response.HeldPayoutResponse
+// @Failure 400 {object} response.HeldPayoutResponse
+// @Failure 404 {object} response.HeldPayoutResponse
+// @Failure 409 {object} response.HeldPayoutResponse
+// @Router /admin/payouts/held/{id}/reject [post]
+func handleRejectHeldPayout(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return handleReviewPayout(gameService.RejectHeldPayout)
+}
+
+func handleReviewPayout(review func(string, string, *request.ReviewPayoutRequest) (*response.HeldPayoutResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.ReviewPayoutRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, response.HeldPayoutResponse{
+					Success: false,
+					Error:   "Invalid request format: " + err.Error(),
+				})
+				return
+			}
+		}
+		c.Set(adminAuditBodyKey, map[string]string{"note": req.Note})
+		resp, err := review(c.Param("id"), adminActor(c), &req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
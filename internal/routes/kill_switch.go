@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/service"
+)
+
+// rejectWhilePaused answers 503 with Retry-After on routes that submit stakes
+// or payouts while the kill switch is engaged.
+func rejectWhilePaused(gameService service.GameServiceInterface, retryAfter time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state := gameService.KillSwitch()
+		if !state.Paused {
+			c.Next()
+			return
+		}
+		if retryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		}
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"error":   service.ErrPaused.Error(),
+			"reason":  state.Reason,
+		})
+		c.Abort()
+	}
+}
+
+// @Summary Kill switch state
+// @Description Whether staking and payouts are paused, why and by whom
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} response.KillSwitchResponse
+// @Router /admin/kill-switch [get]
+func handleGetKillSwitch(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.GetKillSwitch()
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Set the kill switch
+// @Description Pauses or resumes new stakes and payouts on every replica; pausing needs a reason
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-Admin-Actor header string false "Operator name"
+// @Param kill_switch body request.KillSwitchRequest true "Kill switch"
+// @Success 200 {object} response.KillSwitchResponse
+// @Failure 400 {object} response.KillSwitchResponse
+// @Router /admin/kill-switch [post]
+func handleSetKillSwitch(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.KillSwitchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.KillSwitchResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		c.Set(adminAuditBodyKey, map[string]string{
+			"paused": strconv.FormatBool(*req.Paused),
+			"reason": req.Reason,
+		})
+		resp, err := gameService.SetKillSwitch(&req, adminActor(c))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
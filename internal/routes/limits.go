@@ -0,0 +1,135 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/service"
+)
+
+// requireSelf lets a request through only with a player token for the
+// address in the path, so players can manage nobody's limits but their own.
+func requireSelf() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := middleware.ClaimsFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, response.PlayerLimitsResponse{
+				Success: false,
+				Error:   "A player token is required",
+			})
+			c.Abort()
+			return
+		}
+		if claims.Role != middleware.RolePlayer || !strings.EqualFold(claims.Subject, c.Param("address")) {
+			c.JSON(http.StatusForbidden, response.PlayerLimitsResponse{
+				Success: false,
+				Error:   "Players can only manage their own limits",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// @Summary Get stake limits
+// @Description Returns the player's own limits and breaks, the limits enforced after the operator's are applied, and current usage
+// @Produce json
+// @Param address path string true "Sui address"
+// @Success 200 {object} response.PlayerLimitsResponse
+// @Failure 401 {object} response.PlayerLimitsResponse
+// @Failure 403 {object} response.PlayerLimitsResponse
+// @Router /players/{address}/limits [get]
+func handleGetStakeLimits(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.GetStakeLimits(c.Param("address"))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Set stake limits
+// @Description Sets the player's limits per stake, per day, week and month and on net loss. Omitted fields are unchanged and 0 removes a limit. Tighter limits apply at once; looser ones only after the loosen delay
+// @Accept json
+// @Produce json
+// @Param address path string true "Sui address"
+// @Param limits body request.SetStakeLimitsRequest true "Limits"
+// @Success 200 {object} response.PlayerLimitsResponse
+// @Failure 400 {object} response.PlayerLimitsResponse
+// @Failure 401 {object} response.PlayerLimitsResponse
+// @Failure 403 {object} response.PlayerLimitsResponse
+// @Router /players/{address}/limits [put]
+func handleSetStakeLimits(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.SetStakeLimitsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.PlayerLimitsResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.SetStakeLimits(c.Param("address"), &req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Start a cooling-off period
+// @Description Refuses the player's stakes for 1 to 42 days. A running break can be extended but not shortened
+// @Accept json
+// @Produce json
+// @Param address path string true "Sui address"
+// @Param break body request.BreakRequest true "Length in days"
+// @Success 200 {object} response.PlayerLimitsResponse
+// @Failure 400 {object} response.PlayerLimitsResponse
+// @Failure 401 {object} response.PlayerLimitsResponse
+// @Failure 403 {object} response.PlayerLimitsResponse
+// @Router /players/{address}/cooling_off [post]
+func handleStartCoolingOff(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return handleBreak(gameService.StartCoolingOff)
+}
+
+// @Summary Self-exclude
+// @Description Refuses the player's stakes for 180 to 1825 days. A running exclusion can be extended but not shortened
+// @Accept json
+// @Produce json
+// @Param address path string true "Sui address"
+// @Param break body request.BreakRequest true "Length in days"
+// @Success 200 {object} response.PlayerLimitsResponse
+// @Failure 400 {object} response.PlayerLimitsResponse
+// @Failure 401 {object} response.PlayerLimitsResponse
+// @Failure 403 {object} response.PlayerLimitsResponse
+// @Router /players/{address}/self_exclusion [post]
+func handleSelfExclude(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return handleBreak(gameService.SelfExclude)
+}
+
+func handleBreak(start func(string, *request.BreakRequest) (*response.PlayerLimitsResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.BreakRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.PlayerLimitsResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		resp, err := start(c.Param("address"), &req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
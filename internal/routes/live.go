@@ -0,0 +1,129 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/live"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/service"
+)
+
+const (
+	liveWriteWait  = 10 * time.Second
+	livePongWait   = 60 * time.Second
+	livePingPeriod = livePongWait * 9 / 10
+	// liveMaxFrame bounds inbound frames; only small score updates are expected.
+	liveMaxFrame = 4096
+)
+
+var liveUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Callers are authenticated by API key and token, not by origin
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// @Summary Live match channel
+// @Description WebSocket channel for one game. A token with role game_server may send {"type":"score","requester_score":n,"accepter_score":n}; a player token joins as that player; everyone else, or ?role=spectator, joins read-only. Clients receive state, presence, score, stake.completed, transaction.confirmed and game.settled frames.
+// @Param id path string true "Game ID"
+// @Param token query string false "JWT, for clients that cannot set the Authorization header"
+// @Param role query string false "spectator to join read-only with a player token"
+// @Success 101 {string} string "switching protocols"
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /games/live/{id} [get]
+func handleLiveMatch(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, address := live.RoleSpectator, ""
+		if claims, ok := middleware.ClaimsFrom(c); ok && c.Query("role") != live.RoleSpectator {
+			switch claims.Role {
+			case middleware.RoleGameServer:
+				role = live.RoleGameServer
+			case middleware.RolePlayer:
+				role, address = live.RolePlayer, claims.Subject
+			}
+		}
+
+		client, err := gameService.JoinMatch(c.Param("id"), role, address)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		defer client.Close()
+
+		conn, err := liveUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// Upgrade has already written the error response
+			return
+		}
+		defer conn.Close()
+
+		go writeLiveMessages(conn, client)
+
+		conn.SetReadLimit(liveMaxFrame)
+		conn.SetReadDeadline(time.Now().Add(livePongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(livePongWait))
+		})
+		for {
+			var update request.MatchScoreUpdate
+			if err := conn.ReadJSON(&update); err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					log.Printf("⚠️  Live connection to game %s closed: %v", client.GameID, err)
+				}
+				return
+			}
+			if update.Type != live.MessageScore {
+				client.Send(liveError(client, "unsupported message type"))
+				continue
+			}
+			if err := gameService.PushMatchScore(client, &update); err != nil {
+				client.Send(liveError(client, err.Error()))
+			}
+		}
+	}
+}
+
+// writeLiveMessages is the connection's only writer. It stops when the client
+// leaves the room, closing the socket so the read loop ends too.
+func writeLiveMessages(conn *websocket.Conn, client *live.Client) {
+	ticker := time.NewTicker(livePingPeriod)
+	defer ticker.Stop()
+	defer conn.Close()
+
+	for {
+		select {
+		case msg := <-client.C:
+			conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-client.Done:
+			conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		}
+	}
+}
+
+func liveError(client *live.Client, message string) live.Message {
+	return live.Message{
+		Type:      live.MessageError,
+		GameID:    client.GameID,
+		Data:      map[string]interface{}{"error": message},
+		Timestamp: time.Now(),
+	}
+}
@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/service"
+)
+
+// @Summary Join the matchmaking queue
+// @Description Queues the player in the token for a stake tier; matched players are staked automatically
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer player token"
+// @Param ticket body request.JoinQueueRequest true "Join request"
+// @Success 200 {object} response.QueueTicketResponse
+// @Failure 400 {object} response.QueueTicketResponse
+// @Failure 401 {object} response.QueueTicketResponse
+// @Failure 403 {object} response.QueueTicketResponse
+// @Failure 409 {object} response.QueueTicketResponse
+// @Router /matchmaking/queue [post]
+func handleJoinQueue(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.JoinQueueRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.QueueTicketResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		req.Address = playerAddress(c)
+		if err := validateSuiAddress(req.Address); err != nil {
+			c.JSON(http.StatusBadRequest, response.QueueTicketResponse{
+				Success: false,
+				Error:   "Invalid address format: " + err.Error(),
+			})
+			return
+		}
+		if req.RatingWindow < 0 {
+			c.JSON(http.StatusBadRequest, response.QueueTicketResponse{
+				Success: false,
+				Error:   "rating_window must not be negative",
+			})
+			return
+		}
+		resp, err := gameService.JoinQueue(&req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Get a queue ticket
+// @Description Retrieves a matchmaking ticket; once matched it carries the opponent and game_id
+// @Produce json
+// @Param id path string true "Ticket ID"
+// @Success 200 {object} response.QueueTicketResponse
+// @Failure 404 {object} response.QueueTicketResponse
+// @Router /matchmaking/queue/{id} [get]
+func handleGetQueueTicket(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.GetQueueTicket(c.Param("id"))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Leave the matchmaking queue
+// @Description Removes a waiting ticket from the queue; only its player may remove it
+// @Produce json
+// @Param id path string true "Ticket ID"
+// @Param Authorization header string true "Bearer player token"
+// @Success 200 {object} response.QueueTicketResponse
+// @Failure 401 {object} response.QueueTicketResponse
+// @Failure 403 {object} response.QueueTicketResponse
+// @Failure 409 {object} response.QueueTicketResponse
+// @Router /matchmaking/queue/{id}/leave [post]
+func handleLeaveQueue(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := request.LeaveQueueRequest{Address: playerAddress(c)}
+		resp, err := gameService.LeaveQueue(c.Param("id"), &req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
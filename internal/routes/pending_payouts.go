@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/service"
+)
+
+// @Summary List pending payouts
+// @Description Payouts above the approval threshold with their approvals so far, newest first
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param status query string false "pending_approval (default), sending, approved or expired"
+// @Param limit query int false "Maximum entries (default 50, max 500)"
+// @Success 200 {object} response.PendingPayoutListResponse
+// @Router /admin/payouts/pending [get]
+func handleListPendingPayouts(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query request.PendingPayoutQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, response.PendingPayoutListResponse{
+				Success: false,
+				Error:   "Invalid query: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.ListPendingPayouts(&query)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Approve a pending payout
+// @Description Records the calling admin's approval. The payout is sent once enough distinct admins have approved; only named admin keys can approve
+// @Produce json
+// @Param X-Admin-Key header string true "Named admin key"
+// @Param id path string true "Pending payout ID"
+// @Success 200 {object} response.PendingPayoutResponse
+// @Failure 403 {object} response.PendingPayoutResponse
+// @Failure 404 {object} response.PendingPayoutResponse
+// @Failure 409 {object} response.PendingPayoutResponse
+// @Router /admin/payouts/pending/{id}/approve [post]
+func handleApprovePendingPayout(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The X-Admin-Actor header is not proof of identity, so only the
+		// holder of a named key counts as an approver
+		admin, _ := middleware.AdminIdentity(c)
+		resp, err := gameService.ApprovePendingPayout(c.Param("id"), admin)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/service"
+)
+
+// @Summary Stake pool state
+// @Description Decoded on-chain state of the stake pool: balance, fee, admin, paused flag and game count
+// @Produce json
+// @Success 200 {object} response.PoolResponse
+// @Failure 502 {object} response.PoolResponse
+// @Router /pool [get]
+func handleGetPool(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.GetPool()
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/service"
+)
+
+// @Summary Refund a stuck game
+// @Description Returns both stakes of a game left in staked for longer than STUCK_GAME_TIMEOUT. The kill switch and address screening still apply
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param id path string true "Game ID"
+// @Param refund body request.RefundGameRequest false "Why the game is refunded"
+// @Success 200 {object} response.RefundGameResponse
+// @Failure 404 {object} response.RefundGameResponse
+// @Failure 409 {object} response.RefundGameResponse
+// @Router /admin/games/{id}/refund [post]
+func handleRefundGame(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.RefundGameRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, response.RefundGameResponse{
+					Success: false,
+					Error:   "Invalid request format: " + err.Error(),
+				})
+				return
+			}
+		}
+		c.Set(adminAuditBodyKey, map[string]string{"reason": req.Reason})
+		resp, err := gameService.RefundStuckGame(c.Param("id"), adminActor(c), &req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"jollfi-gaming-api/internal/dto/request"
 	"jollfi-gaming-api/internal/dto/response"
 	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/ratelimit"
 	"jollfi-gaming-api/internal/service"
 )
 
@@ -33,6 +34,12 @@ func SetupRoutes(gameService service.GameServiceInterface, cfg *config.Config) *
 	}
 	r := gin.New()
 	r.RedirectTrailingSlash = false
+	// Only trusted proxies may set the client IP through X-Forwarded-For;
+	// without any the connection's address is used
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Printf("⚠️  Invalid TRUSTED_PROXIES, trusting none: %v", err)
+		r.SetTrustedProxies(nil)
+	}
 	r.Use(func(c *gin.Context) {
 		c.Set("config", cfg)
 		c.Next()
@@ -40,14 +47,15 @@ func SetupRoutes(gameService service.GameServiceInterface, cfg *config.Config) *
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	setupMiddleware(r, cfg)
+	setupMiddleware(r, gameService, cfg)
 	setupAPIRoutes(r, gameService, cfg)
-	setupUtilityRoutes(r, cfg)
+	setupAdminRoutes(r, gameService, cfg)
+	setupUtilityRoutes(r, gameService, cfg)
 	setupErrorHandlers(r)
 	return r
 }
 
-func setupMiddleware(r *gin.Engine, cfg *config.Config) {
+func setupMiddleware(r *gin.Engine, gameService service.GameServiceInterface, cfg *config.Config) {
 	if cfg.EnableLogging {
 		r.Use(middleware.LoggerMiddleware())
 	}
@@ -68,35 +76,119 @@ func setupMiddleware(r *gin.Engine, cfg *config.Config) {
 			c.Next()
 		})
 	}
-	if cfg.RateLimit > 0 {
-		r.Use(middleware.RateLimitMiddleware(cfg.RateLimit))
-	}
+	r.Use(middleware.RateLimit(
+		gameService.RateLimitStore(),
+		middleware.RateLimitKey(cfg.JWTSecret, cfg.APIKey, cfg.RateLimitKeys()...),
+		rateLimitPolicy(cfg),
+	))
 	if cfg.APIKey != "" {
 		r.Use(middleware.APIKeyMiddleware(cfg.APIKey))
 	}
 	r.Use(middleware.MetricsMiddleware())
 }
 
+// rateLimitPolicy gives each route group its own budget: POST routes under
+// /api/v1 (stakes, payouts, challenges, matchmaking) are the writes group and
+// /admin the admin group; everything else shares the default. Probes are not
+// limited so a busy node is never restarted for it.
+func rateLimitPolicy(cfg *config.Config) func(c *gin.Context) (middleware.RateLimitPolicy, bool) {
+	writes := middleware.RateLimitPolicy{Name: "writes", Limit: ratelimit.PerMinute(cfg.RateLimitWrites)}
+	admin := middleware.RateLimitPolicy{Name: "admin", Limit: ratelimit.PerMinute(cfg.RateLimitAdmin)}
+	general := middleware.RateLimitPolicy{Name: "default", Limit: ratelimit.PerMinute(cfg.RateLimit)}
+	return func(c *gin.Context) (middleware.RateLimitPolicy, bool) {
+		path := c.FullPath()
+		switch {
+		case path == "/health" || path == "/livez" || path == "/readyz":
+			return middleware.RateLimitPolicy{}, false
+		case strings.HasPrefix(path, "/admin"):
+			return admin, true
+		case c.Request.Method == http.MethodPost && strings.HasPrefix(path, "/api/v1/"):
+			return writes, true
+		}
+		return general, true
+	}
+}
+
 func setupAPIRoutes(r *gin.Engine, gameService service.GameServiceInterface, cfg *config.Config) {
+	retryAfter := time.Duration(cfg.KillSwitchRetryAfter) * time.Second
+	dependencyRetryAfter := time.Duration(cfg.DependencyCheckInterval) * time.Second
+	needsMongo := requireDependencies(gameService, dependencyRetryAfter, service.DependencyMongo)
+	needsSui := requireDependencies(gameService, dependencyRetryAfter, service.DependencySui)
+	needsBoth := requireDependencies(gameService, dependencyRetryAfter, service.DependencyMongo, service.DependencySui)
 	api := r.Group("/api/v1")
 	{
 		gamesWithValidation := api.Group("/games")
 		gamesWithValidation.Use(middleware.ValidationMiddleware())
 		{
-			gamesWithValidation.POST("/pay_winner", handlePayWinner(gameService))
-			gamesWithValidation.GET("/stakes/:address", handleGetStakeHistory(gameService))
-			gamesWithValidation.GET("/history/:address", handleGetGameHistory(gameService))
-			gamesWithValidation.GET("/stats", handleGetGameStats(gameService))
+			gamesWithValidation.POST("/pay_winner", needsBoth, rejectWhilePaused(gameService, retryAfter), middleware.JWTMiddleware(cfg.JWTSecret), requireGameServer(), handlePayWinner(gameService))
+			gamesWithValidation.GET("/stakes/:address", needsMongo, handleGetStakeHistory(gameService))
+			gamesWithValidation.GET("/history/:address", needsMongo, handleGetGameHistory(gameService))
+			gamesWithValidation.GET("/stats", needsMongo, handleGetGameStats(gameService))
+			gamesWithValidation.POST("/:id/report", needsMongo, middleware.JWTMiddleware(cfg.JWTSecret), requirePlayer(), handleReportScore(gameService))
 		}
 		gamesWithoutValidation := api.Group("/games")
 		{
-			gamesWithoutValidation.POST("/stake", handleStakeGame(gameService))
+			gamesWithoutValidation.POST("/stake", needsBoth, rejectWhilePaused(gameService, retryAfter), handleStakeGame(gameService))
+			gamesWithoutValidation.GET("/stream", handleGameStream(gameService, time.Duration(cfg.StreamHeartbeatInterval)*time.Second))
+		}
+		liveMatches := api.Group("/games/live")
+		liveMatches.Use(
+			needsMongo,
+			middleware.JWTMiddleware(cfg.JWTSecret),
+			middleware.ConnectionLimitMiddleware(cfg.LiveMaxConnections, cfg.LiveMaxConnectionsPerClient),
+		)
+		{
+			liveMatches.GET("/:id", handleLiveMatch(gameService))
+		}
+		players := api.Group("/players")
+		players.Use(needsMongo)
+		{
+			players.GET("/:address", handleGetPlayerProfile(gameService))
+			players.GET("/:address/rating", handleGetPlayerRating(gameService))
+		}
+		limits := api.Group("/players/:address")
+		limits.Use(needsMongo, middleware.JWTMiddleware(cfg.JWTSecret), requireSelf())
+		{
+			limits.GET("/limits", handleGetStakeLimits(gameService))
+			limits.PUT("/limits", handleSetStakeLimits(gameService))
+			limits.POST("/cooling_off", handleStartCoolingOff(gameService))
+			limits.POST("/self_exclusion", handleSelfExclude(gameService))
+		}
+		api.GET("/leaderboard", needsMongo, handleGetLeaderboard(gameService))
+		api.GET("/pool", needsSui, handleGetPool(gameService))
+		api.GET("/game-types", needsMongo, handleListGameTypes(gameService))
+		asPlayer := []gin.HandlerFunc{middleware.JWTMiddleware(cfg.JWTSecret), requirePlayer()}
+		challenges := api.Group("/challenges")
+		challenges.Use(needsMongo)
+		{
+			challenges.POST("", append(asPlayer, handleCreateChallenge(gameService))...)
+			challenges.GET("", handleListChallenges(gameService))
+			challenges.POST("/:id/accept", append(asPlayer, needsSui, rejectWhilePaused(gameService, retryAfter), handleAcceptChallenge(gameService))...)
+			challenges.POST("/:id/cancel", append(asPlayer, handleCancelChallenge(gameService))...)
+		}
+		tournaments := api.Group("/tournaments")
+		tournaments.Use(needsMongo)
+		{
+			tournaments.GET("", handleListTournaments(gameService))
+			tournaments.GET("/:id", handleGetTournament(gameService))
+			tournaments.POST("/:id/register", append(asPlayer, handleRegisterForTournament(gameService))...)
+			tournaments.POST("/:id/coin", append(asPlayer, handleSetTournamentCoin(gameService))...)
+			tournaments.POST("/:id/matches/:match/result", needsSui, rejectWhilePaused(gameService, retryAfter), middleware.JWTMiddleware(cfg.JWTSecret), requireGameServer(), handleReportTournamentResult(gameService))
+		}
+		queue := api.Group("/matchmaking/queue")
+		queue.Use(needsMongo)
+		{
+			queue.POST("", append(asPlayer, handleJoinQueue(gameService))...)
+			queue.GET("/:id", handleGetQueueTicket(gameService))
+			queue.POST("/:id/leave", append(asPlayer, handleLeaveQueue(gameService))...)
 		}
 	}
 }
 
-func setupUtilityRoutes(r *gin.Engine, cfg *config.Config) {
-	r.GET("/health", middleware.HealthCheckHandler())
+func setupUtilityRoutes(r *gin.Engine, gameService service.GameServiceInterface, cfg *config.Config) {
+	r.GET("/health", middleware.HealthCheckHandler(suiRPCHealthCheck(gameService), connectionsHealthCheck(gameService)))
+	r.GET("/livez", handleLivez())
+	r.GET("/readyz", handleReadyz(gameService))
 	r.POST("/health", func(c *gin.Context) {
 		log.Printf("Explicit POST handler for /health triggered")
 		c.JSON(http.StatusMethodNotAllowed, gin.H{
@@ -137,8 +229,28 @@ func setupUtilityRoutes(r *gin.Engine, cfg *config.Config) {
 					"pay_winner":    "POST /api/v1/games/pay_winner",
 					"stake_history": "GET /api/v1/games/stakes/:address",
 					"game_history":  "GET /api/v1/games/history/:address",
+					"report_score":  "POST /api/v1/games/:id/report",
 					"stats":         "GET /api/v1/games/stats",
+					"player":        "GET /api/v1/players/:address",
+					"rating":        "GET /api/v1/players/:address/rating",
+					"limits":        "GET|PUT /api/v1/players/:address/limits",
+					"cooling_off":   "POST /api/v1/players/:address/cooling_off",
+					"exclusion":     "POST /api/v1/players/:address/self_exclusion",
+					"leaderboard":   "GET /api/v1/leaderboard",
+					"challenges":    "GET|POST /api/v1/challenges",
+					"accept":        "POST /api/v1/challenges/:id/accept",
+					"cancel":        "POST /api/v1/challenges/:id/cancel",
+					"matchmaking":   "POST /api/v1/matchmaking/queue",
+					"queue_ticket":  "GET /api/v1/matchmaking/queue/:id",
+					"leave_queue":   "POST /api/v1/matchmaking/queue/:id/leave",
+					"stream":        "GET /api/v1/games/stream?address=",
+					"live_match":    "GET /api/v1/games/live/:id (WebSocket)",
+					"pool":          "GET /api/v1/pool",
+					"game_types":    "GET /api/v1/game-types",
+					"admin":         "/admin (X-Admin-Key)",
 					"health":        "GET /health",
+					"livez":         "GET /livez",
+					"readyz":        "GET /readyz",
 				},
 			},
 		})
@@ -182,6 +294,7 @@ type tempStakeRequest struct {
 	RequesterAddress string `json:"requester_address"`
 	AccepterAddress  string `json:"accepter_address"`
 	StakeAmount      int64  `json:"stake_amount"`
+	GameType         string `json:"game_type"`
 }
 
 // @Summary Stake in a game
@@ -232,6 +345,7 @@ func handleStakeGame(gameService service.GameServiceInterface) gin.HandlerFunc {
 			RequesterAddress: tempReq.RequesterAddress,
 			AccepterAddress:  tempReq.AccepterAddress,
 			StakeAmount:      uint64(tempReq.StakeAmount),
+			GameType:         tempReq.GameType,
 		}
 		if err := validateStakeRequest(&req); err != nil {
 			c.JSON(http.StatusBadRequest, response.StakeResponse{
@@ -242,7 +356,7 @@ func handleStakeGame(gameService service.GameServiceInterface) gin.HandlerFunc {
 		}
 		resp, err := gameService.StakeGame(&req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, resp)
+			c.JSON(serviceErrorStatus(err), resp)
 			return
 		}
 		c.JSON(http.StatusOK, resp)
@@ -253,9 +367,13 @@ func handleStakeGame(gameService service.GameServiceInterface) gin.HandlerFunc {
 // @Description Processes winner payment on the Sui blockchain
 // @Accept json
 // @Produce json
+// @Param Authorization header string true "Bearer game server token"
 // @Param pay_winner body request.PayWinnerRequest true "Pay winner request"
 // @Success 200 {object} response.PayWinnerResponse
+// @Success 202 {object} response.PayWinnerResponse "Held for review by the payout guard"
 // @Failure 400 {object} response.PayWinnerResponse
+// @Failure 401 {object} response.PayWinnerResponse
+// @Failure 403 {object} response.PayWinnerResponse
 // @Router /games/pay_winner [post]
 func handlePayWinner(gameService service.GameServiceInterface) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -283,15 +401,85 @@ func handlePayWinner(gameService service.GameServiceInterface) gin.HandlerFunc {
 			})
 			return
 		}
+		req.GameServer = gameServerID(c)
 		resp, err := gameService.PayWinner(&req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, resp)
+			c.JSON(serviceErrorStatus(err), resp)
 			return
 		}
 		c.JSON(http.StatusOK, resp)
 	}
 }
 
+// requireGameServer lets a request through only with a game server token, so
+// every reported result is paid out on behalf of a known game server.
+func requireGameServer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := middleware.ClaimsFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, response.PayWinnerResponse{
+				Success: false,
+				Error:   "A game server token is required",
+			})
+			c.Abort()
+			return
+		}
+		if claims.Role != middleware.RoleGameServer || claims.Subject == "" {
+			c.JSON(http.StatusForbidden, response.PayWinnerResponse{
+				Success: false,
+				Error:   "Only game servers can report results",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// requirePlayer lets a request through only with a player token. Handlers act
+// for the token's subject, never for an address in the body.
+func requirePlayer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := middleware.ClaimsFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "A player token is required",
+			})
+			c.Abort()
+			return
+		}
+		if claims.Role != middleware.RolePlayer || claims.Subject == "" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Only players can do this",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// playerAddress returns the address of the player requirePlayer checked.
+func playerAddress(c *gin.Context) string {
+	claims, ok := middleware.ClaimsFrom(c)
+	if !ok {
+		return ""
+	}
+	return claims.Subject
+}
+
+// gameServerID names the game server reporting a result, for the payout
+// guard's daily cap: the subject of the token requireGameServer checked.
+func gameServerID(c *gin.Context) string {
+	claims, ok := middleware.ClaimsFrom(c)
+	if !ok {
+		return ""
+	}
+	return middleware.RoleGameServer + ":" + claims.Subject
+}
+
 // @Summary Get stake history
 // @Description Retrieves stake history for a given address
 // @Produce json
@@ -358,6 +546,110 @@ func handleGetGameHistory(gameService service.GameServiceInterface) gin.HandlerF
 	}
 }
 
+// @Summary Get player profile
+// @Description Retrieves lifetime stats, streaks and head-to-head records for a given address
+// @Produce json
+// @Param address path string true "Sui address"
+// @Success 200 {object} response.PlayerProfileResponse
+// @Failure 400 {object} response.PlayerProfileResponse
+// @Router /players/{address} [get]
+func handleGetPlayerProfile(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address := c.Param("address")
+		if address == "" {
+			c.JSON(http.StatusBadRequest, response.PlayerProfileResponse{
+				Success: false,
+				Error:   "Address parameter is required",
+			})
+			return
+		}
+		if err := validateSuiAddress(address); err != nil {
+			c.JSON(http.StatusBadRequest, response.PlayerProfileResponse{
+				Success: false,
+				Error:   "Invalid address format: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.GetPlayerProfile(address)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Get player rating
+// @Description Retrieves the Glicko-2 rating and recent rating history (up to 50 games) for a given address
+// @Produce json
+// @Param address path string true "Sui address"
+// @Success 200 {object} response.PlayerRatingResponse
+// @Failure 400 {object} response.PlayerRatingResponse
+// @Router /players/{address}/rating [get]
+func handleGetPlayerRating(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address := c.Param("address")
+		if address == "" {
+			c.JSON(http.StatusBadRequest, response.PlayerRatingResponse{
+				Success: false,
+				Error:   "Address parameter is required",
+			})
+			return
+		}
+		if err := validateSuiAddress(address); err != nil {
+			c.JSON(http.StatusBadRequest, response.PlayerRatingResponse{
+				Success: false,
+				Error:   "Invalid address format: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.GetPlayerRating(address)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Get leaderboard
+// @Description Retrieves ranked players for a metric and period, with an optional player's own rank
+// @Produce json
+// @Param metric query string false "wins, profit or volume" default(wins)
+// @Param period query string false "day, week, month or all" default(all)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param address query string false "Sui address to include the player's own rank"
+// @Param game_type query string false "Rank games of one game type only"
+// @Success 200 {object} response.LeaderboardResponse
+// @Failure 400 {object} response.LeaderboardResponse
+// @Router /leaderboard [get]
+func handleGetLeaderboard(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query request.LeaderboardQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, response.LeaderboardResponse{
+				Success: false,
+				Error:   "Invalid query parameters: " + err.Error(),
+			})
+			return
+		}
+		if err := validateLeaderboardQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, response.LeaderboardResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.GetLeaderboard(&query)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
 // @Summary Get game stats
 // @Description Retrieves game statistics (placeholder)
 // @Produce json
@@ -428,6 +720,36 @@ func validatePayWinnerRequest(req *request.PayWinnerRequest) error {
 	return nil
 }
 
+func validateLeaderboardQuery(query *request.LeaderboardQuery) error {
+	if query.Metric != "" && !contains(service.LeaderboardMetrics, query.Metric) {
+		return fmt.Errorf("metric must be one of: %s", strings.Join(service.LeaderboardMetrics, ", "))
+	}
+	if query.Period != "" && !contains(service.LeaderboardPeriods, query.Period) {
+		return fmt.Errorf("period must be one of: %s", strings.Join(service.LeaderboardPeriods, ", "))
+	}
+	if query.Page < 0 {
+		return fmt.Errorf("page must be greater than 0")
+	}
+	if query.Limit < 0 || query.Limit > service.MaxLeaderboardLimit {
+		return fmt.Errorf("limit must be between 1 and %d", service.MaxLeaderboardLimit)
+	}
+	if query.Address != "" {
+		if err := validateSuiAddress(query.Address); err != nil {
+			return fmt.Errorf("invalid address: %v", err)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func validateSuiAddress(address string) error {
 	if len(address) < 40 || len(address) > 66 {
 		return fmt.Errorf("invalid address length")
@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/service"
+	"jollfi-gaming-api/internal/stream"
+)
+
+const (
+	defaultStreamHeartbeat = 15 * time.Second
+	// streamRetry is the reconnect delay, in milliseconds, suggested to clients.
+	streamRetry = 3000
+)
+
+// @Summary Stream game updates
+// @Description Server-Sent Events for stakes, settlements and transaction outcomes involving an address. Resume with the Last-Event-ID header (or last_event_id query parameter).
+// @Produce text/event-stream
+// @Param address query string true "Sui address"
+// @Param last_event_id query string false "Last event ID seen"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} map[string]interface{}
+// @Router /games/stream [get]
+func handleGameStream(gameService service.GameServiceInterface, heartbeat time.Duration) gin.HandlerFunc {
+	if heartbeat <= 0 {
+		heartbeat = defaultStreamHeartbeat
+	}
+	return func(c *gin.Context) {
+		address := c.Query("address")
+		if err := validateSuiAddress(address); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid address format: " + err.Error(),
+			})
+			return
+		}
+		// Browsers only send Last-Event-ID on automatic reconnects, so a
+		// client opening a fresh connection may pass it as a query parameter
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("last_event_id")
+		}
+
+		sub, err := gameService.SubscribeGameStream(address, lastEventID)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		defer sub.Close()
+
+		// The stream outlives the server's write timeout
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry)
+		for _, msg := range sub.Replay {
+			writeStreamMessage(c.Writer, msg)
+		}
+		c.Writer.Flush()
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-sub.Done:
+				// Dropped for falling behind; the client resumes from its last ID
+				return
+			case msg := <-sub.C:
+				writeStreamMessage(c.Writer, msg)
+				c.Writer.Flush()
+			case <-ticker.C:
+				fmt.Fprint(c.Writer, ": heartbeat\n\n")
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+func writeStreamMessage(w io.Writer, msg stream.Message) {
+	data, err := json.Marshal(msg.Event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", msg.ID, msg.Event.Type, data)
+}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/service"
+)
+
+// @Summary Report your score
+// @Description Records a player's own score for a staked game. Used to settle the game if it times out without a result
+// @Accept json
+// @Produce json
+// @Param id path string true "Game ID"
+// @Param Authorization header string true "Bearer player token"
+// @Param report body request.ScoreReportRequest true "Score"
+// @Success 200 {object} response.ScoreReportResponse
+// @Failure 401 {object} response.ScoreReportResponse
+// @Failure 403 {object} response.ScoreReportResponse
+// @Failure 404 {object} response.ScoreReportResponse
+// @Failure 409 {object} response.ScoreReportResponse
+// @Router /games/{id}/report [post]
+func handleReportScore(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.ScoreReportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.ScoreReportResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		req.Address = playerAddress(c)
+		resp, err := gameService.ReportScore(c.Param("id"), &req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary List timeout settlements
+// @Description What the timeout scheduler did with games left in staked, and why, newest first
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param game_id query string false "Only this game"
+// @Param limit query int false "Maximum entries (default 50, max 500)"
+// @Success 200 {object} response.TimeoutSettlementListResponse
+// @Router /admin/games/timeouts [get]
+func handleListTimeoutSettlements(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query request.TimeoutSettlementQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, response.TimeoutSettlementListResponse{
+				Success: false,
+				Error:   "Invalid query: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.ListTimeoutSettlements(&query)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,229 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/service"
+)
+
+// @Summary List tournaments
+// @Description Tournaments, newest first
+// @Produce json
+// @Param status query string false "registering, in_progress, completed or cancelled"
+// @Param limit query int false "Maximum entries (default 50, max 200)"
+// @Success 200 {object} response.TournamentListResponse
+// @Router /tournaments [get]
+func handleListTournaments(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query request.TournamentQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, response.TournamentListResponse{
+				Success: false,
+				Error:   "Invalid query: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.ListTournaments(&query)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Get a tournament
+// @Description A tournament with its players, bracket, standings and prize pool
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} response.TournamentResponse
+// @Failure 404 {object} response.TournamentResponse
+// @Router /tournaments/{id} [get]
+func handleGetTournament(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.GetTournament(c.Param("id"))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Register for a tournament
+// @Description Enters the player in the token with the coin to stake in their first match
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param Authorization header string true "Bearer player token"
+// @Param entry body request.TournamentEntryRequest true "Coin"
+// @Success 200 {object} response.TournamentResponse
+// @Failure 401 {object} response.TournamentResponse
+// @Failure 403 {object} response.TournamentResponse
+// @Failure 409 {object} response.TournamentResponse
+// @Router /tournaments/{id}/register [post]
+func handleRegisterForTournament(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return handleTournamentEntry(gameService.RegisterForTournament)
+}
+
+// @Summary Stage a coin for the next match
+// @Description Sets the coin the player in the token stakes in their next match, and stakes that match if the opponent's coin is staged too
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param Authorization header string true "Bearer player token"
+// @Param entry body request.TournamentEntryRequest true "Coin"
+// @Success 200 {object} response.TournamentResponse
+// @Failure 401 {object} response.TournamentResponse
+// @Failure 403 {object} response.TournamentResponse
+// @Failure 404 {object} response.TournamentResponse
+// @Router /tournaments/{id}/coin [post]
+func handleSetTournamentCoin(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return handleTournamentEntry(gameService.SetTournamentCoin)
+}
+
+func handleTournamentEntry(enter func(string, *request.TournamentEntryRequest) (*response.TournamentResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.TournamentEntryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.TournamentResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		req.Address = playerAddress(c)
+		if err := validateSuiAddress(req.Address); err != nil {
+			c.JSON(http.StatusBadRequest, response.TournamentResponse{
+				Success: false,
+				Error:   "Invalid address format: " + err.Error(),
+			})
+			return
+		}
+		resp, err := enter(c.Param("id"), &req)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Report a tournament match result
+// @Description Pays out a staked match like pay_winner, including the payout guard and approval threshold. The bracket moves on once the payout is sent
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param match path int true "Match number"
+// @Param Authorization header string true "Bearer game server token"
+// @Param result body request.TournamentResultRequest true "Scores"
+// @Success 200 {object} response.PayWinnerResponse
+// @Success 202 {object} response.PayWinnerResponse
+// @Failure 400 {object} response.PayWinnerResponse
+// @Failure 401 {object} response.PayWinnerResponse
+// @Failure 403 {object} response.PayWinnerResponse
+// @Failure 409 {object} response.PayWinnerResponse
+// @Router /tournaments/{id}/matches/{match}/result [post]
+func handleReportTournamentResult(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		number, err := strconv.Atoi(c.Param("match"))
+		if err != nil || number < 1 {
+			c.JSON(http.StatusBadRequest, response.PayWinnerResponse{
+				Success: false,
+				Error:   "Invalid match number",
+			})
+			return
+		}
+		var req request.TournamentResultRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.PayWinnerResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		resp, err := gameService.ReportTournamentResult(c.Param("id"), number, &req, gameServerID(c))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Create a tournament
+// @Description Opens a tournament for registration
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param tournament body request.CreateTournamentRequest true "Tournament"
+// @Success 200 {object} response.TournamentResponse
+// @Failure 400 {object} response.TournamentResponse
+// @Router /admin/tournaments [post]
+func handleCreateTournament(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req request.CreateTournamentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.TournamentResponse{
+				Success: false,
+				Error:   "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+		c.Set(adminAuditBodyKey, map[string]string{
+			"name":        req.Name,
+			"format":      req.Format,
+			"entry_stake": strconv.FormatUint(req.EntryStake, 10),
+			"capacity":    strconv.Itoa(req.Capacity),
+		})
+		resp, err := gameService.CreateTournament(&req, adminActor(c))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Start a tournament
+// @Description Closes registration, seeds players by rating and stakes the first matches whose players have a coin staged
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} response.TournamentResponse
+// @Failure 400 {object} response.TournamentResponse
+// @Failure 409 {object} response.TournamentResponse
+// @Router /admin/tournaments/{id}/start [post]
+func handleStartTournament(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.StartTournament(c.Param("id"), adminActor(c))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary Cancel a tournament
+// @Description Calls off a tournament that has not started
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} response.TournamentResponse
+// @Failure 409 {object} response.TournamentResponse
+// @Router /admin/tournaments/{id}/cancel [post]
+func handleCancelTournament(gameService service.GameServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := gameService.CancelTournament(c.Param("id"), adminActor(c))
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), resp)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,137 @@
+// Package screening checks addresses against external sanctions or ban lists,
+// on top of the blocklist the service keeps in Mongo. A Provider can be backed
+// by anything; FileProvider reads a local list.
+package screening
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result is a provider's verdict on one address.
+type Result struct {
+	Blocked bool
+	Reason  string
+	Source  string
+}
+
+// Provider screens addresses. An error means the address could not be
+// screened, not that it is clean.
+type Provider interface {
+	Screen(ctx context.Context, address string) (Result, error)
+}
+
+// Entry is one line of a blocklist file or CSV import.
+type Entry struct {
+	Address string
+	Reason  string
+}
+
+// NormalizeAddress lowercases an address so lookups ignore hex case.
+func NormalizeAddress(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
+
+// ParseCSV reads address[,reason] lines. Blank lines, lines starting with #
+// and an "address" header row are skipped.
+func ParseCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	var entries []Entry
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		address := NormalizeAddress(record[0])
+		if address == "" || (line == 1 && address == "address") {
+			continue
+		}
+		entry := Entry{Address: address}
+		if len(record) > 1 {
+			entry.Reason = strings.TrimSpace(record[1])
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// FileProvider screens against a local address[,reason] CSV file. The file is
+// read again whenever its modification time changes, so it can be replaced
+// without a restart.
+type FileProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	entries map[string]string
+}
+
+// NewFileProvider loads path, failing if it cannot be read.
+func NewFileProvider(path string) (*FileProvider, error) {
+	p := &FileProvider{path: path}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileProvider) Screen(ctx context.Context, address string) (Result, error) {
+	if err := p.reload(); err != nil {
+		return Result{}, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	reason, blocked := p.entries[NormalizeAddress(address)]
+	if !blocked {
+		return Result{}, nil
+	}
+	return Result{Blocked: true, Reason: reason, Source: "file"}, nil
+}
+
+// Len returns the number of addresses currently loaded.
+func (p *FileProvider) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+func (p *FileProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("screening file: %w", err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.entries != nil && info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	file, err := os.Open(p.path)
+	if err != nil {
+		return fmt.Errorf("screening file: %w", err)
+	}
+	defer file.Close()
+	entries, err := ParseCSV(file)
+	if err != nil {
+		return fmt.Errorf("screening file: %w", err)
+	}
+
+	p.entries = make(map[string]string, len(entries))
+	for _, entry := range entries {
+		p.entries[entry.Address] = entry.Reason
+	}
+	p.modTime = info.ModTime()
+	return nil
+}
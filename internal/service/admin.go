@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	// SuiCoinType is the coin the operator wallet pays gas and stakes in.
+	SuiCoinType = "0x2::sui::SUI"
+
+	adminAuditCollection = "admin_audit_log"
+
+	DefaultAuditLimit = 50
+	MaxAuditLimit     = 500
+)
+
+var (
+	ErrInvalidAdminRequest = errors.New("invalid admin request")
+	ErrPoolNotConfigured   = errors.New("stake pool is not configured")
+	ErrChainUnavailable    = errors.New("blockchain node unavailable")
+)
+
+// GetOperatorBalance sums the operator wallet's coins of coinType, SUI when
+// empty.
+func (s *GameService) GetOperatorBalance(coinType string) (*response.AdminBalanceResponse, error) {
+	if coinType == "" {
+		coinType = SuiCoinType
+	}
+	balance, err := s.suiClient.GetTotalBalance(context.Background(), coinType)
+	if err != nil {
+		log.Printf("❌ Failed to fetch operator balance: %v", err)
+		return &response.AdminBalanceResponse{
+			Success:  false,
+			CoinType: coinType,
+			Error:    fmt.Sprintf("Failed to fetch balance: %v", err),
+		}, fmt.Errorf("%w: %v", ErrChainUnavailable, err)
+	}
+	return &response.AdminBalanceResponse{
+		Success:  true,
+		CoinType: coinType,
+		Balance:  balance,
+	}, nil
+}
+
+// GetOperatorCoins lists every coin object of coinType, SUI when empty, held
+// by the operator wallet.
+func (s *GameService) GetOperatorCoins(coinType string) (*response.AdminCoinsResponse, error) {
+	if coinType == "" {
+		coinType = SuiCoinType
+	}
+	coins, err := s.suiClient.GetAllCoins(context.Background(), coinType)
+	if err != nil {
+		log.Printf("❌ Failed to fetch operator coins: %v", err)
+		return &response.AdminCoinsResponse{
+			Success:  false,
+			CoinType: coinType,
+			Coins:    []map[string]interface{}{},
+			Error:    fmt.Sprintf("Failed to fetch coins: %v", err),
+		}, fmt.Errorf("%w: %v", ErrChainUnavailable, err)
+	}
+	if coins == nil {
+		coins = []map[string]interface{}{}
+	}
+	return &response.AdminCoinsResponse{
+		Success:  true,
+		CoinType: coinType,
+		Coins:    coins,
+		Count:    len(coins),
+	}, nil
+}
+
+// GetStakePoolState returns the raw on-chain object of the configured pool.
+func (s *GameService) GetStakePoolState() (*response.AdminPoolResponse, error) {
+	if s.poolID == "" {
+		return &response.AdminPoolResponse{
+			Success: false,
+			Error:   "Stake pool is not configured",
+		}, ErrPoolNotConfigured
+	}
+	pool, err := s.suiClient.GetStakePool(s.poolID)
+	if err != nil {
+		log.Printf("❌ Failed to fetch stake pool %s: %v", s.poolID, err)
+		return &response.AdminPoolResponse{
+			Success: false,
+			PoolID:  s.poolID,
+			Error:   fmt.Sprintf("Failed to fetch stake pool: %v", err),
+		}, fmt.Errorf("%w: %v", ErrChainUnavailable, err)
+	}
+	return &response.AdminPoolResponse{
+		Success: true,
+		PoolID:  s.poolID,
+		Pool:    pool,
+	}, nil
+}
+
+// ListPendingTransactions returns the stake and payout transactions still
+// waiting for the confirmation worker.
+func (s *GameService) ListPendingTransactions() (*response.AdminTransactionsResponse, error) {
+	pending, err := s.mongoClient.GetPendingTransactions(context.Background())
+	if err != nil {
+		log.Printf("❌ Failed to fetch pending transactions: %v", err)
+		return &response.AdminTransactionsResponse{
+			Success:      false,
+			Transactions: []data.Transaction{},
+			Error:        fmt.Sprintf("Failed to fetch pending transactions: %v", err),
+		}, err
+	}
+	transactions := []data.Transaction{}
+	for _, raw := range pending {
+		if tx, ok := raw.(data.Transaction); ok {
+			transactions = append(transactions, tx)
+		}
+	}
+	return &response.AdminTransactionsResponse{
+		Success:      true,
+		Transactions: transactions,
+		Count:        len(transactions),
+	}, nil
+}
+
+// GetCollectionStats returns document counts for the core collections.
+func (s *GameService) GetCollectionStats() (*response.AdminCollectionStatsResponse, error) {
+	stats, err := s.mongoClient.GetCollectionStats(context.Background())
+	if err != nil {
+		log.Printf("❌ Failed to fetch collection stats: %v", err)
+		return &response.AdminCollectionStatsResponse{
+			Success:     false,
+			Collections: map[string]interface{}{},
+			Error:       fmt.Sprintf("Failed to fetch collection stats: %v", err),
+		}, err
+	}
+	return &response.AdminCollectionStatsResponse{
+		Success:     true,
+		Collections: stats,
+	}, nil
+}
+
+// CleanupOldGames deletes completed games that finished more than
+// olderThanDays ago.
+func (s *GameService) CleanupOldGames(olderThanDays int) (*response.AdminCleanupResponse, error) {
+	if olderThanDays < 1 {
+		return &response.AdminCleanupResponse{
+			Success:       false,
+			OlderThanDays: olderThanDays,
+			Error:         "older_than_days must be at least 1",
+		}, ErrInvalidAdminRequest
+	}
+	deleted, err := s.mongoClient.CleanupOldGames(context.Background(), olderThanDays)
+	if err != nil {
+		log.Printf("❌ Failed to clean up old games: %v", err)
+		return &response.AdminCleanupResponse{
+			Success:       false,
+			OlderThanDays: olderThanDays,
+			Error:         fmt.Sprintf("Failed to clean up games: %v", err),
+		}, err
+	}
+	log.Printf("🧹 Deleted %d completed games older than %d days", deleted, olderThanDays)
+	return &response.AdminCleanupResponse{
+		Success:       true,
+		OlderThanDays: olderThanDays,
+		Deleted:       deleted,
+	}, nil
+}
+
+// RecordAdminAction appends an entry to the admin audit log.
+func (s *GameService) RecordAdminAction(entry *models.AdminAuditEntry) error {
+	entry.ID = primitive.NewObjectID()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if _, err := s.collection(adminAuditCollection).InsertOne(context.Background(), *entry); err != nil {
+		log.Printf("❌ Failed to write admin audit entry for %s: %v", entry.Action, err)
+		return err
+	}
+	return nil
+}
+
+// ListAdminAuditLog returns audit entries, newest first.
+func (s *GameService) ListAdminAuditLog(query *request.AdminAuditQuery) (*response.AdminAuditLogResponse, error) {
+	filter := bson.M{}
+	if query.Action != "" {
+		filter["action"] = query.Action
+	}
+	if query.Actor != "" {
+		filter["actor"] = query.Actor
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultAuditLimit
+	}
+	if limit > MaxAuditLimit {
+		limit = MaxAuditLimit
+	}
+
+	ctx := context.Background()
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit))
+	entries := []models.AdminAuditEntry{}
+	cursor, err := s.collection(adminAuditCollection).Find(ctx, filter, opts)
+	if err == nil {
+		defer cursor.Close(ctx)
+		err = cursor.All(ctx, &entries)
+	}
+	if err != nil {
+		log.Printf("❌ Failed to fetch admin audit log: %v", err)
+		return &response.AdminAuditLogResponse{
+			Success: false,
+			Entries: []models.AdminAuditEntry{},
+			Error:   fmt.Sprintf("Failed to fetch audit log: %v", err),
+		}, err
+	}
+	return &response.AdminAuditLogResponse{
+		Success: true,
+		Entries: entries,
+		Count:   len(entries),
+	}, nil
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/screening"
+)
+
+const (
+	blocklistCollection = "blocklist"
+
+	BlocklistSourceAdmin  = "admin"
+	BlocklistSourceImport = "import"
+
+	// StakeCodeAddressBlocked is the stake refusal code for a blocked address.
+	StakeCodeAddressBlocked = "address_blocked"
+
+	DefaultBlocklistLimit = 100
+	MaxBlocklistLimit     = 1000
+)
+
+var (
+	ErrAddressBlocked         = errors.New("address is blocked")
+	ErrBlocklistEntryNotFound = errors.New("address is not on the blocklist")
+	ErrScreeningUnavailable   = errors.New("address screening unavailable")
+)
+
+// BlockedError names the address screening refused.
+type BlockedError struct {
+	Address string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrAddressBlocked, e.Address)
+}
+
+func (e *BlockedError) Unwrap() error {
+	return ErrAddressBlocked
+}
+
+// ConfigureScreening adds an external screening provider, checked after the
+// blocklist. nil removes it.
+func (s *GameService) ConfigureScreening(provider screening.Provider) {
+	s.screening = provider
+}
+
+// screenAddresses refuses a stake or payout if any of the addresses is on the
+// blocklist or flagged by the screening provider, and writes the attempt to
+// the audit log. It fails closed: an address that cannot be screened is
+// refused too.
+func (s *GameService) screenAddresses(ctx context.Context, action string, addresses ...string) error {
+	normalized := make([]string, len(addresses))
+	for i, address := range addresses {
+		normalized[i] = screening.NormalizeAddress(address)
+	}
+
+	var entries []models.BlocklistEntry
+	if err := findAll(ctx, s.collection(blocklistCollection), bson.M{"_id": bson.M{"$in": normalized}}, &entries); err != nil {
+		log.Printf("❌ Failed to check the blocklist for %s: %v", action, err)
+		return fmt.Errorf("%w: %v", ErrScreeningUnavailable, err)
+	}
+	if len(entries) > 0 {
+		entry := entries[0]
+		return s.refuseBlocked(action, addresses, entry.Address, entry.Source, entry.Reason)
+	}
+
+	if s.screening == nil {
+		return nil
+	}
+	for _, address := range normalized {
+		result, err := s.screening.Screen(ctx, address)
+		if err != nil {
+			log.Printf("❌ Failed to screen %s for %s: %v", address, action, err)
+			return fmt.Errorf("%w: %v", ErrScreeningUnavailable, err)
+		}
+		if result.Blocked {
+			return s.refuseBlocked(action, addresses, address, result.Source, result.Reason)
+		}
+	}
+	return nil
+}
+
+func (s *GameService) refuseBlocked(action string, addresses []string, blocked, source, reason string) error {
+	log.Printf("⛔ Blocked %s for %s (%s: %s)", action, blocked, source, reason)
+	params := map[string]string{
+		"address": blocked,
+		"source":  source,
+	}
+	if reason != "" {
+		params["reason"] = reason
+	}
+	for i, address := range addresses {
+		params[fmt.Sprintf("address_%d", i+1)] = address
+	}
+	s.RecordAdminAction(&models.AdminAuditEntry{
+		Action:     "BLOCKED " + action,
+		Actor:      "screening",
+		Params:     params,
+		StatusCode: 403,
+		CreatedAt:  s.clock.Now(),
+	})
+	return &BlockedError{Address: blocked}
+}
+
+// AddBlocklistEntry blocks an address, or updates the reason it is blocked
+// for.
+func (s *GameService) AddBlocklistEntry(req *request.BlocklistEntryRequest, actor string) (*response.BlocklistEntryResponse, error) {
+	entry, err := s.upsertBlocklistEntry(context.Background(), screening.Entry{Address: req.Address, Reason: req.Reason}, BlocklistSourceAdmin, actor)
+	if err != nil {
+		log.Printf("❌ Failed to block %s: %v", req.Address, err)
+		return &response.BlocklistEntryResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to add blocklist entry: %v", err),
+		}, err
+	}
+	log.Printf("⛔ Blocked address %s", entry.Address)
+	return &response.BlocklistEntryResponse{
+		Success: true,
+		Entry:   entry,
+	}, nil
+}
+
+// ImportBlocklist blocks every address in entries. Entries are written one by
+// one, so an import that fails partway leaves the earlier ones in place.
+func (s *GameService) ImportBlocklist(entries []screening.Entry, actor string) (*response.BlocklistImportResponse, error) {
+	ctx := context.Background()
+	imported := 0
+	for _, entry := range entries {
+		if _, err := s.upsertBlocklistEntry(ctx, entry, BlocklistSourceImport, actor); err != nil {
+			log.Printf("❌ Blocklist import failed after %d of %d entries: %v", imported, len(entries), err)
+			return &response.BlocklistImportResponse{
+				Success:  false,
+				Imported: imported,
+				Error:    fmt.Sprintf("Failed to import blocklist: %v", err),
+			}, err
+		}
+		imported++
+	}
+	log.Printf("⛔ Imported %d blocklist entries", imported)
+	return &response.BlocklistImportResponse{
+		Success:  true,
+		Imported: imported,
+	}, nil
+}
+
+func (s *GameService) upsertBlocklistEntry(ctx context.Context, entry screening.Entry, source, actor string) (*models.BlocklistEntry, error) {
+	address := screening.NormalizeAddress(entry.Address)
+	now := s.clock.Now()
+	_, err := s.collection(blocklistCollection).UpdateOne(ctx,
+		bson.M{"_id": address},
+		bson.M{
+			"$set":         bson.M{"reason": entry.Reason, "source": source, "added_by": actor},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var stored models.BlocklistEntry
+	if _, err := findOne(ctx, s.collection(blocklistCollection), bson.M{"_id": address}, &stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// RemoveBlocklistEntry unblocks an address.
+func (s *GameService) RemoveBlocklistEntry(address string) (*response.BlocklistEntryResponse, error) {
+	address = screening.NormalizeAddress(address)
+	result, err := s.collection(blocklistCollection).DeleteOne(context.Background(), bson.M{"_id": address})
+	if err != nil {
+		log.Printf("❌ Failed to unblock %s: %v", address, err)
+		return &response.BlocklistEntryResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to remove blocklist entry: %v", err),
+		}, err
+	}
+	if result.DeletedCount == 0 {
+		return &response.BlocklistEntryResponse{
+			Success: false,
+			Error:   "Address is not on the blocklist",
+		}, ErrBlocklistEntryNotFound
+	}
+	log.Printf("✅ Unblocked address %s", address)
+	return &response.BlocklistEntryResponse{
+		Success: true,
+		Entry:   &models.BlocklistEntry{Address: address},
+	}, nil
+}
+
+// ListBlocklist returns blocklist entries, newest first.
+func (s *GameService) ListBlocklist(query *request.BlocklistQuery) (*response.BlocklistResponse, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultBlocklistLimit
+	}
+	if limit > MaxBlocklistLimit {
+		limit = MaxBlocklistLimit
+	}
+
+	entries := []models.BlocklistEntry{}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit))
+	if err := findAll(context.Background(), s.collection(blocklistCollection), bson.M{}, &entries, opts); err != nil {
+		log.Printf("❌ Failed to fetch blocklist: %v", err)
+		return &response.BlocklistResponse{
+			Success: false,
+			Entries: []models.BlocklistEntry{},
+			Error:   fmt.Sprintf("Failed to fetch blocklist: %v", err),
+		}, err
+	}
+	return &response.BlocklistResponse{
+		Success: true,
+		Entries: entries,
+		Count:   len(entries),
+	}, nil
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	// GameStatusAccepting marks a challenge claimed by an accepter whose stake
+	// transaction is in flight, so no second accepter can claim it.
+	GameStatusAccepting = "accepting"
+	GameStatusCancelled = "cancelled"
+	GameStatusExpired   = "expired"
+
+	DefaultChallengeTTL = 30 * time.Minute
+	MaxChallengeTTL     = 24 * time.Hour
+)
+
+var (
+	ErrInvalidChallenge   = errors.New("invalid challenge request")
+	ErrChallengeNotFound  = errors.New("challenge not found")
+	ErrChallengeNotOpen   = errors.New("challenge is no longer open")
+	ErrChallengeForbidden = errors.New("address may not act on this challenge")
+)
+
+func (s *GameService) CreateChallenge(req *request.CreateChallengeRequest) (*response.ChallengeResponse, error) {
+	if req.RequesterAddress == "" || req.RequesterCoinID == "" || req.StakeAmount == 0 {
+		return &response.ChallengeResponse{
+			Success: false,
+			Error:   "Invalid challenge request: missing required fields",
+		}, ErrInvalidChallenge
+	}
+	if strings.EqualFold(req.AccepterAddress, req.RequesterAddress) {
+		return &response.ChallengeResponse{
+			Success: false,
+			Error:   "Invalid challenge request: cannot challenge yourself",
+		}, ErrInvalidChallenge
+	}
+
+	gameType, err := s.offeredGameType(context.Background(), req.GameType, req.StakeAmount)
+	if err != nil {
+		return &response.ChallengeResponse{Success: false, Error: err.Error()}, err
+	}
+
+	ttl := DefaultChallengeTTL
+	if req.ExpiresIn > 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if ttl > MaxChallengeTTL {
+		ttl = MaxChallengeTTL
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	game := data.Game{
+		RequesterAddress: req.RequesterAddress,
+		AccepterAddress:  req.AccepterAddress,
+		RequesterCoinID:  req.RequesterCoinID,
+		StakeAmount:      req.StakeAmount,
+		GameType:         gameType,
+		Status:           GameStatusPending,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		ExpiresAt:        &expiresAt,
+	}
+
+	gameID, err := s.mongoClient.CreateGame(context.Background(), game)
+	if err != nil {
+		log.Printf("❌ Failed to create challenge for %s: %v", req.RequesterAddress, err)
+		return &response.ChallengeResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to create challenge: %v", err),
+		}, err
+	}
+	game.ID, _ = primitive.ObjectIDFromHex(gameID)
+
+	log.Printf("✅ Challenge %s opened by %s for %d", gameID, req.RequesterAddress, req.StakeAmount)
+	return &response.ChallengeResponse{
+		Success:   true,
+		Challenge: toChallenge(game),
+	}, nil
+}
+
+// ListChallenges returns unexpired pending challenges, newest first. Without an
+// address only challenges open to anyone are listed; with one, the challenges
+// that address created or was targeted by are included too.
+func (s *GameService) ListChallenges(address string) (*response.ChallengeListResponse, error) {
+	games, err := s.mongoClient.GetGamesByStatus(context.Background(), GameStatusPending)
+	if err != nil {
+		log.Printf("❌ Failed to fetch open challenges: %v", err)
+		return &response.ChallengeListResponse{
+			Success:    false,
+			Challenges: []models.Challenge{},
+			Error:      fmt.Sprintf("Failed to fetch challenges: %v", err),
+		}, err
+	}
+
+	now := time.Now()
+	challenges := []models.Challenge{}
+	for _, raw := range games {
+		game, ok := raw.(data.Game)
+		if !ok || isExpired(game, now) {
+			continue
+		}
+		visible := game.AccepterAddress == "" ||
+			(address != "" && (game.RequesterAddress == address || game.AccepterAddress == address))
+		if visible {
+			challenges = append(challenges, *toChallenge(game))
+		}
+	}
+	sort.Slice(challenges, func(i, j int) bool {
+		return challenges[i].CreatedAt.After(challenges[j].CreatedAt)
+	})
+
+	return &response.ChallengeListResponse{
+		Success:    true,
+		Challenges: challenges,
+		Count:      len(challenges),
+	}, nil
+}
+
+// AcceptChallenge claims a pending challenge for the accepter and stakes both
+// coins through the regular stake flow, moving the game to staked.
+func (s *GameService) AcceptChallenge(challengeID string, req *request.AcceptChallengeRequest) (*response.StakeResponse, error) {
+	ctx := context.Background()
+
+	game, err := s.getChallenge(ctx, challengeID)
+	if err != nil {
+		return &response.StakeResponse{Success: false, Error: err.Error()}, err
+	}
+	if req.AccepterAddress == "" || req.AccepterCoinID == "" {
+		return &response.StakeResponse{
+			Success: false,
+			Error:   "Invalid accept request: missing required fields",
+		}, ErrInvalidChallenge
+	}
+	if game.Status != GameStatusPending {
+		return &response.StakeResponse{Success: false, Error: ErrChallengeNotOpen.Error()}, ErrChallengeNotOpen
+	}
+	if strings.EqualFold(req.AccepterAddress, game.RequesterAddress) {
+		return &response.StakeResponse{
+			Success: false,
+			Error:   "Cannot accept your own challenge",
+		}, ErrChallengeForbidden
+	}
+	if game.AccepterAddress != "" && !strings.EqualFold(game.AccepterAddress, req.AccepterAddress) {
+		return &response.StakeResponse{
+			Success: false,
+			Error:   "Challenge is reserved for another opponent",
+		}, ErrChallengeForbidden
+	}
+	if isExpired(*game, time.Now()) {
+		s.expireChallenge(ctx, game)
+		return &response.StakeResponse{Success: false, Error: "Challenge has expired"}, ErrChallengeNotOpen
+	}
+
+	claimed, err := s.mongoClient.TransitionGame(ctx, challengeID, GameStatusPending, bson.M{
+		"status":           GameStatusAccepting,
+		"accepter_address": req.AccepterAddress,
+		"accepter_coin_id": req.AccepterCoinID,
+	})
+	if err != nil {
+		log.Printf("❌ Failed to claim challenge %s: %v", challengeID, err)
+		return &response.StakeResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to accept challenge: %v", err),
+		}, err
+	}
+	if !claimed {
+		return &response.StakeResponse{Success: false, Error: ErrChallengeNotOpen.Error()}, ErrChallengeNotOpen
+	}
+
+	resp, err := s.stakeGame(&request.StakeRequest{
+		RequesterCoinID:  game.RequesterCoinID,
+		AccepterCoinID:   req.AccepterCoinID,
+		RequesterAddress: game.RequesterAddress,
+		AccepterAddress:  req.AccepterAddress,
+		StakeAmount:      game.StakeAmount,
+		GameType:         game.GameType,
+	}, challengeID)
+	if err != nil {
+		// Hand the challenge back so it can be accepted again
+		if _, releaseErr := s.mongoClient.TransitionGame(ctx, challengeID, GameStatusAccepting, bson.M{
+			"status":           GameStatusPending,
+			"accepter_address": game.AccepterAddress,
+			"accepter_coin_id": "",
+		}); releaseErr != nil {
+			log.Printf("⚠️  Failed to release challenge %s after stake failure: %v", challengeID, releaseErr)
+		}
+		return resp, err
+	}
+
+	log.Printf("✅ Challenge %s accepted by %s", challengeID, req.AccepterAddress)
+	return resp, nil
+}
+
+func (s *GameService) CancelChallenge(challengeID string, req *request.CancelChallengeRequest) (*response.ChallengeResponse, error) {
+	ctx := context.Background()
+
+	game, err := s.getChallenge(ctx, challengeID)
+	if err != nil {
+		return &response.ChallengeResponse{Success: false, Error: err.Error()}, err
+	}
+	if !strings.EqualFold(req.RequesterAddress, game.RequesterAddress) {
+		return &response.ChallengeResponse{
+			Success: false,
+			Error:   "Only the requester can cancel a challenge",
+		}, ErrChallengeForbidden
+	}
+
+	cancelled, err := s.mongoClient.TransitionGame(ctx, challengeID, GameStatusPending, bson.M{"status": GameStatusCancelled})
+	if err != nil {
+		log.Printf("❌ Failed to cancel challenge %s: %v", challengeID, err)
+		return &response.ChallengeResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to cancel challenge: %v", err),
+		}, err
+	}
+	if !cancelled {
+		return &response.ChallengeResponse{Success: false, Error: ErrChallengeNotOpen.Error()}, ErrChallengeNotOpen
+	}
+	game.Status = GameStatusCancelled
+
+	log.Printf("✅ Challenge %s cancelled by %s", challengeID, req.RequesterAddress)
+	return &response.ChallengeResponse{
+		Success:   true,
+		Challenge: toChallenge(*game),
+	}, nil
+}
+
+// ExpireChallenges moves every pending challenge whose deadline passed before
+// now to expired and returns how many it expired.
+func (s *GameService) ExpireChallenges(ctx context.Context, now time.Time) (int, error) {
+	games, err := s.mongoClient.GetGamesByStatus(ctx, GameStatusPending)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, raw := range games {
+		game, ok := raw.(data.Game)
+		if !ok || !isExpired(game, now) {
+			continue
+		}
+		if s.expireChallenge(ctx, &game) {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// RunChallengeSweeper expires stale challenges every interval until ctx is
+// done.
+func (s *GameService) RunChallengeSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if expired, err := s.ExpireChallenges(ctx, now); err != nil {
+				log.Printf("⚠️  Challenge sweep failed: %v", err)
+			} else if expired > 0 {
+				log.Printf("🧹 Expired %d challenges", expired)
+			}
+		}
+	}
+}
+
+func (s *GameService) getChallenge(ctx context.Context, challengeID string) (*data.Game, error) {
+	if _, err := primitive.ObjectIDFromHex(challengeID); err != nil {
+		return nil, ErrChallengeNotFound
+	}
+	raw, err := s.mongoClient.GetGame(ctx, challengeID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, ErrChallengeNotFound
+		}
+		log.Printf("❌ Failed to fetch challenge %s: %v", challengeID, err)
+		return nil, fmt.Errorf("failed to fetch challenge: %v", err)
+	}
+	game, ok := raw.(data.Game)
+	if !ok || game.ExpiresAt == nil {
+		// Games staked directly never went through the challenge flow
+		return nil, ErrChallengeNotFound
+	}
+	return &game, nil
+}
+
+func (s *GameService) expireChallenge(ctx context.Context, game *data.Game) bool {
+	expired, err := s.mongoClient.TransitionGame(ctx, game.ID.Hex(), GameStatusPending, bson.M{"status": GameStatusExpired})
+	if err != nil {
+		log.Printf("⚠️  Failed to expire challenge %s: %v", game.ID.Hex(), err)
+		return false
+	}
+	return expired
+}
+
+func isExpired(game data.Game, now time.Time) bool {
+	return game.ExpiresAt != nil && !now.Before(*game.ExpiresAt)
+}
+
+func toChallenge(game data.Game) *models.Challenge {
+	challenge := &models.Challenge{
+		ID:                game.ID.Hex(),
+		RequesterAddress:  game.RequesterAddress,
+		AccepterAddress:   game.AccepterAddress,
+		StakeAmount:       game.StakeAmount,
+		GameType:          game.GameType,
+		Status:            game.Status,
+		TransactionDigest: game.TransactionDigest,
+		CreatedAt:         game.CreatedAt,
+	}
+	if game.ExpiresAt != nil {
+		challenge.ExpiresAt = *game.ExpiresAt
+	}
+	return challenge
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/interfaces"
+)
+
+// gamesDatabase holds the collections owned by the service layer (stakes,
+// payouts and everything derived from them).
+const gamesDatabase = "jollfi_games"
+
+func (s *GameService) collection(name string) interfaces.MongoCollectionInterface {
+	return s.mongoClient.GetDatabase(gamesDatabase).Collection(name)
+}
+
+// findOne decodes the first document matching filter into result and reports
+// whether one was found.
+func findOne(ctx context.Context, collection interfaces.MongoCollectionInterface, filter interface{}, result interface{}, opts ...*options.FindOptions) (bool, error) {
+	opts = append(opts, options.Find().SetLimit(1))
+	cursor, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return false, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return false, nil
+	}
+	if err := cursor.Decode(result); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findAll decodes every document matching filter into results.
+func findAll(ctx context.Context, collection interfaces.MongoCollectionInterface, filter interface{}, results interface{}, opts ...*options.FindOptions) error {
+	cursor, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, results)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	DependencyMongo = "mongo"
+	DependencySui   = "sui"
+
+	// dependencyCheckTimeout bounds each reachability check.
+	dependencyCheckTimeout = 5 * time.Second
+)
+
+// dependencyTracker remembers which backing services answered at the last
+// check, and what to run when one comes back.
+type dependencyTracker struct {
+	mu        sync.Mutex
+	states    map[string]models.DependencyState
+	onRecover map[string][]func(ctx context.Context) error
+}
+
+// OnDependencyRecovered registers a hook run whenever the dependency becomes
+// reachable, including the first time it is seen up. If a hook fails the
+// dependency stays unavailable and the hook is retried at the next check.
+func (s *GameService) OnDependencyRecovered(name string, hook func(ctx context.Context) error) {
+	s.dependencies.mu.Lock()
+	defer s.dependencies.mu.Unlock()
+	if s.dependencies.onRecover == nil {
+		s.dependencies.onRecover = make(map[string][]func(ctx context.Context) error)
+	}
+	s.dependencies.onRecover[name] = append(s.dependencies.onRecover[name], hook)
+}
+
+// DependencyAvailable reports whether the dependency answered at the last
+// check. A dependency that has never been checked counts as available.
+func (s *GameService) DependencyAvailable(name string) bool {
+	s.dependencies.mu.Lock()
+	defer s.dependencies.mu.Unlock()
+	state, ok := s.dependencies.states[name]
+	return !ok || state.Available
+}
+
+// DependencyStates returns the last known state of each checked dependency.
+func (s *GameService) DependencyStates() map[string]models.DependencyState {
+	s.dependencies.mu.Lock()
+	defer s.dependencies.mu.Unlock()
+	states := make(map[string]models.DependencyState, len(s.dependencies.states))
+	for name, state := range s.dependencies.states {
+		states[name] = state
+	}
+	return states
+}
+
+// CheckDependencies pings Mongo and the Sui RPC and records whether each is
+// reachable, running the recovery hooks of any that came back.
+func (s *GameService) CheckDependencies(ctx context.Context) {
+	checks := map[string]func(ctx context.Context) error{
+		DependencyMongo: s.mongoClient.Ping,
+		DependencySui: func(ctx context.Context) error {
+			_, err := s.suiClient.LatestCheckpoint(ctx)
+			return err
+		},
+	}
+
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+			defer cancel()
+			s.recordDependency(ctx, name, check(checkCtx))
+		}(name, check)
+	}
+	wg.Wait()
+}
+
+// RunDependencyMonitor re-checks the dependencies every interval until ctx
+// is cancelled, so a replica started while one was down recovers by itself.
+func (s *GameService) RunDependencyMonitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.CheckDependencies(ctx)
+		}
+	}
+}
+
+func (s *GameService) recordDependency(ctx context.Context, name string, err error) {
+	s.dependencies.mu.Lock()
+	previous, seen := s.dependencies.states[name]
+	hooks := s.dependencies.onRecover[name]
+	s.dependencies.mu.Unlock()
+
+	if err == nil && (!seen || !previous.Available) {
+		for _, hook := range hooks {
+			if hookErr := hook(ctx); hookErr != nil {
+				err = fmt.Errorf("reachable but not usable: %v", hookErr)
+				break
+			}
+		}
+	}
+
+	now := s.clock.Now()
+	state := models.DependencyState{Available: err == nil, Since: now, CheckedAt: now}
+	if seen && previous.Available == state.Available {
+		state.Since = previous.Since
+	}
+	if err != nil {
+		state.LastError = err.Error()
+	}
+
+	switch {
+	case err != nil && (!seen || previous.Available):
+		log.Printf("⚠️  %s is unavailable, running degraded: %v", name, err)
+	case err == nil && seen && !previous.Available:
+		log.Printf("✅ %s is available again", name)
+	}
+
+	s.dependencies.mu.Lock()
+	defer s.dependencies.mu.Unlock()
+	if s.dependencies.states == nil {
+		s.dependencies.states = make(map[string]models.DependencyState)
+	}
+	s.dependencies.states[name] = state
+}
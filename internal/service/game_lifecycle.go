@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+)
+
+const (
+	GameStatusPending   = "pending"
+	GameStatusStaked    = "staked"
+	GameStatusCompleted = "completed"
+	// GameStatusHeldForReview marks a game whose payout the guard parked for
+	// an admin to approve or reject.
+	GameStatusHeldForReview = "held_for_review"
+	// GameStatusPendingApproval marks a game whose payout is waiting for
+	// enough admins to approve it.
+	GameStatusPendingApproval = "pending_approval"
+	// GameStatusSettling marks a game whose payout is being sent.
+	GameStatusSettling = "settling"
+)
+
+var (
+	ErrGameNotStaked  = errors.New("game is not awaiting a result")
+	ErrPayoutMismatch = errors.New("payout does not match the staked game")
+)
+
+// recordStakedGame opens the lifecycle record for a game whose stake has landed
+// on chain. Failures are logged only; the stake itself already succeeded.
+func (s *GameService) recordStakedGame(ctx context.Context, req *request.StakeRequest, txDigest string) string {
+	stakedAt := s.clock.Now()
+	game := data.Game{
+		RequesterAddress:  req.RequesterAddress,
+		AccepterAddress:   req.AccepterAddress,
+		RequesterCoinID:   req.RequesterCoinID,
+		AccepterCoinID:    req.AccepterCoinID,
+		StakeAmount:       req.StakeAmount,
+		GameType:          req.GameType,
+		Status:            GameStatusStaked,
+		TransactionDigest: txDigest,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+		StakedAt:          &stakedAt,
+	}
+
+	gameID, err := s.mongoClient.CreateGame(ctx, game)
+	if err != nil {
+		log.Printf("⚠️  Failed to record game lifecycle (transaction still succeeded): %v", err)
+		return ""
+	}
+	return gameID
+}
+
+// markChallengeStaked moves an accepted challenge to staked once its stake
+// landed on chain. Failures are logged only; the stake itself already succeeded.
+func (s *GameService) markChallengeStaked(ctx context.Context, challengeID, txDigest string) {
+	updates := bson.M{"status": GameStatusStaked, "transaction_digest": txDigest, "staked_at": s.clock.Now()}
+	ok, err := s.mongoClient.TransitionGame(ctx, challengeID, GameStatusAccepting, updates)
+	if err != nil || !ok {
+		log.Printf("⚠️  Failed to mark challenge %s staked (transaction still succeeded): %v", challengeID, err)
+	}
+}
+
+// gameStakedAt returns when a game was staked, which every game-duration rule
+// counts from. Games recorded before stake times were kept fall back to when
+// they were created.
+func gameStakedAt(game data.Game) time.Time {
+	if game.StakedAt != nil {
+		return *game.StakedAt
+	}
+	return game.CreatedAt
+}
+
+// findStakedGame returns the staked game a payout settles, either by its ID or
+// by the most recent staked game between the same players for the same amount.
+// A game named by ID must still be staked, between the same players for the
+// same amount.
+func (s *GameService) findStakedGame(ctx context.Context, req *request.PayWinnerRequest) (*data.Game, error) {
+	if req.GameID != "" {
+		raw, err := s.mongoClient.GetGame(ctx, req.GameID)
+		if err != nil {
+			return nil, ErrGameNotFound
+		}
+		game, ok := raw.(data.Game)
+		if !ok {
+			return nil, fmt.Errorf("unexpected game record type %T", raw)
+		}
+		if game.Status != GameStatusStaked {
+			return nil, fmt.Errorf("%w: game is %s", ErrGameNotStaked, game.Status)
+		}
+		if !isPair(game.RequesterAddress, game.AccepterAddress, req.RequesterAddress, req.AccepterAddress) {
+			return nil, fmt.Errorf("%w: game was staked by %s and %s", ErrPayoutMismatch, game.RequesterAddress, game.AccepterAddress)
+		}
+		if game.StakeAmount != req.StakeAmount {
+			return nil, fmt.Errorf("%w: game was staked for %d", ErrPayoutMismatch, game.StakeAmount)
+		}
+		return &game, nil
+	}
+
+	games, err := s.mongoClient.GetGamesByAddress(ctx, req.RequesterAddress)
+	if err != nil {
+		return nil, err
+	}
+	for _, raw := range games {
+		game, ok := raw.(data.Game)
+		if !ok || game.Status != GameStatusStaked || game.StakeAmount != req.StakeAmount {
+			continue
+		}
+		if isPair(game.RequesterAddress, game.AccepterAddress, req.RequesterAddress, req.AccepterAddress) {
+			return &game, nil
+		}
+	}
+	return nil, ErrGameNotFound
+}
+
+// claimGame moves a game from status to settling before its payout is sent,
+// so only one payout or refund can settle it.
+func (s *GameService) claimGame(ctx context.Context, gameID, status string) error {
+	return s.parkGame(ctx, gameID, status, GameStatusSettling)
+}
+
+// parkGame moves a game from status to parked while its payout waits or is
+// sent, and refuses the payout if another one settled the game first.
+func (s *GameService) parkGame(ctx context.Context, gameID, status, parked string) error {
+	moved, err := s.mongoClient.TransitionGame(ctx, gameID, status, bson.M{"status": parked})
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return fmt.Errorf("%w: game is no longer %s", ErrGameNotStaked, status)
+	}
+	return nil
+}
+
+// payoutGame loads the game a held or pending payout settles, or returns nil.
+func (s *GameService) payoutGame(ctx context.Context, gameID string) *data.Game {
+	if gameID == "" {
+		return nil
+	}
+	raw, err := s.mongoClient.GetGame(ctx, gameID)
+	if err != nil {
+		return nil
+	}
+	game, ok := raw.(data.Game)
+	if !ok {
+		return nil
+	}
+	return &game
+}
+
+// releaseGame moves a claimed game back to status after its payout failed.
+func (s *GameService) releaseGame(ctx context.Context, gameID, status string) {
+	if _, err := s.mongoClient.TransitionGame(ctx, gameID, GameStatusSettling, bson.M{"status": status}); err != nil {
+		log.Printf("⚠️  Failed to release game %s after its payout failed: %v", gameID, err)
+	}
+}
+
+// completeGame closes the lifecycle record of a claimed game once the payout
+// transaction landed. A game with a refund reason is closed as refunded.
+func (s *GameService) completeGame(ctx context.Context, game *data.Game, req *request.PayWinnerRequest, winner, txDigest string) {
+	now := time.Now()
+	requesterScore, accepterScore := req.RequesterScore, req.AccepterScore
+	if game.RequesterAddress != req.RequesterAddress {
+		requesterScore, accepterScore = accepterScore, requesterScore
+	}
+
+	updates := bson.M{
+		"status":             GameStatusCompleted,
+		"requester_score":    requesterScore,
+		"accepter_score":     accepterScore,
+		"winner":             winner,
+		"transaction_digest": txDigest,
+		"completed_at":       now,
+	}
+	if game.RefundReason != "" {
+		updates["status"] = GameStatusRefunded
+		updates["refund_reason"] = game.RefundReason
+	}
+	ok, err := s.mongoClient.TransitionGame(ctx, game.ID.Hex(), GameStatusSettling, updates)
+	if err != nil || !ok {
+		log.Printf("⚠️  Failed to complete game %s (transaction still succeeded): %v", game.ID.Hex(), err)
+	}
+}
+
+// determineWinner mirrors the contract: the higher score wins and equal scores
+// are a draw, reported as an empty winner.
+func determineWinner(requesterAddress, accepterAddress string, requesterScore, accepterScore uint64) string {
+	switch {
+	case requesterScore > accepterScore:
+		return requesterAddress
+	case accepterScore > requesterScore:
+		return accepterAddress
+	default:
+		return ""
+	}
+}
+
+// The contract keeps these shares of the pot when it pays a winner, in basis
+// points, and sends the rest to the winner as the prize.
+const (
+	payoutAPIFeeBps    = 800
+	payoutEscrowFeeBps = 200
+)
+
+// splitPot mirrors the contract's payout: the fees it keeps out of the total
+// stake and the prize left for the winner.
+func splitPot(totalStake uint64) (prize, apiFee, escrowFee uint64) {
+	apiFee = totalStake * payoutAPIFeeBps / 10000
+	escrowFee = totalStake * payoutEscrowFeeBps / 10000
+	return totalStake - apiFee - escrowFee, apiFee, escrowFee
+}
+
+func isPair(a1, b1, a2, b2 string) bool {
+	return (a1 == a2 && b1 == b2) || (a1 == b2 && b1 == a2)
+}
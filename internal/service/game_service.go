@@ -2,34 +2,107 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"jollfi-gaming-api/internal/config"
 	"log"
+	"net/http"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/data"
 	"jollfi-gaming-api/internal/dto/request"
 	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/events"
 	"jollfi-gaming-api/internal/interfaces"
+	"jollfi-gaming-api/internal/lease"
+	"jollfi-gaming-api/internal/live"
+	"jollfi-gaming-api/internal/matchmaking"
 	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/ratelimit"
+	"jollfi-gaming-api/internal/screening"
+	"jollfi-gaming-api/internal/stream"
+	"jollfi-gaming-api/internal/webhook"
 )
 
 type GameService struct {
 	suiClient   interfaces.SuiClientInterface
 	mongoClient interfaces.MongoClientInterface
+	matchmaking matchmaking.Config
+	clock       matchmaking.Clock
+	events      *events.Bus
+	webhooks    webhook.Config
+	httpClient  *http.Client
+	stream      *stream.Hub
+	live        *live.Hub
+	poolID      string
+
+	killSwitchConfig KillSwitchConfig
+	killSwitch       killSwitchCache
+
+	readinessConfig ReadinessConfig
+	readiness       readinessCache
+	dependencies    dependencyTracker
+
+	rateLimitStore ratelimit.Store
+	wallet         *walletLock
+
+	responsibleGaming ResponsibleGamingConfig
+	screening         screening.Provider
+	payoutGuard       PayoutGuardConfig
+	payoutApproval    PayoutApprovalConfig
+	refunds           RefundConfig
+
+	timeoutSettlement TimeoutSettlementConfig
+	timeoutLeader     *lease.Leader
 }
 
 var _ GameServiceInterface = (*GameService)(nil)
 
 func NewGameService(suiClient interfaces.SuiClientInterface, mongoClient interfaces.MongoClientInterface) *GameService {
-	return &GameService{
+	s := &GameService{
 		suiClient:   suiClient,
 		mongoClient: mongoClient,
+		matchmaking: matchmaking.DefaultConfig(),
+		clock:       matchmaking.SystemClock{},
+		events:      events.NewBus(),
+		webhooks:    webhook.DefaultConfig(),
+		httpClient:  &http.Client{},
+		stream:      stream.NewHub(DefaultStreamReplayBuffer),
+		live:        live.NewHub(DefaultLiveRoomSize),
+
+		killSwitchConfig: DefaultKillSwitchConfig(),
+		readinessConfig:  DefaultReadinessConfig(),
+		rateLimitStore:   ratelimit.NewMemoryStore(),
+
+		responsibleGaming: DefaultResponsibleGamingConfig(),
+		payoutApproval:    DefaultPayoutApprovalConfig(),
+		refunds:           DefaultRefundConfig(),
 	}
+	s.ConfigureWalletLease(DefaultWalletLeaseConfig())
+	s.ConfigureTimeoutSettlement(DefaultTimeoutSettlementConfig())
+	s.events.Subscribe(s.enqueueWebhooks)
+	s.events.Subscribe(s.settleTournamentMatch)
+	s.events.Subscribe(s.releaseRefundedTournamentMatch)
+	s.events.Subscribe(func(event events.Event) { s.stream.Publish(event) })
+	s.events.Subscribe(func(event events.Event) { s.live.Publish(event) })
+	return s
+}
+
+// Events returns the bus the service publishes stake, settlement and
+// transaction events on.
+func (s *GameService) Events() *events.Bus {
+	return s.events
 }
 
 func (s *GameService) StakeGame(req *request.StakeRequest) (*response.StakeResponse, error) {
+	return s.stakeGame(req, "")
+}
+
+// stakeGame submits the stake on chain. A challengeID moves that accepted
+// challenge to staked; otherwise a new game lifecycle record is opened.
+func (s *GameService) stakeGame(req *request.StakeRequest, challengeID string) (*response.StakeResponse, error) {
 	if req.RequesterCoinID == "" || req.AccepterCoinID == "" || req.StakeAmount == 0 {
 		return &response.StakeResponse{
 			Success: false,
@@ -43,14 +116,54 @@ func (s *GameService) StakeGame(req *request.StakeRequest) (*response.StakeRespo
 		}, fmt.Errorf("addresses are required")
 	}
 
+	rules, err := s.gameTypeRules(context.Background(), req.GameType)
+	if err == nil {
+		err = checkStakeRules(rules, req.StakeAmount)
+	}
+	if err != nil {
+		resp := &response.StakeResponse{Success: false, Error: err.Error()}
+		switch {
+		case errors.Is(err, ErrUnknownGameType):
+			resp.Code = StakeCodeUnknownGameType
+		case errors.Is(err, ErrGameRuleViolation):
+			resp.Code = StakeCodeStakeOutOfRange
+		}
+		return resp, err
+	}
+	if rules != nil {
+		req.GameType = rules.Name
+	}
+
+	if state := s.KillSwitch(); state.Paused {
+		return &response.StakeResponse{
+			Success: false,
+			Error:   pausedError(state),
+		}, ErrPaused
+	}
+
+	if err := s.screenAddresses(context.Background(), "stake", req.RequesterAddress, req.AccepterAddress); err != nil {
+		resp := &response.StakeResponse{Success: false, Error: err.Error()}
+		if errors.Is(err, ErrAddressBlocked) {
+			resp.Code = StakeCodeAddressBlocked
+		}
+		return resp, err
+	}
+
+	if err := s.checkStakeLimits(context.Background(), req); err != nil {
+		resp := &response.StakeResponse{Success: false, Error: err.Error()}
+		var refusal *StakeRefusal
+		if errors.As(err, &refusal) {
+			resp.Code = refusal.Code
+			log.Printf("⛔ %v", refusal)
+		}
+		return resp, err
+	}
+
 	log.Printf("🔄 Processing stake: Amount per player: %d SUI (10%% fee will be deducted by blockchain)", req.StakeAmount)
 	log.Printf("🔄 Requester: %s, Accepter: %s", req.RequesterAddress, req.AccepterAddress)
-	txDigest, err := s.suiClient.ExternalStake(
-		req.RequesterCoinID,
-		req.AccepterCoinID,
-		req.StakeAmount,
-		context.Background(),
-	)
+	txDigest, err := s.withWallet(context.Background(), func(ctx context.Context) (string, error) {
+		return s.suiClient.ExternalStake(req.RequesterCoinID, req.AccepterCoinID, req.StakeAmount, ctx)
+	})
 	if err != nil {
 		log.Printf("❌ Blockchain stake failed: %v", err)
 		return &response.StakeResponse{
@@ -65,20 +178,42 @@ func (s *GameService) StakeGame(req *request.StakeRequest) (*response.StakeRespo
 		RequesterAddress: req.RequesterAddress,
 		AccepterAddress:  req.AccepterAddress,
 		StakeAmount:      req.StakeAmount,
+		GameType:         req.GameType,
 		Status:           "completed",
 		Timestamp:        time.Now().Unix(),
 		TransactionHash:  txDigest,
 	}
 
-	collection := s.mongoClient.GetDatabase("jollfi_games").Collection("stakes")
+	collection := s.collection("stakes")
 	_, err = collection.InsertOne(context.Background(), stake)
 	if err != nil {
 		log.Printf("⚠️  Database save failed (transaction still succeeded): %v", err)
 	}
 
+	gameID := challengeID
+	if challengeID != "" {
+		s.markChallengeStaked(context.Background(), challengeID, txDigest)
+	} else {
+		gameID = s.recordStakedGame(context.Background(), req, txDigest)
+	}
+	s.recordTransaction(context.Background(), TransactionTypeStake, gameID, req.RequesterAddress, req.AccepterAddress, req.StakeAmount*2, txDigest)
+	s.events.Publish(events.Event{
+		Type:      events.StakeCompleted,
+		GameID:    gameID,
+		Addresses: []string{req.RequesterAddress, req.AccepterAddress},
+		Data: map[string]interface{}{
+			"game_type":          req.GameType,
+			"requester_address":  req.RequesterAddress,
+			"accepter_address":   req.AccepterAddress,
+			"stake_amount":       req.StakeAmount,
+			"transaction_digest": txDigest,
+		},
+	})
+
 	log.Printf("✅ Stake transaction successful: TxDigest: %s", txDigest)
 	return &response.StakeResponse{
 		Success:           true,
+		GameID:            gameID,
 		TransactionDigest: txDigest,
 		Message:           "Stake successful. 10% fee deducted from each player by blockchain.",
 	}, nil
@@ -92,41 +227,163 @@ func (s *GameService) PayWinner(req *request.PayWinnerRequest) (*response.PayWin
 		}, fmt.Errorf("invalid pay winner request")
 	}
 
+	if state := s.KillSwitch(); state.Paused {
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   pausedError(state),
+		}, ErrPaused
+	}
+
+	if err := s.screenAddresses(context.Background(), "pay_winner", req.RequesterAddress, req.AccepterAddress); err != nil {
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	game, err := s.findStakedGame(context.Background(), req)
+	if err != nil {
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	rules, err := s.payoutRules(context.Background(), req, game)
+	if err == nil {
+		err = checkResultRules(rules, req)
+	}
+	if err != nil {
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	violations, err := s.checkPayout(context.Background(), req, game, rules)
+	if err != nil {
+		log.Printf("❌ Payout guard failed: %v", err)
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Payout checks failed: %v", err),
+		}, err
+	}
+	if len(violations) > 0 {
+		return s.holdPayout(context.Background(), req, game, violations)
+	}
+	return s.dispatchPayout(context.Background(), req, game, GameStatusStaked)
+}
+
+// sendPayout pays the winner on chain and records the settlement. game is the
+// game being settled and status the status it is in: staked, held_for_review
+// or pending_approval. The game is claimed before anything is sent, and goes
+// back to status if sending fails.
+func (s *GameService) sendPayout(req *request.PayWinnerRequest, game *data.Game, status string) (*response.PayWinnerResponse, error) {
 	log.Printf("🔄 Processing winner payment: Requester Score: %d, Accepter Score: %d, Original Stake: %d",
 		req.RequesterScore, req.AccepterScore, req.StakeAmount)
 
-	txDigest, err := s.suiClient.ExternalPayWinner(
-		req.RequesterAddress,
-		req.AccepterAddress,
-		req.RequesterScore,
-		req.AccepterScore,
-		req.StakeAmount,
-		context.Background(),
-	)
+	if game == nil {
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   ErrGameNotFound.Error(),
+		}, ErrGameNotFound
+	}
+	rules, err := s.payoutRules(context.Background(), req, game)
+	if err != nil {
+		log.Printf("❌ Failed to resolve game rules for payout: %v", err)
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	if err := s.claimGame(context.Background(), game.ID.Hex(), status); err != nil {
+		log.Printf("❌ Failed to claim game %s for payout: %v", game.ID.Hex(), err)
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	requesterScore, accepterScore := chainScores(rules, req.RequesterScore, req.AccepterScore)
+	refund := req.RequesterScore == req.AccepterScore && s.refundsDraw(rules)
+	var txDigest string
+	if refund {
+		txDigest, err = s.sendRefund(context.Background(), req.RequesterAddress, req.AccepterAddress, req.StakeAmount)
+	} else {
+		txDigest, err = s.withWallet(context.Background(), func(ctx context.Context) (string, error) {
+			return s.suiClient.ExternalPayWinner(req.RequesterAddress, req.AccepterAddress, requesterScore, accepterScore, req.StakeAmount, ctx)
+		})
+	}
 	if err != nil {
 		log.Printf("❌ Blockchain pay winner failed: %v", err)
+		s.releaseGame(context.Background(), game.ID.Hex(), status)
 		return &response.PayWinnerResponse{
 			Success: false,
 			Error:   fmt.Sprintf("Blockchain transaction failed: %v", err),
 		}, err
 	}
 
+	winner := determineWinner(req.RequesterAddress, req.AccepterAddress, requesterScore, accepterScore)
 	payWinner := models.PayWinner{
+		GameType:         req.GameType,
 		RequesterAddress: req.RequesterAddress,
 		AccepterAddress:  req.AccepterAddress,
 		RequesterScore:   req.RequesterScore,
 		AccepterScore:    req.AccepterScore,
+		Winner:           winner,
+		TotalStake:       req.StakeAmount * 2,
 		StakeAmount:      req.StakeAmount,
 		Timestamp:        time.Now().Unix(),
 		TransactionHash:  txDigest,
+		GameServer:       req.GameServer,
+		Refunded:         refund,
+		GameID:           game.ID.Hex(),
+	}
+	if winner != "" {
+		payWinner.PrizeAmount, payWinner.APIFee, payWinner.EscrowFee = splitPot(payWinner.TotalStake)
 	}
+	if refund {
+		game.RefundReason = RefundReasonDraw
+	}
+	s.completeGame(context.Background(), game, req, winner, txDigest)
 
-	collection := s.mongoClient.GetDatabase("jollfi_games").Collection("pay_winners")
+	collection := s.collection("pay_winners")
 	_, err = collection.InsertOne(context.Background(), payWinner)
 	if err != nil {
 		log.Printf("⚠️  Database save failed (transaction still succeeded): %v", err)
 	}
 
+	s.updateLeaderboards(context.Background(), payWinner)
+	s.updateRatings(context.Background(), payWinner)
+	if refund {
+		s.recordTransaction(context.Background(), TransactionTypeRefund, payWinner.GameID, req.RequesterAddress, req.AccepterAddress, payWinner.TotalStake, txDigest)
+	} else {
+		s.recordTransaction(context.Background(), TransactionTypePayout, payWinner.GameID, req.RequesterAddress, winner, payWinner.TotalStake, txDigest)
+	}
+	s.events.Publish(events.Event{
+		Type:      events.GameSettled,
+		GameID:    payWinner.GameID,
+		Addresses: []string{req.RequesterAddress, req.AccepterAddress},
+		Data: map[string]interface{}{
+			"game_type":          req.GameType,
+			"requester_address":  req.RequesterAddress,
+			"accepter_address":   req.AccepterAddress,
+			"requester_score":    req.RequesterScore,
+			"accepter_score":     req.AccepterScore,
+			"winner":             winner,
+			"stake_amount":       req.StakeAmount,
+			"total_stake":        payWinner.TotalStake,
+			"refunded":           refund,
+			"transaction_digest": txDigest,
+		},
+	})
+
+	if refund {
+		log.Printf("✅ Draw refunded to both players: TxDigest: %s", txDigest)
+		return &response.PayWinnerResponse{
+			Success:           true,
+			TransactionDigest: txDigest,
+			Status:            GameStatusRefunded,
+			Message:           "Draw settled by refunding both stakes.",
+		}, nil
+	}
 	log.Printf("✅ Pay winner transaction successful: TxDigest: %s", txDigest)
 	return &response.PayWinnerResponse{
 		Success:           true,
@@ -143,7 +400,7 @@ func (s *GameService) GetStakeHistory(address string) (*response.StakeHistoryRes
 		}, fmt.Errorf("address is required")
 	}
 
-	collection := s.mongoClient.GetDatabase("jollfi_games").Collection("stakes")
+	collection := s.collection("stakes")
 	filter := bson.M{
 		"$or": []bson.M{
 			{"requester_address": address},
@@ -186,7 +443,7 @@ func (s *GameService) GetGameHistory(address string) (*response.GameHistoryRespo
 		}, fmt.Errorf("address is required")
 	}
 
-	collection := s.mongoClient.GetDatabase("jollfi_games").Collection("pay_winners")
+	collection := s.collection("pay_winners")
 	filter := bson.M{
 		"$or": []bson.M{
 			{"requester_address": address},
@@ -223,8 +480,5 @@ func (s *GameService) GetGameHistory(address string) (*response.GameHistoryRespo
 }
 
 func NewTestGameService(suiClient interfaces.SuiClientInterface, mongoClient interfaces.MongoClientInterface, cfg *config.Config) GameServiceInterface {
-	return &GameService{
-		suiClient:   suiClient,
-		mongoClient: mongoClient,
-	}
+	return NewGameService(suiClient, mongoClient)
 }
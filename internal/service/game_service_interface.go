@@ -3,6 +3,11 @@ package service
 import (
 	"jollfi-gaming-api/internal/dto/request"
 	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/live"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/ratelimit"
+	"jollfi-gaming-api/internal/screening"
+	"jollfi-gaming-api/internal/stream"
 )
 
 type GameServiceInterface interface {
@@ -10,4 +15,66 @@ type GameServiceInterface interface {
 	PayWinner(req *request.PayWinnerRequest) (*response.PayWinnerResponse, error)
 	GetStakeHistory(address string) (*response.StakeHistoryResponse, error)
 	GetGameHistory(address string) (*response.GameHistoryResponse, error)
+	GetPlayerProfile(address string) (*response.PlayerProfileResponse, error)
+	GetPlayerRating(address string) (*response.PlayerRatingResponse, error)
+	GetStakeLimits(address string) (*response.PlayerLimitsResponse, error)
+	SetStakeLimits(address string, req *request.SetStakeLimitsRequest) (*response.PlayerLimitsResponse, error)
+	StartCoolingOff(address string, req *request.BreakRequest) (*response.PlayerLimitsResponse, error)
+	SelfExclude(address string, req *request.BreakRequest) (*response.PlayerLimitsResponse, error)
+	GetLeaderboard(query *request.LeaderboardQuery) (*response.LeaderboardResponse, error)
+	CreateChallenge(req *request.CreateChallengeRequest) (*response.ChallengeResponse, error)
+	ListChallenges(address string) (*response.ChallengeListResponse, error)
+	AcceptChallenge(challengeID string, req *request.AcceptChallengeRequest) (*response.StakeResponse, error)
+	CancelChallenge(challengeID string, req *request.CancelChallengeRequest) (*response.ChallengeResponse, error)
+	JoinQueue(req *request.JoinQueueRequest) (*response.QueueTicketResponse, error)
+	GetQueueTicket(ticketID string) (*response.QueueTicketResponse, error)
+	LeaveQueue(ticketID string, req *request.LeaveQueueRequest) (*response.QueueTicketResponse, error)
+	RegisterWebhook(req *request.RegisterWebhookRequest) (*response.WebhookResponse, error)
+	ListWebhooks() (*response.WebhookListResponse, error)
+	DeleteWebhook(webhookID string) (*response.WebhookResponse, error)
+	ListWebhookDeliveries(query *request.WebhookDeliveryQuery) (*response.WebhookDeliveryListResponse, error)
+	RedeliverWebhook(deliveryID string) (*response.WebhookDeliveryResponse, error)
+	SubscribeGameStream(address, lastEventID string) (*stream.Subscription, error)
+	JoinMatch(gameID, role, address string) (*live.Client, error)
+	PushMatchScore(client *live.Client, update *request.MatchScoreUpdate) error
+	GetPool() (*response.PoolResponse, error)
+	RPCStatus() []models.RPCEndpointStatus
+	Readiness() *response.ReadinessResponse
+	DependencyAvailable(name string) bool
+	DependencyStates() map[string]models.DependencyState
+	RateLimitStore() ratelimit.Store
+	GetOperatorBalance(coinType string) (*response.AdminBalanceResponse, error)
+	GetOperatorCoins(coinType string) (*response.AdminCoinsResponse, error)
+	GetStakePoolState() (*response.AdminPoolResponse, error)
+	ListPendingTransactions() (*response.AdminTransactionsResponse, error)
+	GetCollectionStats() (*response.AdminCollectionStatsResponse, error)
+	CleanupOldGames(olderThanDays int) (*response.AdminCleanupResponse, error)
+	KillSwitch() models.KillSwitch
+	GetKillSwitch() (*response.KillSwitchResponse, error)
+	SetKillSwitch(req *request.KillSwitchRequest, actor string) (*response.KillSwitchResponse, error)
+	AddBlocklistEntry(req *request.BlocklistEntryRequest, actor string) (*response.BlocklistEntryResponse, error)
+	ImportBlocklist(entries []screening.Entry, actor string) (*response.BlocklistImportResponse, error)
+	RemoveBlocklistEntry(address string) (*response.BlocklistEntryResponse, error)
+	ListBlocklist(query *request.BlocklistQuery) (*response.BlocklistResponse, error)
+	ListHeldPayouts(query *request.HeldPayoutQuery) (*response.HeldPayoutListResponse, error)
+	ApproveHeldPayout(id, actor string, req *request.ReviewPayoutRequest) (*response.HeldPayoutResponse, error)
+	RejectHeldPayout(id, actor string, req *request.ReviewPayoutRequest) (*response.HeldPayoutResponse, error)
+	ListPendingPayouts(query *request.PendingPayoutQuery) (*response.PendingPayoutListResponse, error)
+	ApprovePendingPayout(id, admin string) (*response.PendingPayoutResponse, error)
+	RefundStuckGame(id, actor string, req *request.RefundGameRequest) (*response.RefundGameResponse, error)
+	ReportScore(gameID string, req *request.ScoreReportRequest) (*response.ScoreReportResponse, error)
+	ListTimeoutSettlements(query *request.TimeoutSettlementQuery) (*response.TimeoutSettlementListResponse, error)
+	CreateTournament(req *request.CreateTournamentRequest, actor string) (*response.TournamentResponse, error)
+	ListTournaments(query *request.TournamentQuery) (*response.TournamentListResponse, error)
+	GetTournament(id string) (*response.TournamentResponse, error)
+	RegisterForTournament(id string, req *request.TournamentEntryRequest) (*response.TournamentResponse, error)
+	SetTournamentCoin(id string, req *request.TournamentEntryRequest) (*response.TournamentResponse, error)
+	StartTournament(id, actor string) (*response.TournamentResponse, error)
+	CancelTournament(id, actor string) (*response.TournamentResponse, error)
+	ReportTournamentResult(id string, number int, req *request.TournamentResultRequest, gameServer string) (*response.PayWinnerResponse, error)
+	ListGameTypes() (*response.GameTypeListResponse, error)
+	UpsertGameType(name string, req *request.GameTypeRequest, actor string) (*response.GameTypeResponse, error)
+	DeleteGameType(name string) (*response.GameTypeResponse, error)
+	RecordAdminAction(entry *models.AdminAuditEntry) error
+	ListAdminAuditLog(query *request.AdminAuditQuery) (*response.AdminAuditLogResponse, error)
 }
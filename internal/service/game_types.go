@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	gameTypesCollection = "game_types"
+
+	StakeCodeUnknownGameType = "unknown_game_type"
+	StakeCodeStakeOutOfRange = "stake_out_of_range"
+)
+
+var (
+	ErrInvalidGameType   = errors.New("invalid game type")
+	ErrGameTypeNotFound  = errors.New("game type not found")
+	ErrUnknownGameType   = errors.New("unknown game type")
+	ErrGameRuleViolation = errors.New("game rules violated")
+)
+
+var gameTypeName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// NormalizeGameType lowercases a game type name, as it is stored.
+func NormalizeGameType(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// UpsertGameType creates a game type or replaces its rules. Changes apply to
+// stakes and payouts from then on, including payouts of games staked before.
+func (s *GameService) UpsertGameType(name string, req *request.GameTypeRequest, actor string) (*response.GameTypeResponse, error) {
+	name = NormalizeGameType(name)
+	if req.ScoreDirection == "" {
+		req.ScoreDirection = models.ScoreDirectionHigher
+	}
+	if req.TieHandling == "" {
+		req.TieHandling = models.TieHandlingDraw
+	}
+	if err := validateGameType(name, req); err != nil {
+		return &response.GameTypeResponse{Success: false, Error: err.Error()}, err
+	}
+
+	ctx := context.Background()
+	now := s.clock.Now()
+	_, err := s.collection(gameTypesCollection).UpdateOne(ctx,
+		bson.M{"_id": name},
+		bson.M{
+			"$set": bson.M{
+				"score_direction": req.ScoreDirection,
+				"tie_handling":    req.TieHandling,
+				"min_stake":       req.MinStake,
+				"max_stake":       req.MaxStake,
+				"max_score":       req.MaxScore,
+				"updated_by":      actor,
+				"updated_at":      now,
+			},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		log.Printf("❌ Failed to save game type %s: %v", name, err)
+		return &response.GameTypeResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to save game type: %v", err),
+		}, err
+	}
+
+	var stored models.GameType
+	if _, err := findOne(ctx, s.collection(gameTypesCollection), bson.M{"_id": name}, &stored); err != nil {
+		return &response.GameTypeResponse{Success: false, Error: err.Error()}, err
+	}
+	log.Printf("✅ Game type %s saved by %s: %s score wins, ties %s", name, actor, stored.ScoreDirection, stored.TieHandling)
+	return &response.GameTypeResponse{
+		Success:  true,
+		GameType: &stored,
+	}, nil
+}
+
+func validateGameType(name string, req *request.GameTypeRequest) error {
+	switch {
+	case !gameTypeName.MatchString(name):
+		return fmt.Errorf("%w: name must be 1-32 lowercase letters, digits, - or _", ErrInvalidGameType)
+	case req.ScoreDirection != models.ScoreDirectionHigher && req.ScoreDirection != models.ScoreDirectionLower:
+		return fmt.Errorf("%w: score_direction must be %s or %s", ErrInvalidGameType, models.ScoreDirectionHigher, models.ScoreDirectionLower)
+	case req.TieHandling != models.TieHandlingDraw && req.TieHandling != models.TieHandlingReject && req.TieHandling != models.TieHandlingRefund:
+		return fmt.Errorf("%w: tie_handling must be %s, %s or %s", ErrInvalidGameType, models.TieHandlingDraw, models.TieHandlingReject, models.TieHandlingRefund)
+	case req.MaxStake > 0 && req.MinStake > req.MaxStake:
+		return fmt.Errorf("%w: min_stake is above max_stake", ErrInvalidGameType)
+	}
+	return nil
+}
+
+// DeleteGameType removes a game type. Games already staked under it cannot
+// be paid out until it is added again.
+func (s *GameService) DeleteGameType(name string) (*response.GameTypeResponse, error) {
+	name = NormalizeGameType(name)
+	result, err := s.collection(gameTypesCollection).DeleteOne(context.Background(), bson.M{"_id": name})
+	if err != nil {
+		log.Printf("❌ Failed to delete game type %s: %v", name, err)
+		return &response.GameTypeResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to delete game type: %v", err),
+		}, err
+	}
+	if result.DeletedCount == 0 {
+		return &response.GameTypeResponse{
+			Success: false,
+			Error:   "Game type not found",
+		}, ErrGameTypeNotFound
+	}
+	log.Printf("✅ Deleted game type %s", name)
+	return &response.GameTypeResponse{
+		Success:  true,
+		GameType: &models.GameType{Name: name},
+	}, nil
+}
+
+// ListGameTypes returns every game type by name.
+func (s *GameService) ListGameTypes() (*response.GameTypeListResponse, error) {
+	gameTypes := []models.GameType{}
+	opts := options.Find().SetSort(bson.M{"_id": 1})
+	if err := findAll(context.Background(), s.collection(gameTypesCollection), bson.M{}, &gameTypes, opts); err != nil {
+		log.Printf("❌ Failed to fetch game types: %v", err)
+		return &response.GameTypeListResponse{
+			Success:   false,
+			GameTypes: []models.GameType{},
+			Error:     fmt.Sprintf("Failed to fetch game types: %v", err),
+		}, err
+	}
+	return &response.GameTypeListResponse{
+		Success:   true,
+		GameTypes: gameTypes,
+		Count:     len(gameTypes),
+	}, nil
+}
+
+// gameTypeRules returns the rules of a game type, or nil for an untyped game,
+// which is played under the contract's rules: the higher score wins and ties
+// are draws.
+func (s *GameService) gameTypeRules(ctx context.Context, name string) (*models.GameType, error) {
+	if name == "" {
+		return nil, nil
+	}
+	var rules models.GameType
+	found, err := findOne(ctx, s.collection(gameTypesCollection), bson.M{"_id": NormalizeGameType(name)}, &rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load game type %s: %v", name, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGameType, name)
+	}
+	return &rules, nil
+}
+
+// scoreDirection returns which score wins a game of a type.
+func (s *GameService) scoreDirection(ctx context.Context, gameType string) (string, error) {
+	rules, err := s.gameTypeRules(ctx, gameType)
+	if err != nil {
+		return "", err
+	}
+	if rules == nil {
+		return models.ScoreDirectionHigher, nil
+	}
+	return rules.ScoreDirection, nil
+}
+
+// checkStakeRules refuses a stake outside its game type's stake range.
+func checkStakeRules(rules *models.GameType, amount uint64) error {
+	if rules == nil {
+		return nil
+	}
+	if amount < rules.MinStake {
+		return fmt.Errorf("%w: %s stakes must be at least %d", ErrGameRuleViolation, rules.Name, rules.MinStake)
+	}
+	if rules.MaxStake > 0 && amount > rules.MaxStake {
+		return fmt.Errorf("%w: %s stakes must be at most %d", ErrGameRuleViolation, rules.Name, rules.MaxStake)
+	}
+	return nil
+}
+
+// offeredGameType checks a stake offered before both players are known, on a
+// queue ticket or a challenge, against its game type's rules and returns the
+// type's stored name.
+func (s *GameService) offeredGameType(ctx context.Context, name string, amount uint64) (string, error) {
+	rules, err := s.gameTypeRules(ctx, name)
+	if err == nil {
+		err = checkStakeRules(rules, amount)
+	}
+	if err != nil || rules == nil {
+		return "", err
+	}
+	return rules.Name, nil
+}
+
+// payoutRules resolves the game type a payout is played under and sets it on
+// the request. A staked game's own type wins; a request naming a different
+// one is refused.
+func (s *GameService) payoutRules(ctx context.Context, req *request.PayWinnerRequest, game *data.Game) (*models.GameType, error) {
+	gameType := NormalizeGameType(req.GameType)
+	if game != nil {
+		if gameType != "" && gameType != NormalizeGameType(game.GameType) {
+			return nil, fmt.Errorf("%w: game was staked as %q, not %q", ErrGameRuleViolation, game.GameType, req.GameType)
+		}
+		gameType = NormalizeGameType(game.GameType)
+	}
+	req.GameType = gameType
+	return s.gameTypeRules(ctx, gameType)
+}
+
+// checkResultRules refuses a tie in a game type that does not allow one.
+func checkResultRules(rules *models.GameType, req *request.PayWinnerRequest) error {
+	if rules != nil && rules.TieHandling == models.TieHandlingReject && req.RequesterScore == req.AccepterScore {
+		return fmt.Errorf("%w: %s games cannot end in a tie", ErrGameRuleViolation, rules.Name)
+	}
+	return nil
+}
+
+// chainScores returns the scores to send to the contract, which always pays
+// the higher score. Lower-wins games swap them, so the lower score is paid
+// and ties stay ties.
+func chainScores(rules *models.GameType, requesterScore, accepterScore uint64) (uint64, uint64) {
+	if rules != nil && rules.ScoreDirection == models.ScoreDirectionLower {
+		return accepterScore, requesterScore
+	}
+	return requesterScore, accepterScore
+}
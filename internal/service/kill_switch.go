@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	systemFlagsCollection = "system_flags"
+	killSwitchID          = "kill_switch"
+
+	// killSwitchReadTimeout bounds each refresh of the flag, so a slow Mongo
+	// cannot hold up the stakes and payouts that check it.
+	killSwitchReadTimeout = 2 * time.Second
+)
+
+var ErrPaused = errors.New("staking and payouts are paused")
+
+// KillSwitchConfig controls how often the pause flag is re-read from Mongo and
+// when it engages by itself.
+type KillSwitchConfig struct {
+	// CacheTTL bounds how long a replica may act on a stale flag after another
+	// replica changes it.
+	CacheTTL time.Duration
+	// MinGasBalance, in MIST, pauses the service when the operator wallet
+	// drops below it. Zero disables the check.
+	MinGasBalance uint64
+}
+
+func DefaultKillSwitchConfig() KillSwitchConfig {
+	return KillSwitchConfig{CacheTTL: 5 * time.Second}
+}
+
+type killSwitchCache struct {
+	mu       sync.Mutex
+	state    models.KillSwitch
+	loadedAt time.Time
+	// version counts the writes to the cache, so a refresh that raced with a
+	// change made on this replica does not overwrite it.
+	version    int
+	refreshing bool
+}
+
+// killSwitchUnavailable is reported until the flag has been read once, so a
+// replica that cannot reach Mongo fails closed rather than staking and paying
+// while it may be paused.
+var killSwitchUnavailable = models.KillSwitch{
+	ID:     killSwitchID,
+	Paused: true,
+	Reason: "the pause flag has not been read yet",
+}
+
+func (s *GameService) ConfigureKillSwitch(config KillSwitchConfig) {
+	s.killSwitchConfig = config
+}
+
+// KillSwitch returns the pause flag, re-reading it from Mongo once the cached
+// copy is older than the TTL. One caller refreshes at a time, outside the lock,
+// while the others use the cached copy. If Mongo cannot be read the last known
+// state is kept; until the flag has loaded once it reads as paused.
+func (s *GameService) KillSwitch() models.KillSwitch {
+	cache := &s.killSwitch
+	cache.mu.Lock()
+	now := s.clock.Now()
+	fresh := !cache.loadedAt.IsZero() && now.Sub(cache.loadedAt) < s.killSwitchConfig.CacheTTL
+	if fresh || cache.refreshing {
+		state := cache.current()
+		cache.mu.Unlock()
+		return state
+	}
+	cache.refreshing = true
+	version := cache.version
+	cache.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), killSwitchReadTimeout)
+	state, err := s.loadKillSwitch(ctx)
+	cancel()
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.refreshing = false
+	if err != nil {
+		log.Printf("⚠️  Failed to refresh kill switch, keeping last state: %v", err)
+		return cache.current()
+	}
+	if cache.version != version {
+		return cache.current()
+	}
+	cache.state = state
+	cache.loadedAt = now
+	cache.version++
+	return state
+}
+
+// current returns the cached flag, or killSwitchUnavailable if it never
+// loaded. The caller holds mu.
+func (c *killSwitchCache) current() models.KillSwitch {
+	if c.loadedAt.IsZero() {
+		return killSwitchUnavailable
+	}
+	return c.state
+}
+
+// GetKillSwitch reads the pause flag straight from Mongo.
+func (s *GameService) GetKillSwitch() (*response.KillSwitchResponse, error) {
+	state, err := s.loadKillSwitch(context.Background())
+	if err != nil {
+		log.Printf("❌ Failed to read kill switch: %v", err)
+		return &response.KillSwitchResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to read kill switch: %v", err),
+		}, err
+	}
+	s.cacheKillSwitch(state)
+	return &response.KillSwitchResponse{Success: true, KillSwitch: &state}, nil
+}
+
+// SetKillSwitch pauses or resumes staking and payouts. Pausing needs a reason.
+// The change applies on this replica at once and on the others within the
+// cache TTL.
+func (s *GameService) SetKillSwitch(req *request.KillSwitchRequest, actor string) (*response.KillSwitchResponse, error) {
+	if req.Paused == nil || (*req.Paused && req.Reason == "") {
+		return &response.KillSwitchResponse{
+			Success: false,
+			Error:   "A reason is required to pause",
+		}, ErrInvalidAdminRequest
+	}
+	state, err := s.saveKillSwitch(context.Background(), models.KillSwitch{
+		Paused:    *req.Paused,
+		Reason:    req.Reason,
+		Source:    models.KillSwitchSourceAdmin,
+		UpdatedBy: actor,
+	})
+	if err != nil {
+		log.Printf("❌ Failed to update kill switch: %v", err)
+		return &response.KillSwitchResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to update kill switch: %v", err),
+		}, err
+	}
+	return &response.KillSwitchResponse{Success: true, KillSwitch: &state}, nil
+}
+
+// CheckGasBalance engages the kill switch when the operator wallet holds less
+// than the configured minimum. It never disengages it; an admin resumes once
+// the wallet is topped up. It reports whether it engaged the switch.
+func (s *GameService) CheckGasBalance(ctx context.Context) (bool, error) {
+	minimum := s.killSwitchConfig.MinGasBalance
+	if minimum == 0 || s.KillSwitch().Paused {
+		return false, nil
+	}
+	balance, err := s.suiClient.GetBalance(ctx)
+	if err != nil {
+		return false, err
+	}
+	if balance >= minimum {
+		return false, nil
+	}
+	if _, err := s.saveKillSwitch(ctx, models.KillSwitch{
+		Paused: true,
+		Reason: fmt.Sprintf("operator gas balance %d MIST is below the %d MIST minimum", balance, minimum),
+		Source: models.KillSwitchSourceGasMonitor,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RunGasMonitor checks the operator gas balance every interval until ctx is
+// done.
+func (s *GameService) RunGasMonitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CheckGasBalance(ctx); err != nil {
+				log.Printf("⚠️  Gas balance check failed: %v", err)
+			}
+		}
+	}
+}
+
+func (s *GameService) loadKillSwitch(ctx context.Context) (models.KillSwitch, error) {
+	var state models.KillSwitch
+	if _, err := findOne(ctx, s.collection(systemFlagsCollection), bson.M{"_id": killSwitchID}, &state); err != nil {
+		return models.KillSwitch{}, err
+	}
+	state.ID = killSwitchID
+	return state, nil
+}
+
+func (s *GameService) saveKillSwitch(ctx context.Context, state models.KillSwitch) (models.KillSwitch, error) {
+	state.ID = killSwitchID
+	state.UpdatedAt = s.clock.Now()
+	_, err := s.collection(systemFlagsCollection).UpdateOne(ctx,
+		bson.M{"_id": killSwitchID},
+		bson.M{"$set": bson.M{
+			"paused":     state.Paused,
+			"reason":     state.Reason,
+			"source":     state.Source,
+			"updated_by": state.UpdatedBy,
+			"updated_at": state.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return models.KillSwitch{}, err
+	}
+	s.cacheKillSwitch(state)
+
+	by := state.UpdatedBy
+	if by == "" {
+		by = state.Source
+	}
+	if state.Paused {
+		log.Printf("⛔ Kill switch engaged by %s: %s", by, state.Reason)
+	} else {
+		log.Printf("✅ Kill switch released by %s", by)
+	}
+	return state, nil
+}
+
+func (s *GameService) cacheKillSwitch(state models.KillSwitch) {
+	s.killSwitch.mu.Lock()
+	defer s.killSwitch.mu.Unlock()
+	s.killSwitch.state = state
+	s.killSwitch.loadedAt = s.clock.Now()
+	s.killSwitch.version++
+}
+
+// pausedError describes why a stake or payout was refused.
+func pausedError(state models.KillSwitch) string {
+	if state.Reason == "" {
+		return ErrPaused.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrPaused.Error(), state.Reason)
+}
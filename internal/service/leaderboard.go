@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/interfaces"
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	LeaderboardMetricWins   = "wins"
+	LeaderboardMetricProfit = "profit"
+	LeaderboardMetricVolume = "volume"
+
+	LeaderboardPeriodDay   = "day"
+	LeaderboardPeriodWeek  = "week"
+	LeaderboardPeriodMonth = "month"
+	LeaderboardPeriodAll   = "all"
+
+	DefaultLeaderboardLimit = 20
+	MaxLeaderboardLimit     = 100
+)
+
+var LeaderboardMetrics = []string{LeaderboardMetricWins, LeaderboardMetricProfit, LeaderboardMetricVolume}
+
+var LeaderboardPeriods = []string{LeaderboardPeriodDay, LeaderboardPeriodWeek, LeaderboardPeriodMonth, LeaderboardPeriodAll}
+
+// LeaderboardPeriodKey buckets a time into the UTC period a leaderboard
+// document belongs to.
+func LeaderboardPeriodKey(period string, t time.Time) string {
+	t = t.UTC()
+	switch period {
+	case LeaderboardPeriodDay:
+		return t.Format("2006-01-02")
+	case LeaderboardPeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case LeaderboardPeriodMonth:
+		return t.Format("2006-01")
+	default:
+		return LeaderboardPeriodAll
+	}
+}
+
+// updateLeaderboards folds a settled game into the materialized rankings of
+// both players for every period, overall and for its game type. Failures are
+// logged only; the payout itself already succeeded.
+func (s *GameService) updateLeaderboards(ctx context.Context, game models.PayWinner) {
+	playedAt := time.Unix(game.Timestamp, 0)
+	gameTypes := []string{""}
+	if game.GameType != "" {
+		gameTypes = append(gameTypes, game.GameType)
+	}
+
+	for _, address := range []string{game.RequesterAddress, game.AccepterAddress} {
+		var wins, losses, draws int64
+		switch game.Winner {
+		case address:
+			wins = 1
+		case "":
+			draws = 1
+		default:
+			losses = 1
+		}
+
+		for _, gameType := range gameTypes {
+			for _, period := range LeaderboardPeriods {
+				collection, filter := s.leaderboardCollection(gameType, bson.M{
+					"period":     period,
+					"period_key": LeaderboardPeriodKey(period, playedAt),
+					"address":    address,
+				})
+				update := bson.M{
+					"$inc": bson.M{
+						"games":  int64(1),
+						"wins":   wins,
+						"losses": losses,
+						"draws":  draws,
+						"profit": gameProfit(address, game.Winner, game),
+						"volume": int64(game.StakeAmount),
+					},
+					"$set": bson.M{"updated_at": time.Now()},
+				}
+				if _, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+					log.Printf("⚠️  Failed to update %s leaderboard for %s: %v", period, address, err)
+				}
+			}
+		}
+	}
+}
+
+func (s *GameService) GetLeaderboard(query *request.LeaderboardQuery) (*response.LeaderboardResponse, error) {
+	if query.Metric == "" {
+		query.Metric = LeaderboardMetricWins
+	}
+	if query.Period == "" {
+		query.Period = LeaderboardPeriodAll
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = DefaultLeaderboardLimit
+	}
+	if query.Limit > MaxLeaderboardLimit {
+		query.Limit = MaxLeaderboardLimit
+	}
+
+	ctx := context.Background()
+	periodKey := LeaderboardPeriodKey(query.Period, time.Now())
+	resp := &response.LeaderboardResponse{
+		Metric:    query.Metric,
+		Period:    query.Period,
+		PeriodKey: periodKey,
+		GameType:  NormalizeGameType(query.GameType),
+		Page:      query.Page,
+		Limit:     query.Limit,
+	}
+
+	collection, filter := s.leaderboardCollection(NormalizeGameType(query.GameType), bson.M{"period": query.Period, "period_key": periodKey})
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("❌ Failed to count %s leaderboard: %v", query.Period, err)
+		resp.Error = fmt.Sprintf("Failed to fetch leaderboard: %v", err)
+		return resp, err
+	}
+	resp.Total = total
+
+	skip := int64((query.Page - 1) * query.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: query.Metric, Value: -1}, {Key: "address", Value: 1}}).
+		SetSkip(skip).
+		SetLimit(int64(query.Limit))
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("❌ Failed to fetch %s leaderboard: %v", query.Period, err)
+		resp.Error = fmt.Sprintf("Failed to fetch leaderboard: %v", err)
+		return resp, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.LeaderboardEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		log.Printf("❌ Failed to decode %s leaderboard: %v", query.Period, err)
+		resp.Error = fmt.Sprintf("Failed to decode leaderboard: %v", err)
+		return resp, err
+	}
+
+	// Competition ranking: equal values share a rank, the next value skips ahead
+	for i := range entries {
+		if i > 0 && metricValue(entries[i], query.Metric) == metricValue(entries[i-1], query.Metric) {
+			entries[i].Rank = entries[i-1].Rank
+			continue
+		}
+		if i == 0 && skip > 0 {
+			rank, err := s.leaderboardRank(ctx, collection, filter, query.Metric, entries[i])
+			if err != nil {
+				resp.Error = fmt.Sprintf("Failed to rank leaderboard: %v", err)
+				return resp, err
+			}
+			entries[i].Rank = rank
+			continue
+		}
+		entries[i].Rank = skip + int64(i) + 1
+	}
+	if entries == nil {
+		entries = []models.LeaderboardEntry{}
+	}
+	resp.Entries = entries
+
+	if query.Address != "" {
+		var player models.LeaderboardEntry
+		playerFilter := bson.M{"address": query.Address}
+		for k, v := range filter {
+			playerFilter[k] = v
+		}
+		found, err := findOne(ctx, collection, playerFilter, &player)
+		if err != nil {
+			log.Printf("❌ Failed to fetch leaderboard entry for %s: %v", query.Address, err)
+			resp.Error = fmt.Sprintf("Failed to fetch player rank: %v", err)
+			return resp, err
+		}
+		if found {
+			rank, err := s.leaderboardRank(ctx, collection, filter, query.Metric, player)
+			if err != nil {
+				resp.Error = fmt.Sprintf("Failed to rank player: %v", err)
+				return resp, err
+			}
+			player.Rank = rank
+			resp.Player = &player
+		}
+	}
+
+	resp.Success = true
+	return resp, nil
+}
+
+// leaderboardCollection returns where a leaderboard lives: the overall one in
+// leaderboards, and each game type's in game_type_leaderboards, keyed by the
+// type as well.
+func (s *GameService) leaderboardCollection(gameType string, filter bson.M) (interfaces.MongoCollectionInterface, bson.M) {
+	if gameType == "" {
+		return s.collection("leaderboards"), filter
+	}
+	filter["game_type"] = gameType
+	return s.collection("game_type_leaderboards"), filter
+}
+
+// leaderboardRank counts how many players are strictly ahead of the entry.
+func (s *GameService) leaderboardRank(ctx context.Context, collection interfaces.MongoCollectionInterface, filter bson.M, metric string, entry models.LeaderboardEntry) (int64, error) {
+	ahead := bson.M{metric: bson.M{"$gt": metricValue(entry, metric)}}
+	for k, v := range filter {
+		ahead[k] = v
+	}
+	count, err := collection.CountDocuments(ctx, ahead)
+	if err != nil {
+		return 0, err
+	}
+	return count + 1, nil
+}
+
+func metricValue(entry models.LeaderboardEntry, metric string) int64 {
+	switch metric {
+	case LeaderboardMetricProfit:
+		return entry.Profit
+	case LeaderboardMetricVolume:
+		return entry.Volume
+	default:
+		return entry.Wins
+	}
+}
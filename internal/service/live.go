@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/live"
+)
+
+// DefaultLiveRoomSize caps the connections to a single match.
+const DefaultLiveRoomSize = 200
+
+var (
+	ErrMatchNotFound  = errors.New("match not found")
+	ErrMatchForbidden = errors.New("not allowed on this match")
+	ErrMatchNotLive   = errors.New("match is not in play")
+)
+
+// ConfigureLive replaces the live match hub with one admitting maxPerGame
+// connections per match. Existing connections stay on the old hub.
+func (s *GameService) ConfigureLive(maxPerGame int) {
+	s.live = live.NewHub(maxPerGame)
+}
+
+// JoinMatch opens a live channel on the game for the given role. Players must
+// be one of the game's two addresses. The new client is first sent the
+// current state of the game. The caller must Close the client.
+func (s *GameService) JoinMatch(gameID, role, address string) (*live.Client, error) {
+	game, err := s.liveGame(context.Background(), gameID)
+	if err != nil {
+		return nil, err
+	}
+	switch role {
+	case live.RolePlayer:
+		if address == "" || (address != game.RequesterAddress && address != game.AccepterAddress) {
+			return nil, ErrMatchForbidden
+		}
+	case live.RoleGameServer, live.RoleSpectator:
+	default:
+		return nil, ErrMatchForbidden
+	}
+
+	client, err := s.live.Join(gameID, role, address)
+	if err != nil {
+		return nil, err
+	}
+
+	state := map[string]interface{}{
+		"status":            game.Status,
+		"requester_address": game.RequesterAddress,
+		"accepter_address":  game.AccepterAddress,
+		"stake_amount":      game.StakeAmount,
+		"role":              role,
+	}
+	if game.Winner != "" {
+		state["winner"] = game.Winner
+	}
+	if game.RequesterScore != nil && game.AccepterScore != nil {
+		state["requester_score"] = *game.RequesterScore
+		state["accepter_score"] = *game.AccepterScore
+	} else if score := s.live.Score(gameID); score != nil {
+		state["requester_score"] = score["requester_score"]
+		state["accepter_score"] = score["accepter_score"]
+	}
+	client.Send(live.Message{Type: live.MessageState, GameID: gameID, Data: state})
+	return client, nil
+}
+
+// PushMatchScore broadcasts a running score from the game server to everyone
+// watching a staked match.
+func (s *GameService) PushMatchScore(client *live.Client, update *request.MatchScoreUpdate) error {
+	if client.Role != live.RoleGameServer {
+		return ErrMatchForbidden
+	}
+	game, err := s.liveGame(context.Background(), client.GameID)
+	if err != nil {
+		return err
+	}
+	if game.Status != GameStatusStaked {
+		return ErrMatchNotLive
+	}
+
+	s.live.Broadcast(live.Message{
+		Type:   live.MessageScore,
+		GameID: client.GameID,
+		Data: map[string]interface{}{
+			"requester_address": game.RequesterAddress,
+			"accepter_address":  game.AccepterAddress,
+			"requester_score":   update.RequesterScore,
+			"accepter_score":    update.AccepterScore,
+		},
+	})
+	return nil
+}
+
+func (s *GameService) liveGame(ctx context.Context, gameID string) (*data.Game, error) {
+	if _, err := primitive.ObjectIDFromHex(gameID); err != nil {
+		return nil, ErrMatchNotFound
+	}
+	raw, err := s.mongoClient.GetGame(ctx, gameID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, ErrMatchNotFound
+		}
+		return nil, err
+	}
+	game, ok := raw.(data.Game)
+	if !ok {
+		return nil, ErrMatchNotFound
+	}
+	return &game, nil
+}
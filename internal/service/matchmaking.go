@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"jollfi-gaming-api/internal/breaker"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/lease"
+	"jollfi-gaming-api/internal/matchmaking"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/screening"
+)
+
+var (
+	ErrInvalidTicket    = errors.New("invalid queue request")
+	ErrTicketNotFound   = errors.New("queue ticket not found")
+	ErrTicketNotWaiting = errors.New("queue ticket is no longer waiting")
+	ErrTicketForbidden  = errors.New("address may not act on this ticket")
+	ErrAlreadyQueued    = errors.New("address is already waiting in the queue")
+)
+
+const matchmakingCollection = "matchmaking_queue"
+
+// ConfigureMatchmaking replaces the pairing settings and the clock used to
+// timestamp tickets and widen rating windows.
+func (s *GameService) ConfigureMatchmaking(config matchmaking.Config, clock matchmaking.Clock) {
+	s.matchmaking = config
+	s.clock = clock
+}
+
+func (s *GameService) JoinQueue(req *request.JoinQueueRequest) (*response.QueueTicketResponse, error) {
+	if req.Address == "" || req.CoinID == "" || req.StakeAmount == 0 || req.RatingWindow < 0 {
+		return &response.QueueTicketResponse{
+			Success: false,
+			Error:   "Invalid queue request: missing required fields",
+		}, ErrInvalidTicket
+	}
+
+	ctx := context.Background()
+	collection := s.collection(matchmakingCollection)
+
+	gameType, err := s.offeredGameType(ctx, req.GameType, req.StakeAmount)
+	if err != nil {
+		return &response.QueueTicketResponse{Success: false, Error: err.Error()}, err
+	}
+
+	var existing models.QueueTicket
+	found, err := findOne(ctx, collection, bson.M{
+		"address": req.Address,
+		"status":  bson.M{"$in": []string{models.QueueStatusWaiting, models.QueueStatusMatching}},
+	}, &existing)
+	if err != nil {
+		log.Printf("❌ Failed to check queue for %s: %v", req.Address, err)
+		return &response.QueueTicketResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to join queue: %v", err),
+		}, err
+	}
+	if found {
+		return &response.QueueTicketResponse{
+			Success: false,
+			Ticket:  &existing,
+			Error:   ErrAlreadyQueued.Error(),
+		}, ErrAlreadyQueued
+	}
+
+	current, err := s.PlayerRating(ctx, req.Address)
+	if err != nil {
+		log.Printf("❌ Failed to fetch rating for %s: %v", req.Address, err)
+		return &response.QueueTicketResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to join queue: %v", err),
+		}, err
+	}
+
+	ticket := models.QueueTicket{
+		ID:           primitive.NewObjectID(),
+		Address:      req.Address,
+		CoinID:       req.CoinID,
+		StakeAmount:  req.StakeAmount,
+		GameType:     gameType,
+		Rating:       current.Rating,
+		RatingWindow: req.RatingWindow,
+		Status:       models.QueueStatusWaiting,
+		JoinedAt:     s.clock.Now(),
+		Live:         true,
+	}
+	_, err = collection.InsertOne(ctx, ticket)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent join won the unique index on waiting tickets
+		return &response.QueueTicketResponse{Success: false, Error: ErrAlreadyQueued.Error()}, ErrAlreadyQueued
+	}
+	if err != nil {
+		log.Printf("❌ Failed to queue %s: %v", req.Address, err)
+		return &response.QueueTicketResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to join queue: %v", err),
+		}, err
+	}
+
+	log.Printf("✅ %s joined the %d tier queue at rating %.0f", req.Address, req.StakeAmount, ticket.Rating)
+	return &response.QueueTicketResponse{
+		Success: true,
+		Ticket:  &ticket,
+	}, nil
+}
+
+func (s *GameService) GetQueueTicket(ticketID string) (*response.QueueTicketResponse, error) {
+	ticket, err := s.getQueueTicket(context.Background(), ticketID)
+	if err != nil {
+		return &response.QueueTicketResponse{Success: false, Error: err.Error()}, err
+	}
+	return &response.QueueTicketResponse{
+		Success: true,
+		Ticket:  ticket,
+	}, nil
+}
+
+func (s *GameService) LeaveQueue(ticketID string, req *request.LeaveQueueRequest) (*response.QueueTicketResponse, error) {
+	ctx := context.Background()
+
+	ticket, err := s.getQueueTicket(ctx, ticketID)
+	if err != nil {
+		return &response.QueueTicketResponse{Success: false, Error: err.Error()}, err
+	}
+	if !strings.EqualFold(ticket.Address, req.Address) {
+		return &response.QueueTicketResponse{
+			Success: false,
+			Error:   "Only the player who joined can leave the queue",
+		}, ErrTicketForbidden
+	}
+
+	left, err := s.transitionTicket(ctx, ticket.ID, models.QueueStatusWaiting, bson.M{"status": models.QueueStatusCancelled})
+	if err != nil {
+		log.Printf("❌ Failed to remove ticket %s: %v", ticketID, err)
+		return &response.QueueTicketResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to leave queue: %v", err),
+		}, err
+	}
+	if !left {
+		return &response.QueueTicketResponse{Success: false, Error: ErrTicketNotWaiting.Error()}, ErrTicketNotWaiting
+	}
+	ticket.Status = models.QueueStatusCancelled
+
+	log.Printf("✅ %s left the queue", req.Address)
+	return &response.QueueTicketResponse{
+		Success: true,
+		Ticket:  ticket,
+	}, nil
+}
+
+// MatchQueue runs one pairing pass: it expires stale tickets, pairs the rest
+// and stakes every match. It returns the number of games staked.
+func (s *GameService) MatchQueue(ctx context.Context) (int, error) {
+	collection := s.collection(matchmakingCollection)
+	now := s.clock.Now()
+
+	cursor, err := collection.Find(ctx, bson.M{"status": models.QueueStatusWaiting})
+	if err != nil {
+		return 0, err
+	}
+	var tickets []models.QueueTicket
+	err = cursor.All(ctx, &tickets)
+	cursor.Close(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, ticket := range tickets {
+		if s.matchmaking.Expired(ticket, now) {
+			if _, err := s.transitionTicket(ctx, ticket.ID, models.QueueStatusWaiting, bson.M{"status": models.QueueStatusExpired}); err != nil {
+				log.Printf("⚠️  Failed to expire ticket %s: %v", ticket.ID.Hex(), err)
+			}
+		}
+	}
+
+	if s.KillSwitch().Paused {
+		// Leave the queue waiting rather than failing every match
+		return 0, nil
+	}
+
+	staked := 0
+	for _, match := range s.matchmaking.FindMatches(tickets, now) {
+		if s.stakeMatch(ctx, match, now) {
+			staked++
+		}
+	}
+	return staked, nil
+}
+
+// RunMatchmaker pairs the queue every interval until ctx is done.
+func (s *GameService) RunMatchmaker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			staked, err := s.MatchQueue(ctx)
+			if err != nil {
+				log.Printf("⚠️  Matchmaking pass failed: %v", err)
+				continue
+			}
+			if staked > 0 {
+				log.Printf("🎮 Matchmaking staked %d games", staked)
+			}
+		}
+	}
+}
+
+// stakeMatch claims both tickets and stakes the game, with the longer waiting
+// player as requester. Claims are conditional on the tickets still waiting, so
+// another replica pairing the same queue cannot stake them twice.
+func (s *GameService) stakeMatch(ctx context.Context, match matchmaking.Match, now time.Time) bool {
+	first, second := match.First, match.Second
+	claim := bson.M{"status": models.QueueStatusMatching}
+
+	claimed, err := s.transitionTicket(ctx, first.ID, models.QueueStatusWaiting, claim)
+	if err != nil || !claimed {
+		return false
+	}
+	claimed, err = s.transitionTicket(ctx, second.ID, models.QueueStatusWaiting, claim)
+	if err != nil || !claimed {
+		if _, err := s.transitionTicket(ctx, first.ID, models.QueueStatusMatching, bson.M{"status": models.QueueStatusWaiting}); err != nil {
+			log.Printf("⚠️  Failed to return ticket %s to the queue: %v", first.ID.Hex(), err)
+		}
+		return false
+	}
+
+	resp, err := s.stakeGame(&request.StakeRequest{
+		RequesterCoinID:  first.CoinID,
+		AccepterCoinID:   second.CoinID,
+		RequesterAddress: first.Address,
+		AccepterAddress:  second.Address,
+		StakeAmount:      first.StakeAmount,
+		GameType:         first.GameType,
+	}, "")
+	if err != nil {
+		log.Printf("❌ Matchmaking stake failed for %s vs %s: %v", first.Address, second.Address, err)
+		s.failMatch(ctx, first, second, resp.Error, err)
+		return false
+	}
+
+	for _, pair := range [][2]models.QueueTicket{{first, second}, {second, first}} {
+		s.finishTicket(ctx, pair[0].ID, bson.M{
+			"status":             models.QueueStatusMatched,
+			"opponent":           pair[1].Address,
+			"game_id":            resp.GameID,
+			"transaction_digest": resp.TransactionDigest,
+			"matched_at":         now,
+		})
+	}
+	log.Printf("✅ Matched %s (%.0f) with %s (%.0f) for %d", first.Address, first.Rating, second.Address, second.Rating, first.StakeAmount)
+	return true
+}
+
+// failMatch settles both claimed tickets after a failed stake. A refusal that
+// names one player fails only that player's ticket and puts the other back in
+// the queue. A transient error puts both back to be paired again; anything
+// else fails both.
+func (s *GameService) failMatch(ctx context.Context, first, second models.QueueTicket, reason string, err error) {
+	requeue := bson.M{"status": models.QueueStatusWaiting}
+	failed := bson.M{"status": models.QueueStatusFailed, "error": reason}
+
+	if transientStakeError(err) {
+		s.finishTicket(ctx, first.ID, requeue)
+		s.finishTicket(ctx, second.ID, requeue)
+		return
+	}
+	culprit := stakeCulprit(err)
+	for _, ticket := range []models.QueueTicket{first, second} {
+		if culprit != "" && screening.NormalizeAddress(ticket.Address) != culprit {
+			s.finishTicket(ctx, ticket.ID, requeue)
+			continue
+		}
+		s.finishTicket(ctx, ticket.ID, failed)
+	}
+}
+
+// stakeCulprit returns the normalized address a stake was refused for, or ""
+// if the refusal does not name a player.
+func stakeCulprit(err error) string {
+	var refusal *StakeRefusal
+	if errors.As(err, &refusal) {
+		return screening.NormalizeAddress(refusal.Address)
+	}
+	var blocked *BlockedError
+	if errors.As(err, &blocked) {
+		return screening.NormalizeAddress(blocked.Address)
+	}
+	return ""
+}
+
+// transientStakeError reports whether a stake failed for reasons that have
+// nothing to do with the players, so the same pair can be staked again.
+func transientStakeError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, ErrWalletBusy) || errors.Is(err, ErrPaused) ||
+		errors.Is(err, ErrScreeningUnavailable) || errors.Is(err, breaker.ErrOpen) ||
+		errors.Is(err, lease.ErrLost) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &netErr)
+}
+
+func (s *GameService) finishTicket(ctx context.Context, id primitive.ObjectID, updates bson.M) {
+	if _, err := s.transitionTicket(ctx, id, models.QueueStatusMatching, updates); err != nil {
+		log.Printf("⚠️  Failed to update ticket %s: %v", id.Hex(), err)
+	}
+}
+
+// transitionTicket moves a ticket out of fromStatus. A ticket that leaves the
+// queue for good loses its live flag, freeing the address to join again.
+func (s *GameService) transitionTicket(ctx context.Context, id primitive.ObjectID, fromStatus string, updates bson.M) (bool, error) {
+	update := bson.M{"$set": updates}
+	if status := updates["status"]; status != models.QueueStatusWaiting && status != models.QueueStatusMatching {
+		update["$unset"] = bson.M{"live": ""}
+	}
+	result, err := s.collection(matchmakingCollection).UpdateOne(ctx,
+		bson.M{"_id": id, "status": fromStatus},
+		update,
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (s *GameService) getQueueTicket(ctx context.Context, ticketID string) (*models.QueueTicket, error) {
+	id, err := primitive.ObjectIDFromHex(ticketID)
+	if err != nil {
+		return nil, ErrTicketNotFound
+	}
+	var ticket models.QueueTicket
+	found, err := findOne(ctx, s.collection(matchmakingCollection), bson.M{"_id": id}, &ticket)
+	if err != nil {
+		log.Printf("❌ Failed to fetch ticket %s: %v", ticketID, err)
+		return nil, fmt.Errorf("failed to fetch queue ticket: %v", err)
+	}
+	if !found {
+		return nil, ErrTicketNotFound
+	}
+	return &ticket, nil
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	pendingPayoutsCollection = "pending_payouts"
+
+	PayoutStatusPendingApproval = "pending_approval"
+	PayoutStatusSending         = "sending"
+	PayoutStatusExpired         = "expired"
+
+	DefaultPendingPayoutLimit = 50
+	MaxPendingPayoutLimit     = 500
+)
+
+var (
+	ErrPayoutPendingApproval   = errors.New("payout pending approval")
+	ErrPendingPayoutNotFound   = errors.New("pending payout not found")
+	ErrPendingPayoutNotPending = errors.New("pending payout is not awaiting approval")
+	ErrPendingPayoutExpired    = errors.New("pending payout expired")
+	ErrAlreadyApproved         = errors.New("payout already approved by this admin")
+	ErrApproverUnverified      = errors.New("approving a payout requires a named admin key")
+)
+
+// PayoutApprovalConfig sets which payouts need sign-off from several admins
+// before they are sent.
+type PayoutApprovalConfig struct {
+	// Threshold is the stake amount at or above which a payout needs
+	// approval. 0 turns approvals off.
+	Threshold uint64
+	// Required is how many distinct admins must approve.
+	Required int
+	// TTL is how long a pending payout waits before it expires.
+	TTL time.Duration
+}
+
+func DefaultPayoutApprovalConfig() PayoutApprovalConfig {
+	return PayoutApprovalConfig{Required: 2, TTL: day}
+}
+
+func (s *GameService) ConfigurePayoutApproval(config PayoutApprovalConfig) {
+	s.payoutApproval = config
+}
+
+// dispatchPayout sends a payout that passed the payout guard, unless its stake
+// needs approval first. The threshold applies to the stake recorded for the
+// game, not the one reported with the result. status is the game's status
+// now, which it leaves either way.
+func (s *GameService) dispatchPayout(ctx context.Context, req *request.PayWinnerRequest, game *data.Game, status string) (*response.PayWinnerResponse, error) {
+	if game == nil {
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   ErrGameNotFound.Error(),
+		}, ErrGameNotFound
+	}
+	if threshold := s.payoutApproval.Threshold; threshold > 0 && game.StakeAmount >= threshold {
+		return s.requestPayoutApproval(ctx, req, game, status)
+	}
+	return s.sendPayout(req, game, status)
+}
+
+// requestPayoutApproval parks a payout until enough admins approve it, and
+// moves its game from status to pending_approval so it cannot be settled in
+// the meantime.
+func (s *GameService) requestPayoutApproval(ctx context.Context, req *request.PayWinnerRequest, game *data.Game, status string) (*response.PayWinnerResponse, error) {
+	required := s.payoutApproval.Required
+	if required < 1 {
+		required = 1
+	}
+	now := s.clock.Now()
+	pending := models.PendingPayout{
+		ID:               primitive.NewObjectID(),
+		GameType:         req.GameType,
+		RequesterAddress: req.RequesterAddress,
+		AccepterAddress:  req.AccepterAddress,
+		RequesterScore:   req.RequesterScore,
+		AccepterScore:    req.AccepterScore,
+		StakeAmount:      game.StakeAmount,
+		GameServer:       req.GameServer,
+		GameID:           game.ID.Hex(),
+		Required:         required,
+		Approvals:        []models.PayoutApproval{},
+		Status:           PayoutStatusPendingApproval,
+		ExpiresAt:        now.Add(s.payoutApproval.TTL),
+		CreatedAt:        now,
+	}
+	if err := s.parkGame(ctx, pending.GameID, status, GameStatusPendingApproval); err != nil {
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	if _, err := s.collection(pendingPayoutsCollection).InsertOne(ctx, pending); err != nil {
+		log.Printf("❌ Failed to queue payout for approval: %v", err)
+		s.mongoClient.TransitionGame(ctx, pending.GameID, GameStatusPendingApproval, bson.M{"status": status})
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to queue payout for approval: %v", err),
+		}, err
+	}
+
+	log.Printf("⚠️  Payout %s for stake %d needs %d approvals", pending.ID.Hex(), game.StakeAmount, required)
+	return &response.PayWinnerResponse{
+		Success:         false,
+		Status:          PayoutStatusPendingApproval,
+		PendingPayoutID: pending.ID.Hex(),
+		Error:           fmt.Sprintf("Payout needs approval from %d admins", required),
+	}, ErrPayoutPendingApproval
+}
+
+// ListPendingPayouts returns pending payouts in a status, pending_approval by
+// default, newest first. Payouts past their deadline are expired first.
+func (s *GameService) ListPendingPayouts(query *request.PendingPayoutQuery) (*response.PendingPayoutListResponse, error) {
+	ctx := context.Background()
+	if _, err := s.ExpirePendingPayouts(ctx, s.clock.Now()); err != nil {
+		log.Printf("⚠️  Failed to expire pending payouts: %v", err)
+	}
+
+	status := query.Status
+	if status == "" {
+		status = PayoutStatusPendingApproval
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultPendingPayoutLimit
+	}
+	if limit > MaxPendingPayoutLimit {
+		limit = MaxPendingPayoutLimit
+	}
+
+	payouts := []models.PendingPayout{}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit))
+	if err := findAll(ctx, s.collection(pendingPayoutsCollection), bson.M{"status": status}, &payouts, opts); err != nil {
+		log.Printf("❌ Failed to fetch pending payouts: %v", err)
+		return &response.PendingPayoutListResponse{
+			Success: false,
+			Payouts: []models.PendingPayout{},
+			Error:   fmt.Sprintf("Failed to fetch pending payouts: %v", err),
+		}, err
+	}
+	return &response.PendingPayoutListResponse{
+		Success: true,
+		Payouts: payouts,
+		Count:   len(payouts),
+	}, nil
+}
+
+// ApprovePendingPayout records admin's approval of a pending payout and sends
+// it once enough distinct admins have approved. The kill switch and address
+// screening are checked again before sending. If sending fails the payout
+// stays pending with the error recorded, and any of its approvers can retry
+// by approving again.
+func (s *GameService) ApprovePendingPayout(id, admin string) (*response.PendingPayoutResponse, error) {
+	if admin == "" {
+		return &response.PendingPayoutResponse{Success: false, Error: ErrApproverUnverified.Error()}, ErrApproverUnverified
+	}
+	ctx := context.Background()
+	pending, err := s.findPendingPayout(ctx, id)
+	if err != nil {
+		return &response.PendingPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+	if err := s.checkPendingPayout(ctx, pending); err != nil {
+		return &response.PendingPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+
+	if !pending.ApprovedBy(admin) {
+		result, err := s.collection(pendingPayoutsCollection).UpdateOne(ctx,
+			bson.M{
+				"_id":             pending.ID,
+				"status":          PayoutStatusPendingApproval,
+				"expires_at":      bson.M{"$gt": s.clock.Now()},
+				"approvals.admin": bson.M{"$ne": admin},
+			},
+			bson.M{"$push": bson.M{"approvals": models.PayoutApproval{Admin: admin, ApprovedAt: s.clock.Now()}}},
+		)
+		if err != nil {
+			log.Printf("❌ Failed to record approval of payout %s: %v", id, err)
+			return &response.PendingPayoutResponse{Success: false, Error: err.Error()}, err
+		}
+		if pending, err = s.findPendingPayout(ctx, id); err != nil {
+			return &response.PendingPayoutResponse{Success: false, Error: err.Error()}, err
+		}
+		if result.MatchedCount == 0 {
+			// Another request changed the payout first
+			if err := s.checkPendingPayout(ctx, pending); err != nil {
+				return &response.PendingPayoutResponse{Success: false, Error: err.Error()}, err
+			}
+		}
+		log.Printf("✅ Payout %s approved by %s (%d/%d)", id, admin, len(pending.Approvals), pending.Required)
+	} else if len(pending.Approvals) < pending.Required {
+		return &response.PendingPayoutResponse{Success: false, Error: ErrAlreadyApproved.Error()}, ErrAlreadyApproved
+	}
+
+	if len(pending.Approvals) < pending.Required {
+		return &response.PendingPayoutResponse{Success: true, Payout: pending}, nil
+	}
+	return s.sendPendingPayout(ctx, pending)
+}
+
+// sendPendingPayout sends a payout that collected its approvals. Only one
+// approver can claim the send when several approve at once.
+func (s *GameService) sendPendingPayout(ctx context.Context, pending *models.PendingPayout) (*response.PendingPayoutResponse, error) {
+	id := pending.ID.Hex()
+	if state := s.KillSwitch(); state.Paused {
+		return &response.PendingPayoutResponse{Success: false, Error: pausedError(state)}, ErrPaused
+	}
+	if err := s.screenAddresses(ctx, "pay_winner", pending.RequesterAddress, pending.AccepterAddress); err != nil {
+		return &response.PendingPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+
+	result, err := s.collection(pendingPayoutsCollection).UpdateOne(ctx,
+		bson.M{"_id": pending.ID, "status": PayoutStatusPendingApproval},
+		bson.M{"$set": bson.M{"status": PayoutStatusSending}},
+	)
+	if err != nil {
+		return &response.PendingPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+	if result.MatchedCount == 0 {
+		return &response.PendingPayoutResponse{Success: false, Error: ErrPendingPayoutNotPending.Error()}, ErrPendingPayoutNotPending
+	}
+
+	game := s.payoutGame(ctx, pending.GameID)
+
+	payout := &request.PayWinnerRequest{
+		GameID:           pending.GameID,
+		GameType:         pending.GameType,
+		RequesterAddress: pending.RequesterAddress,
+		AccepterAddress:  pending.AccepterAddress,
+		RequesterScore:   pending.RequesterScore,
+		AccepterScore:    pending.AccepterScore,
+		StakeAmount:      pending.StakeAmount,
+		GameServer:       pending.GameServer,
+	}
+	sent, err := s.sendPayout(payout, game, GameStatusPendingApproval)
+	if err != nil {
+		log.Printf("❌ Approved payout %s failed, keeping it pending: %v", id, err)
+		s.collection(pendingPayoutsCollection).UpdateOne(ctx,
+			bson.M{"_id": pending.ID},
+			bson.M{"$set": bson.M{"status": PayoutStatusPendingApproval, "last_error": err.Error()}},
+		)
+		return &response.PendingPayoutResponse{
+			Success: false,
+			Error:   sent.Error,
+		}, err
+	}
+
+	s.collection(pendingPayoutsCollection).UpdateOne(ctx,
+		bson.M{"_id": pending.ID},
+		bson.M{
+			"$set":   bson.M{"status": PayoutStatusApproved, "transaction_digest": sent.TransactionDigest},
+			"$unset": bson.M{"last_error": ""},
+		},
+	)
+	log.Printf("✅ Pending payout %s sent after %d approvals", id, len(pending.Approvals))
+	pending, err = s.findPendingPayout(ctx, id)
+	if err != nil {
+		return &response.PendingPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+	return &response.PendingPayoutResponse{Success: true, Payout: pending}, nil
+}
+
+// checkPendingPayout returns why a payout cannot be approved, expiring it if
+// its deadline has passed.
+func (s *GameService) checkPendingPayout(ctx context.Context, pending *models.PendingPayout) error {
+	if pending.Status != PayoutStatusPendingApproval {
+		return ErrPendingPayoutNotPending
+	}
+	if !s.clock.Now().Before(pending.ExpiresAt) {
+		s.expirePendingPayout(ctx, pending)
+		return ErrPendingPayoutExpired
+	}
+	return nil
+}
+
+// ExpirePendingPayouts moves every pending payout whose deadline passed before
+// now to expired and returns how many it expired. Their games go back to
+// staked so the result can be reported again.
+func (s *GameService) ExpirePendingPayouts(ctx context.Context, now time.Time) (int, error) {
+	var payouts []models.PendingPayout
+	filter := bson.M{"status": PayoutStatusPendingApproval, "expires_at": bson.M{"$lte": now}}
+	if err := findAll(ctx, s.collection(pendingPayoutsCollection), filter, &payouts); err != nil {
+		return 0, err
+	}
+	expired := 0
+	for i := range payouts {
+		if s.expirePendingPayout(ctx, &payouts[i]) {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// RunPayoutExpiry expires payouts that waited too long for approval every
+// interval until ctx is done.
+func (s *GameService) RunPayoutExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if expired, err := s.ExpirePendingPayouts(ctx, now); err != nil {
+				log.Printf("⚠️  Pending payout sweep failed: %v", err)
+			} else if expired > 0 {
+				log.Printf("🧹 Expired %d pending payouts", expired)
+			}
+		}
+	}
+}
+
+func (s *GameService) expirePendingPayout(ctx context.Context, pending *models.PendingPayout) bool {
+	result, err := s.collection(pendingPayoutsCollection).UpdateOne(ctx,
+		bson.M{"_id": pending.ID, "status": PayoutStatusPendingApproval},
+		bson.M{"$set": bson.M{"status": PayoutStatusExpired}},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		return false
+	}
+	if pending.GameID != "" {
+		if _, err := s.mongoClient.TransitionGame(ctx, pending.GameID, GameStatusPendingApproval, bson.M{"status": GameStatusStaked}); err != nil {
+			log.Printf("⚠️  Failed to release game %s after its payout expired: %v", pending.GameID, err)
+		}
+	}
+	log.Printf("⛔ Pending payout %s expired with %d/%d approvals", pending.ID.Hex(), len(pending.Approvals), pending.Required)
+	return true
+}
+
+func (s *GameService) findPendingPayout(ctx context.Context, id string) (*models.PendingPayout, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrPendingPayoutNotFound
+	}
+	var pending models.PendingPayout
+	found, err := findOne(ctx, s.collection(pendingPayoutsCollection), bson.M{"_id": oid}, &pending)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrPendingPayoutNotFound
+	}
+	return &pending, nil
+}
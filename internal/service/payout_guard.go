@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	heldPayoutsCollection = "held_payouts"
+
+	PayoutStatusHeld     = "held_for_review"
+	PayoutStatusApproved = "approved"
+	PayoutStatusRejected = "rejected"
+
+	PayoutViolationScore    = "score_implausible"
+	PayoutViolationDuration = "game_too_short"
+	PayoutViolationDailyCap = "daily_cap_exceeded"
+
+	DefaultHeldPayoutLimit = 50
+	MaxHeldPayoutLimit     = 500
+)
+
+var (
+	ErrPayoutHeld           = errors.New("payout held for review")
+	ErrHeldPayoutNotFound   = errors.New("held payout not found")
+	ErrHeldPayoutNotPending = errors.New("held payout already reviewed")
+	ErrInvalidPayoutReview  = errors.New("invalid payout review")
+)
+
+// PayoutGuardConfig sets the checks a payout must pass before it is sent.
+// Zero values turn a check off.
+type PayoutGuardConfig struct {
+	// MaxScore caps either player's score; MaxScores overrides it per game
+	// type.
+	MaxScore  uint64
+	MaxScores map[string]uint64
+	// MinGameDuration is the shortest plausible time between stake and
+	// payout.
+	MinGameDuration time.Duration
+	// DailyCap limits the total stake one game server can pay out over 24
+	// hours.
+	DailyCap uint64
+}
+
+func (s *GameService) ConfigurePayoutGuard(config PayoutGuardConfig) {
+	s.payoutGuard = config
+}
+
+// checkPayout returns the guard's violations for a payout, if any. A game
+// type's own max score takes precedence over the guard's.
+func (s *GameService) checkPayout(ctx context.Context, req *request.PayWinnerRequest, game *data.Game, rules *models.GameType) ([]string, error) {
+	config := s.payoutGuard
+	now := s.clock.Now()
+	var violations []string
+
+	maxScore, ok := config.MaxScores[req.GameType]
+	if !ok {
+		maxScore = config.MaxScore
+	}
+	if rules != nil && rules.MaxScore > 0 {
+		maxScore = rules.MaxScore
+	}
+	if maxScore > 0 && (req.RequesterScore > maxScore || req.AccepterScore > maxScore) {
+		violations = append(violations, PayoutViolationScore)
+	}
+
+	if config.MinGameDuration > 0 && now.Sub(gameStakedAt(*game)) < config.MinGameDuration {
+		violations = append(violations, PayoutViolationDuration)
+	}
+
+	if config.DailyCap > 0 {
+		var payouts []models.PayWinner
+		filter := bson.M{
+			"game_server": req.GameServer,
+			"timestamp":   bson.M{"$gte": now.Add(-24 * time.Hour).Unix()},
+		}
+		if err := findAll(ctx, s.collection("pay_winners"), filter, &payouts); err != nil {
+			return nil, fmt.Errorf("failed to total payouts for %s: %v", req.GameServer, err)
+		}
+		total := req.StakeAmount * 2
+		for _, payout := range payouts {
+			total += payout.TotalStake
+		}
+		if total > config.DailyCap {
+			violations = append(violations, PayoutViolationDailyCap)
+		}
+	}
+	return violations, nil
+}
+
+// holdPayout parks a payout for review instead of sending it, and moves its
+// game to held_for_review so it cannot be settled again in the meantime.
+func (s *GameService) holdPayout(ctx context.Context, req *request.PayWinnerRequest, game *data.Game, violations []string) (*response.PayWinnerResponse, error) {
+	held := models.HeldPayout{
+		ID:               primitive.NewObjectID(),
+		GameType:         req.GameType,
+		RequesterAddress: req.RequesterAddress,
+		AccepterAddress:  req.AccepterAddress,
+		RequesterScore:   req.RequesterScore,
+		AccepterScore:    req.AccepterScore,
+		StakeAmount:      req.StakeAmount,
+		GameServer:       req.GameServer,
+		GameID:           game.ID.Hex(),
+		Violations:       violations,
+		Status:           PayoutStatusHeld,
+		CreatedAt:        s.clock.Now(),
+	}
+	if err := s.parkGame(ctx, held.GameID, GameStatusStaked, GameStatusHeldForReview); err != nil {
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	if _, err := s.collection(heldPayoutsCollection).InsertOne(ctx, held); err != nil {
+		log.Printf("❌ Failed to hold payout: %v", err)
+		s.mongoClient.TransitionGame(ctx, held.GameID, GameStatusHeldForReview, bson.M{"status": GameStatusStaked})
+		return &response.PayWinnerResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to hold payout for review: %v", err),
+		}, err
+	}
+
+	log.Printf("⚠️  Payout %s held for review: %s", held.ID.Hex(), strings.Join(violations, ", "))
+	return &response.PayWinnerResponse{
+		Success:      false,
+		Status:       PayoutStatusHeld,
+		HeldPayoutID: held.ID.Hex(),
+		Violations:   violations,
+		Error:        "Payout held for review: " + strings.Join(violations, ", "),
+	}, ErrPayoutHeld
+}
+
+// ListHeldPayouts returns held payouts in a status, held_for_review by
+// default, newest first.
+func (s *GameService) ListHeldPayouts(query *request.HeldPayoutQuery) (*response.HeldPayoutListResponse, error) {
+	status := query.Status
+	if status == "" {
+		status = PayoutStatusHeld
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultHeldPayoutLimit
+	}
+	if limit > MaxHeldPayoutLimit {
+		limit = MaxHeldPayoutLimit
+	}
+
+	payouts := []models.HeldPayout{}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit))
+	if err := findAll(context.Background(), s.collection(heldPayoutsCollection), bson.M{"status": status}, &payouts, opts); err != nil {
+		log.Printf("❌ Failed to fetch held payouts: %v", err)
+		return &response.HeldPayoutListResponse{
+			Success: false,
+			Payouts: []models.HeldPayout{},
+			Error:   fmt.Sprintf("Failed to fetch held payouts: %v", err),
+		}, err
+	}
+	return &response.HeldPayoutListResponse{
+		Success: true,
+		Payouts: payouts,
+		Count:   len(payouts),
+	}, nil
+}
+
+// ApproveHeldPayout sends a held payout as it was reported. The kill switch
+// and address screening still apply, and a stake above the approval threshold
+// still needs approval from several admins. If sending fails the payout goes
+// back to held_for_review with the error recorded.
+func (s *GameService) ApproveHeldPayout(id, actor string, req *request.ReviewPayoutRequest) (*response.HeldPayoutResponse, error) {
+	ctx := context.Background()
+	held, err := s.findHeldPayout(ctx, id)
+	if err != nil {
+		return &response.HeldPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+	if state := s.KillSwitch(); state.Paused {
+		return &response.HeldPayoutResponse{Success: false, Error: pausedError(state)}, ErrPaused
+	}
+	if err := s.screenAddresses(ctx, "pay_winner", held.RequesterAddress, held.AccepterAddress); err != nil {
+		return &response.HeldPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+	if err := s.reviewHeldPayout(ctx, held, PayoutStatusApproved, actor, req.Note); err != nil {
+		return &response.HeldPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+
+	game := s.payoutGame(ctx, held.GameID)
+
+	payout := &request.PayWinnerRequest{
+		GameID:           held.GameID,
+		GameType:         held.GameType,
+		RequesterAddress: held.RequesterAddress,
+		AccepterAddress:  held.AccepterAddress,
+		RequesterScore:   held.RequesterScore,
+		AccepterScore:    held.AccepterScore,
+		StakeAmount:      held.StakeAmount,
+		GameServer:       held.GameServer,
+	}
+	sent, err := s.dispatchPayout(ctx, payout, game, GameStatusHeldForReview)
+	if errors.Is(err, ErrPayoutPendingApproval) {
+		s.collection(heldPayoutsCollection).UpdateOne(ctx,
+			bson.M{"_id": held.ID},
+			bson.M{"$set": bson.M{"pending_payout_id": sent.PendingPayoutID}},
+		)
+		log.Printf("✅ Held payout %s approved by %s, now pending approval as %s", id, actor, sent.PendingPayoutID)
+		return s.heldPayoutResponse(ctx, id)
+	}
+	if err != nil {
+		log.Printf("❌ Approved payout %s failed, holding it again: %v", id, err)
+		s.collection(heldPayoutsCollection).UpdateOne(ctx,
+			bson.M{"_id": held.ID},
+			bson.M{"$set": bson.M{"status": PayoutStatusHeld, "last_error": err.Error()}},
+		)
+		return &response.HeldPayoutResponse{
+			Success: false,
+			Error:   sent.Error,
+		}, err
+	}
+
+	s.collection(heldPayoutsCollection).UpdateOne(ctx,
+		bson.M{"_id": held.ID},
+		bson.M{"$set": bson.M{"transaction_digest": sent.TransactionDigest}, "$unset": bson.M{"last_error": ""}},
+	)
+	log.Printf("✅ Held payout %s approved by %s", id, actor)
+	return s.heldPayoutResponse(ctx, id)
+}
+
+// RejectHeldPayout drops a held payout. Its game goes back to staked so the
+// correct result can be reported.
+func (s *GameService) RejectHeldPayout(id, actor string, req *request.ReviewPayoutRequest) (*response.HeldPayoutResponse, error) {
+	if strings.TrimSpace(req.Note) == "" {
+		err := fmt.Errorf("%w: a note is required to reject a payout", ErrInvalidPayoutReview)
+		return &response.HeldPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+	ctx := context.Background()
+	held, err := s.findHeldPayout(ctx, id)
+	if err != nil {
+		return &response.HeldPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+	if err := s.reviewHeldPayout(ctx, held, PayoutStatusRejected, actor, req.Note); err != nil {
+		return &response.HeldPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+	if held.GameID != "" {
+		if _, err := s.mongoClient.TransitionGame(ctx, held.GameID, GameStatusHeldForReview, bson.M{"status": GameStatusStaked}); err != nil {
+			log.Printf("⚠️  Failed to release game %s after rejecting its payout: %v", held.GameID, err)
+		}
+	}
+	log.Printf("⛔ Held payout %s rejected by %s: %s", id, actor, req.Note)
+	return s.heldPayoutResponse(ctx, id)
+}
+
+func (s *GameService) findHeldPayout(ctx context.Context, id string) (*models.HeldPayout, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrHeldPayoutNotFound
+	}
+	var held models.HeldPayout
+	found, err := findOne(ctx, s.collection(heldPayoutsCollection), bson.M{"_id": oid}, &held)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrHeldPayoutNotFound
+	}
+	return &held, nil
+}
+
+// reviewHeldPayout moves a payout out of held_for_review. Only one review can
+// win when two admins act at once.
+func (s *GameService) reviewHeldPayout(ctx context.Context, held *models.HeldPayout, status, actor, note string) error {
+	result, err := s.collection(heldPayoutsCollection).UpdateOne(ctx,
+		bson.M{"_id": held.ID, "status": PayoutStatusHeld},
+		bson.M{"$set": bson.M{
+			"status":      status,
+			"reviewed_by": actor,
+			"review_note": note,
+			"reviewed_at": s.clock.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrHeldPayoutNotPending
+	}
+	return nil
+}
+
+func (s *GameService) heldPayoutResponse(ctx context.Context, id string) (*response.HeldPayoutResponse, error) {
+	held, err := s.findHeldPayout(ctx, id)
+	if err != nil {
+		return &response.HeldPayoutResponse{Success: false, Error: err.Error()}, err
+	}
+	return &response.HeldPayoutResponse{
+		Success: true,
+		Payout:  held,
+	}, nil
+}
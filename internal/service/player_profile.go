@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+)
+
+// maxHeadToHeadOpponents caps how many rivals are reported on a profile.
+const maxHeadToHeadOpponents = 5
+
+func (s *GameService) GetPlayerProfile(address string) (*response.PlayerProfileResponse, error) {
+	if address == "" {
+		return &response.PlayerProfileResponse{
+			Success: false,
+			Error:   "Address is required",
+		}, fmt.Errorf("address is required")
+	}
+
+	ctx := context.Background()
+
+	stats, err := s.mongoClient.GetUserStats(ctx, address)
+	if err != nil {
+		log.Printf("❌ Failed to fetch user stats for %s: %v", address, err)
+		return &response.PlayerProfileResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to fetch player stats: %v", err),
+		}, err
+	}
+
+	var payouts []models.PayWinner
+	if err := s.findPlayerRecords(ctx, "pay_winners", address, &payouts); err != nil {
+		log.Printf("❌ Failed to fetch payouts for %s: %v", address, err)
+		return &response.PlayerProfileResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to fetch player games: %v", err),
+		}, err
+	}
+
+	var stakes []models.Stake
+	if err := s.findPlayerRecords(ctx, "stakes", address, &stakes); err != nil {
+		log.Printf("❌ Failed to fetch stakes for %s: %v", address, err)
+		return &response.PlayerProfileResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to fetch player stakes: %v", err),
+		}, err
+	}
+
+	profile := buildPlayerProfile(address, payouts, stakes)
+	profile.Stats = stats
+
+	log.Printf("✅ Built profile for %s from %d settled games", address, profile.GamesSettled)
+	return &response.PlayerProfileResponse{
+		Success: true,
+		Profile: profile,
+	}, nil
+}
+
+// findPlayerRecords decodes every record of a collection the address took part
+// in, oldest first so streaks can be computed in play order.
+func (s *GameService) findPlayerRecords(ctx context.Context, collectionName, address string, results interface{}) error {
+	collection := s.collection(collectionName)
+	filter := bson.M{
+		"$or": []bson.M{
+			{"requester_address": address},
+			{"accepter_address": address},
+		},
+	}
+	opts := options.Find().SetSort(bson.M{"timestamp": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
+
+// buildPlayerProfile folds settled games (oldest first) into profile totals.
+func buildPlayerProfile(address string, payouts []models.PayWinner, stakes []models.Stake) *models.PlayerProfile {
+	profile := &models.PlayerProfile{
+		Address:    address,
+		HeadToHead: []models.HeadToHeadRecord{},
+	}
+	rivals := make(map[string]*models.HeadToHeadRecord)
+	streak := 0
+
+	for _, game := range payouts {
+		opponent := game.AccepterAddress
+		if opponent == address {
+			opponent = game.RequesterAddress
+		}
+		record, ok := rivals[opponent]
+		if !ok {
+			record = &models.HeadToHeadRecord{Opponent: opponent}
+			rivals[opponent] = record
+		}
+
+		winner := game.Winner
+		if winner == "" {
+			winner = determineWinner(game.RequesterAddress, game.AccepterAddress, game.RequesterScore, game.AccepterScore)
+		}
+		profit := gameProfit(address, winner, game)
+
+		profile.GamesSettled++
+		profile.NetProfit += profit
+		record.Games++
+		record.NetProfit += profit
+		if game.Timestamp > record.LastPlayed {
+			record.LastPlayed = game.Timestamp
+		}
+
+		switch winner {
+		case address:
+			profile.Wins++
+			record.Wins++
+			streak++
+			if streak > profile.LongestWinStreak {
+				profile.LongestWinStreak = streak
+			}
+		case "":
+			profile.Draws++
+			record.Draws++
+			streak = 0
+		default:
+			profile.Losses++
+			record.Losses++
+			streak = 0
+		}
+		profile.CurrentWinStreak = streak
+
+		trackSeen(profile, game.Timestamp)
+	}
+
+	for _, stake := range stakes {
+		trackSeen(profile, stake.Timestamp)
+	}
+
+	for _, record := range rivals {
+		profile.HeadToHead = append(profile.HeadToHead, *record)
+	}
+	sort.Slice(profile.HeadToHead, func(i, j int) bool {
+		a, b := profile.HeadToHead[i], profile.HeadToHead[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.LastPlayed > b.LastPlayed
+	})
+	if len(profile.HeadToHead) > maxHeadToHeadOpponents {
+		profile.HeadToHead = profile.HeadToHead[:maxHeadToHeadOpponents]
+	}
+
+	return profile
+}
+
+// gameProfit is the player's result for one settled game in MIST: the winner
+// gains the prize net of the contract's fees, less their own stake.
+func gameProfit(address, winner string, game models.PayWinner) int64 {
+	switch winner {
+	case "":
+		return 0
+	case address:
+		prize := game.PrizeAmount
+		if prize == 0 {
+			// Payouts settled before the prize was recorded
+			prize, _, _ = splitPot(game.TotalStake)
+		}
+		return int64(prize) - int64(game.StakeAmount)
+	default:
+		return -int64(game.StakeAmount)
+	}
+}
+
+func trackSeen(profile *models.PlayerProfile, timestamp int64) {
+	if timestamp == 0 {
+		return
+	}
+	if profile.FirstSeen == 0 || timestamp < profile.FirstSeen {
+		profile.FirstSeen = timestamp
+	}
+	if timestamp > profile.LastSeen {
+		profile.LastSeen = timestamp
+	}
+}
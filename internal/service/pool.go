@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"jollfi-gaming-api/internal/dto/response"
+)
+
+// ConfigurePool sets the on-chain stake pool object the service reports on.
+func (s *GameService) ConfigurePool(poolID string) {
+	s.poolID = poolID
+}
+
+// GetPool returns the stake pool's decoded on-chain state.
+func (s *GameService) GetPool() (*response.PoolResponse, error) {
+	pool, err := s.suiClient.GetPool(context.Background())
+	if err != nil {
+		log.Printf("❌ Failed to fetch stake pool: %v", err)
+		return &response.PoolResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to fetch stake pool: %v", err),
+		}, fmt.Errorf("%w: %v", ErrChainUnavailable, err)
+	}
+	return &response.PoolResponse{
+		Success: true,
+		Pool:    pool,
+	}, nil
+}
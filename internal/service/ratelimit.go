@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"jollfi-gaming-api/internal/ratelimit"
+)
+
+const (
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendMongo  = "mongo"
+)
+
+// rateLimitStore counts in Mongo so replicas share one budget, and falls back
+// to counting in this process while Mongo is down or failing.
+type rateLimitStore struct {
+	service *GameService
+	shared  ratelimit.Store
+	local   ratelimit.Store
+}
+
+func (r *rateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	if !r.service.DependencyAvailable(DependencyMongo) {
+		return r.local.Take(ctx, key, limit)
+	}
+	result, err := r.shared.Take(ctx, key, limit)
+	if err != nil {
+		log.Printf("⚠️  Shared rate limit store failed, counting locally: %v", err)
+		return r.local.Take(ctx, key, limit)
+	}
+	return result, nil
+}
+
+// ConfigureRateLimit picks where request budgets are counted: "memory" keeps
+// them per replica, "mongo" shares them across replicas.
+func (s *GameService) ConfigureRateLimit(backend string) {
+	local := ratelimit.NewMemoryStoreWithClock(s.clock.Now)
+	if backend != RateLimitBackendMongo {
+		s.rateLimitStore = local
+		return
+	}
+	s.rateLimitStore = &rateLimitStore{
+		service: s,
+		shared:  ratelimit.NewMongoStoreWithClock(s.collection(ratelimit.MongoCollection), s.clock.Now),
+		local:   local,
+	}
+}
+
+// RateLimitStore returns the store request budgets are counted in.
+func (s *GameService) RateLimitStore() ratelimit.Store {
+	return s.rateLimitStore
+}
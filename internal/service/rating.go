@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/rating"
+)
+
+const (
+	// maxRatingUpdateAttempts bounds the retries when a concurrent settlement
+	// updated the same player between our read and write.
+	maxRatingUpdateAttempts = 5
+	ratingHistoryLimit      = 50
+)
+
+type userRating struct {
+	Address string               `bson:"address"`
+	Rating  *models.PlayerRating `bson:"rating"`
+}
+
+// PlayerRating returns the player's current rating, or the starting rating if
+// they have never played a rated game.
+func (s *GameService) PlayerRating(ctx context.Context, address string) (models.PlayerRating, error) {
+	var user userRating
+	found, err := findOne(ctx, s.collection("users"), bson.M{"address": address}, &user)
+	if err != nil {
+		return models.PlayerRating{}, err
+	}
+	if !found || user.Rating == nil {
+		start := rating.Default()
+		return models.PlayerRating{
+			Rating:     start.Rating,
+			Deviation:  start.Deviation,
+			Volatility: start.Volatility,
+		}, nil
+	}
+	return *user.Rating, nil
+}
+
+func (s *GameService) GetPlayerRating(address string) (*response.PlayerRatingResponse, error) {
+	if address == "" {
+		return &response.PlayerRatingResponse{
+			Success: false,
+			Error:   "Address is required",
+		}, fmt.Errorf("address is required")
+	}
+
+	ctx := context.Background()
+	current, err := s.PlayerRating(ctx, address)
+	if err != nil {
+		log.Printf("❌ Failed to fetch rating for %s: %v", address, err)
+		return &response.PlayerRatingResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to fetch rating: %v", err),
+		}, err
+	}
+
+	opts := options.Find().SetSort(bson.M{"timestamp": -1}).SetLimit(ratingHistoryLimit)
+	cursor, err := s.collection("rating_history").Find(ctx, bson.M{"address": address}, opts)
+	if err != nil {
+		log.Printf("❌ Failed to fetch rating history for %s: %v", address, err)
+		return &response.PlayerRatingResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to fetch rating history: %v", err),
+		}, err
+	}
+	defer cursor.Close(ctx)
+
+	var history []models.RatingHistoryEntry
+	if err := cursor.All(ctx, &history); err != nil {
+		log.Printf("❌ Failed to decode rating history for %s: %v", address, err)
+		return &response.PlayerRatingResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to decode rating history: %v", err),
+		}, err
+	}
+
+	log.Printf("✅ Retrieved rating %.0f with %d history entries for address: %s", current.Rating, len(history), address)
+	return &response.PlayerRatingResponse{
+		Success: true,
+		Address: address,
+		Rating:  &current,
+		History: history,
+	}, nil
+}
+
+// updateRatings rates both players of a settled game against each other's
+// pre-game rating. Failures are logged only; the payout itself already
+// succeeded.
+func (s *GameService) updateRatings(ctx context.Context, game models.PayWinner) {
+	requesterBefore, err := s.PlayerRating(ctx, game.RequesterAddress)
+	if err != nil {
+		log.Printf("⚠️  Failed to read rating for %s: %v", game.RequesterAddress, err)
+		return
+	}
+	accepterBefore, err := s.PlayerRating(ctx, game.AccepterAddress)
+	if err != nil {
+		log.Printf("⚠️  Failed to read rating for %s: %v", game.AccepterAddress, err)
+		return
+	}
+
+	s.ratePlayer(ctx, game, game.RequesterAddress, game.AccepterAddress, accepterBefore)
+	s.ratePlayer(ctx, game, game.AccepterAddress, game.RequesterAddress, requesterBefore)
+}
+
+func (s *GameService) ratePlayer(ctx context.Context, game models.PayWinner, address, opponent string, opponentRating models.PlayerRating) {
+	score, result := rating.Loss, "loss"
+	switch game.Winner {
+	case address:
+		score, result = rating.Win, "win"
+	case "":
+		score, result = rating.Draw, "draw"
+	}
+
+	for attempt := 1; attempt <= maxRatingUpdateAttempts; attempt++ {
+		before, after, applied, err := s.applyRating(ctx, address, opponentRating, score)
+		if err != nil {
+			log.Printf("⚠️  Failed to update rating for %s: %v", address, err)
+			return
+		}
+		if !applied {
+			continue
+		}
+
+		entry := models.RatingHistoryEntry{
+			Address:         address,
+			GameID:          game.GameID,
+			Opponent:        opponent,
+			OpponentRating:  opponentRating.Rating,
+			Result:          result,
+			RatingBefore:    before.Rating,
+			RatingAfter:     after.Rating,
+			DeviationBefore: before.Deviation,
+			DeviationAfter:  after.Deviation,
+			VolatilityAfter: after.Volatility,
+			Timestamp:       game.Timestamp,
+		}
+		if _, err := s.collection("rating_history").InsertOne(ctx, entry); err != nil {
+			log.Printf("⚠️  Failed to record rating history for %s: %v", address, err)
+		}
+		return
+	}
+	log.Printf("⚠️  Gave up updating rating for %s after %d conflicting attempts", address, maxRatingUpdateAttempts)
+}
+
+// applyRating performs one compare-and-swap of the player's rating, keyed on
+// the number of rated games. applied is false when another settlement won the
+// race and the caller should retry.
+func (s *GameService) applyRating(ctx context.Context, address string, opponent models.PlayerRating, score float64) (before, after models.PlayerRating, applied bool, err error) {
+	before, err = s.PlayerRating(ctx, address)
+	if err != nil {
+		return before, after, false, err
+	}
+
+	next := rating.Update(
+		rating.Rating{Rating: before.Rating, Deviation: before.Deviation, Volatility: before.Volatility},
+		[]rating.Result{{
+			Opponent: rating.Rating{Rating: opponent.Rating, Deviation: opponent.Deviation, Volatility: opponent.Volatility},
+			Score:    score,
+		}},
+	)
+	now := time.Now()
+	after = models.PlayerRating{
+		Rating:     next.Rating,
+		Deviation:  next.Deviation,
+		Volatility: next.Volatility,
+		Games:      before.Games + 1,
+		UpdatedAt:  now,
+	}
+
+	filter := bson.M{"address": address, "rating.games": before.Games}
+	opts := options.Update()
+	if before.Games == 0 {
+		filter = bson.M{"address": address, "rating": bson.M{"$exists": false}}
+		opts.SetUpsert(true)
+	}
+	update := bson.M{
+		"$set":         bson.M{"rating": after, "updated_at": now},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+
+	result, err := s.collection("users").UpdateOne(ctx, filter, update, opts)
+	if mongo.IsDuplicateKeyError(err) {
+		return before, after, false, nil
+	}
+	if err != nil {
+		return before, after, false, err
+	}
+	return before, after, result.MatchedCount > 0 || result.UpsertedCount > 0, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"jollfi-gaming-api/internal/dto/response"
+)
+
+const (
+	DependencyOK   = "ok"
+	DependencyFail = "fail"
+
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// ReadinessConfig controls the dependency checks behind the readiness probe.
+type ReadinessConfig struct {
+	// CacheTTL is how long a result is reused, so frequent probes from
+	// several sources cost one round of checks.
+	CacheTTL time.Duration
+	// Timeout bounds each dependency check.
+	Timeout time.Duration
+	// MaxCheckpointAge is how old the latest Sui checkpoint may be before the
+	// node is considered stalled.
+	MaxCheckpointAge time.Duration
+	// MinGasBalance, in MIST, is the operator balance below which the service
+	// is not ready. Zero only reports the balance.
+	MinGasBalance uint64
+}
+
+func DefaultReadinessConfig() ReadinessConfig {
+	return ReadinessConfig{
+		CacheTTL:         2 * time.Second,
+		Timeout:          3 * time.Second,
+		MaxCheckpointAge: time.Minute,
+	}
+}
+
+type readinessCache struct {
+	mu     sync.Mutex
+	result *response.ReadinessResponse
+}
+
+func (s *GameService) ConfigureReadiness(config ReadinessConfig) {
+	s.readinessConfig = config
+}
+
+// dependencyCheck returns detail to report, or an error if the dependency is
+// not usable.
+type dependencyCheck func(ctx context.Context) (map[string]interface{}, error)
+
+// Readiness checks Mongo, Sui checkpoint freshness, the operator's gas
+// balance and the stake pool concurrently. A result younger than the cache TTL
+// is returned as is.
+func (s *GameService) Readiness() *response.ReadinessResponse {
+	s.readiness.mu.Lock()
+	defer s.readiness.mu.Unlock()
+
+	now := s.clock.Now()
+	if cached := s.readiness.result; cached != nil && now.Sub(cached.CheckedAt) < s.readinessConfig.CacheTTL {
+		return cached
+	}
+
+	checks := map[string]dependencyCheck{
+		"mongo": s.checkMongo,
+		"sui":   s.checkSuiCheckpoint,
+		"gas":   s.checkGasBalance,
+		"pool":  s.checkPool,
+	}
+	result := &response.ReadinessResponse{
+		Success:   true,
+		Status:    StatusReady,
+		Checks:    make(map[string]response.DependencyStatus, len(checks)),
+		CheckedAt: now,
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check dependencyCheck) {
+			defer wg.Done()
+			status := s.runDependencyCheck(check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Checks[name] = status
+			if status.Status != DependencyOK {
+				result.Success = false
+				result.Status = StatusNotReady
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	s.readiness.result = result
+	return result
+}
+
+func (s *GameService) runDependencyCheck(check dependencyCheck) response.DependencyStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), s.readinessConfig.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check(ctx)
+	status := response.DependencyStatus{
+		Status:    DependencyOK,
+		LatencyMs: float64(time.Since(start)) / float64(time.Millisecond),
+		Detail:    detail,
+	}
+	if err != nil {
+		status.Status = DependencyFail
+		status.Error = err.Error()
+	}
+	return status
+}
+
+func (s *GameService) checkMongo(ctx context.Context) (map[string]interface{}, error) {
+	return nil, s.mongoClient.Ping(ctx)
+}
+
+func (s *GameService) checkSuiCheckpoint(ctx context.Context) (map[string]interface{}, error) {
+	checkpoint, err := s.suiClient.LatestCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	age := s.clock.Now().Sub(checkpoint.Timestamp)
+	detail := map[string]interface{}{
+		"checkpoint":  checkpoint.SequenceNumber,
+		"age_seconds": age.Seconds(),
+	}
+	if age > s.readinessConfig.MaxCheckpointAge {
+		return detail, fmt.Errorf("latest checkpoint is %s old, over the %s limit", age.Round(time.Second), s.readinessConfig.MaxCheckpointAge)
+	}
+	return detail, nil
+}
+
+func (s *GameService) checkGasBalance(ctx context.Context) (map[string]interface{}, error) {
+	balance, err := s.suiClient.GetBalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	detail := map[string]interface{}{
+		"balance":     balance,
+		"min_balance": s.readinessConfig.MinGasBalance,
+	}
+	if balance < s.readinessConfig.MinGasBalance {
+		return detail, fmt.Errorf("operator balance %d MIST is below the %d MIST minimum", balance, s.readinessConfig.MinGasBalance)
+	}
+	return detail, nil
+}
+
+func (s *GameService) checkPool(ctx context.Context) (map[string]interface{}, error) {
+	pool, err := s.suiClient.GetPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"object_id": pool.ObjectID, "paused": pool.Paused}, nil
+}
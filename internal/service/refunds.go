@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/events"
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	// GameStatusRefunding marks a game whose refund is being sent.
+	GameStatusRefunding = "refunding"
+	GameStatusRefunded  = "refunded"
+
+	RefundReasonDraw      = "draw"
+	RefundReasonAbandoned = "abandoned"
+)
+
+var (
+	ErrGameNotFound      = errors.New("game not found")
+	ErrGameNotRefundable = errors.New("game is not staked")
+	ErrRefundTooEarly    = errors.New("game is not stuck yet")
+)
+
+// RefundConfig sets when stakes are returned to both players.
+type RefundConfig struct {
+	// Draws refunds tied games that have no game type, instead of settling
+	// them through the contract. Typed games follow their tie_handling.
+	Draws bool
+	// StuckAfter is how long a game must sit in staked before an admin can
+	// refund it.
+	StuckAfter time.Duration
+}
+
+func DefaultRefundConfig() RefundConfig {
+	return RefundConfig{StuckAfter: time.Hour}
+}
+
+func (s *GameService) ConfigureRefunds(config RefundConfig) {
+	s.refunds = config
+}
+
+// refundsDraw reports whether a tie under rules is settled by a refund.
+func (s *GameService) refundsDraw(rules *models.GameType) bool {
+	if rules != nil {
+		return rules.TieHandling == models.TieHandlingRefund
+	}
+	return s.refunds.Draws
+}
+
+// sendRefund returns each player's stake from the contract's pool. Refunds
+// only ever go through the contract, which knows what is left of the stakes;
+// if it cannot be reached the refund fails and the game stays staked.
+func (s *GameService) sendRefund(ctx context.Context, requesterAddress, accepterAddress string, stakeAmount uint64) (string, error) {
+	return s.withWallet(ctx, func(ctx context.Context) (string, error) {
+		return s.suiClient.ExternalRefund(requesterAddress, accepterAddress, stakeAmount, ctx)
+	})
+}
+
+// RefundStuckGame refunds both stakes of a game left in staked for longer
+// than the configured timeout. The kill switch and address screening apply.
+func (s *GameService) RefundStuckGame(id, actor string, req *request.RefundGameRequest) (*response.RefundGameResponse, error) {
+	ctx := context.Background()
+	raw, err := s.mongoClient.GetGame(ctx, id)
+	if err != nil {
+		return &response.RefundGameResponse{Success: false, Error: ErrGameNotFound.Error()}, ErrGameNotFound
+	}
+	game, ok := raw.(data.Game)
+	if !ok {
+		err := fmt.Errorf("unexpected game record type %T", raw)
+		return &response.RefundGameResponse{Success: false, Error: err.Error()}, err
+	}
+	if game.Status != GameStatusStaked {
+		err := fmt.Errorf("%w: game is %s", ErrGameNotRefundable, game.Status)
+		return &response.RefundGameResponse{Success: false, Error: err.Error()}, err
+	}
+	if stuckAt := gameStakedAt(game).Add(s.refunds.StuckAfter); s.clock.Now().Before(stuckAt) {
+		err := fmt.Errorf("%w: it can be refunded from %s", ErrRefundTooEarly, stuckAt.UTC().Format(time.RFC3339))
+		return &response.RefundGameResponse{Success: false, Error: err.Error()}, err
+	}
+	if state := s.KillSwitch(); state.Paused {
+		return &response.RefundGameResponse{Success: false, Error: pausedError(state)}, ErrPaused
+	}
+	if err := s.screenAddresses(ctx, "refund", game.RequesterAddress, game.AccepterAddress); err != nil {
+		return &response.RefundGameResponse{Success: false, Error: err.Error()}, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = RefundReasonAbandoned
+	}
+	if _, err := s.refundGame(ctx, &game, reason); err != nil {
+		return &response.RefundGameResponse{Success: false, Error: err.Error()}, err
+	}
+	log.Printf("✅ Game %s refunded by %s: %s", id, actor, reason)
+
+	if raw, err := s.mongoClient.GetGame(ctx, id); err == nil {
+		if refunded, ok := raw.(data.Game); ok {
+			game = refunded
+		}
+	}
+	return &response.RefundGameResponse{Success: true, Game: &game}, nil
+}
+
+// refundGame claims a staked game, refunds both stakes and records the game
+// as refunded. If the refund fails the game goes back to staked.
+func (s *GameService) refundGame(ctx context.Context, game *data.Game, reason string) (string, error) {
+	id := game.ID.Hex()
+	claimed, err := s.mongoClient.TransitionGame(ctx, id, GameStatusStaked, bson.M{"status": GameStatusRefunding})
+	if err != nil {
+		return "", err
+	}
+	if !claimed {
+		return "", fmt.Errorf("%w: game is no longer staked", ErrGameNotRefundable)
+	}
+
+	txDigest, err := s.sendRefund(ctx, game.RequesterAddress, game.AccepterAddress, game.StakeAmount)
+	if err != nil {
+		log.Printf("❌ Refund of game %s failed: %v", id, err)
+		if _, releaseErr := s.mongoClient.TransitionGame(ctx, id, GameStatusRefunding, bson.M{"status": GameStatusStaked}); releaseErr != nil {
+			log.Printf("⚠️  Failed to release game %s after its refund failed: %v", id, releaseErr)
+		}
+		return "", fmt.Errorf("blockchain transaction failed: %v", err)
+	}
+
+	now := s.clock.Now()
+	if _, err := s.mongoClient.TransitionGame(ctx, id, GameStatusRefunding, bson.M{
+		"status":             GameStatusRefunded,
+		"transaction_digest": txDigest,
+		"refund_reason":      reason,
+		"completed_at":       now,
+	}); err != nil {
+		log.Printf("⚠️  Failed to mark game %s refunded (transaction still succeeded): %v", id, err)
+	}
+	s.recordTransaction(ctx, TransactionTypeRefund, id, game.RequesterAddress, game.AccepterAddress, game.StakeAmount*2, txDigest)
+	s.events.Publish(events.Event{
+		Type:      events.GameRefunded,
+		GameID:    id,
+		Addresses: []string{game.RequesterAddress, game.AccepterAddress},
+		Data: map[string]interface{}{
+			"game_type":          game.GameType,
+			"requester_address":  game.RequesterAddress,
+			"accepter_address":   game.AccepterAddress,
+			"stake_amount":       game.StakeAmount,
+			"reason":             reason,
+			"transaction_digest": txDigest,
+		},
+	})
+	return txDigest, nil
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/screening"
+)
+
+const (
+	StakeCodeSelfExcluded = "self_excluded"
+	StakeCodeCoolingOff   = "cooling_off"
+	StakeCodeMaxStake     = "max_stake_exceeded"
+	StakeCodeDailyLimit   = "daily_limit_exceeded"
+	StakeCodeWeeklyLimit  = "weekly_limit_exceeded"
+	StakeCodeMonthlyLimit = "monthly_limit_exceeded"
+	StakeCodeNetLossLimit = "net_loss_limit_exceeded"
+
+	MinCoolingOffDays    = 1
+	MaxCoolingOffDays    = 42
+	MinSelfExclusionDays = 180
+	MaxSelfExclusionDays = 1825
+
+	day = 24 * time.Hour
+)
+
+var (
+	ErrStakeRefused  = errors.New("stake refused by responsible gaming controls")
+	ErrInvalidLimits = errors.New("invalid responsible gaming request")
+)
+
+// StakeRefusal says which control refused a stake and for which player.
+type StakeRefusal struct {
+	Code    string
+	Address string
+	Reason  string
+}
+
+func (e *StakeRefusal) Error() string {
+	return fmt.Sprintf("stake refused for %s: %s", e.Address, e.Reason)
+}
+
+func (e *StakeRefusal) Unwrap() error {
+	return ErrStakeRefused
+}
+
+// ResponsibleGamingConfig sets the operator's own limits, which apply on top
+// of whatever players choose, and how long loosening a limit takes.
+type ResponsibleGamingConfig struct {
+	Limits      models.StakeLimits
+	LoosenDelay time.Duration
+}
+
+func DefaultResponsibleGamingConfig() ResponsibleGamingConfig {
+	return ResponsibleGamingConfig{LoosenDelay: day}
+}
+
+func (s *GameService) ConfigureResponsibleGaming(config ResponsibleGamingConfig) {
+	s.responsibleGaming = config
+}
+
+type userResponsibleGaming struct {
+	Address           string                    `bson:"address"`
+	ResponsibleGaming *models.ResponsibleGaming `bson:"responsible_gaming"`
+}
+
+// playerControls returns the player's controls with any loosening that has
+// come into effect applied. Controls are keyed by the normalized address so a
+// change of hex case cannot step around them.
+func (s *GameService) playerControls(ctx context.Context, address string, now time.Time) (models.ResponsibleGaming, error) {
+	var user userResponsibleGaming
+	found, err := findOne(ctx, s.collection("users"), bson.M{"address": screening.NormalizeAddress(address)}, &user)
+	if err != nil || !found || user.ResponsibleGaming == nil {
+		return models.ResponsibleGaming{}, err
+	}
+	controls := *user.ResponsibleGaming
+	if controls.Pending != nil && !now.Before(controls.Pending.EffectiveAt) {
+		controls.Limits = controls.Pending.Limits
+		controls.Pending = nil
+	}
+	return controls, nil
+}
+
+func (s *GameService) savePlayerControls(ctx context.Context, address string, controls models.ResponsibleGaming) error {
+	now := s.clock.Now()
+	controls.UpdatedAt = now
+	_, err := s.collection("users").UpdateOne(ctx,
+		bson.M{"address": screening.NormalizeAddress(address)},
+		bson.M{
+			"$set":         bson.M{"responsible_gaming": controls, "updated_at": now},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// effectiveLimits combines the player's limits with the operator's, keeping
+// the tighter of each.
+func (s *GameService) effectiveLimits(player models.StakeLimits) models.StakeLimits {
+	operator := s.responsibleGaming.Limits
+	return models.StakeLimits{
+		MaxStake: tighterLimit(player.MaxStake, operator.MaxStake),
+		Daily:    tighterLimit(player.Daily, operator.Daily),
+		Weekly:   tighterLimit(player.Weekly, operator.Weekly),
+		Monthly:  tighterLimit(player.Monthly, operator.Monthly),
+		NetLoss:  tighterLimit(player.NetLoss, operator.NetLoss),
+	}
+}
+
+// tighterLimit returns the lower of two limits, where zero means none.
+func tighterLimit(a, b uint64) uint64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// stakeUsage totals the player's stakes over the last day, week and month and
+// their net loss over settled games in the last month. Stakes are recorded
+// with the address as the player sent it, so they are matched in any case.
+func (s *GameService) stakeUsage(ctx context.Context, address string, now time.Time) (models.StakeUsage, error) {
+	var usage models.StakeUsage
+	address = screening.NormalizeAddress(address)
+	anyCase := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(address) + "$", Options: "i"}
+	filter := bson.M{
+		"$or": []bson.M{
+			{"requester_address": anyCase},
+			{"accepter_address": anyCase},
+		},
+		"timestamp": bson.M{"$gte": now.Add(-30 * day).Unix()},
+	}
+
+	var stakes []models.Stake
+	if err := findAll(ctx, s.collection("stakes"), filter, &stakes); err != nil {
+		return usage, err
+	}
+	for _, stake := range stakes {
+		usage.Monthly += stake.StakeAmount
+		if stake.Timestamp >= now.Add(-7*day).Unix() {
+			usage.Weekly += stake.StakeAmount
+		}
+		if stake.Timestamp >= now.Add(-day).Unix() {
+			usage.Daily += stake.StakeAmount
+		}
+	}
+
+	var payouts []models.PayWinner
+	if err := findAll(ctx, s.collection("pay_winners"), filter, &payouts); err != nil {
+		return usage, err
+	}
+	for _, payout := range payouts {
+		switch screening.NormalizeAddress(payout.Winner) {
+		case "":
+		case address:
+			usage.NetLoss -= int64(payout.StakeAmount)
+		default:
+			usage.NetLoss += int64(payout.StakeAmount)
+		}
+	}
+	return usage, nil
+}
+
+// checkStakeLimits refuses the stake if either player is on a break or would
+// go over one of their limits with it.
+func (s *GameService) checkStakeLimits(ctx context.Context, req *request.StakeRequest) error {
+	now := s.clock.Now()
+	for _, address := range []string{screening.NormalizeAddress(req.RequesterAddress), screening.NormalizeAddress(req.AccepterAddress)} {
+		controls, err := s.playerControls(ctx, address, now)
+		if err != nil {
+			return fmt.Errorf("failed to read stake limits for %s: %v", address, err)
+		}
+		if refusal := checkBreaks(address, controls, now); refusal != nil {
+			return refusal
+		}
+
+		limits := s.effectiveLimits(controls.Limits)
+		if limits.MaxStake > 0 && req.StakeAmount > limits.MaxStake {
+			return &StakeRefusal{StakeCodeMaxStake, address, fmt.Sprintf("stake %d is over the %d limit per stake", req.StakeAmount, limits.MaxStake)}
+		}
+		if limits.Daily == 0 && limits.Weekly == 0 && limits.Monthly == 0 && limits.NetLoss == 0 {
+			continue
+		}
+		usage, err := s.stakeUsage(ctx, address, now)
+		if err != nil {
+			return fmt.Errorf("failed to total stakes for %s: %v", address, err)
+		}
+		if refusal := checkUsage(address, limits, usage, req.StakeAmount); refusal != nil {
+			return refusal
+		}
+	}
+	return nil
+}
+
+func checkBreaks(address string, controls models.ResponsibleGaming, now time.Time) *StakeRefusal {
+	if until := controls.ExcludedUntil; until != nil && now.Before(*until) {
+		return &StakeRefusal{StakeCodeSelfExcluded, address, "self-excluded until " + until.UTC().Format(time.RFC3339)}
+	}
+	if until := controls.CoolingOffUntil; until != nil && now.Before(*until) {
+		return &StakeRefusal{StakeCodeCoolingOff, address, "cooling off until " + until.UTC().Format(time.RFC3339)}
+	}
+	return nil
+}
+
+func checkUsage(address string, limits models.StakeLimits, usage models.StakeUsage, amount uint64) *StakeRefusal {
+	totals := []struct {
+		code, period string
+		used, limit  uint64
+	}{
+		{StakeCodeDailyLimit, "daily", usage.Daily, limits.Daily},
+		{StakeCodeWeeklyLimit, "weekly", usage.Weekly, limits.Weekly},
+		{StakeCodeMonthlyLimit, "monthly", usage.Monthly, limits.Monthly},
+	}
+	for _, total := range totals {
+		if total.limit > 0 && total.used+amount > total.limit {
+			return &StakeRefusal{total.code, address, fmt.Sprintf("%s stakes would reach %d, over the %d limit", total.period, total.used+amount, total.limit)}
+		}
+	}
+	// The new stake counts as lost, so the limit holds whatever the outcome
+	if limits.NetLoss > 0 && usage.NetLoss+int64(amount) > int64(limits.NetLoss) {
+		return &StakeRefusal{StakeCodeNetLossLimit, address, fmt.Sprintf("net loss could reach %d, over the %d limit", usage.NetLoss+int64(amount), limits.NetLoss)}
+	}
+	return nil
+}
+
+func (s *GameService) GetStakeLimits(address string) (*response.PlayerLimitsResponse, error) {
+	ctx := context.Background()
+	now := s.clock.Now()
+	controls, err := s.playerControls(ctx, address, now)
+	if err != nil {
+		log.Printf("❌ Failed to read stake limits for %s: %v", address, err)
+		return &response.PlayerLimitsResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to read stake limits: %v", err),
+		}, err
+	}
+	usage, err := s.stakeUsage(ctx, address, now)
+	if err != nil {
+		log.Printf("❌ Failed to total stakes for %s: %v", address, err)
+		return &response.PlayerLimitsResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to total stakes: %v", err),
+		}, err
+	}
+	effective := s.effectiveLimits(controls.Limits)
+	return &response.PlayerLimitsResponse{
+		Success:   true,
+		Address:   address,
+		Controls:  &controls,
+		Effective: &effective,
+		Usage:     &usage,
+	}, nil
+}
+
+// SetStakeLimits applies tightened limits at once. Loosened or removed limits
+// wait for the loosen delay; each request replaces any loosening still
+// pending.
+func (s *GameService) SetStakeLimits(address string, req *request.SetStakeLimitsRequest) (*response.PlayerLimitsResponse, error) {
+	ctx := context.Background()
+	now := s.clock.Now()
+	controls, err := s.playerControls(ctx, address, now)
+	if err != nil {
+		log.Printf("❌ Failed to read stake limits for %s: %v", address, err)
+		return &response.PlayerLimitsResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to read stake limits: %v", err),
+		}, err
+	}
+
+	current := controls.Limits
+	applied, requested := current, current
+	loosened := false
+	fields := []struct {
+		value            *uint64
+		applied, current *uint64
+		requested        *uint64
+	}{
+		{req.MaxStake, &applied.MaxStake, &current.MaxStake, &requested.MaxStake},
+		{req.Daily, &applied.Daily, &current.Daily, &requested.Daily},
+		{req.Weekly, &applied.Weekly, &current.Weekly, &requested.Weekly},
+		{req.Monthly, &applied.Monthly, &current.Monthly, &requested.Monthly},
+		{req.NetLoss, &applied.NetLoss, &current.NetLoss, &requested.NetLoss},
+	}
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		*field.requested = *field.value
+		if tighterLimit(*field.value, *field.current) == *field.value {
+			*field.applied = *field.value
+		} else {
+			loosened = true
+		}
+	}
+
+	controls.Limits = applied
+	controls.Pending = nil
+	if loosened {
+		controls.Pending = &models.PendingStakeLimits{
+			Limits:      requested,
+			EffectiveAt: now.Add(s.responsibleGaming.LoosenDelay),
+		}
+	}
+	if err := s.savePlayerControls(ctx, address, controls); err != nil {
+		log.Printf("❌ Failed to save stake limits for %s: %v", address, err)
+		return &response.PlayerLimitsResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to save stake limits: %v", err),
+		}, err
+	}
+
+	log.Printf("✅ Stake limits updated for %s (loosening pending: %t)", address, loosened)
+	return s.GetStakeLimits(address)
+}
+
+// StartCoolingOff blocks the player's stakes for the given number of days.
+func (s *GameService) StartCoolingOff(address string, req *request.BreakRequest) (*response.PlayerLimitsResponse, error) {
+	return s.startBreak(address, req.Days, MinCoolingOffDays, MaxCoolingOffDays, "cooling-off", func(controls *models.ResponsibleGaming) **time.Time {
+		return &controls.CoolingOffUntil
+	})
+}
+
+// SelfExclude blocks the player's stakes for the given number of days.
+func (s *GameService) SelfExclude(address string, req *request.BreakRequest) (*response.PlayerLimitsResponse, error) {
+	return s.startBreak(address, req.Days, MinSelfExclusionDays, MaxSelfExclusionDays, "self-exclusion", func(controls *models.ResponsibleGaming) **time.Time {
+		return &controls.ExcludedUntil
+	})
+}
+
+// startBreak sets the end of a break. A break can be extended but never
+// shortened, so a longer break already running is kept.
+func (s *GameService) startBreak(address string, days, minDays, maxDays int, name string, until func(*models.ResponsibleGaming) **time.Time) (*response.PlayerLimitsResponse, error) {
+	if days < minDays || days > maxDays {
+		err := fmt.Errorf("%w: %s must be between %d and %d days", ErrInvalidLimits, name, minDays, maxDays)
+		return &response.PlayerLimitsResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	ctx := context.Background()
+	now := s.clock.Now()
+	controls, err := s.playerControls(ctx, address, now)
+	if err != nil {
+		log.Printf("❌ Failed to read stake limits for %s: %v", address, err)
+		return &response.PlayerLimitsResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to read stake limits: %v", err),
+		}, err
+	}
+
+	end := now.Add(time.Duration(days) * day)
+	if current := *until(&controls); current == nil || current.Before(end) {
+		*until(&controls) = &end
+	}
+	if err := s.savePlayerControls(ctx, address, controls); err != nil {
+		log.Printf("❌ Failed to save %s for %s: %v", name, address, err)
+		return &response.PlayerLimitsResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to save %s: %v", name, err),
+		}, err
+	}
+
+	log.Printf("⛔ %s started for %s until %s", name, address, (*until(&controls)).UTC().Format(time.RFC3339))
+	return s.GetStakeLimits(address)
+}
@@ -0,0 +1,8 @@
+package service
+
+import "jollfi-gaming-api/internal/models"
+
+// RPCStatus reports the Sui RPC endpoints and their circuit breakers.
+func (s *GameService) RPCStatus() []models.RPCEndpointStatus {
+	return s.suiClient.RPCStatus()
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"errors"
+
+	"jollfi-gaming-api/internal/stream"
+)
+
+// DefaultStreamReplayBuffer is how many recent events are kept for clients
+// resuming a stream with Last-Event-ID.
+const DefaultStreamReplayBuffer = 256
+
+var ErrInvalidStream = errors.New("invalid stream request")
+
+// ConfigureStream replaces the live event hub with one keeping bufferSize
+// events for replay. Existing subscribers stay on the old hub.
+func (s *GameService) ConfigureStream(bufferSize int) {
+	s.stream = stream.NewHub(bufferSize)
+}
+
+// SubscribeGameStream subscribes to the stake, settlement and transaction
+// events involving address. The caller must Close the subscription.
+func (s *GameService) SubscribeGameStream(address, lastEventID string) (*stream.Subscription, error) {
+	if address == "" {
+		return nil, ErrInvalidStream
+	}
+	return s.stream.Subscribe(address, lastEventID), nil
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/lease"
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	timeoutSettlementsCollection = "timeout_settlements"
+
+	// TimeoutPolicyRefund refunds every game past the deadline.
+	TimeoutPolicyRefund = "refund"
+	// TimeoutPolicyAwardReporter awards a game past the deadline to its only
+	// player who reported a score, and refunds it otherwise.
+	TimeoutPolicyAwardReporter = "award_reporter"
+
+	TimeoutActionRefund = "refund"
+	TimeoutActionAward  = "award"
+
+	TimeoutStatusFailed = "failed"
+
+	// RefundReasonTimeout marks a game refunded by the timeout scheduler.
+	RefundReasonTimeout = "timeout"
+
+	// TimeoutGameServer is who the scheduler reports awards as, for the
+	// payout guard's daily cap.
+	TimeoutGameServer = "timeout_settlement"
+
+	DefaultTimeoutSettlementLimit = 50
+	MaxTimeoutSettlementLimit     = 500
+)
+
+// TimeoutSettlementConfig sets how games left in staked are settled
+// automatically.
+type TimeoutSettlementConfig struct {
+	// After is how long a game may stay staked before it is settled. 0 turns
+	// the scheduler off.
+	After time.Duration
+	// Policy is refund or award_reporter.
+	Policy string
+	// Holder identifies this replica for the leader lease.
+	Holder string
+	// LeaseTTL is how long the leader keeps the lease without renewing it.
+	// It should outlast the interval between passes.
+	LeaseTTL time.Duration
+}
+
+func DefaultTimeoutSettlementConfig() TimeoutSettlementConfig {
+	return TimeoutSettlementConfig{
+		Policy:   TimeoutPolicyRefund,
+		Holder:   defaultLeaseHolder(),
+		LeaseTTL: 3 * time.Minute,
+	}
+}
+
+// ConfigureTimeoutSettlement replaces the scheduler's settings. Every
+// replica competes for the same leader lease, so only one settles games.
+func (s *GameService) ConfigureTimeoutSettlement(config TimeoutSettlementConfig) {
+	s.timeoutSettlement = config
+	s.timeoutLeader = lease.NewLeader(lease.New(s.collection(lease.Collection), "timeout_settlement", config.Holder, lease.Config{
+		TTL: config.LeaseTTL,
+		Now: func() time.Time { return s.clock.Now() },
+	}))
+}
+
+// ReportScore records a player's own score for a staked game. req.Address is
+// the authenticated player. Reporting again replaces the earlier score. Reports only matter if the game times
+// out under the award_reporter policy.
+func (s *GameService) ReportScore(gameID string, req *request.ScoreReportRequest) (*response.ScoreReportResponse, error) {
+	ctx := context.Background()
+	game, err := s.liveGame(ctx, gameID)
+	if errors.Is(err, ErrMatchNotFound) {
+		err = ErrGameNotFound
+	}
+	if err != nil {
+		return &response.ScoreReportResponse{Success: false, Error: err.Error()}, err
+	}
+
+	field := ""
+	switch {
+	case strings.EqualFold(req.Address, game.RequesterAddress):
+		field = "requester_report"
+	case strings.EqualFold(req.Address, game.AccepterAddress):
+		field = "accepter_report"
+	default:
+		return &response.ScoreReportResponse{Success: false, Error: ErrMatchForbidden.Error()}, ErrMatchForbidden
+	}
+	report := data.ScoreReport{Score: req.Score, ReportedAt: s.clock.Now()}
+	ok, err := s.mongoClient.TransitionGame(ctx, gameID, GameStatusStaked, bson.M{field: report})
+	if err != nil {
+		log.Printf("❌ Failed to record score report for game %s: %v", gameID, err)
+		return &response.ScoreReportResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to record score: %v", err),
+		}, err
+	}
+	if !ok {
+		return &response.ScoreReportResponse{Success: false, Error: ErrMatchNotLive.Error()}, ErrMatchNotLive
+	}
+
+	game, err = s.liveGame(ctx, gameID)
+	if err != nil {
+		return &response.ScoreReportResponse{Success: false, Error: err.Error()}, err
+	}
+	log.Printf("✅ %s reported a score of %d for game %s", req.Address, req.Score, gameID)
+	return &response.ScoreReportResponse{Success: true, Game: game}, nil
+}
+
+// SettleTimedOutGames settles every game that has been staked for longer
+// than the configured deadline, if this replica holds the leader lease. It
+// returns how many were settled.
+func (s *GameService) SettleTimedOutGames(ctx context.Context) (int, error) {
+	config := s.timeoutSettlement
+	if config.After <= 0 {
+		return 0, nil
+	}
+	if leader, err := s.timeoutLeader.Hold(ctx); err != nil || !leader {
+		return 0, err
+	}
+	if state := s.KillSwitch(); state.Paused {
+		log.Printf("⛔ Timeout settlement skipped: %s", pausedError(state))
+		return 0, nil
+	}
+
+	raw, err := s.mongoClient.GetGamesByStatus(ctx, GameStatusStaked)
+	if err != nil {
+		return 0, err
+	}
+	deadline := s.clock.Now().Add(-config.After)
+	settled := 0
+	for _, r := range raw {
+		game, ok := r.(data.Game)
+		if !ok || gameStakedAt(game).After(deadline) {
+			continue
+		}
+		// Renew before each game, so a replica that lost the lease stops
+		if leader, err := s.timeoutLeader.Hold(ctx); err != nil || !leader {
+			return settled, err
+		}
+		if s.settleTimedOutGame(ctx, game).Status != TimeoutStatusFailed {
+			settled++
+		}
+	}
+	return settled, nil
+}
+
+// settleTimedOutGame applies the policy to one game and records what was
+// done and why.
+func (s *GameService) settleTimedOutGame(ctx context.Context, game data.Game) models.TimeoutSettlement {
+	config := s.timeoutSettlement
+	record := models.TimeoutSettlement{
+		ID:               primitive.NewObjectID(),
+		GameID:           game.ID.Hex(),
+		Policy:           config.Policy,
+		RequesterAddress: game.RequesterAddress,
+		AccepterAddress:  game.AccepterAddress,
+		StakeAmount:      game.StakeAmount,
+		StakedAt:         gameStakedAt(game),
+		CreatedAt:        s.clock.Now(),
+	}
+
+	award, winner, reason := s.timeoutAward(ctx, game)
+	record.Reason = reason
+	if award != nil {
+		record.Action = TimeoutActionAward
+		record.Winner = winner
+		// PayWinner claims the game before sending, so a game server's result
+		// or a refund arriving meanwhile is refused
+		resp, err := s.PayWinner(award)
+		switch {
+		case errors.Is(err, ErrPayoutHeld), errors.Is(err, ErrPayoutPendingApproval):
+			record.Status = resp.Status
+		case err != nil:
+			record.Status = TimeoutStatusFailed
+			record.Error = err.Error()
+		default:
+			record.Status = GameStatusCompleted
+			record.TransactionDigest = resp.TransactionDigest
+		}
+	} else {
+		record.Action = TimeoutActionRefund
+		err := s.screenAddresses(ctx, "refund", game.RequesterAddress, game.AccepterAddress)
+		if err == nil {
+			record.TransactionDigest, err = s.refundGame(ctx, &game, RefundReasonTimeout)
+		}
+		if err != nil {
+			record.Status = TimeoutStatusFailed
+			record.Error = err.Error()
+		} else {
+			record.Status = GameStatusRefunded
+		}
+	}
+
+	if record.Status == TimeoutStatusFailed {
+		log.Printf("❌ Timeout %s of game %s failed (%s): %s", record.Action, record.GameID, reason, record.Error)
+	} else {
+		log.Printf("🧹 Timeout %s of game %s, now %s: %s", record.Action, record.GameID, record.Status, reason)
+	}
+	if _, err := s.collection(timeoutSettlementsCollection).InsertOne(ctx, record); err != nil {
+		log.Printf("⚠️  Failed to record timeout settlement of game %s: %v", record.GameID, err)
+	}
+	return record
+}
+
+// timeoutAward returns the payout awarding a timed-out game to its reporter
+// and who that is, or nil if the game should be refunded, with the reason
+// either way. The player who did not report forfeits: they are scored 0 in
+// higher-wins games and one point behind in lower-wins games.
+func (s *GameService) timeoutAward(ctx context.Context, game data.Game) (*request.PayWinnerRequest, string, string) {
+	stuck := s.clock.Now().Sub(gameStakedAt(game)).Round(time.Minute)
+	if s.timeoutSettlement.Policy != TimeoutPolicyAwardReporter {
+		return nil, "", fmt.Sprintf("no result reported %s after staking", stuck)
+	}
+
+	reporter, report := game.RequesterAddress, game.RequesterReport
+	switch {
+	case game.RequesterReport == nil && game.AccepterReport == nil:
+		return nil, "", fmt.Sprintf("no result or score reported %s after staking", stuck)
+	case game.RequesterReport != nil && game.AccepterReport != nil:
+		return nil, "", fmt.Sprintf("both players reported a score but no result was reported %s after staking", stuck)
+	case game.AccepterReport != nil:
+		reporter, report = game.AccepterAddress, game.AccepterReport
+	}
+
+	direction, err := s.scoreDirection(ctx, game.GameType)
+	if err != nil {
+		return nil, "", fmt.Sprintf("only %s reported a score, but the game type could not be loaded: %v", reporter, err)
+	}
+	reporterScore, forfeitScore := report.Score, uint64(0)
+	if direction == models.ScoreDirectionLower {
+		forfeitScore = report.Score + 1
+	} else if report.Score == 0 {
+		return nil, "", fmt.Sprintf("only %s reported a score, and it was 0", reporter)
+	}
+
+	award := &request.PayWinnerRequest{
+		GameID:           game.ID.Hex(),
+		RequesterAddress: game.RequesterAddress,
+		AccepterAddress:  game.AccepterAddress,
+		RequesterScore:   reporterScore,
+		AccepterScore:    forfeitScore,
+		StakeAmount:      game.StakeAmount,
+		GameType:         game.GameType,
+		GameServer:       TimeoutGameServer,
+	}
+	if reporter == game.AccepterAddress {
+		award.RequesterScore, award.AccepterScore = forfeitScore, reporterScore
+	}
+	return award, reporter, fmt.Sprintf("only %s reported a score (%d) in the %s after staking", reporter, report.Score, stuck)
+}
+
+// ListTimeoutSettlements returns what the timeout scheduler did, newest
+// first.
+func (s *GameService) ListTimeoutSettlements(query *request.TimeoutSettlementQuery) (*response.TimeoutSettlementListResponse, error) {
+	filter := bson.M{}
+	if query.GameID != "" {
+		filter["game_id"] = query.GameID
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultTimeoutSettlementLimit
+	}
+	if limit > MaxTimeoutSettlementLimit {
+		limit = MaxTimeoutSettlementLimit
+	}
+
+	settlements := []models.TimeoutSettlement{}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit))
+	if err := findAll(context.Background(), s.collection(timeoutSettlementsCollection), filter, &settlements, opts); err != nil {
+		log.Printf("❌ Failed to fetch timeout settlements: %v", err)
+		return &response.TimeoutSettlementListResponse{
+			Success:     false,
+			Settlements: []models.TimeoutSettlement{},
+			Error:       fmt.Sprintf("Failed to fetch timeout settlements: %v", err),
+		}, err
+	}
+	return &response.TimeoutSettlementListResponse{
+		Success:     true,
+		Settlements: settlements,
+		Count:       len(settlements),
+	}, nil
+}
+
+// RunTimeoutSettlement settles timed-out games every interval until ctx is
+// done, then gives up the leader lease.
+func (s *GameService) RunTimeoutSettlement(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := s.timeoutLeader.Resign(releaseCtx); err != nil {
+				log.Printf("⚠️  Failed to release timeout settlement lease: %v", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if settled, err := s.SettleTimedOutGames(ctx); err != nil {
+				log.Printf("⚠️  Timeout settlement failed: %v", err)
+			} else if settled > 0 {
+				log.Printf("🧹 Settled %d timed-out games", settled)
+			}
+		}
+	}
+}
@@ -0,0 +1,791 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/events"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/screening"
+	"jollfi-gaming-api/internal/tournament"
+)
+
+const (
+	tournamentsCollection       = "tournaments"
+	tournamentEntriesCollection = "tournament_entries"
+	tournamentMatchesCollection = "tournament_matches"
+
+	// MaxTournamentCapacity caps registrations; a full round robin of this
+	// size plays 2016 matches.
+	MaxTournamentCapacity = 64
+
+	DefaultTournamentLimit = 50
+	MaxTournamentLimit     = 200
+)
+
+var (
+	ErrInvalidTournament        = errors.New("invalid tournament request")
+	ErrTournamentNotFound       = errors.New("tournament not found")
+	ErrTournamentNotOpen        = errors.New("tournament is not open for registration")
+	ErrTournamentFull           = errors.New("tournament is full")
+	ErrAlreadyRegistered        = errors.New("address is already registered")
+	ErrNotRegistered            = errors.New("address is not registered")
+	ErrTournamentNotRunning     = errors.New("tournament is not in progress")
+	ErrTournamentMatchNotFound  = errors.New("tournament match not found")
+	ErrTournamentMatchNotStaked = errors.New("tournament match is not awaiting a result")
+)
+
+// CreateTournament opens a tournament for registration.
+func (s *GameService) CreateTournament(req *request.CreateTournamentRequest, actor string) (*response.TournamentResponse, error) {
+	if err := validateTournament(req); err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	rules, err := s.gameTypeRules(context.Background(), req.GameType)
+	if err == nil {
+		err = checkStakeRules(rules, req.EntryStake)
+	}
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if rules != nil {
+		req.GameType = rules.Name
+	}
+	t := models.Tournament{
+		ID:         primitive.NewObjectID(),
+		Name:       strings.TrimSpace(req.Name),
+		Format:     req.Format,
+		GameType:   req.GameType,
+		EntryStake: req.EntryStake,
+		Capacity:   req.Capacity,
+		Status:     models.TournamentStatusRegistering,
+		CreatedBy:  actor,
+		CreatedAt:  s.clock.Now(),
+	}
+	if _, err := s.collection(tournamentsCollection).InsertOne(context.Background(), t); err != nil {
+		log.Printf("❌ Failed to create tournament: %v", err)
+		return &response.TournamentResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to create tournament: %v", err),
+		}, err
+	}
+	log.Printf("✅ Tournament %s (%s, %d players at %d) created by %s", t.ID.Hex(), t.Format, t.Capacity, t.EntryStake, actor)
+	return &response.TournamentResponse{Success: true, Tournament: &t}, nil
+}
+
+func validateTournament(req *request.CreateTournamentRequest) error {
+	switch {
+	case strings.TrimSpace(req.Name) == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidTournament)
+	case req.Format != models.TournamentFormatSingleElimination && req.Format != models.TournamentFormatRoundRobin:
+		return fmt.Errorf("%w: format must be %s or %s", ErrInvalidTournament, models.TournamentFormatSingleElimination, models.TournamentFormatRoundRobin)
+	case req.EntryStake == 0:
+		return fmt.Errorf("%w: entry_stake must be positive", ErrInvalidTournament)
+	case req.Capacity < 2 || req.Capacity > MaxTournamentCapacity:
+		return fmt.Errorf("%w: capacity must be between 2 and %d", ErrInvalidTournament, MaxTournamentCapacity)
+	}
+	return nil
+}
+
+// ListTournaments returns tournaments, newest first, optionally in one status.
+func (s *GameService) ListTournaments(query *request.TournamentQuery) (*response.TournamentListResponse, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultTournamentLimit
+	}
+	if limit > MaxTournamentLimit {
+		limit = MaxTournamentLimit
+	}
+	filter := bson.M{}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+
+	tournaments := []models.Tournament{}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit))
+	if err := findAll(context.Background(), s.collection(tournamentsCollection), filter, &tournaments, opts); err != nil {
+		log.Printf("❌ Failed to fetch tournaments: %v", err)
+		return &response.TournamentListResponse{
+			Success:     false,
+			Tournaments: []models.Tournament{},
+			Error:       fmt.Sprintf("Failed to fetch tournaments: %v", err),
+		}, err
+	}
+	return &response.TournamentListResponse{
+		Success:     true,
+		Tournaments: tournaments,
+		Count:       len(tournaments),
+	}, nil
+}
+
+// GetTournament returns a tournament with its players, bracket and standings.
+func (s *GameService) GetTournament(id string) (*response.TournamentResponse, error) {
+	ctx := context.Background()
+	t, err := s.findTournament(ctx, id)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	return s.tournamentResponse(ctx, t)
+}
+
+// RegisterForTournament enters a player with the coin for their first match.
+// Blocked players and players on a break are refused.
+func (s *GameService) RegisterForTournament(id string, req *request.TournamentEntryRequest) (*response.TournamentResponse, error) {
+	ctx := context.Background()
+	t, err := s.findTournament(ctx, id)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if t.Status != models.TournamentStatusRegistering {
+		return &response.TournamentResponse{Success: false, Error: ErrTournamentNotOpen.Error()}, ErrTournamentNotOpen
+	}
+	address := screening.NormalizeAddress(req.Address)
+	if _, found, err := s.findTournamentEntry(ctx, id, address); err != nil || found {
+		if err == nil {
+			err = ErrAlreadyRegistered
+		}
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if err := s.screenAddresses(ctx, "tournament_register", address); err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	now := s.clock.Now()
+	controls, err := s.playerControls(ctx, address, now)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if refusal := checkBreaks(address, controls, now); refusal != nil {
+		return &response.TournamentResponse{Success: false, Error: refusal.Error()}, refusal
+	}
+
+	// Claim a place first so concurrent registrations cannot overfill it
+	result, err := s.collection(tournamentsCollection).UpdateOne(ctx,
+		bson.M{"_id": t.ID, "status": models.TournamentStatusRegistering, "registered": bson.M{"$lt": t.Capacity}},
+		bson.M{"$inc": bson.M{"registered": 1}},
+	)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if result.MatchedCount == 0 {
+		if t, err = s.findTournament(ctx, id); err == nil && t.Status == models.TournamentStatusRegistering {
+			err = ErrTournamentFull
+		} else if err == nil {
+			err = ErrTournamentNotOpen
+		}
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+
+	result, err = s.collection(tournamentEntriesCollection).UpdateOne(ctx,
+		bson.M{"_id": tournamentEntryID(id, address)},
+		bson.M{"$setOnInsert": bson.M{
+			"tournament_id": id,
+			"address":       address,
+			"coin_id":       req.CoinID,
+			"registered_at": now,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil || result.UpsertedCount == 0 {
+		s.collection(tournamentsCollection).UpdateOne(ctx, bson.M{"_id": t.ID}, bson.M{"$inc": bson.M{"registered": -1}})
+		if err == nil {
+			err = ErrAlreadyRegistered
+		}
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+
+	log.Printf("✅ %s registered for tournament %s", address, id)
+	return s.GetTournament(id)
+}
+
+// SetTournamentCoin stages the coin a registered player stakes in their next
+// match, replacing any coin already staged, and stakes the player's next
+// match if their opponent is ready too.
+func (s *GameService) SetTournamentCoin(id string, req *request.TournamentEntryRequest) (*response.TournamentResponse, error) {
+	ctx := context.Background()
+	t, err := s.findTournament(ctx, id)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if t.Status != models.TournamentStatusRegistering && t.Status != models.TournamentStatusInProgress {
+		return &response.TournamentResponse{Success: false, Error: ErrTournamentNotRunning.Error()}, ErrTournamentNotRunning
+	}
+	address := screening.NormalizeAddress(req.Address)
+	result, err := s.collection(tournamentEntriesCollection).UpdateOne(ctx,
+		bson.M{"_id": tournamentEntryID(id, address)},
+		bson.M{"$set": bson.M{"coin_id": req.CoinID}},
+	)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if result.MatchedCount == 0 {
+		return &response.TournamentResponse{Success: false, Error: ErrNotRegistered.Error()}, ErrNotRegistered
+	}
+	if t.Status == models.TournamentStatusInProgress {
+		s.stakeReadyMatches(ctx, t)
+	}
+	return s.GetTournament(id)
+}
+
+// StartTournament closes registration, seeds players by rating, lays out the
+// bracket and stakes every first match whose players have a coin staged.
+func (s *GameService) StartTournament(id, actor string) (*response.TournamentResponse, error) {
+	ctx := context.Background()
+	t, err := s.findTournament(ctx, id)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	var entries []models.TournamentEntry
+	if err := findAll(ctx, s.collection(tournamentEntriesCollection), bson.M{"tournament_id": id}, &entries); err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if len(entries) < 2 {
+		err := fmt.Errorf("%w: at least 2 players must register before the start", ErrInvalidTournament)
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+
+	players, err := s.seedTournament(ctx, entries)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	matches, err := tournament.Bracket(t.Format, players)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+
+	// Claim the start only once the bracket is built, and conditionally, so
+	// a concurrent start cannot record a second bracket
+	result, err := s.collection(tournamentsCollection).UpdateOne(ctx,
+		bson.M{"_id": t.ID, "status": models.TournamentStatusRegistering},
+		bson.M{"$set": bson.M{"status": models.TournamentStatusInProgress, "started_at": s.clock.Now()}},
+	)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if result.MatchedCount == 0 {
+		return &response.TournamentResponse{Success: false, Error: ErrTournamentNotOpen.Error()}, ErrTournamentNotOpen
+	}
+	t.Status = models.TournamentStatusInProgress
+
+	var recorded []primitive.ObjectID
+	for _, match := range matches {
+		match.ID = primitive.NewObjectID()
+		match.TournamentID = id
+		if _, err := s.collection(tournamentMatchesCollection).InsertOne(ctx, match); err != nil {
+			log.Printf("❌ Failed to record match %d of tournament %s: %v", match.Number, id, err)
+			s.abortStart(ctx, t, recorded)
+			return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+		}
+		recorded = append(recorded, match.ID)
+	}
+
+	log.Printf("✅ Tournament %s started by %s with %d players and %d matches", id, actor, len(players), len(matches))
+	s.stakeReadyMatches(ctx, t)
+	return s.GetTournament(id)
+}
+
+// abortStart undoes a start whose bracket could not be recorded: it removes
+// the matches already written and reopens registration, so the start can be
+// retried.
+func (s *GameService) abortStart(ctx context.Context, t *models.Tournament, recorded []primitive.ObjectID) {
+	for _, matchID := range recorded {
+		if _, err := s.collection(tournamentMatchesCollection).DeleteOne(ctx, bson.M{"_id": matchID}); err != nil {
+			log.Printf("⚠️  Failed to remove match %s of aborted tournament %s: %v", matchID.Hex(), t.ID.Hex(), err)
+		}
+	}
+	if _, err := s.collection(tournamentsCollection).UpdateOne(ctx,
+		bson.M{"_id": t.ID, "status": models.TournamentStatusInProgress},
+		bson.M{"$set": bson.M{"status": models.TournamentStatusRegistering}, "$unset": bson.M{"started_at": ""}},
+	); err != nil {
+		log.Printf("⚠️  Failed to reopen tournament %s after an aborted start: %v", t.ID.Hex(), err)
+	}
+	t.Status = models.TournamentStatusRegistering
+}
+
+// seedTournament orders players by rating, best first, earliest registration
+// breaking ties, and records each player's seed.
+func (s *GameService) seedTournament(ctx context.Context, entries []models.TournamentEntry) ([]string, error) {
+	for i := range entries {
+		rating, err := s.PlayerRating(ctx, entries[i].Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rating for %s: %v", entries[i].Address, err)
+		}
+		entries[i].Rating = rating.Rating
+	}
+	sort.SliceStable(entries, func(a, b int) bool {
+		if entries[a].Rating != entries[b].Rating {
+			return entries[a].Rating > entries[b].Rating
+		}
+		return entries[a].RegisteredAt.Before(entries[b].RegisteredAt)
+	})
+
+	players := make([]string, len(entries))
+	for i, entry := range entries {
+		players[i] = entry.Address
+		s.collection(tournamentEntriesCollection).UpdateOne(ctx,
+			bson.M{"_id": entry.ID},
+			bson.M{"$set": bson.M{"seed": i + 1, "rating": entry.Rating}},
+		)
+	}
+	return players, nil
+}
+
+// CancelTournament calls off a tournament that has not started. Nothing has
+// been staked yet, so there is nothing to refund.
+func (s *GameService) CancelTournament(id, actor string) (*response.TournamentResponse, error) {
+	ctx := context.Background()
+	t, err := s.findTournament(ctx, id)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	result, err := s.collection(tournamentsCollection).UpdateOne(ctx,
+		bson.M{"_id": t.ID, "status": models.TournamentStatusRegistering},
+		bson.M{"$set": bson.M{"status": models.TournamentStatusCancelled, "completed_at": s.clock.Now()}},
+	)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	if result.MatchedCount == 0 {
+		return &response.TournamentResponse{Success: false, Error: ErrTournamentNotOpen.Error()}, ErrTournamentNotOpen
+	}
+	log.Printf("⛔ Tournament %s cancelled by %s", id, actor)
+	return s.GetTournament(id)
+}
+
+// ReportTournamentResult pays out a staked match through PayWinner, so the
+// payout guard and approval threshold apply as for any game. The bracket
+// moves on once the payout is sent, which may be after an admin review.
+// Single elimination matches cannot be drawn.
+func (s *GameService) ReportTournamentResult(id string, number int, req *request.TournamentResultRequest, gameServer string) (*response.PayWinnerResponse, error) {
+	ctx := context.Background()
+	t, err := s.findTournament(ctx, id)
+	if err != nil {
+		return &response.PayWinnerResponse{Success: false, Error: err.Error()}, err
+	}
+	if t.Status != models.TournamentStatusInProgress {
+		return &response.PayWinnerResponse{Success: false, Error: ErrTournamentNotRunning.Error()}, ErrTournamentNotRunning
+	}
+	var match models.TournamentMatch
+	found, err := findOne(ctx, s.collection(tournamentMatchesCollection), bson.M{"tournament_id": id, "number": number}, &match)
+	if err != nil {
+		return &response.PayWinnerResponse{Success: false, Error: err.Error()}, err
+	}
+	if !found {
+		return &response.PayWinnerResponse{Success: false, Error: ErrTournamentMatchNotFound.Error()}, ErrTournamentMatchNotFound
+	}
+	if match.Status != models.TournamentMatchStaked || !s.gameInStatus(ctx, match.GameID, GameStatusStaked) {
+		// A reported result may still be waiting in review
+		return &response.PayWinnerResponse{Success: false, Error: ErrTournamentMatchNotStaked.Error()}, ErrTournamentMatchNotStaked
+	}
+	if t.Format == models.TournamentFormatSingleElimination && req.RequesterScore == req.AccepterScore {
+		err := fmt.Errorf("%w: single elimination matches need a winner", ErrInvalidTournament)
+		return &response.PayWinnerResponse{Success: false, Error: err.Error()}, err
+	}
+
+	return s.PayWinner(&request.PayWinnerRequest{
+		GameID:           match.GameID,
+		RequesterAddress: match.RequesterAddress,
+		AccepterAddress:  match.AccepterAddress,
+		RequesterScore:   req.RequesterScore,
+		AccepterScore:    req.AccepterScore,
+		StakeAmount:      t.EntryStake,
+		GameServer:       gameServer,
+	})
+}
+
+func (s *GameService) gameInStatus(ctx context.Context, gameID, status string) bool {
+	raw, err := s.mongoClient.GetGame(ctx, gameID)
+	if err != nil {
+		return false
+	}
+	game, ok := raw.(data.Game)
+	return ok && game.Status == status
+}
+
+// settleTournamentMatch records the result of the tournament match a settled
+// game belongs to. It runs on the event bus while the payout is still being
+// answered, so advancing the winner and staking the next matches, which
+// needs the wallet, is left to the tournament worker.
+func (s *GameService) settleTournamentMatch(event events.Event) {
+	if event.Type != events.GameSettled || event.GameID == "" {
+		return
+	}
+	ctx := context.Background()
+	var match models.TournamentMatch
+	found, err := findOne(ctx, s.collection(tournamentMatchesCollection), bson.M{"game_id": event.GameID, "status": models.TournamentMatchStaked}, &match)
+	if err != nil || !found {
+		return
+	}
+
+	requesterScore, _ := event.Data["requester_score"].(uint64)
+	accepterScore, _ := event.Data["accepter_score"].(uint64)
+	if requester, _ := event.Data["requester_address"].(string); requester != match.RequesterAddress {
+		requesterScore, accepterScore = accepterScore, requesterScore
+	}
+	winner, _ := event.Data["winner"].(string)
+	digest, _ := event.Data["transaction_digest"].(string)
+
+	result, err := s.collection(tournamentMatchesCollection).UpdateOne(ctx,
+		bson.M{"_id": match.ID, "status": models.TournamentMatchStaked},
+		bson.M{"$set": bson.M{
+			"status":             models.TournamentMatchComplete,
+			"requester_score":    requesterScore,
+			"accepter_score":     accepterScore,
+			"winner":             winner,
+			"transaction_digest": digest,
+			"advance_pending":    true,
+		}},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		return
+	}
+	log.Printf("✅ Tournament %s match %d won by %s", match.TournamentID, match.Number, winner)
+}
+
+// AdvanceTournaments moves the winners of completed matches on, then
+// finishes each tournament they belong to or stakes its matches that became
+// ready. It returns how many matches were advanced.
+func (s *GameService) AdvanceTournaments(ctx context.Context) (int, error) {
+	var pending []models.TournamentMatch
+	opts := options.Find().SetSort(bson.M{"number": 1})
+	filter := bson.M{"status": models.TournamentMatchComplete, "advance_pending": true}
+	if err := findAll(ctx, s.collection(tournamentMatchesCollection), filter, &pending, opts); err != nil {
+		return 0, err
+	}
+
+	advanced := 0
+	var touched []string
+	seen := make(map[string]bool)
+	for _, match := range pending {
+		// Advancing is idempotent, so a replica racing on the same match
+		// only repeats the same writes before one of them clears the flag.
+		if match.NextMatch > 0 {
+			s.advanceTournamentMatch(ctx, match)
+		}
+		result, err := s.collection(tournamentMatchesCollection).UpdateOne(ctx,
+			bson.M{"_id": match.ID, "advance_pending": true},
+			bson.M{"$unset": bson.M{"advance_pending": ""}},
+		)
+		if err != nil {
+			log.Printf("⚠️  Failed to clear tournament %s match %d: %v", match.TournamentID, match.Number, err)
+			continue
+		}
+		if result.MatchedCount == 0 {
+			continue
+		}
+		advanced++
+		if !seen[match.TournamentID] {
+			seen[match.TournamentID] = true
+			touched = append(touched, match.TournamentID)
+		}
+	}
+
+	for _, id := range touched {
+		t, err := s.findTournament(ctx, id)
+		if err != nil {
+			log.Printf("⚠️  Failed to load tournament %s: %v", id, err)
+			continue
+		}
+		if t.Status != models.TournamentStatusInProgress {
+			continue
+		}
+		if !s.finishTournament(ctx, t) {
+			s.stakeReadyMatches(ctx, t)
+		}
+	}
+	return advanced, nil
+}
+
+// RunTournamentWorker advances tournaments every interval until ctx is done.
+func (s *GameService) RunTournamentWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.AdvanceTournaments(ctx); err != nil {
+				log.Printf("⚠️  Tournament pass failed: %v", err)
+			}
+		}
+	}
+}
+
+// releaseRefundedTournamentMatch puts a match whose game was refunded back
+// to ready, to be staked again once both players stage a new coin.
+func (s *GameService) releaseRefundedTournamentMatch(event events.Event) {
+	if event.Type != events.GameRefunded || event.GameID == "" {
+		return
+	}
+	ctx := context.Background()
+	var match models.TournamentMatch
+	found, err := findOne(ctx, s.collection(tournamentMatchesCollection), bson.M{"game_id": event.GameID, "status": models.TournamentMatchStaked}, &match)
+	if err != nil || !found {
+		return
+	}
+	reason, _ := event.Data["reason"].(string)
+	s.collection(tournamentMatchesCollection).UpdateOne(ctx,
+		bson.M{"_id": match.ID, "status": models.TournamentMatchStaked},
+		bson.M{
+			"$set":   bson.M{"status": models.TournamentMatchReady, "last_error": "game refunded: " + reason},
+			"$unset": bson.M{"game_id": "", "stake_digest": ""},
+		},
+	)
+	log.Printf("⚠️  Tournament %s match %d refunded, waiting for new coins", match.TournamentID, match.Number)
+}
+
+// advanceTournamentMatch puts a single elimination winner into their next
+// match and readies it once both players are in.
+func (s *GameService) advanceTournamentMatch(ctx context.Context, match models.TournamentMatch) {
+	field := "requester_address"
+	if match.NextSlot == tournament.SlotAccepter {
+		field = "accepter_address"
+	}
+	filter := bson.M{"tournament_id": match.TournamentID, "number": match.NextMatch}
+	if _, err := s.collection(tournamentMatchesCollection).UpdateOne(ctx, filter, bson.M{"$set": bson.M{field: match.Winner}}); err != nil {
+		log.Printf("⚠️  Failed to advance %s to match %d: %v", match.Winner, match.NextMatch, err)
+		return
+	}
+	var next models.TournamentMatch
+	if found, err := findOne(ctx, s.collection(tournamentMatchesCollection), filter, &next); err != nil || !found {
+		return
+	}
+	if next.RequesterAddress != "" && next.AccepterAddress != "" {
+		s.collection(tournamentMatchesCollection).UpdateOne(ctx,
+			bson.M{"_id": next.ID, "status": models.TournamentMatchWaiting},
+			bson.M{"$set": bson.M{"status": models.TournamentMatchReady}},
+		)
+	}
+}
+
+// finishTournament completes a tournament once every match is played and
+// reports whether it did.
+func (s *GameService) finishTournament(ctx context.Context, t *models.Tournament) bool {
+	matches, err := s.tournamentMatches(ctx, t.ID.Hex())
+	if err != nil || !tournament.Finished(matches) {
+		return false
+	}
+	players, err := s.tournamentPlayers(ctx, t.ID.Hex())
+	if err != nil {
+		return false
+	}
+	direction, err := s.scoreDirection(ctx, t.GameType)
+	if err != nil {
+		log.Printf("⚠️  Failed to finish tournament %s: %v", t.ID.Hex(), err)
+		return false
+	}
+	champion := tournament.Champion(t.Format, players, matches, direction)
+	result, err := s.collection(tournamentsCollection).UpdateOne(ctx,
+		bson.M{"_id": t.ID, "status": models.TournamentStatusInProgress},
+		bson.M{"$set": bson.M{
+			"status":       models.TournamentStatusCompleted,
+			"winner":       champion,
+			"completed_at": s.clock.Now(),
+		}},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		return false
+	}
+	log.Printf("🏆 Tournament %s won by %s", t.ID.Hex(), champion)
+	return true
+}
+
+// stakeReadyMatches stakes every ready match whose players both have a coin
+// staged, in bracket order.
+func (s *GameService) stakeReadyMatches(ctx context.Context, t *models.Tournament) {
+	var ready []models.TournamentMatch
+	opts := options.Find().SetSort(bson.M{"number": 1})
+	filter := bson.M{"tournament_id": t.ID.Hex(), "status": models.TournamentMatchReady}
+	if err := findAll(ctx, s.collection(tournamentMatchesCollection), filter, &ready, opts); err != nil {
+		log.Printf("⚠️  Failed to fetch ready matches of tournament %s: %v", t.ID.Hex(), err)
+		return
+	}
+	for _, match := range ready {
+		s.stakeTournamentMatch(ctx, t, match)
+	}
+}
+
+// stakeTournamentMatch claims a ready match and both players' staged coins,
+// then stakes it like any other game. Claims are conditional, so a replica
+// staking the same tournament cannot spend a coin twice. If either coin is
+// missing or the stake fails, the coins are put back and the match stays
+// ready.
+func (s *GameService) stakeTournamentMatch(ctx context.Context, t *models.Tournament, match models.TournamentMatch) {
+	matches := s.collection(tournamentMatchesCollection)
+	result, err := matches.UpdateOne(ctx,
+		bson.M{"_id": match.ID, "status": models.TournamentMatchReady},
+		bson.M{"$set": bson.M{"status": models.TournamentMatchStaking}},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		return
+	}
+	release := func(updates bson.M) {
+		updates["status"] = models.TournamentMatchReady
+		matches.UpdateOne(ctx, bson.M{"_id": match.ID, "status": models.TournamentMatchStaking}, bson.M{"$set": updates})
+	}
+
+	id := t.ID.Hex()
+	requesterCoin := s.takeTournamentCoin(ctx, id, match.RequesterAddress)
+	accepterCoin := s.takeTournamentCoin(ctx, id, match.AccepterAddress)
+	if requesterCoin == "" || accepterCoin == "" {
+		s.returnTournamentCoin(ctx, id, match.RequesterAddress, requesterCoin)
+		s.returnTournamentCoin(ctx, id, match.AccepterAddress, accepterCoin)
+		release(bson.M{})
+		return
+	}
+
+	resp, err := s.stakeGame(&request.StakeRequest{
+		RequesterCoinID:  requesterCoin,
+		AccepterCoinID:   accepterCoin,
+		RequesterAddress: match.RequesterAddress,
+		AccepterAddress:  match.AccepterAddress,
+		StakeAmount:      t.EntryStake,
+		GameType:         t.GameType,
+	}, "")
+	if err != nil {
+		log.Printf("❌ Stake for tournament %s match %d failed: %v", id, match.Number, err)
+		s.returnTournamentCoin(ctx, id, match.RequesterAddress, requesterCoin)
+		s.returnTournamentCoin(ctx, id, match.AccepterAddress, accepterCoin)
+		release(bson.M{"last_error": resp.Error})
+		return
+	}
+
+	matches.UpdateOne(ctx,
+		bson.M{"_id": match.ID, "status": models.TournamentMatchStaking},
+		bson.M{
+			"$set":   bson.M{"status": models.TournamentMatchStaked, "game_id": resp.GameID, "stake_digest": resp.TransactionDigest},
+			"$unset": bson.M{"last_error": ""},
+		},
+	)
+	log.Printf("✅ Tournament %s match %d staked: %s vs %s", id, match.Number, match.RequesterAddress, match.AccepterAddress)
+}
+
+// takeTournamentCoin removes a player's staged coin and returns it, or ""
+// if none is staged.
+func (s *GameService) takeTournamentCoin(ctx context.Context, id, address string) string {
+	entry, found, err := s.findTournamentEntry(ctx, id, address)
+	if err != nil || !found || entry.CoinID == "" {
+		return ""
+	}
+	result, err := s.collection(tournamentEntriesCollection).UpdateOne(ctx,
+		bson.M{"_id": entry.ID, "coin_id": entry.CoinID},
+		bson.M{"$unset": bson.M{"coin_id": ""}},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		return ""
+	}
+	return entry.CoinID
+}
+
+// returnTournamentCoin puts back a coin taken for a stake that did not
+// happen, unless the player has staged a new one since.
+func (s *GameService) returnTournamentCoin(ctx context.Context, id, address, coinID string) {
+	if coinID == "" {
+		return
+	}
+	s.collection(tournamentEntriesCollection).UpdateOne(ctx,
+		bson.M{"_id": tournamentEntryID(id, address), "coin_id": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"coin_id": coinID}},
+	)
+}
+
+func (s *GameService) tournamentResponse(ctx context.Context, t *models.Tournament) (*response.TournamentResponse, error) {
+	id := t.ID.Hex()
+	var entries []models.TournamentEntry
+	opts := options.Find().SetSort(bson.M{"registered_at": 1})
+	if err := findAll(ctx, s.collection(tournamentEntriesCollection), bson.M{"tournament_id": id}, &entries, opts); err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+	matches, err := s.tournamentMatches(ctx, id)
+	if err != nil {
+		return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+	}
+
+	resp := &response.TournamentResponse{
+		Success:    true,
+		Tournament: t,
+		Players:    entries,
+		Matches:    matches,
+	}
+	if len(matches) > 0 {
+		players, err := s.tournamentPlayers(ctx, id)
+		if err != nil {
+			return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+		}
+		direction, err := s.scoreDirection(ctx, t.GameType)
+		if err != nil {
+			return &response.TournamentResponse{Success: false, Error: err.Error()}, err
+		}
+		resp.Standings = tournament.Standings(players, matches, direction)
+	}
+	for _, match := range matches {
+		if match.GameID != "" {
+			resp.PrizePool += t.EntryStake * 2
+		}
+	}
+	return resp, nil
+}
+
+func (s *GameService) tournamentMatches(ctx context.Context, id string) ([]models.TournamentMatch, error) {
+	matches := []models.TournamentMatch{}
+	opts := options.Find().SetSort(bson.M{"number": 1})
+	if err := findAll(ctx, s.collection(tournamentMatchesCollection), bson.M{"tournament_id": id}, &matches, opts); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// tournamentPlayers returns a started tournament's players in seed order.
+func (s *GameService) tournamentPlayers(ctx context.Context, id string) ([]string, error) {
+	var entries []models.TournamentEntry
+	opts := options.Find().SetSort(bson.M{"seed": 1})
+	if err := findAll(ctx, s.collection(tournamentEntriesCollection), bson.M{"tournament_id": id}, &entries, opts); err != nil {
+		return nil, err
+	}
+	players := make([]string, len(entries))
+	for i, entry := range entries {
+		players[i] = entry.Address
+	}
+	return players, nil
+}
+
+func (s *GameService) findTournament(ctx context.Context, id string) (*models.Tournament, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrTournamentNotFound
+	}
+	var t models.Tournament
+	found, err := findOne(ctx, s.collection(tournamentsCollection), bson.M{"_id": oid}, &t)
+	if err != nil {
+		log.Printf("❌ Failed to fetch tournament %s: %v", id, err)
+		return nil, fmt.Errorf("failed to fetch tournament: %v", err)
+	}
+	if !found {
+		return nil, ErrTournamentNotFound
+	}
+	return &t, nil
+}
+
+func (s *GameService) findTournamentEntry(ctx context.Context, id, address string) (*models.TournamentEntry, bool, error) {
+	var entry models.TournamentEntry
+	found, err := findOne(ctx, s.collection(tournamentEntriesCollection), bson.M{"_id": tournamentEntryID(id, address)}, &entry)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	return &entry, true, nil
+}
+
+func tournamentEntryID(id, address string) string {
+	return id + ":" + screening.NormalizeAddress(address)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/events"
+)
+
+const (
+	TransactionTypeStake  = "stake"
+	TransactionTypePayout = "payout"
+	TransactionTypeRefund = "refund"
+
+	TransactionStatusPending   = "pending"
+	TransactionStatusConfirmed = "confirmed"
+	TransactionStatusFailed    = "failed"
+)
+
+// recordTransaction stores a submitted transaction as pending so the
+// confirmation worker can follow it to finality. Failures are logged only; the
+// transaction itself already went out.
+func (s *GameService) recordTransaction(ctx context.Context, txType, gameID, from, to string, amount uint64, txDigest string) {
+	tx := data.Transaction{
+		Type:        txType,
+		FromAddress: from,
+		ToAddress:   to,
+		Amount:      amount,
+		TxDigest:    txDigest,
+		Status:      TransactionStatusPending,
+		CreatedAt:   time.Now(),
+	}
+	tx.GameID, _ = primitive.ObjectIDFromHex(gameID)
+
+	if _, err := s.mongoClient.CreateTransaction(ctx, tx); err != nil {
+		log.Printf("⚠️  Failed to record %s transaction %s: %v", txType, txDigest, err)
+	}
+}
+
+// ConfirmTransactions checks every pending transaction against the chain and
+// settles those that reached finality, publishing transaction.confirmed or
+// transaction.failed for each. It returns how many were settled.
+func (s *GameService) ConfirmTransactions(ctx context.Context) (int, error) {
+	pending, err := s.mongoClient.GetPendingTransactions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, raw := range pending {
+		tx, ok := raw.(data.Transaction)
+		if !ok || tx.TxDigest == "" {
+			continue
+		}
+		block, err := s.suiClient.GetTransactionBlock(ctx, tx.TxDigest)
+		if err != nil {
+			// Not indexed yet, or the node is unavailable; try again next pass
+			continue
+		}
+		status, failure, checkpoint := transactionOutcome(block)
+		if status == "" {
+			continue
+		}
+		if err := s.mongoClient.UpdateTransactionStatus(ctx, tx.TxDigest, status, checkpoint); err != nil {
+			log.Printf("⚠️  Failed to mark transaction %s %s: %v", tx.TxDigest, status, err)
+			continue
+		}
+		settled++
+
+		eventType := events.TransactionConfirmed
+		if status == TransactionStatusFailed {
+			eventType = events.TransactionFailed
+			log.Printf("⚠️  Transaction %s failed on chain: %s", tx.TxDigest, failure)
+		}
+		payload := map[string]interface{}{
+			"transaction_digest": tx.TxDigest,
+			"type":               tx.Type,
+			"amount":             tx.Amount,
+			"status":             status,
+		}
+		if checkpoint != nil {
+			payload["checkpoint"] = *checkpoint
+		}
+		if failure != "" {
+			payload["error"] = failure
+		}
+		event := events.Event{
+			Type:      eventType,
+			Addresses: nonEmpty(tx.FromAddress, tx.ToAddress),
+			Data:      payload,
+		}
+		if !tx.GameID.IsZero() {
+			event.GameID = tx.GameID.Hex()
+		}
+		s.events.Publish(event)
+	}
+	return settled, nil
+}
+
+// RunConfirmationWorker settles pending transactions every interval until ctx
+// is done.
+func (s *GameService) RunConfirmationWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			settled, err := s.ConfirmTransactions(ctx)
+			if err != nil {
+				log.Printf("⚠️  Transaction confirmation pass failed: %v", err)
+				continue
+			}
+			if settled > 0 {
+				log.Printf("🔗 Settled %d transactions", settled)
+			}
+		}
+	}
+}
+
+// transactionOutcome reads effects.status from a sui_getTransactionBlock
+// result. status is empty while the outcome is not known yet.
+func transactionOutcome(block interface{}) (status, failure string, checkpoint *uint64) {
+	result, ok := block.(map[string]interface{})
+	if !ok {
+		return "", "", nil
+	}
+	if raw, ok := result["checkpoint"].(string); ok {
+		if n, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			checkpoint = &n
+		}
+	}
+	effects, _ := result["effects"].(map[string]interface{})
+	outcome, _ := effects["status"].(map[string]interface{})
+	switch outcome["status"] {
+	case "success":
+		return TransactionStatusConfirmed, "", checkpoint
+	case "failure":
+		failure, _ = outcome["error"].(string)
+		return TransactionStatusFailed, failure, checkpoint
+	}
+	return "", "", nil
+}
+
+func nonEmpty(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"jollfi-gaming-api/internal/lease"
+)
+
+// ErrWalletBusy means the operator wallet stayed in use by another
+// transaction for longer than the configured wait.
+var ErrWalletBusy = errors.New("operator wallet is busy, try again")
+
+// WalletLeaseConfig controls how transactions signed by the operator wallet
+// are serialized across replicas. Every replica signs with the same key and
+// picks gas coins on its own, so two concurrent transactions could use the
+// same object version; only the holder of the wallet's lease submits.
+type WalletLeaseConfig struct {
+	// Name identifies the wallet. Replicas signing with the same key must use
+	// the same name.
+	Name string
+	// Holder identifies this replica.
+	Holder string
+	// TTL is how long the wallet stays locked after a replica dies holding
+	// it.
+	TTL time.Duration
+	// Wait bounds how long a transaction queues for the wallet.
+	Wait time.Duration
+}
+
+func DefaultWalletLeaseConfig() WalletLeaseConfig {
+	return WalletLeaseConfig{
+		Name:   "operator_wallet",
+		Holder: defaultLeaseHolder(),
+		TTL:    30 * time.Second,
+		Wait:   20 * time.Second,
+	}
+}
+
+// defaultLeaseHolder is the host name plus a random suffix, so a restarted
+// pod never mistakes its predecessor's lease for its own.
+func defaultLeaseHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "replica"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return host + "-" + hex.EncodeToString(suffix)
+}
+
+// walletLock queues this replica's transactions locally, so only one of them
+// at a time competes for the lease.
+type walletLock struct {
+	config WalletLeaseConfig
+	slot   chan struct{}
+	lease  *lease.Lease
+}
+
+func (s *GameService) ConfigureWalletLease(config WalletLeaseConfig) {
+	s.wallet = &walletLock{
+		config: config,
+		slot:   make(chan struct{}, 1),
+		lease: lease.New(s.collection(lease.Collection), config.Name, config.Holder, lease.Config{
+			TTL: config.TTL,
+			Now: func() time.Time { return s.clock.Now() },
+		}),
+	}
+}
+
+// withWallet runs submit while holding the operator wallet. The lease is
+// renewed right before the transaction is sent; if another replica took the
+// wallet over in the meantime, the fencing token no longer matches and
+// nothing is sent.
+func (s *GameService) withWallet(ctx context.Context, submit func(ctx context.Context) (string, error)) (string, error) {
+	wallet := s.wallet
+	waitCtx, cancel := context.WithTimeout(ctx, wallet.config.Wait)
+	defer cancel()
+
+	select {
+	case wallet.slot <- struct{}{}:
+	case <-waitCtx.Done():
+		return "", ErrWalletBusy
+	}
+	defer func() { <-wallet.slot }()
+
+	grant, err := wallet.lease.Acquire(waitCtx)
+	if errors.Is(err, lease.ErrHeld) {
+		return "", ErrWalletBusy
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to lock operator wallet: %w", err)
+	}
+	defer func() {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := wallet.lease.Release(releaseCtx, grant); err != nil {
+			log.Printf("⚠️  Failed to release operator wallet lease: %v", err)
+		}
+	}()
+
+	fenced := lease.WithFence(ctx, func(ctx context.Context) error {
+		return wallet.lease.Renew(ctx, grant)
+	})
+	digest, err := submit(fenced)
+	if errors.Is(err, lease.ErrLost) {
+		log.Printf("❌ Operator wallet lease %d lost before submitting, transaction not sent", grant.Token)
+	}
+	return digest, err
+}
@@ -0,0 +1,455 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/events"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/webhook"
+)
+
+var (
+	ErrInvalidWebhook   = errors.New("invalid webhook request")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+const (
+	webhooksCollection   = "webhooks"
+	deliveriesCollection = "webhook_deliveries"
+
+	DefaultDeliveryLimit = 50
+	MaxDeliveryLimit     = 200
+	// dispatchBatchSize bounds the deliveries attempted per dispatcher pass.
+	dispatchBatchSize = 100
+	// maxResponseExcerpt bounds how much of a failing receiver's body is kept.
+	maxResponseExcerpt = 512
+)
+
+// ConfigureWebhooks replaces the delivery settings and the HTTP client used to
+// reach receivers.
+func (s *GameService) ConfigureWebhooks(config webhook.Config, client *http.Client) {
+	s.webhooks = config
+	s.httpClient = client
+}
+
+func (s *GameService) RegisterWebhook(req *request.RegisterWebhookRequest) (*response.WebhookResponse, error) {
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return &response.WebhookResponse{
+			Success: false,
+			Error:   "Invalid webhook request: url must be an absolute http(s) URL",
+		}, ErrInvalidWebhook
+	}
+	for _, eventType := range req.Events {
+		if !events.Known(eventType) {
+			return &response.WebhookResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Invalid webhook request: unknown event %q", eventType),
+			}, ErrInvalidWebhook
+		}
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = webhook.NewSecret(); err != nil {
+			log.Printf("❌ Failed to generate webhook secret: %v", err)
+			return &response.WebhookResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Failed to register webhook: %v", err),
+			}, err
+		}
+	}
+	subscribed := req.Events
+	if subscribed == nil {
+		subscribed = []string{}
+	}
+
+	endpoint := models.WebhookEndpoint{
+		ID:          primitive.NewObjectID(),
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      subscribed,
+		Description: req.Description,
+		Active:      true,
+		CreatedAt:   s.clock.Now(),
+	}
+	if _, err := s.collection(webhooksCollection).InsertOne(context.Background(), endpoint); err != nil {
+		log.Printf("❌ Failed to register webhook %s: %v", req.URL, err)
+		return &response.WebhookResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to register webhook: %v", err),
+		}, err
+	}
+
+	log.Printf("✅ Registered webhook %s for %v", req.URL, subscribed)
+	// The secret is only ever returned here, at registration
+	return &response.WebhookResponse{
+		Success: true,
+		Webhook: &endpoint,
+	}, nil
+}
+
+func (s *GameService) ListWebhooks() (*response.WebhookListResponse, error) {
+	ctx := context.Background()
+	endpoints, err := s.webhookEndpoints(ctx, bson.M{})
+	if err != nil {
+		log.Printf("❌ Failed to fetch webhooks: %v", err)
+		return &response.WebhookListResponse{
+			Success:  false,
+			Webhooks: []models.WebhookEndpoint{},
+			Error:    fmt.Sprintf("Failed to fetch webhooks: %v", err),
+		}, err
+	}
+	for i := range endpoints {
+		endpoints[i].Secret = ""
+	}
+	return &response.WebhookListResponse{
+		Success:  true,
+		Webhooks: endpoints,
+		Count:    len(endpoints),
+	}, nil
+}
+
+func (s *GameService) DeleteWebhook(webhookID string) (*response.WebhookResponse, error) {
+	id, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return &response.WebhookResponse{Success: false, Error: ErrWebhookNotFound.Error()}, ErrWebhookNotFound
+	}
+	result, err := s.collection(webhooksCollection).DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		log.Printf("❌ Failed to delete webhook %s: %v", webhookID, err)
+		return &response.WebhookResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to delete webhook: %v", err),
+		}, err
+	}
+	if result.DeletedCount == 0 {
+		return &response.WebhookResponse{Success: false, Error: ErrWebhookNotFound.Error()}, ErrWebhookNotFound
+	}
+
+	log.Printf("✅ Deleted webhook %s", webhookID)
+	return &response.WebhookResponse{Success: true}, nil
+}
+
+// ListWebhookDeliveries returns the delivery log, newest first, optionally
+// narrowed to one status or endpoint.
+func (s *GameService) ListWebhookDeliveries(query *request.WebhookDeliveryQuery) (*response.WebhookDeliveryListResponse, error) {
+	filter := bson.M{}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+	if query.EndpointID != "" {
+		id, err := primitive.ObjectIDFromHex(query.EndpointID)
+		if err != nil {
+			return &response.WebhookDeliveryListResponse{
+				Success:    false,
+				Deliveries: []models.WebhookDelivery{},
+				Error:      "Invalid endpoint_id",
+			}, ErrInvalidWebhook
+		}
+		filter["endpoint_id"] = id
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultDeliveryLimit
+	}
+	if limit > MaxDeliveryLimit {
+		limit = MaxDeliveryLimit
+	}
+
+	ctx := context.Background()
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit))
+	deliveries, err := s.webhookDeliveries(ctx, filter, opts)
+	if err != nil {
+		log.Printf("❌ Failed to fetch webhook deliveries: %v", err)
+		return &response.WebhookDeliveryListResponse{
+			Success:    false,
+			Deliveries: []models.WebhookDelivery{},
+			Error:      fmt.Sprintf("Failed to fetch deliveries: %v", err),
+		}, err
+	}
+	return &response.WebhookDeliveryListResponse{
+		Success:    true,
+		Deliveries: deliveries,
+		Count:      len(deliveries),
+	}, nil
+}
+
+// RedeliverWebhook resets a delivery's retry budget and attempts it straight
+// away, whatever state it was in. The updated delivery is returned.
+func (s *GameService) RedeliverWebhook(deliveryID string) (*response.WebhookDeliveryResponse, error) {
+	id, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return &response.WebhookDeliveryResponse{Success: false, Error: ErrDeliveryNotFound.Error()}, ErrDeliveryNotFound
+	}
+
+	ctx := context.Background()
+	now := s.clock.Now()
+	result, err := s.collection(deliveriesCollection).UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": models.DeliveryStatusPending, "attempts": 0, "next_attempt_at": now}},
+	)
+	if err != nil {
+		log.Printf("❌ Failed to reset delivery %s: %v", deliveryID, err)
+		return &response.WebhookDeliveryResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to redeliver: %v", err),
+		}, err
+	}
+	if result.MatchedCount == 0 {
+		return &response.WebhookDeliveryResponse{Success: false, Error: ErrDeliveryNotFound.Error()}, ErrDeliveryNotFound
+	}
+
+	var delivery models.WebhookDelivery
+	if _, err := findOne(ctx, s.collection(deliveriesCollection), bson.M{"_id": id}, &delivery); err != nil {
+		return &response.WebhookDeliveryResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to redeliver: %v", err),
+		}, err
+	}
+	if s.claimDelivery(ctx, &delivery, now) {
+		s.attemptDelivery(ctx, &delivery)
+	}
+
+	log.Printf("✅ Redelivered webhook %s: %s", deliveryID, delivery.Status)
+	return &response.WebhookDeliveryResponse{
+		Success:  true,
+		Delivery: &delivery,
+	}, nil
+}
+
+// DispatchWebhooks attempts every pending delivery that is due and returns how
+// many were delivered.
+func (s *GameService) DispatchWebhooks(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	opts := options.Find().SetSort(bson.M{"next_attempt_at": 1}).SetLimit(dispatchBatchSize)
+	due, err := s.webhookDeliveries(ctx, bson.M{
+		"status":          models.DeliveryStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range due {
+		if !s.claimDelivery(ctx, &due[i], now) {
+			continue
+		}
+		if s.attemptDelivery(ctx, &due[i]) {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// RunWebhookDispatcher sends due deliveries every interval until ctx is done.
+func (s *GameService) RunWebhookDispatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			delivered, err := s.DispatchWebhooks(ctx)
+			if err != nil {
+				log.Printf("⚠️  Webhook dispatch failed: %v", err)
+				continue
+			}
+			if delivered > 0 {
+				log.Printf("📨 Delivered %d webhooks", delivered)
+			}
+		}
+	}
+}
+
+// enqueueWebhooks queues a delivery of the event for every endpoint that
+// subscribes to it. Failures are logged only; the event itself already
+// happened.
+func (s *GameService) enqueueWebhooks(event events.Event) {
+	ctx := context.Background()
+	endpoints, err := s.webhookEndpoints(ctx, bson.M{"active": true})
+	if err != nil {
+		log.Printf("⚠️  Failed to load webhooks for %s: %v", event.Type, err)
+		return
+	}
+
+	var payload []byte
+	for _, endpoint := range endpoints {
+		if !endpoint.Accepts(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				log.Printf("⚠️  Failed to encode %s event: %v", event.Type, err)
+				return
+			}
+		}
+		delivery := models.WebhookDelivery{
+			ID:            primitive.NewObjectID(),
+			EndpointID:    endpoint.ID,
+			URL:           endpoint.URL,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: s.clock.Now(),
+			CreatedAt:     s.clock.Now(),
+		}
+		if _, err := s.collection(deliveriesCollection).InsertOne(ctx, delivery); err != nil {
+			log.Printf("⚠️  Failed to queue %s webhook for %s: %v", event.Type, endpoint.URL, err)
+		}
+	}
+}
+
+// claimDelivery counts the attempt before it is made, conditional on nobody
+// else having made it, and pushes the next attempt out past the request
+// timeout so a crashed sender is retried rather than lost.
+func (s *GameService) claimDelivery(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) bool {
+	result, err := s.collection(deliveriesCollection).UpdateOne(ctx,
+		bson.M{"_id": delivery.ID, "status": models.DeliveryStatusPending, "attempts": delivery.Attempts},
+		bson.M{"$set": bson.M{
+			"attempts":        delivery.Attempts + 1,
+			"next_attempt_at": now.Add(2 * s.webhooks.Timeout),
+		}},
+	)
+	if err != nil {
+		log.Printf("⚠️  Failed to claim delivery %s: %v", delivery.ID.Hex(), err)
+		return false
+	}
+	if result.MatchedCount == 0 {
+		return false
+	}
+	delivery.Attempts++
+	return true
+}
+
+// attemptDelivery POSTs the payload to the endpoint and records the outcome:
+// delivered on any 2xx, otherwise retried with backoff until the attempts run
+// out and the delivery is dead-lettered. Each attempt is stamped when it is
+// made, not when its batch was picked, so slow receivers earlier in a batch
+// cannot push later signatures out of the receivers' tolerance.
+func (s *GameService) attemptDelivery(ctx context.Context, delivery *models.WebhookDelivery) bool {
+	now := s.clock.Now()
+	statusCode, sendErr := s.sendWebhook(ctx, delivery, now)
+
+	updates := bson.M{"last_attempt_at": now, "last_status_code": statusCode}
+	delivery.LastAttemptAt = &now
+	delivery.LastStatusCode = statusCode
+	switch {
+	case sendErr == nil:
+		updates["status"] = models.DeliveryStatusDelivered
+		updates["delivered_at"] = now
+		updates["last_error"] = ""
+		delivery.Status = models.DeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= s.webhooks.MaxAttempts:
+		updates["status"] = models.DeliveryStatusDeadLetter
+		updates["last_error"] = sendErr.Error()
+		delivery.Status = models.DeliveryStatusDeadLetter
+		delivery.LastError = sendErr.Error()
+		log.Printf("⚠️  Webhook %s to %s dead-lettered after %d attempts: %v", delivery.ID.Hex(), delivery.URL, delivery.Attempts, sendErr)
+	default:
+		next := now.Add(s.webhooks.Backoff(delivery.Attempts))
+		updates["status"] = models.DeliveryStatusPending
+		updates["next_attempt_at"] = next
+		updates["last_error"] = sendErr.Error()
+		delivery.Status = models.DeliveryStatusPending
+		delivery.NextAttemptAt = next
+		delivery.LastError = sendErr.Error()
+	}
+
+	if _, err := s.collection(deliveriesCollection).UpdateOne(ctx,
+		bson.M{"_id": delivery.ID},
+		bson.M{"$set": updates},
+	); err != nil {
+		log.Printf("⚠️  Failed to record webhook attempt %s: %v", delivery.ID.Hex(), err)
+	}
+	return sendErr == nil
+}
+
+func (s *GameService) sendWebhook(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	var endpoint models.WebhookEndpoint
+	found, err := findOne(ctx, s.collection(webhooksCollection), bson.M{"_id": delivery.EndpointID}, &endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load endpoint: %v", err)
+	}
+	if !found {
+		return 0, ErrWebhookNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.webhooks.Timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Jollfi-Webhooks/1.0")
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.DeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseExcerpt))
+		return resp.StatusCode, fmt.Errorf("receiver returned %d: %s", resp.StatusCode, bytes.TrimSpace(excerpt))
+	}
+	return resp.StatusCode, nil
+}
+
+func (s *GameService) webhookEndpoints(ctx context.Context, filter bson.M) ([]models.WebhookEndpoint, error) {
+	cursor, err := s.collection(webhooksCollection).Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	endpoints := []models.WebhookEndpoint{}
+	if err := cursor.All(ctx, &endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (s *GameService) webhookDeliveries(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]models.WebhookDelivery, error) {
+	cursor, err := s.collection(deliveriesCollection).Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
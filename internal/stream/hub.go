@@ -0,0 +1,175 @@
+// Package stream fans published events out to live per-address subscribers,
+// such as Server-Sent Events clients. A short ring buffer of recent messages
+// lets a reconnecting client resume from the last message it saw.
+package stream
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"jollfi-gaming-api/internal/events"
+)
+
+// subscriberBuffer is how many messages may queue for a subscriber before it
+// is considered too slow and dropped. A dropped client resumes by reconnecting
+// with its last message ID.
+const subscriberBuffer = 64
+
+// Message is an event with its position in the hub's sequence.
+type Message struct {
+	// ID is "<epoch>-<seq>". The epoch changes whenever the process restarts,
+	// so IDs from a previous run are recognised as unknown.
+	ID    string
+	Event events.Event
+}
+
+// Subscription receives the messages for one address until closed.
+type Subscription struct {
+	// Replay holds the buffered messages the subscriber missed, oldest first.
+	Replay []Message
+	// C delivers live messages.
+	C <-chan Message
+	// Done is closed when the subscription ends, either through Close or
+	// because the subscriber fell too far behind.
+	Done <-chan struct{}
+
+	hub     *Hub
+	address string
+	ch      chan Message
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Close unsubscribes from the hub. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+}
+
+// Hub assigns each published event a sequence number, keeps the most recent
+// ones for replay and forwards them to the subscribers they concern.
+type Hub struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	buffer      []Message
+	size        int
+	subscribers map[*Subscription]struct{}
+}
+
+// NewHub returns a hub that keeps the last bufferSize messages for replay.
+func NewHub(bufferSize int) *Hub {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+	return &Hub{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:        bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish sequences the event and delivers it to every subscriber whose
+// address it involves. It never blocks: a subscriber with a full queue is
+// dropped instead.
+func (h *Hub) Publish(event events.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	msg := Message{ID: fmt.Sprintf("%s-%d", h.epoch, h.seq), Event: event}
+	if h.size > 0 {
+		if len(h.buffer) == h.size {
+			h.buffer = append(h.buffer[:0], h.buffer[1:]...)
+		}
+		h.buffer = append(h.buffer, msg)
+	}
+
+	for sub := range h.subscribers {
+		if !involves(event, sub.address) {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+			h.drop(sub)
+		}
+	}
+}
+
+// Subscribe registers a subscriber for address. Buffered messages after
+// lastEventID are returned in Replay; an empty lastEventID replays nothing,
+// and one from an earlier run or already evicted replays the whole buffer.
+func (h *Hub) Subscribe(address, lastEventID string) *Subscription {
+	ch := make(chan Message, subscriberBuffer)
+	done := make(chan struct{})
+	sub := &Subscription{C: ch, Done: done, hub: h, address: address, ch: ch, done: done}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if lastEventID != "" {
+		after := h.resumePoint(lastEventID)
+		for _, msg := range h.buffer {
+			if h.sequence(msg.ID) > after && involves(msg.Event, address) {
+				sub.Replay = append(sub.Replay, msg)
+			}
+		}
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Subscribers returns the number of live subscriptions.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(sub)
+}
+
+// drop must be called with h.mu held.
+func (h *Hub) drop(sub *Subscription) {
+	delete(h.subscribers, sub)
+	sub.once.Do(func() { close(sub.done) })
+}
+
+// resumePoint returns the sequence number to replay after. Unknown IDs map to
+// zero so the whole buffer is replayed.
+func (h *Hub) resumePoint(lastEventID string) uint64 {
+	epoch, seq, ok := strings.Cut(lastEventID, "-")
+	if !ok || epoch != h.epoch {
+		return 0
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil || n > h.seq {
+		return 0
+	}
+	if len(h.buffer) > 0 && n+1 < h.sequence(h.buffer[0].ID) {
+		// The client missed more than the buffer holds
+		return 0
+	}
+	return n
+}
+
+func (h *Hub) sequence(id string) uint64 {
+	_, seq, _ := strings.Cut(id, "-")
+	n, _ := strconv.ParseUint(seq, 10, 64)
+	return n
+}
+
+func involves(event events.Event, address string) bool {
+	for _, a := range event.Addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
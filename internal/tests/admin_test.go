@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func adminRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	req.Header.Set("X-Admin-Actor", "ops@jollfi")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminRoutes_Operations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	gameService.ConfigurePool("0xpool")
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret"})
+
+	var balance response.AdminBalanceResponse
+	w := adminRequest(router, "GET", "/admin/wallet/balance", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &balance) != nil || balance.Balance != 1000000 || balance.CoinType != service.SuiCoinType {
+		t.Errorf("Expected the summed SUI balance, got %d %s", w.Code, w.Body.String())
+	}
+
+	var coins response.AdminCoinsResponse
+	w = adminRequest(router, "GET", "/admin/wallet/coins", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &coins) != nil || coins.Count != 3 {
+		t.Errorf("Expected 3 coins, got %d %s", w.Code, w.Body.String())
+	}
+
+	var pool response.AdminPoolResponse
+	w = adminRequest(router, "GET", "/admin/pool", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &pool) != nil || pool.PoolID != "0xpool" || pool.Pool == nil {
+		t.Errorf("Expected the pool object, got %d %s", w.Code, w.Body.String())
+	}
+
+	if _, err := gameService.StakeGame(stakeRequest()); err != nil {
+		t.Fatalf("Expected no error staking, got %v", err)
+	}
+	var pending response.AdminTransactionsResponse
+	w = adminRequest(router, "GET", "/admin/transactions/pending", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &pending) != nil || pending.Count != 1 || pending.Transactions[0].Type != service.TransactionTypeStake {
+		t.Errorf("Expected the pending stake transaction, got %d %s", w.Code, w.Body.String())
+	}
+
+	var stats response.AdminCollectionStatsResponse
+	w = adminRequest(router, "GET", "/admin/collections/stats", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &stats) != nil || stats.Collections["transactions"] != float64(1) {
+		t.Errorf("Expected collection counts, got %d %s", w.Code, w.Body.String())
+	}
+
+	ctx := context.Background()
+	old := time.Now().AddDate(0, 0, -100)
+	recent := time.Now().AddDate(0, 0, -1)
+	mockMongoClient.CreateGame(ctx, data.Game{Status: "completed", CompletedAt: &old})
+	mockMongoClient.CreateGame(ctx, data.Game{Status: "completed", CompletedAt: &recent})
+	if w := adminRequest(router, "POST", "/admin/games/cleanup", `{"older_than_days":0}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a zero cutoff, got %d", w.Code)
+	}
+	var cleanup response.AdminCleanupResponse
+	w = adminRequest(router, "POST", "/admin/games/cleanup", `{"older_than_days":30}`)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &cleanup) != nil || cleanup.Deleted != 1 {
+		t.Errorf("Expected only the old game to be deleted, got %d %s", w.Code, w.Body.String())
+	}
+
+	mockSuiClient.SetShouldFail(true)
+	if w := adminRequest(router, "GET", "/admin/wallet/balance", ""); w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502 when the node fails, got %d", w.Code)
+	}
+
+	audit, err := gameService.ListAdminAuditLog(&request.AdminAuditQuery{Actor: "ops@jollfi"})
+	if err != nil || audit.Count != 8 {
+		t.Fatalf("Expected every admin request to be audited, got %+v, %v", audit, err)
+	}
+	statuses := map[string][]int{}
+	for _, entry := range audit.Entries {
+		statuses[entry.Action] = append(statuses[entry.Action], entry.StatusCode)
+		if entry.Action == "POST /admin/games/cleanup" && entry.StatusCode == http.StatusOK && entry.Params["older_than_days"] != "30" {
+			t.Errorf("Expected the cleanup cutoff to be recorded, got %+v", entry.Params)
+		}
+	}
+	if len(statuses["GET /admin/wallet/balance"]) != 2 || len(statuses["POST /admin/games/cleanup"]) != 2 {
+		t.Errorf("Expected two balance and two cleanup entries, got %+v", statuses)
+	}
+
+	unauthorized, _ := http.NewRequest("GET", "/admin/wallet/balance", nil)
+	router.ServeHTTP(httptest.NewRecorder(), unauthorized)
+	if audit, _ := gameService.ListAdminAuditLog(&request.AdminAuditQuery{}); audit.Count != 8 {
+		t.Errorf("Expected unauthenticated requests not to be audited, got %d entries", audit.Count)
+	}
+}
+
+func TestAdminRoutes_PoolNotConfigured(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	gameService := service.NewGameService(mocks.NewMockSuiClient(), mocks.NewMockMongoClient())
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret"})
+
+	if w := adminRequest(router, "GET", "/admin/pool", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 without a pool ID, got %d", w.Code)
+	}
+}
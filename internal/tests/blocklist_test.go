@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/screening"
+	"jollfi-gaming-api/internal/service"
+)
+
+type failingScreen struct{}
+
+func (failingScreen) Screen(ctx context.Context, address string) (screening.Result, error) {
+	return screening.Result{}, errors.New("provider down")
+}
+
+func TestScreening_FileProviderReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sanctions.csv")
+	if err := os.WriteFile(path, []byte("address,reason\n# exported list\n0xAAA, OFAC SDN\n0xbbb\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider, err := screening.NewFileProvider(path)
+	if err != nil || provider.Len() != 2 {
+		t.Fatalf("Expected 2 addresses loaded, got %v", err)
+	}
+	ctx := context.Background()
+	if result, err := provider.Screen(ctx, "0xaaa"); err != nil || !result.Blocked || result.Reason != "OFAC SDN" {
+		t.Errorf("Expected 0xaaa to be blocked case-insensitively with its reason, got %+v, %v", result, err)
+	}
+	if result, _ := provider.Screen(ctx, "0xccc"); result.Blocked {
+		t.Errorf("Expected an unlisted address to pass")
+	}
+
+	os.WriteFile(path, []byte("0xccc,banned\n"), 0o600)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(path, later, later)
+	if result, _ := provider.Screen(ctx, "0xccc"); !result.Blocked {
+		t.Errorf("Expected the replaced file to be picked up")
+	}
+	if result, _ := provider.Screen(ctx, "0xaaa"); result.Blocked {
+		t.Errorf("Expected addresses dropped from the file to pass")
+	}
+
+	os.Remove(path)
+	if _, err := provider.Screen(ctx, "0xccc"); err == nil {
+		t.Errorf("Expected an error once the file is gone")
+	}
+}
+
+func TestGameService_BlocklistRefusesStakesAndPayouts(t *testing.T) {
+	gameService, mockSuiClient, _, _ := newReadinessService(t)
+	submitted := 0
+	mockSuiClient.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		submitted++
+		return "digest", nil
+	}
+
+	if _, err := gameService.AddBlocklistEntry(&request.BlocklistEntryRequest{Address: "0xBBB", Reason: "chargeback fraud"}, "ops"); err != nil {
+		t.Fatalf("Expected no error blocking, got %v", err)
+	}
+	resp, err := gameService.StakeGame(stakeRequest())
+	if !errors.Is(err, service.ErrAddressBlocked) || resp.Code != service.StakeCodeAddressBlocked {
+		t.Errorf("Expected the stake to be refused for the blocked accepter, got %+v, %v", resp, err)
+	}
+	payout := &request.PayWinnerRequest{RequesterAddress: "0xaaa", AccepterAddress: "0xbbb", StakeAmount: 100, RequesterScore: 1}
+	if _, err := gameService.PayWinner(payout); !errors.Is(err, service.ErrAddressBlocked) {
+		t.Errorf("Expected the payout to be refused, got %v", err)
+	}
+	if submitted != 0 {
+		t.Errorf("Expected nothing submitted on chain, got %d", submitted)
+	}
+
+	audit, _ := gameService.ListAdminAuditLog(&request.AdminAuditQuery{Actor: "screening"})
+	if audit.Count != 2 {
+		t.Fatalf("Expected both blocked attempts in the audit log, got %+v", audit.Entries)
+	}
+	for _, entry := range audit.Entries {
+		if !strings.HasPrefix(entry.Action, "BLOCKED ") || entry.Params["address"] != "0xbbb" || entry.Params["reason"] != "chargeback fraud" {
+			t.Errorf("Expected the blocked address and reason recorded, got %+v", entry)
+		}
+	}
+
+	if _, err := gameService.RemoveBlocklistEntry("0xbbb"); err != nil {
+		t.Fatalf("Expected no error unblocking, got %v", err)
+	}
+	if _, err := gameService.RemoveBlocklistEntry("0xbbb"); !errors.Is(err, service.ErrBlocklistEntryNotFound) {
+		t.Errorf("Expected removing an unblocked address to fail, got %v", err)
+	}
+	if _, err := gameService.StakeGame(stakeRequest()); err != nil {
+		t.Errorf("Expected stakes to go through once unblocked, got %v", err)
+	}
+
+	gameService.ConfigureScreening(failingScreen{})
+	if _, err := gameService.StakeGame(stakeRequest()); !errors.Is(err, service.ErrScreeningUnavailable) {
+		t.Errorf("Expected stakes to fail closed while screening is down, got %v", err)
+	}
+}
+
+func TestAdminRoutes_Blocklist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret"})
+	blocked := "0x1234567890abcdef1234567890abcdef12345678"
+
+	csv := "address,reason\n" + strings.ToUpper(blocked[2:]) + ",missing prefix\n" + blocked + ",sanctioned\n0xabcdef1234567890abcdef1234567890abcdef12\n"
+	var imported response.BlocklistImportResponse
+	w := adminRequest(router, "POST", "/admin/blocklist/import", csv)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &imported) != nil || imported.Imported != 2 || len(imported.Invalid) != 1 {
+		t.Errorf("Expected 2 imported and 1 invalid, got %d %s", w.Code, w.Body.String())
+	}
+
+	var list response.BlocklistResponse
+	w = adminRequest(router, "GET", "/admin/blocklist", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &list) != nil || list.Count != 2 || list.Entries[0].Source != service.BlocklistSourceImport || list.Entries[0].AddedBy != "ops@jollfi" {
+		t.Errorf("Expected the imported entries, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := adminRequest(router, "POST", "/admin/blocklist", `{"address":"not-an-address"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid address, got %d", w.Code)
+	}
+
+	stake := `{"requester_coin_id":"0x123","accepter_coin_id":"0x456","requester_address":"0x` + strings.ToUpper(blocked[2:]) + `","accepter_address":"0xfedcba1234567890abcdef1234567890abcdef12","stake_amount":5}`
+	w = adminRequest(router, "POST", "/api/v1/games/stake", stake)
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), service.StakeCodeAddressBlocked) {
+		t.Errorf("Expected 403 address_blocked, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := adminRequest(router, "DELETE", "/admin/blocklist/"+blocked, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected the address to be unblocked, got %d", w.Code)
+	}
+	if w := adminRequest(router, "DELETE", "/admin/blocklist/"+blocked, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an address that is not blocked, got %d", w.Code)
+	}
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/breaker"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func TestBreaker_Transitions(t *testing.T) {
+	clock := newFakeClock()
+	b := breaker.New(breaker.Config{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenProbes: 1, Now: clock.Now})
+
+	b.Allow()
+	b.Failure(errors.New("timeout"))
+	b.Allow()
+	b.Success()
+	b.Allow()
+	b.Failure(errors.New("timeout"))
+	if b.State() != breaker.Closed {
+		t.Fatalf("Expected a success to reset the failure count, got %s", b.State())
+	}
+	b.Allow()
+	b.Failure(errors.New("timeout"))
+	if status := b.Status(); status.State != breaker.Open || status.LastError != "timeout" || status.OpenedAt == nil {
+		t.Fatalf("Expected the breaker to open, got %+v", status)
+	}
+	if err := b.Allow(); err != breaker.ErrOpen {
+		t.Errorf("Expected calls to be refused while open, got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if b.State() != breaker.HalfOpen {
+		t.Fatalf("Expected half_open after the timeout, got %s", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Expected one probe to be allowed, got %v", err)
+	}
+	if err := b.Allow(); err != breaker.ErrOpen {
+		t.Errorf("Expected a second concurrent probe to be refused, got %v", err)
+	}
+	b.Failure(errors.New("still down"))
+	if b.State() != breaker.Open {
+		t.Fatalf("Expected a failed probe to reopen, got %s", b.State())
+	}
+
+	clock.Advance(time.Minute)
+	b.Allow()
+	b.Success()
+	if status := b.Status(); status.State != breaker.Closed || status.LastError != "" {
+		t.Errorf("Expected a good probe to close the breaker, got %+v", status)
+	}
+}
+
+func newResilientSuiClient(t *testing.T, url string, rpc data.RPCConfig) *data.SuiClient {
+	client, err := data.NewSuiClient(url, testPrivateKey, &data.Config{PackageID: "0x2a", ModuleName: "game", PoolID: "0xpool"})
+	if err != nil {
+		t.Fatalf("Expected no error creating client, got %v", err)
+	}
+	client.ConfigureRPC(rpc)
+	return client
+}
+
+func TestSuiClient_RetriesReadsButNotWrites(t *testing.T) {
+	node := newFakeSuiNode(t)
+	node.failWith(http.StatusServiceUnavailable)
+	client := newResilientSuiClient(t, node.URL, data.RPCConfig{
+		Timeout:        time.Second,
+		MaxRetries:     2,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  5 * time.Millisecond,
+		Breaker:        breaker.Config{FailureThreshold: 100},
+	})
+	ctx := context.Background()
+
+	if _, err := client.GetBalance(ctx); err == nil || !strings.Contains(err.Error(), "HTTP 503") {
+		t.Errorf("Expected the HTTP failure to be returned, got %v", err)
+	}
+	if n := node.callCount("suix_getBalance"); n != 3 {
+		t.Errorf("Expected a read to be tried 3 times, got %d", n)
+	}
+	if _, err := client.ExecuteTransactionBlock(ctx, []byte("tx")); err == nil {
+		t.Error("Expected the submission to fail")
+	}
+	if n := node.callCount("sui_executeTransactionBlock"); n != 1 {
+		t.Errorf("Expected a submission to be tried once, got %d", n)
+	}
+
+	node.failWith(0)
+	node.respond("suix_getBalance", errors.New("invalid address"))
+	if _, err := client.GetBalance(ctx); err == nil || !strings.Contains(err.Error(), "invalid address") {
+		t.Errorf("Expected the RPC error, got %v", err)
+	}
+	if n := node.callCount("suix_getBalance"); n != 4 {
+		t.Errorf("Expected RPC errors not to be retried, got %d calls", n)
+	}
+	if status := client.RPCStatus()[0]; status.ConsecutiveFailures != 0 {
+		t.Errorf("Expected an answering node to reset the failure count, got %+v", status)
+	}
+}
+
+func TestSuiClient_BreakerOpensAndProbes(t *testing.T) {
+	node := newFakeSuiNode(t)
+	node.respond("suix_getBalance", map[string]interface{}{"totalBalance": "5000"})
+	clock := newFakeClock()
+	client := newResilientSuiClient(t, node.URL, data.RPCConfig{
+		Timeout: time.Second,
+		Breaker: breaker.Config{FailureThreshold: 2, OpenTimeout: 30 * time.Second, HalfOpenProbes: 1, Now: clock.Now},
+	})
+	ctx := context.Background()
+
+	node.failWith(http.StatusInternalServerError)
+	client.GetBalance(ctx)
+	client.GetBalance(ctx)
+	if status := client.RPCStatus()[0]; status.Breaker != string(breaker.Open) || status.ConsecutiveFailures != 2 {
+		t.Fatalf("Expected the breaker to open after 2 failures, got %+v", status)
+	}
+	if _, err := client.GetBalance(ctx); !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("Expected calls to fail fast while open, got %v", err)
+	}
+	if n := node.callCount("suix_getBalance"); n != 2 {
+		t.Errorf("Expected no request to reach the node while open, got %d", n)
+	}
+
+	clock.Advance(30 * time.Second)
+	if status := client.RPCStatus()[0]; status.Breaker != string(breaker.HalfOpen) {
+		t.Fatalf("Expected half_open after the open timeout, got %+v", status)
+	}
+	client.GetBalance(ctx)
+	if status := client.RPCStatus()[0]; status.Breaker != string(breaker.Open) {
+		t.Fatalf("Expected a failed probe to reopen the breaker, got %+v", status)
+	}
+
+	clock.Advance(30 * time.Second)
+	node.failWith(0)
+	balance, err := client.GetBalance(ctx)
+	if err != nil || balance != 5000 {
+		t.Fatalf("Expected the probe to succeed, got %d, %v", balance, err)
+	}
+	if status := client.RPCStatus()[0]; status.Breaker != string(breaker.Closed) || status.OpenedAt != nil {
+		t.Errorf("Expected the breaker to close, got %+v", status)
+	}
+}
+
+func TestHealthRoute_ReportsBreakerState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	node := newFakeSuiNode(t)
+	node.failWith(http.StatusBadGateway)
+	client := newResilientSuiClient(t, node.URL+"/v1/secret-key?token=abc", data.RPCConfig{
+		Timeout: time.Second,
+		Breaker: breaker.Config{FailureThreshold: 1, OpenTimeout: time.Minute},
+	})
+	gameService := service.NewGameService(client, mocks.NewMockMongoClient())
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test"})
+
+	health := func() map[string]interface{} {
+		req, _ := http.NewRequest("GET", "/health", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected /health to stay 200, got %d", w.Code)
+		}
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return body
+	}
+
+	if body := health(); body["status"] != "healthy" {
+		t.Errorf("Expected healthy before any failure, got %v", body)
+	}
+
+	client.GetBalance(context.Background())
+	body := health()
+	if body["status"] != "degraded" {
+		t.Errorf("Expected degraded while the breaker is open, got %v", body["status"])
+	}
+	endpoints := body["dependencies"].(map[string]interface{})["sui_rpc"].([]interface{})
+	endpoint := endpoints[0].(map[string]interface{})
+	if endpoint["breaker"] != string(breaker.Open) || endpoint["last_error"] == "" {
+		t.Errorf("Expected the open breaker to be reported, got %+v", endpoint)
+	}
+	if endpoint["url"] != node.URL {
+		t.Errorf("Expected the URL without path or query, got %v", endpoint["url"])
+	}
+}
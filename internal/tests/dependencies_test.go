@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func TestGameService_DependencyMonitor_DegradesAndRecovers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", DependencyCheckInterval: 5})
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	var mongoRecoveries, suiRecoveries int
+	gameService.OnDependencyRecovered(service.DependencyMongo, func(ctx context.Context) error {
+		mongoRecoveries++
+		return nil
+	})
+	gameService.OnDependencyRecovered(service.DependencySui, func(ctx context.Context) error {
+		suiRecoveries++
+		return nil
+	})
+
+	if !gameService.DependencyAvailable(service.DependencyMongo) {
+		t.Error("Expected an unchecked dependency to count as available")
+	}
+
+	mockMongoClient.SetPingError(errors.New("connection refused"))
+	gameService.CheckDependencies(context.Background())
+	if gameService.DependencyAvailable(service.DependencyMongo) || !gameService.DependencyAvailable(service.DependencySui) {
+		t.Fatalf("Expected only Mongo to be down, got %+v", gameService.DependencyStates())
+	}
+	if mongoRecoveries != 0 || suiRecoveries != 1 {
+		t.Errorf("Expected only the Sui hook to run, got mongo=%d sui=%d", mongoRecoveries, suiRecoveries)
+	}
+
+	w := get("/api/v1/leaderboard")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "5" || !strings.Contains(w.Body.String(), `"dependency":"mongo"`) {
+		t.Errorf("Expected 503 with Retry-After for a Mongo route, got %d %s %s", w.Code, w.Header().Get("Retry-After"), w.Body.String())
+	}
+	if w := get("/api/v1/pool"); w.Code != http.StatusOK {
+		t.Errorf("Expected a Sui-only route to keep working, got %d", w.Code)
+	}
+	if w := get("/readyz"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz to be not ready, got %d", w.Code)
+	}
+	var health map[string]interface{}
+	w = get("/health")
+	json.Unmarshal(w.Body.Bytes(), &health)
+	if w.Code != http.StatusOK || health["status"] != "degraded" {
+		t.Errorf("Expected /health to stay 200 and report degraded, got %d %v", w.Code, health["status"])
+	}
+
+	mockMongoClient.SetPingError(nil)
+	clock.Advance(5 * time.Second)
+	gameService.CheckDependencies(context.Background())
+	gameService.CheckDependencies(context.Background())
+	state := gameService.DependencyStates()[service.DependencyMongo]
+	if !state.Available || !state.Since.Equal(clock.Now()) || state.LastError != "" {
+		t.Errorf("Expected Mongo to be back since the last check, got %+v", state)
+	}
+	if mongoRecoveries != 1 || suiRecoveries != 1 {
+		t.Errorf("Expected each hook to run once per recovery, got mongo=%d sui=%d", mongoRecoveries, suiRecoveries)
+	}
+	if w := get("/api/v1/leaderboard"); w.Code != http.StatusOK {
+		t.Errorf("Expected the Mongo route to work again, got %d %s", w.Code, w.Body.String())
+	}
+	if w := get("/readyz"); w.Code != http.StatusOK {
+		t.Errorf("Expected /readyz to be ready again, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGameService_DependencyMonitor_FailedHookKeepsDependencyDown(t *testing.T) {
+	gameService, mockSuiClient, _, _ := newReadinessService(t)
+	poolValid := false
+	gameService.OnDependencyRecovered(service.DependencySui, func(ctx context.Context) error {
+		if !poolValid {
+			return errors.New("invalid pool configuration")
+		}
+		return nil
+	})
+
+	mockSuiClient.SetShouldFail(true)
+	gameService.CheckDependencies(context.Background())
+	if gameService.DependencyAvailable(service.DependencySui) {
+		t.Fatal("Expected Sui to be down")
+	}
+
+	mockSuiClient.SetShouldFail(false)
+	gameService.CheckDependencies(context.Background())
+	state := gameService.DependencyStates()[service.DependencySui]
+	if state.Available || !strings.Contains(state.LastError, "invalid pool configuration") {
+		t.Errorf("Expected a failing hook to keep Sui down, got %+v", state)
+	}
+
+	poolValid = true
+	gameService.CheckDependencies(context.Background())
+	if !gameService.DependencyAvailable(service.DependencySui) {
+		t.Errorf("Expected the hook to be retried and Sui to come up, got %+v", gameService.DependencyStates())
+	}
+}
@@ -2,10 +2,15 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jollfi-gaming-api/internal/data"
 	"jollfi-gaming-api/internal/dto/request"
 	"jollfi-gaming-api/internal/mocks"
 	"jollfi-gaming-api/internal/service"
@@ -76,6 +81,7 @@ func TestGameService_StakeGame_BlockchainFailure(t *testing.T) {
 	mockSuiClient.SetShouldFail(true)
 
 	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	recordGame(t, mockMongoClient, "0x123", "0x456")
 
 	req := &request.StakeRequest{
 		RequesterCoinID:  "0xcoin123",
@@ -94,6 +100,19 @@ func TestGameService_StakeGame_BlockchainFailure(t *testing.T) {
 	}
 }
 
+// recordGame stakes 100 between two players, for a payout to settle.
+func recordGame(t *testing.T, mongoClient *mocks.MockMongoClient, requester, accepter string) {
+	if _, err := mongoClient.CreateGame(context.Background(), data.Game{
+		RequesterAddress: requester,
+		AccepterAddress:  accepter,
+		StakeAmount:      100,
+		Status:           service.GameStatusStaked,
+		CreatedAt:        time.Now(),
+	}); err != nil {
+		t.Fatalf("Expected no error creating game, got %v", err)
+	}
+}
+
 func TestGameService_PayWinner_Success(t *testing.T) {
 	mockSuiClient := mocks.NewMockSuiClient()
 	mockMongoClient := mocks.NewMockMongoClient()
@@ -106,6 +125,7 @@ func TestGameService_PayWinner_Success(t *testing.T) {
 	}
 
 	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	recordGame(t, mockMongoClient, "0x123", "0x456")
 
 	req := &request.PayWinnerRequest{
 		RequesterAddress: "0x123",
@@ -157,6 +177,7 @@ func TestGameService_PayWinner_BlockchainFailure(t *testing.T) {
 	mockSuiClient.SetShouldFail(true)
 
 	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	recordGame(t, mockMongoClient, "0x123", "0x456")
 
 	req := &request.PayWinnerRequest{
 		RequesterAddress: "0x123",
@@ -340,6 +361,7 @@ func TestGameService_PayWinner_ValidationScenarios(t *testing.T) {
 	mockSuiClient := mocks.NewMockSuiClient()
 	mockMongoClient := mocks.NewMockMongoClient()
 	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	recordGame(t, mockMongoClient, "0x123", "0x456")
 
 	testCases := []struct {
 		name          string
@@ -448,3 +470,463 @@ func TestGameService_CustomMockBehavior(t *testing.T) {
 		t.Errorf("Expected transaction digest '%s', got %s", expectedDigest, resp.TransactionDigest)
 	}
 }
+
+func TestGameService_StakeAndPayWinner_GameLifecycle(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	stakeResp, err := gameService.StakeGame(&request.StakeRequest{
+		RequesterCoinID:  "0xcoin123",
+		AccepterCoinID:   "0xcoin456",
+		RequesterAddress: "0x123",
+		AccepterAddress:  "0x456",
+		StakeAmount:      100,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error staking, got %v", err)
+	}
+	if stakeResp.GameID == "" {
+		t.Fatalf("Expected game ID in stake response")
+	}
+
+	staked, err := mockMongoClient.GetGame(context.Background(), stakeResp.GameID)
+	if err != nil {
+		t.Fatalf("Expected staked game to be recorded, got %v", err)
+	}
+	if game := staked.(data.Game); game.Status != "staked" {
+		t.Errorf("Expected status 'staked', got %s", game.Status)
+	}
+
+	_, err = gameService.PayWinner(&request.PayWinnerRequest{
+		RequesterAddress: "0x123",
+		AccepterAddress:  "0x456",
+		RequesterScore:   3,
+		AccepterScore:    7,
+		StakeAmount:      100,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error paying winner, got %v", err)
+	}
+
+	completed, _ := mockMongoClient.GetGame(context.Background(), stakeResp.GameID)
+	game := completed.(data.Game)
+	if game.Status != "completed" {
+		t.Errorf("Expected status 'completed', got %s", game.Status)
+	}
+	if game.Winner != "0x456" {
+		t.Errorf("Expected winner '0x456', got %s", game.Winner)
+	}
+	if game.CompletedAt == nil {
+		t.Errorf("Expected completed_at to be set")
+	}
+}
+
+func TestGameService_GetPlayerProfile_Success(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	games := []struct {
+		opponent       string
+		playerScore    uint64
+		opponentScore  uint64
+		playerIsCaller bool
+	}{
+		{"0x456", 1, 5, true},
+		{"0x456", 9, 2, true},
+		{"0x789", 4, 4, false},
+		{"0x789", 8, 1, false},
+		{"0x456", 6, 3, true},
+		{"0xabc", 7, 0, true},
+	}
+	for _, g := range games {
+		req := &request.PayWinnerRequest{
+			RequesterAddress: "0x123",
+			AccepterAddress:  g.opponent,
+			RequesterScore:   g.playerScore,
+			AccepterScore:    g.opponentScore,
+			StakeAmount:      100,
+		}
+		if !g.playerIsCaller {
+			req.RequesterAddress, req.AccepterAddress = g.opponent, "0x123"
+			req.RequesterScore, req.AccepterScore = g.opponentScore, g.playerScore
+		}
+		if _, err := gameService.StakeGame(&request.StakeRequest{
+			RequesterCoinID:  "0xcoin1",
+			AccepterCoinID:   "0xcoin2",
+			RequesterAddress: req.RequesterAddress,
+			AccepterAddress:  req.AccepterAddress,
+			StakeAmount:      100,
+		}); err != nil {
+			t.Fatalf("Expected no error staking, got %v", err)
+		}
+		if _, err := gameService.PayWinner(req); err != nil {
+			t.Fatalf("Expected no error paying winner, got %v", err)
+		}
+	}
+
+	resp, err := gameService.GetPlayerProfile("0x123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !resp.Success || resp.Profile == nil {
+		t.Fatalf("Expected successful profile response, got %+v", resp)
+	}
+
+	profile := resp.Profile
+	if profile.GamesSettled != 6 || profile.Wins != 4 || profile.Losses != 1 || profile.Draws != 1 {
+		t.Errorf("Expected 6 games (4W/1L/1D), got %d games (%dW/%dL/%dD)",
+			profile.GamesSettled, profile.Wins, profile.Losses, profile.Draws)
+	}
+	// Each win pays the 180 prize left after the contract's 10% fee on the
+	// 200 pot, a profit of 80 on the player's 100 stake
+	if profile.NetProfit != 220 {
+		t.Errorf("Expected net profit 220, got %d", profile.NetProfit)
+	}
+	if profile.CurrentWinStreak != 3 {
+		t.Errorf("Expected current win streak 3, got %d", profile.CurrentWinStreak)
+	}
+	if profile.LongestWinStreak != 3 {
+		t.Errorf("Expected longest win streak 3, got %d", profile.LongestWinStreak)
+	}
+	if profile.FirstSeen == 0 || profile.LastSeen < profile.FirstSeen {
+		t.Errorf("Expected first/last seen to be set, got %d/%d", profile.FirstSeen, profile.LastSeen)
+	}
+	if totalWins, ok := profile.Stats["total_wins"].(int64); !ok || totalWins != 4 {
+		t.Errorf("Expected lifecycle total_wins 4, got %v", profile.Stats["total_wins"])
+	}
+
+	if len(profile.HeadToHead) != 3 {
+		t.Fatalf("Expected 3 head-to-head records, got %d", len(profile.HeadToHead))
+	}
+	top := profile.HeadToHead[0]
+	if top.Opponent != "0x456" || top.Games != 3 || top.Wins != 2 || top.Losses != 1 {
+		t.Errorf("Expected 0x456 as top rival with 2W/1L, got %+v", top)
+	}
+}
+
+func TestGameService_GetPlayerProfile_EmptyAddress(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	resp, err := gameService.GetPlayerProfile("")
+	if err == nil {
+		t.Errorf("Expected error for empty address, got nil")
+	}
+	if resp.Success {
+		t.Errorf("Expected success=false for empty address, got %v", resp.Success)
+	}
+}
+
+func TestGameService_GetLeaderboard_RanksAndPagination(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	games := []struct {
+		requester, accepter string
+		requesterScore      uint64
+		accepterScore       uint64
+	}{
+		{"0xaaa", "0xbbb", 5, 1},
+		{"0xaaa", "0xccc", 7, 2},
+		{"0xbbb", "0xccc", 3, 0},
+		{"0xddd", "0xccc", 9, 4},
+	}
+	for _, g := range games {
+		recordGame(t, mockMongoClient, g.requester, g.accepter)
+		if _, err := gameService.PayWinner(&request.PayWinnerRequest{
+			RequesterAddress: g.requester,
+			AccepterAddress:  g.accepter,
+			RequesterScore:   g.requesterScore,
+			AccepterScore:    g.accepterScore,
+			StakeAmount:      100,
+		}); err != nil {
+			t.Fatalf("Expected no error paying winner, got %v", err)
+		}
+	}
+
+	resp, err := gameService.GetLeaderboard(&request.LeaderboardQuery{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Metric != "wins" || resp.Period != "all" || resp.Total != 4 {
+		t.Errorf("Expected wins/all leaderboard with 4 players, got %s/%s with %d", resp.Metric, resp.Period, resp.Total)
+	}
+	expected := []struct {
+		address string
+		rank    int64
+	}{{"0xaaa", 1}, {"0xbbb", 2}, {"0xddd", 2}, {"0xccc", 4}}
+	if len(resp.Entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(resp.Entries))
+	}
+	for i, want := range expected {
+		if resp.Entries[i].Address != want.address || resp.Entries[i].Rank != want.rank {
+			t.Errorf("Entry %d: expected %s at rank %d, got %s at rank %d",
+				i, want.address, want.rank, resp.Entries[i].Address, resp.Entries[i].Rank)
+		}
+	}
+
+	// A tie straddling the page boundary keeps its shared rank
+	resp, err = gameService.GetLeaderboard(&request.LeaderboardQuery{Page: 2, Limit: 2, Address: "0xccc"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resp.Entries) != 2 || resp.Entries[0].Address != "0xddd" || resp.Entries[0].Rank != 2 || resp.Entries[1].Rank != 4 {
+		t.Errorf("Expected page 2 to be 0xddd (rank 2) and 0xccc (rank 4), got %+v", resp.Entries)
+	}
+	if resp.Player == nil || resp.Player.Rank != 4 || resp.Player.Losses != 3 {
+		t.Errorf("Expected 0xccc at rank 4 with 3 losses, got %+v", resp.Player)
+	}
+
+	resp, err = gameService.GetLeaderboard(&request.LeaderboardQuery{Metric: "volume", Period: "week"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resp.Entries) == 0 || resp.Entries[0].Address != "0xccc" || resp.Entries[0].Volume != 300 {
+		t.Errorf("Expected 0xccc to lead weekly volume with 300, got %+v", resp.Entries)
+	}
+	if resp.PeriodKey != service.LeaderboardPeriodKey("week", time.Now()) {
+		t.Errorf("Expected current week period key, got %s", resp.PeriodKey)
+	}
+
+	// A win is worth the 180 prize left after the contract's 10% fee on the
+	// 200 pot, less the winner's 100 stake
+	resp, err = gameService.GetLeaderboard(&request.LeaderboardQuery{Metric: "profit", Address: "0xbbb"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resp.Entries) == 0 || resp.Entries[0].Address != "0xaaa" || resp.Entries[0].Profit != 160 {
+		t.Errorf("Expected 0xaaa to lead profit with 160 after fees, got %+v", resp.Entries)
+	}
+	if resp.Player == nil || resp.Player.Profit != -20 {
+		t.Errorf("Expected 0xbbb's win and loss to net -20 after fees, got %+v", resp.Player)
+	}
+}
+
+func TestGameService_PayWinner_UpdatesRatings(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	settle := func(requesterScore, accepterScore uint64) {
+		recordGame(t, mockMongoClient, "0xaaa", "0xbbb")
+		if _, err := gameService.PayWinner(&request.PayWinnerRequest{
+			RequesterAddress: "0xaaa",
+			AccepterAddress:  "0xbbb",
+			RequesterScore:   requesterScore,
+			AccepterScore:    accepterScore,
+			StakeAmount:      100,
+		}); err != nil {
+			t.Fatalf("Expected no error paying winner, got %v", err)
+		}
+	}
+
+	settle(10, 5)
+	winner, _ := gameService.GetPlayerRating("0xaaa")
+	loser, _ := gameService.GetPlayerRating("0xbbb")
+	if winner.Rating.Rating <= 1500 || loser.Rating.Rating >= 1500 {
+		t.Fatalf("Expected winner above and loser below 1500, got %.2f and %.2f", winner.Rating.Rating, loser.Rating.Rating)
+	}
+	if math.Abs((winner.Rating.Rating-1500)-(1500-loser.Rating.Rating)) > 0.0001 {
+		t.Errorf("Expected symmetric rating change between new players, got %.4f and %.4f", winner.Rating.Rating, loser.Rating.Rating)
+	}
+	if winner.Rating.Games != 1 || winner.Rating.Deviation >= 350 {
+		t.Errorf("Expected one rated game with reduced deviation, got %+v", winner.Rating)
+	}
+
+	// A draw pulls the higher rated player down and the lower rated one up
+	settle(3, 3)
+	afterDraw, err := gameService.GetPlayerRating("0xaaa")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if afterDraw.Rating.Rating >= winner.Rating.Rating {
+		t.Errorf("Expected draw against a weaker player to lower the rating, got %.2f -> %.2f", winner.Rating.Rating, afterDraw.Rating.Rating)
+	}
+	if afterDraw.Rating.Games != 2 || len(afterDraw.History) != 2 {
+		t.Fatalf("Expected 2 rated games with history, got %d games and %d entries", afterDraw.Rating.Games, len(afterDraw.History))
+	}
+
+	results := map[string]bool{}
+	for _, entry := range afterDraw.History {
+		results[entry.Result] = true
+		if entry.Opponent != "0xbbb" {
+			t.Errorf("Expected opponent 0xbbb, got %s", entry.Opponent)
+		}
+	}
+	if !results["win"] || !results["draw"] {
+		t.Errorf("Expected a win and a draw in history, got %+v", afterDraw.History)
+	}
+}
+
+func TestGameService_GetPlayerRating_Unrated(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	resp, err := gameService.GetPlayerRating("0xnew")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Rating.Rating != 1500 || resp.Rating.Deviation != 350 || resp.Rating.Games != 0 {
+		t.Errorf("Expected starting rating 1500/350, got %+v", resp.Rating)
+	}
+}
+
+func TestGameService_Challenge_AcceptStakesGame(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	var stakedCoins []string
+	mockSuiClient.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		stakedCoins = []string{requesterCoinID, accepterCoinID}
+		return "tx_challenge", nil
+	}
+
+	created, err := gameService.CreateChallenge(&request.CreateChallengeRequest{
+		RequesterAddress: "0xaaa",
+		RequesterCoinID:  "0xcoin_a",
+		StakeAmount:      250,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error creating challenge, got %v", err)
+	}
+	if created.Challenge.Status != "pending" || created.Challenge.ExpiresAt.IsZero() {
+		t.Errorf("Expected pending challenge with expiry, got %+v", created.Challenge)
+	}
+
+	list, _ := gameService.ListChallenges("")
+	if list.Count != 1 {
+		t.Fatalf("Expected 1 open challenge, got %d", list.Count)
+	}
+
+	if _, err := gameService.AcceptChallenge(created.Challenge.ID, &request.AcceptChallengeRequest{
+		AccepterAddress: "0xaaa",
+		AccepterCoinID:  "0xcoin_x",
+	}); !errors.Is(err, service.ErrChallengeForbidden) {
+		t.Errorf("Expected forbidden accepting own challenge, got %v", err)
+	}
+
+	resp, err := gameService.AcceptChallenge(created.Challenge.ID, &request.AcceptChallengeRequest{
+		AccepterAddress: "0xbbb",
+		AccepterCoinID:  "0xcoin_b",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error accepting challenge, got %v", err)
+	}
+	if resp.GameID != created.Challenge.ID || resp.TransactionDigest != "tx_challenge" {
+		t.Errorf("Expected stake of challenge %s, got %+v", created.Challenge.ID, resp)
+	}
+	if len(stakedCoins) != 2 || stakedCoins[0] != "0xcoin_a" || stakedCoins[1] != "0xcoin_b" {
+		t.Errorf("Expected both coins to be staked, got %v", stakedCoins)
+	}
+
+	raw, _ := mockMongoClient.GetGame(context.Background(), created.Challenge.ID)
+	game := raw.(data.Game)
+	if game.Status != "staked" || game.AccepterAddress != "0xbbb" || game.TransactionDigest != "tx_challenge" {
+		t.Errorf("Expected staked game against 0xbbb, got %+v", game)
+	}
+	if game.StakedAt == nil || game.StakedAt.Before(game.CreatedAt) {
+		t.Errorf("Expected the stake time recorded when the challenge was accepted, got %v (created %v)", game.StakedAt, game.CreatedAt)
+	}
+
+	if _, err := gameService.AcceptChallenge(created.Challenge.ID, &request.AcceptChallengeRequest{
+		AccepterAddress: "0xccc",
+		AccepterCoinID:  "0xcoin_c",
+	}); !errors.Is(err, service.ErrChallengeNotOpen) {
+		t.Errorf("Expected second accept to conflict, got %v", err)
+	}
+}
+
+func TestGameService_Challenge_StakeFailureReleasesChallenge(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	mockSuiClient.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		return "", fmt.Errorf("insufficient gas")
+	}
+
+	created, _ := gameService.CreateChallenge(&request.CreateChallengeRequest{
+		RequesterAddress: "0xaaa",
+		RequesterCoinID:  "0xcoin_a",
+		StakeAmount:      100,
+		AccepterAddress:  "0xbbb",
+	})
+
+	if _, err := gameService.AcceptChallenge(created.Challenge.ID, &request.AcceptChallengeRequest{
+		AccepterAddress: "0xccc",
+		AccepterCoinID:  "0xcoin_c",
+	}); !errors.Is(err, service.ErrChallengeForbidden) {
+		t.Errorf("Expected reserved challenge to reject other opponents, got %v", err)
+	}
+	if _, err := gameService.AcceptChallenge(created.Challenge.ID, &request.AcceptChallengeRequest{
+		AccepterAddress: "0xbbb",
+		AccepterCoinID:  "0xcoin_b",
+	}); err == nil {
+		t.Fatalf("Expected stake failure to surface")
+	}
+
+	raw, _ := mockMongoClient.GetGame(context.Background(), created.Challenge.ID)
+	if game := raw.(data.Game); game.Status != "pending" || game.AccepterAddress != "0xbbb" || game.AccepterCoinID != "" {
+		t.Errorf("Expected challenge back in pending for 0xbbb, got %+v", game)
+	}
+
+	list, _ := gameService.ListChallenges("")
+	if list.Count != 0 {
+		t.Errorf("Expected reserved challenge to be hidden without an address, got %d", list.Count)
+	}
+	list, _ = gameService.ListChallenges("0xbbb")
+	if list.Count != 1 {
+		t.Errorf("Expected reserved challenge to be visible to its opponent, got %d", list.Count)
+	}
+}
+
+func TestGameService_Challenge_CancelAndExpire(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	first, _ := gameService.CreateChallenge(&request.CreateChallengeRequest{
+		RequesterAddress: "0xaaa",
+		RequesterCoinID:  "0xcoin_a",
+		StakeAmount:      100,
+	})
+	second, _ := gameService.CreateChallenge(&request.CreateChallengeRequest{
+		RequesterAddress: "0xaaa",
+		RequesterCoinID:  "0xcoin_a2",
+		StakeAmount:      100,
+		ExpiresIn:        60,
+	})
+
+	if _, err := gameService.CancelChallenge(first.Challenge.ID, &request.CancelChallengeRequest{RequesterAddress: "0xbbb"}); !errors.Is(err, service.ErrChallengeForbidden) {
+		t.Errorf("Expected only the requester to cancel, got %v", err)
+	}
+	cancelled, err := gameService.CancelChallenge(first.Challenge.ID, &request.CancelChallengeRequest{RequesterAddress: "0xaaa"})
+	if err != nil || cancelled.Challenge.Status != "cancelled" {
+		t.Fatalf("Expected challenge to be cancelled, got %+v, %v", cancelled, err)
+	}
+
+	expired, err := gameService.ExpireChallenges(context.Background(), time.Now().Add(30*time.Second))
+	if err != nil || expired != 0 {
+		t.Errorf("Expected nothing to expire yet, got %d, %v", expired, err)
+	}
+	expired, err = gameService.ExpireChallenges(context.Background(), time.Now().Add(2*time.Minute))
+	if err != nil || expired != 1 {
+		t.Errorf("Expected 1 expired challenge, got %d, %v", expired, err)
+	}
+
+	if _, err := gameService.AcceptChallenge(second.Challenge.ID, &request.AcceptChallengeRequest{
+		AccepterAddress: "0xbbb",
+		AccepterCoinID:  "0xcoin_b",
+	}); !errors.Is(err, service.ErrChallengeNotOpen) {
+		t.Errorf("Expected expired challenge to be closed, got %v", err)
+	}
+	if _, err := gameService.CancelChallenge(primitive.NewObjectID().Hex(), &request.CancelChallengeRequest{RequesterAddress: "0xaaa"}); !errors.Is(err, service.ErrChallengeNotFound) {
+		t.Errorf("Expected unknown challenge to be not found, got %v", err)
+	}
+}
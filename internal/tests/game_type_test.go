@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func golfRules() *request.GameTypeRequest {
+	return &request.GameTypeRequest{
+		ScoreDirection: models.ScoreDirectionLower,
+		TieHandling:    models.TieHandlingReject,
+		MinStake:       10,
+		MaxStake:       1000,
+		MaxScore:       200,
+	}
+}
+
+func TestGameTypes_StakeRules(t *testing.T) {
+	gameService, _, mockMongoClient, _ := newReadinessService(t)
+
+	invalid := []*request.GameTypeRequest{
+		{ScoreDirection: "sideways"},
+		{TieHandling: "coin_flip"},
+		{MinStake: 500, MaxStake: 100},
+	}
+	for _, rules := range invalid {
+		if _, err := gameService.UpsertGameType("golf", rules, "ops"); !errors.Is(err, service.ErrInvalidGameType) {
+			t.Errorf("Expected %+v to be refused, got %v", rules, err)
+		}
+	}
+	if _, err := gameService.UpsertGameType("mini golf", golfRules(), "ops"); !errors.Is(err, service.ErrInvalidGameType) {
+		t.Errorf("Expected a name with a space to be refused, got %v", err)
+	}
+	saved, err := gameService.UpsertGameType("Golf", golfRules(), "ops")
+	if err != nil || saved.GameType.Name != "golf" || saved.GameType.UpdatedBy != "ops" {
+		t.Fatalf("Expected golf saved, got %+v, %v", saved, err)
+	}
+
+	req := stakeRequest()
+	req.GameType = "darts"
+	resp, err := gameService.StakeGame(req)
+	if !errors.Is(err, service.ErrUnknownGameType) || resp.Code != service.StakeCodeUnknownGameType {
+		t.Errorf("Expected an unknown game type to be refused, got %+v, %v", resp, err)
+	}
+
+	req = stakeRequest()
+	req.GameType = "golf"
+	req.StakeAmount = 5
+	resp, err = gameService.StakeGame(req)
+	if !errors.Is(err, service.ErrGameRuleViolation) || resp.Code != service.StakeCodeStakeOutOfRange {
+		t.Errorf("Expected a stake under the minimum to be refused, got %+v, %v", resp, err)
+	}
+
+	req = stakeRequest()
+	req.GameType = "GOLF"
+	resp, err = gameService.StakeGame(req)
+	if err != nil {
+		t.Fatalf("Expected the stake to go through, got %v", err)
+	}
+	raw, _ := mockMongoClient.GetGame(context.Background(), resp.GameID)
+	if game, _ := raw.(data.Game); game.GameType != "golf" {
+		t.Errorf("Expected the game recorded as golf, got %q", game.GameType)
+	}
+
+	if _, err := gameService.CreateTournament(&request.CreateTournamentRequest{
+		Name: "Open", Format: models.TournamentFormatRoundRobin, EntryStake: 5000, Capacity: 4, GameType: "golf",
+	}, "ops"); !errors.Is(err, service.ErrGameRuleViolation) {
+		t.Errorf("Expected a tournament above the golf stake range to be refused, got %v", err)
+	}
+}
+
+func TestGameTypes_LowerScoreWins(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	gameService.UpsertGameType("golf", golfRules(), "ops")
+	gameService.UpsertGameType("chess", &request.GameTypeRequest{}, "ops")
+
+	var chainScores [2]uint64
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		chainScores = [2]uint64{requesterScore, accepterScore}
+		return "payout-digest", nil
+	}
+
+	gameID := stakedGame(t, mockMongoClient, "golf", clock.Now())
+	if _, err := gameService.PayWinner(payout(gameID, 70, 70)); !errors.Is(err, service.ErrGameRuleViolation) {
+		t.Errorf("Expected a golf tie to be refused, got %v", err)
+	}
+	mismatched := payout(gameID, 72, 68)
+	mismatched.GameType = "chess"
+	if _, err := gameService.PayWinner(mismatched); !errors.Is(err, service.ErrGameRuleViolation) {
+		t.Errorf("Expected a payout naming another game type to be refused, got %v", err)
+	}
+	if resp, err := gameService.PayWinner(payout(gameID, 250, 68)); !errors.Is(err, service.ErrPayoutHeld) || resp.Violations[0] != service.PayoutViolationScore {
+		t.Errorf("Expected a score over the golf maximum to be held, got %+v, %v", resp, err)
+	}
+
+	gameID = stakedGame(t, mockMongoClient, "golf", clock.Now())
+	if _, err := gameService.PayWinner(payout(gameID, 72, 68)); err != nil {
+		t.Fatalf("Expected the payout to be sent, got %v", err)
+	}
+	if chainScores != [2]uint64{68, 72} {
+		t.Errorf("Expected the contract to be sent swapped scores, got %v", chainScores)
+	}
+	raw, _ := mockMongoClient.GetGame(context.Background(), gameID)
+	game, _ := raw.(data.Game)
+	if game.Winner != "0xbbb" || *game.RequesterScore != 72 || *game.AccepterScore != 68 {
+		t.Errorf("Expected 0xbbb to win with the real scores recorded, got %+v", game)
+	}
+
+	golf, err := gameService.GetLeaderboard(&request.LeaderboardQuery{GameType: "golf"})
+	if err != nil || golf.Total != 2 || golf.Entries[0].Address != "0xbbb" || golf.Entries[0].Wins != 1 {
+		t.Errorf("Expected 0xbbb to top the golf leaderboard, got %+v, %v", golf, err)
+	}
+	overall, _ := gameService.GetLeaderboard(&request.LeaderboardQuery{})
+	if overall.Total != 2 || overall.GameType != "" {
+		t.Errorf("Expected the overall leaderboard to count the game too, got %+v", overall)
+	}
+	if chess, _ := gameService.GetLeaderboard(&request.LeaderboardQuery{GameType: "chess"}); chess.Total != 0 {
+		t.Errorf("Expected an empty chess leaderboard, got %+v", chess)
+	}
+}
+
+func TestGameTypes_QueueAndChallengesKeepTheType(t *testing.T) {
+	gameService, _, mockMongoClient, _ := newReadinessService(t)
+	gameService.UpsertGameType("golf", golfRules(), "ops")
+	gameType := func(gameID string) string {
+		raw, _ := mockMongoClient.GetGame(context.Background(), gameID)
+		game, _ := raw.(data.Game)
+		return game.GameType
+	}
+
+	if _, err := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xaaa", CoinID: "0xcoin_a", StakeAmount: 5000, GameType: "golf"}); !errors.Is(err, service.ErrGameRuleViolation) {
+		t.Errorf("Expected a ticket outside golf's stake range to be refused, got %v", err)
+	}
+	if _, err := gameService.CreateChallenge(&request.CreateChallengeRequest{RequesterAddress: "0xddd", RequesterCoinID: "0xcoin_d", StakeAmount: 100, GameType: "darts"}); !errors.Is(err, service.ErrUnknownGameType) {
+		t.Errorf("Expected a challenge for an unknown game type to be refused, got %v", err)
+	}
+
+	golf, _ := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xaaa", CoinID: "0xcoin_a", StakeAmount: 100, GameType: "Golf"})
+	gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xbbb", CoinID: "0xcoin_b", StakeAmount: 100})
+	if n, _ := gameService.MatchQueue(context.Background()); n != 0 {
+		t.Fatalf("Expected a golf ticket never to be paired with an untyped one, got %d matches", n)
+	}
+	gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xccc", CoinID: "0xcoin_c", StakeAmount: 100, GameType: "golf"})
+	if n, _ := gameService.MatchQueue(context.Background()); n != 1 {
+		t.Fatalf("Expected the two golf tickets to be paired, got %d matches", n)
+	}
+	ticket, _ := gameService.GetQueueTicket(golf.Ticket.ID.Hex())
+	if ticket.Ticket.Opponent != "0xccc" || gameType(ticket.Ticket.GameID) != "golf" {
+		t.Errorf("Expected a golf game against 0xccc, got %+v staked as %q", ticket.Ticket, gameType(ticket.Ticket.GameID))
+	}
+
+	created, err := gameService.CreateChallenge(&request.CreateChallengeRequest{RequesterAddress: "0xddd", RequesterCoinID: "0xcoin_d", StakeAmount: 100, GameType: "golf"})
+	if err != nil || created.Challenge.GameType != "golf" {
+		t.Fatalf("Expected a golf challenge, got %+v, %v", created, err)
+	}
+	if _, err := gameService.AcceptChallenge(created.Challenge.ID, &request.AcceptChallengeRequest{AccepterAddress: "0xeee", AccepterCoinID: "0xcoin_e"}); err != nil {
+		t.Fatalf("Expected no error accepting, got %v", err)
+	}
+	if got := gameType(created.Challenge.ID); got != "golf" {
+		t.Errorf("Expected the accepted challenge to be staked as golf, got %q", got)
+	}
+}
+
+func TestGameTypes_Routes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret"})
+
+	if w := adminRequest(router, "PUT", "/admin/game-types/golf", `{"score_direction":"sideways"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid direction, got %d", w.Code)
+	}
+	if w := adminRequest(router, "PUT", "/admin/game-types/golf", `{"score_direction":"lower","max_score":200}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected golf saved, got %d %s", w.Code, w.Body.String())
+	}
+
+	var listed response.GameTypeListResponse
+	w := adminRequest(router, "GET", "/api/v1/game-types", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &listed) != nil || listed.Count != 1 || listed.GameTypes[0].TieHandling != models.TieHandlingDraw {
+		t.Errorf("Expected golf listed with ties as draws, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := adminRequest(router, "DELETE", "/admin/game-types/golf", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected golf deleted, got %d", w.Code)
+	}
+	if w := adminRequest(router, "DELETE", "/admin/game-types/golf", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 deleting it again, got %d", w.Code)
+	}
+}
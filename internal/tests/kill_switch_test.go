@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func pauseRequest(paused bool, reason string) *request.KillSwitchRequest {
+	return &request.KillSwitchRequest{Paused: &paused, Reason: reason}
+}
+
+func TestGameService_KillSwitch_BlocksStakesAndPayouts(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	if _, err := gameService.SetKillSwitch(pauseRequest(true, ""), "ops"); err != service.ErrInvalidAdminRequest {
+		t.Errorf("Expected pausing without a reason to be rejected, got %v", err)
+	}
+	resp, err := gameService.SetKillSwitch(pauseRequest(true, "contract upgrade"), "ops")
+	if err != nil || !resp.KillSwitch.Paused || resp.KillSwitch.UpdatedBy != "ops" || resp.KillSwitch.Source != models.KillSwitchSourceAdmin {
+		t.Fatalf("Expected the switch to engage, got %+v, %v", resp, err)
+	}
+
+	stake, err := gameService.StakeGame(stakeRequest())
+	if err != service.ErrPaused || stake.Error != "staking and payouts are paused: contract upgrade" {
+		t.Errorf("Expected the stake to be refused with the reason, got %+v, %v", stake, err)
+	}
+	if _, err := gameService.PayWinner(&request.PayWinnerRequest{RequesterAddress: "0xaaa", AccepterAddress: "0xbbb", RequesterScore: 1, StakeAmount: 100}); err != service.ErrPaused {
+		t.Errorf("Expected the payout to be refused, got %v", err)
+	}
+	if mockSuiClient.GetTransactionCount() != 0 {
+		t.Errorf("Expected nothing submitted on chain while paused, got %d", mockSuiClient.GetTransactionCount())
+	}
+
+	if _, err := gameService.SetKillSwitch(pauseRequest(false, ""), "ops"); err != nil {
+		t.Fatalf("Expected the switch to release, got %v", err)
+	}
+	if _, err := gameService.StakeGame(stakeRequest()); err != nil {
+		t.Errorf("Expected stakes to resume, got %v", err)
+	}
+}
+
+func TestGameService_KillSwitch_OtherReplicasWithinTTL(t *testing.T) {
+	mockMongoClient := mocks.NewMockMongoClient()
+	admin := service.NewGameService(mocks.NewMockSuiClient(), mockMongoClient)
+	replica := service.NewGameService(mocks.NewMockSuiClient(), mockMongoClient)
+	clock := newFakeClock()
+	replica.ConfigureMatchmaking(testMatchmakingConfig(), clock)
+	replica.ConfigureKillSwitch(service.KillSwitchConfig{CacheTTL: 5 * time.Second})
+
+	if replica.KillSwitch().Paused {
+		t.Fatal("Expected the switch to start released")
+	}
+	admin.SetKillSwitch(pauseRequest(true, "wallet drained"), "ops")
+
+	if replica.KillSwitch().Paused {
+		t.Error("Expected the replica to keep its cached state within the TTL")
+	}
+	clock.Advance(5 * time.Second)
+	if state := replica.KillSwitch(); !state.Paused || state.Reason != "wallet drained" {
+		t.Errorf("Expected the replica to see the pause after the TTL, got %+v", state)
+	}
+}
+
+func TestGameService_KillSwitch_FailsClosedUntilLoaded(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigureKillSwitch(service.KillSwitchConfig{CacheTTL: 5 * time.Second})
+	flags := mockMongoClient.GetDatabase("jollfi_games").Collection("system_flags").(*mocks.MockCollection)
+	submitted := 0
+	mockSuiClient.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		submitted++
+		return "digest", nil
+	}
+
+	flags.FailFinds(context.DeadlineExceeded)
+	if !gameService.KillSwitch().Paused {
+		t.Error("Expected a flag that never loaded to read as paused")
+	}
+	if _, err := gameService.StakeGame(stakeRequest()); !errors.Is(err, service.ErrPaused) || submitted != 0 {
+		t.Errorf("Expected stakes to be refused until the flag loads, got %v with %d submitted", err, submitted)
+	}
+
+	flags.FailFinds(nil)
+	if gameService.KillSwitch().Paused {
+		t.Fatal("Expected the released flag once Mongo answers")
+	}
+	flags.FailFinds(context.DeadlineExceeded)
+	clock.Advance(10 * time.Second)
+	if gameService.KillSwitch().Paused {
+		t.Error("Expected a failed refresh to keep the last loaded state")
+	}
+}
+
+func TestGameService_CheckGasBalance_AutoEngages(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	gameService := service.NewGameService(mockSuiClient, mocks.NewMockMongoClient())
+	gameService.ConfigureKillSwitch(service.KillSwitchConfig{MinGasBalance: 500000})
+	ctx := context.Background()
+
+	if engaged, err := gameService.CheckGasBalance(ctx); engaged || err != nil {
+		t.Fatalf("Expected a funded wallet not to pause, got %v, %v", engaged, err)
+	}
+
+	mockSuiClient.SetBalance(1000)
+	engaged, err := gameService.CheckGasBalance(ctx)
+	if !engaged || err != nil {
+		t.Fatalf("Expected a low balance to pause, got %v, %v", engaged, err)
+	}
+	state := gameService.KillSwitch()
+	if !state.Paused || state.Source != models.KillSwitchSourceGasMonitor || state.Reason == "" {
+		t.Errorf("Expected a gas monitor pause with a reason, got %+v", state)
+	}
+
+	mockSuiClient.SetBalance(1000000)
+	if engaged, _ := gameService.CheckGasBalance(ctx); engaged || !gameService.KillSwitch().Paused {
+		t.Error("Expected the switch to stay engaged until an admin releases it")
+	}
+}
+
+func TestKillSwitchRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	gameService := service.NewGameService(mocks.NewMockSuiClient(), mocks.NewMockMongoClient())
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret", KillSwitchRetryAfter: 120})
+
+	stake := func() *httptest.ResponseRecorder {
+		body := `{"requester_coin_id":"0xcoin_a","accepter_coin_id":"0xcoin_b","requester_address":"0x1234567890abcdef1234567890abcdef12345678","accepter_address":"0xabcdef1234567890abcdef1234567890abcdef12","stake_amount":100}`
+		req, _ := http.NewRequest("POST", "/api/v1/games/stake", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := adminRequest(router, "POST", "/admin/kill-switch", `{"paused":true}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 pausing without a reason, got %d", w.Code)
+	}
+	if w := adminRequest(router, "POST", "/admin/kill-switch", `{"paused":true,"reason":"incident 42"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 pausing, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := stake()
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "120" {
+		t.Errorf("Expected 503 with Retry-After 120, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	read := httptest.NewRecorder()
+	router.ServeHTTP(read, httptest.NewRequest("GET", "/api/v1/games/history/0x1234567890abcdef1234567890abcdef12345678", nil))
+	if read.Code != http.StatusOK {
+		t.Errorf("Expected reads to keep working while paused, got %d", read.Code)
+	}
+	if w := adminRequest(router, "GET", "/admin/kill-switch", ""); w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"reason":"incident 42"`)) {
+		t.Errorf("Expected the pause reason, got %d %s", w.Code, w.Body.String())
+	}
+
+	adminRequest(router, "POST", "/admin/kill-switch", `{"paused":false}`)
+	if w := stake(); w.Code != http.StatusOK {
+		t.Errorf("Expected stakes to resume, got %d: %s", w.Code, w.Body.String())
+	}
+
+	audit, _ := gameService.ListAdminAuditLog(&request.AdminAuditQuery{Action: "POST /admin/kill-switch"})
+	if audit.Count != 3 {
+		t.Errorf("Expected every toggle to be audited, got %d", audit.Count)
+	}
+}
@@ -0,0 +1,285 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/events"
+	"jollfi-gaming-api/internal/live"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+const liveTestSecret = "live-test-secret"
+
+func liveToken(t *testing.T, role, subject string) string {
+	token, err := middleware.SignToken(liveTestSecret, &middleware.Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error signing token, got %v", err)
+	}
+	return token
+}
+
+// tokenRequest sends a JSON request with a bearer token, or none if token is
+// empty.
+func tokenRequest(router *gin.Engine, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// nextLiveMessage returns the next message of the given type, skipping
+// presence and other frames.
+func nextLiveMessage(t *testing.T, client *live.Client, messageType string) live.Message {
+	t.Helper()
+	for {
+		select {
+		case msg := <-client.C:
+			if msg.Type == messageType {
+				return msg
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected a %s message", messageType)
+		}
+	}
+}
+
+func TestLiveHub_RoomLimitAndPresence(t *testing.T) {
+	hub := live.NewHub(2)
+	player, err := hub.Join("game1", live.RolePlayer, "0xaaa")
+	if err != nil {
+		t.Fatalf("Expected no error joining, got %v", err)
+	}
+	spectator, _ := hub.Join("game1", live.RoleSpectator, "")
+	if _, err := hub.Join("game1", live.RoleSpectator, ""); err != live.ErrRoomFull {
+		t.Errorf("Expected the third client to be refused, got %v", err)
+	}
+	if _, err := hub.Join("game2", live.RoleSpectator, ""); err != nil {
+		t.Errorf("Expected the limit to be per game, got %v", err)
+	}
+
+	presence := nextLiveMessage(t, player, live.MessagePresence)
+	presence = nextLiveMessage(t, player, live.MessagePresence)
+	if presence.Data[live.RolePlayer] != 1 || presence.Data[live.RoleSpectator] != 1 {
+		t.Errorf("Expected one player and one spectator, got %+v", presence.Data)
+	}
+
+	spectator.Close()
+	spectator.Close()
+	if presence := nextLiveMessage(t, player, live.MessagePresence); presence.Data[live.RoleSpectator] != 0 {
+		t.Errorf("Expected the spectator to be gone, got %+v", presence.Data)
+	}
+	if hub.Connections("game1") != 1 {
+		t.Errorf("Expected 1 connection left, got %d", hub.Connections("game1"))
+	}
+}
+
+func TestLiveHub_RoutesEventsByGame(t *testing.T) {
+	hub := live.NewHub(0)
+	watcher, _ := hub.Join("game1", live.RoleSpectator, "")
+	defer watcher.Close()
+	nextLiveMessage(t, watcher, live.MessagePresence)
+
+	hub.Publish(events.Event{Type: events.GameSettled, GameID: "game2"})
+	hub.Publish(events.Event{Type: events.StakeCompleted})
+	hub.Publish(events.Event{Type: events.GameSettled, GameID: "game1", Data: map[string]interface{}{"winner": "0xaaa"}})
+
+	msg := <-watcher.C
+	if msg.Type != events.GameSettled || msg.Data["winner"] != "0xaaa" {
+		t.Errorf("Expected only game1's settlement, got %+v", msg)
+	}
+
+	hub.Broadcast(live.Message{Type: live.MessageScore, GameID: "game1", Data: map[string]interface{}{"requester_score": 2}})
+	if score := hub.Score("game1"); score["requester_score"] != 2 {
+		t.Errorf("Expected the last score to be remembered, got %+v", score)
+	}
+}
+
+func TestGameService_JoinMatch_ChecksRoleAndStatus(t *testing.T) {
+	gameService := service.NewGameService(mocks.NewMockSuiClient(), mocks.NewMockMongoClient())
+	staked, err := gameService.StakeGame(stakeRequest())
+	if err != nil {
+		t.Fatalf("Expected no error staking, got %v", err)
+	}
+
+	if _, err := gameService.JoinMatch(staked.GameID, live.RolePlayer, "0xccc"); err != service.ErrMatchForbidden {
+		t.Errorf("Expected a non-participant player to be forbidden, got %v", err)
+	}
+	if _, err := gameService.JoinMatch("665f1c2e8b3a4d0012345678", live.RoleSpectator, ""); err != service.ErrMatchNotFound {
+		t.Errorf("Expected an unknown game to be not found, got %v", err)
+	}
+
+	player, err := gameService.JoinMatch(staked.GameID, live.RolePlayer, "0xaaa")
+	if err != nil {
+		t.Fatalf("Expected the requester to join, got %v", err)
+	}
+	defer player.Close()
+	state := nextLiveMessage(t, player, live.MessageState)
+	if state.Data["status"] != service.GameStatusStaked || state.Data["role"] != live.RolePlayer {
+		t.Errorf("Expected the staked state for a player, got %+v", state.Data)
+	}
+
+	update := &request.MatchScoreUpdate{Type: live.MessageScore, RequesterScore: 3, AccepterScore: 1}
+	if err := gameService.PushMatchScore(player, update); err != service.ErrMatchForbidden {
+		t.Errorf("Expected players not to push scores, got %v", err)
+	}
+
+	server, _ := gameService.JoinMatch(staked.GameID, live.RoleGameServer, "")
+	defer server.Close()
+	if err := gameService.PushMatchScore(server, update); err != nil {
+		t.Fatalf("Expected the game server to push scores, got %v", err)
+	}
+	if score := nextLiveMessage(t, player, live.MessageScore); score.Data["requester_score"] != uint64(3) {
+		t.Errorf("Expected the pushed score, got %+v", score.Data)
+	}
+
+	if _, err := gameService.PayWinner(&request.PayWinnerRequest{GameID: staked.GameID, RequesterAddress: "0xaaa", AccepterAddress: "0xbbb", RequesterScore: 3, AccepterScore: 1, StakeAmount: 100}); err != nil {
+		t.Fatalf("Expected no error paying winner, got %v", err)
+	}
+	nextLiveMessage(t, player, events.GameSettled)
+	if err := gameService.PushMatchScore(server, update); err != service.ErrMatchNotLive {
+		t.Errorf("Expected scores to be refused once settled, got %v", err)
+	}
+}
+
+func TestLiveMatchRoute_WebSocket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	gameService := service.NewGameService(mocks.NewMockSuiClient(), mocks.NewMockMongoClient())
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", JWTSecret: liveTestSecret})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	staked, err := gameService.StakeGame(stakeRequest())
+	if err != nil {
+		t.Fatalf("Expected no error staking, got %v", err)
+	}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/games/live/" + staked.GameID
+
+	dial := func(token string) *websocket.Conn {
+		header := http.Header{}
+		if token != "" {
+			header.Set("Authorization", "Bearer "+token)
+		}
+		conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+		if err != nil {
+			t.Fatalf("Expected the connection to upgrade, got %v (%+v)", err, resp)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	read := func(conn *websocket.Conn, messageType string) live.Message {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		for {
+			var msg live.Message
+			if err := conn.ReadJSON(&msg); err != nil {
+				t.Fatalf("Expected a %s frame, got %v", messageType, err)
+			}
+			if msg.Type == messageType {
+				return msg
+			}
+		}
+	}
+
+	spectator := dial("")
+	if state := read(spectator, live.MessageState); state.Data["role"] != live.RoleSpectator {
+		t.Errorf("Expected to join as a spectator, got %+v", state.Data)
+	}
+	spectator.WriteJSON(request.MatchScoreUpdate{Type: live.MessageScore, RequesterScore: 9})
+	if msg := read(spectator, live.MessageError); msg.Data["error"] != service.ErrMatchForbidden.Error() {
+		t.Errorf("Expected spectators to be read-only, got %+v", msg.Data)
+	}
+
+	gameServer := dial(liveToken(t, middleware.RoleGameServer, "arcade-1"))
+	read(gameServer, live.MessageState)
+	gameServer.WriteJSON(request.MatchScoreUpdate{Type: live.MessageScore, RequesterScore: 5, AccepterScore: 2})
+	if score := read(spectator, live.MessageScore); score.Data["requester_score"] != float64(5) || score.Data["accepter_score"] != float64(2) {
+		t.Errorf("Expected the game server's score, got %+v", score.Data)
+	}
+
+	if _, err := gameService.PayWinner(&request.PayWinnerRequest{GameID: staked.GameID, RequesterAddress: "0xaaa", AccepterAddress: "0xbbb", RequesterScore: 5, AccepterScore: 2, StakeAmount: 100}); err != nil {
+		t.Fatalf("Expected no error paying winner, got %v", err)
+	}
+	if settled := read(spectator, events.GameSettled); settled.GameID != staked.GameID {
+		t.Errorf("Expected the settlement for the game, got %+v", settled)
+	}
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Authorization": {"Bearer " + liveToken(t, middleware.RolePlayer, "0xccc")}})
+	if err == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a non-participant player to get 403, got %v", resp)
+	}
+	_, resp, err = websocket.DefaultDialer.Dial(url+"?token=garbage", nil)
+	if err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected an invalid token to get 401, got %v", resp)
+	}
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.JWTMiddleware(liveTestSecret))
+	router.GET("/test", func(c *gin.Context) {
+		claims, ok := middleware.ClaimsFrom(c)
+		if !ok {
+			c.JSON(200, gin.H{"subject": ""})
+			return
+		}
+		c.JSON(200, gin.H{"subject": claims.Subject})
+	})
+
+	expired, _ := middleware.SignToken(liveTestSecret, &middleware.Claims{
+		Role:             middleware.RolePlayer,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "0xaaa", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))},
+	})
+	noExpiry, _ := middleware.SignToken(liveTestSecret, &middleware.Claims{Role: middleware.RolePlayer})
+	otherSecret, _ := middleware.SignToken("other", &middleware.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		body   string
+	}{
+		{"anonymous", "", http.StatusOK, `"subject":""`},
+		{"valid", liveToken(t, middleware.RolePlayer, "0xaaa"), http.StatusOK, `"subject":"0xaaa"`},
+		{"expired", expired, http.StatusUnauthorized, "Invalid token"},
+		{"no expiry", noExpiry, http.StatusUnauthorized, "Invalid token"},
+		{"wrong secret", otherSecret, http.StatusUnauthorized, "Invalid token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/test", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.body) {
+				t.Errorf("Expected %d with %s, got %d %s", tt.status, tt.body, w.Code, w.Body.String())
+			}
+		})
+	}
+}
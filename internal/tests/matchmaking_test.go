@@ -0,0 +1,285 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"jollfi-gaming-api/internal/breaker"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/matchmaking"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/service"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 5, 27, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func testMatchmakingConfig() matchmaking.Config {
+	return matchmaking.Config{
+		InitialWindow: 100,
+		WidenBy:       50,
+		WidenEvery:    10 * time.Second,
+		MaxWindow:     300,
+		TicketTTL:     5 * time.Minute,
+	}
+}
+
+func queueTicket(address string, stake uint64, rating float64, joined time.Time) models.QueueTicket {
+	return models.QueueTicket{
+		ID:          primitive.NewObjectID(),
+		Address:     address,
+		StakeAmount: stake,
+		Rating:      rating,
+		Status:      models.QueueStatusWaiting,
+		JoinedAt:    joined,
+	}
+}
+
+// ticketLive reports whether the stored ticket carries the flag behind the
+// unique index on live tickets.
+func ticketLive(t *testing.T, mongo *mocks.MockMongoClient, id primitive.ObjectID) bool {
+	n, err := mongo.GetDatabase("jollfi_games").Collection("matchmaking_queue").CountDocuments(context.Background(), bson.M{"_id": id, "live": true})
+	if err != nil {
+		t.Fatalf("Expected to count ticket %s, got %v", id.Hex(), err)
+	}
+	return n == 1
+}
+
+func TestMatchmaking_WindowWidensWithWaitTime(t *testing.T) {
+	cfg := testMatchmakingConfig()
+	clock := newFakeClock()
+	ticket := queueTicket("0xaaa", 100, 1500, clock.Now())
+
+	if w := cfg.Window(ticket, clock.Now()); w != 100 {
+		t.Errorf("Expected initial window 100, got %.0f", w)
+	}
+	clock.Advance(25 * time.Second)
+	if w := cfg.Window(ticket, clock.Now()); w != 200 {
+		t.Errorf("Expected window 200 after two widening steps, got %.0f", w)
+	}
+	clock.Advance(time.Minute)
+	if w := cfg.Window(ticket, clock.Now()); w != 300 {
+		t.Errorf("Expected window capped at 300, got %.0f", w)
+	}
+
+	ticket.RatingWindow = 20
+	ticket.JoinedAt = clock.Now()
+	if w := cfg.Window(ticket, clock.Now()); w != 20 {
+		t.Errorf("Expected requested window 20, got %.0f", w)
+	}
+}
+
+func TestMatchmaking_FindMatches(t *testing.T) {
+	cfg := testMatchmakingConfig()
+	clock := newFakeClock()
+	start := clock.Now()
+
+	tickets := []models.QueueTicket{
+		queueTicket("0xaaa", 100, 1500, start),
+		queueTicket("0xbbb", 100, 1700, start.Add(time.Second)),
+		queueTicket("0xccc", 100, 1560, start.Add(2*time.Second)),
+		queueTicket("0xddd", 200, 1500, start.Add(3*time.Second)),
+		queueTicket("0xeee", 100, 1540, start.Add(4*time.Second)),
+	}
+
+	matches := cfg.FindMatches(tickets, clock.Now())
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+	// 0xaaa is paired with the closest rating in its tier, not the earliest
+	if matches[0].First.Address != "0xaaa" || matches[0].Second.Address != "0xeee" {
+		t.Errorf("Expected 0xaaa vs 0xeee, got %s vs %s", matches[0].First.Address, matches[0].Second.Address)
+	}
+
+	// After waiting, 0xbbb's widened window reaches 0xccc
+	clock.Advance(30 * time.Second)
+	remaining := []models.QueueTicket{tickets[1], tickets[2], tickets[3]}
+	matches = cfg.FindMatches(remaining, clock.Now())
+	if len(matches) != 1 || matches[0].First.Address != "0xbbb" || matches[0].Second.Address != "0xccc" {
+		t.Errorf("Expected 0xbbb vs 0xccc after widening, got %+v", matches)
+	}
+
+	// Tickets past their TTL are never matched
+	clock.Advance(10 * time.Minute)
+	if matches := cfg.FindMatches(remaining, clock.Now()); len(matches) != 0 {
+		t.Errorf("Expected expired tickets not to match, got %d", len(matches))
+	}
+}
+
+func TestMatchmaking_FindMatchesSkipsSameAddress(t *testing.T) {
+	cfg := testMatchmakingConfig()
+	now := time.Now()
+
+	tickets := []models.QueueTicket{
+		queueTicket("0xaaa", 100, 1500, now),
+		queueTicket("0xaaa", 100, 1500, now.Add(time.Second)),
+	}
+	if matches := cfg.FindMatches(tickets, now); len(matches) != 0 {
+		t.Errorf("Expected a player never to be matched with themselves, got %d", len(matches))
+	}
+}
+
+func TestGameService_MatchQueue_StakesMatchAfterWidening(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	clock := newFakeClock()
+	gameService.ConfigureMatchmaking(testMatchmakingConfig(), clock)
+
+	var staked []string
+	mockSuiClient.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		staked = append(staked, requesterCoinID, accepterCoinID)
+		return "tx_match", nil
+	}
+
+	users := mockMongoClient.GetDatabase("jollfi_games").Collection("users")
+	users.InsertOne(context.Background(), bson.M{"address": "0xbbb", "rating": bson.M{"rating": 1680.0, "deviation": 80.0, "volatility": 0.06, "games": int64(12)}})
+
+	first, err := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xaaa", CoinID: "0xcoin_a", StakeAmount: 100})
+	if err != nil {
+		t.Fatalf("Expected no error joining, got %v", err)
+	}
+	clock.Advance(5 * time.Second)
+	second, err := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xbbb", CoinID: "0xcoin_b", StakeAmount: 100})
+	if err != nil {
+		t.Fatalf("Expected no error joining, got %v", err)
+	}
+	if second.Ticket.Rating != 1680 {
+		t.Errorf("Expected ticket to carry the player's rating 1680, got %.0f", second.Ticket.Rating)
+	}
+	if _, err := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xaaa", CoinID: "0xcoin_a2", StakeAmount: 100}); !errors.Is(err, service.ErrAlreadyQueued) {
+		t.Errorf("Expected a second ticket for the same address to be rejected, got %v", err)
+	}
+
+	if n, err := gameService.MatchQueue(context.Background()); err != nil || n != 0 {
+		t.Fatalf("Expected no match while 180 apart, got %d, %v", n, err)
+	}
+
+	clock.Advance(20 * time.Second)
+	if n, err := gameService.MatchQueue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("Expected 1 match once windows widened, got %d, %v", n, err)
+	}
+	if len(staked) != 2 || staked[0] != "0xcoin_a" || staked[1] != "0xcoin_b" {
+		t.Errorf("Expected the longer waiting player to stake as requester, got %v", staked)
+	}
+
+	for _, ticketID := range []string{first.Ticket.ID.Hex(), second.Ticket.ID.Hex()} {
+		resp, err := gameService.GetQueueTicket(ticketID)
+		if err != nil {
+			t.Fatalf("Expected no error fetching ticket, got %v", err)
+		}
+		if resp.Ticket.Status != models.QueueStatusMatched || resp.Ticket.GameID == "" || resp.Ticket.TransactionDigest != "tx_match" {
+			t.Errorf("Expected matched ticket with game, got %+v", resp.Ticket)
+		}
+	}
+
+	if n, _ := gameService.MatchQueue(context.Background()); n != 0 {
+		t.Errorf("Expected matched tickets not to be staked again, got %d", n)
+	}
+}
+
+func TestGameService_MatchQueue_StakeFailuresRequeueTheBlameless(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	clock := newFakeClock()
+	gameService.ConfigureMatchmaking(testMatchmakingConfig(), clock)
+
+	stakeErr := fmt.Errorf("failed to get coins for gas: %w", breaker.ErrOpen)
+	mockSuiClient.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		return "", stakeErr
+	}
+	first, _ := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xaaa", CoinID: "0xcoin_a", StakeAmount: 100})
+	second, _ := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xbbb", CoinID: "0xcoin_b", StakeAmount: 100})
+	status := func(ticketID string) string {
+		resp, err := gameService.GetQueueTicket(ticketID)
+		if err != nil {
+			t.Fatalf("Expected no error fetching ticket, got %v", err)
+		}
+		return resp.Ticket.Status
+	}
+
+	if n, _ := gameService.MatchQueue(context.Background()); n != 0 {
+		t.Fatalf("Expected no match staked while the node is down, got %d", n)
+	}
+	if a, b := status(first.Ticket.ID.Hex()), status(second.Ticket.ID.Hex()); a != models.QueueStatusWaiting || b != models.QueueStatusWaiting {
+		t.Errorf("Expected both tickets back in the queue after a transient error, got %s and %s", a, b)
+	}
+
+	gameService.AddBlocklistEntry(&request.BlocklistEntryRequest{Address: "0xbbb", Reason: "fraud"}, "ops")
+	gameService.MatchQueue(context.Background())
+	if a, b := status(first.Ticket.ID.Hex()), status(second.Ticket.ID.Hex()); a != models.QueueStatusWaiting || b != models.QueueStatusFailed {
+		t.Errorf("Expected only the blocked player's ticket to fail, got %s and %s", a, b)
+	}
+	if !ticketLive(t, mockMongoClient, first.Ticket.ID) || ticketLive(t, mockMongoClient, second.Ticket.ID) {
+		t.Errorf("Expected the requeued ticket to stay live and the failed one to be released")
+	}
+
+	third, _ := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xccc", CoinID: "0xcoin_c", StakeAmount: 100})
+	stakeErr = errors.New("transaction failed with status: map[status:failure]")
+	gameService.MatchQueue(context.Background())
+	if a, c := status(first.Ticket.ID.Hex()), status(third.Ticket.ID.Hex()); a != models.QueueStatusFailed || c != models.QueueStatusFailed {
+		t.Errorf("Expected a failure naming neither player to fail both tickets, got %s and %s", a, c)
+	}
+}
+
+func TestGameService_Queue_LeaveAndExpire(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	clock := newFakeClock()
+	gameService.ConfigureMatchmaking(testMatchmakingConfig(), clock)
+
+	leaving, _ := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xaaa", CoinID: "0xcoin_a", StakeAmount: 100})
+	waiting, _ := gameService.JoinQueue(&request.JoinQueueRequest{Address: "0xbbb", CoinID: "0xcoin_b", StakeAmount: 500})
+
+	if _, err := gameService.LeaveQueue(leaving.Ticket.ID.Hex(), &request.LeaveQueueRequest{Address: "0xbbb"}); !errors.Is(err, service.ErrTicketForbidden) {
+		t.Errorf("Expected only the owner to leave, got %v", err)
+	}
+	if resp, err := gameService.LeaveQueue(leaving.Ticket.ID.Hex(), &request.LeaveQueueRequest{Address: "0xaaa"}); err != nil || resp.Ticket.Status != models.QueueStatusCancelled {
+		t.Errorf("Expected ticket to be cancelled, got %+v, %v", resp, err)
+	}
+	if _, err := gameService.LeaveQueue(leaving.Ticket.ID.Hex(), &request.LeaveQueueRequest{Address: "0xaaa"}); !errors.Is(err, service.ErrTicketNotWaiting) {
+		t.Errorf("Expected leaving twice to conflict, got %v", err)
+	}
+
+	clock.Advance(6 * time.Minute)
+	if _, err := gameService.MatchQueue(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp, _ := gameService.GetQueueTicket(waiting.Ticket.ID.Hex())
+	if resp.Ticket.Status != models.QueueStatusExpired {
+		t.Errorf("Expected ticket past its TTL to expire, got %s", resp.Ticket.Status)
+	}
+	if ticketLive(t, mockMongoClient, leaving.Ticket.ID) || ticketLive(t, mockMongoClient, waiting.Ticket.ID) {
+		t.Errorf("Expected cancelled and expired tickets to leave the live index")
+	}
+	if _, err := gameService.GetQueueTicket("not-an-id"); !errors.Is(err, service.ErrTicketNotFound) {
+		t.Errorf("Expected unknown ticket to be not found, got %v", err)
+	}
+}
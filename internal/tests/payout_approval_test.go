@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func TestPayoutApproval_NeedsDistinctAdmins(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	sent := 0
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		sent++
+		return "payout-digest", nil
+	}
+	gameService.ConfigurePayoutApproval(service.PayoutApprovalConfig{Threshold: 100, Required: 2, TTL: time.Hour})
+
+	small, err := mockMongoClient.CreateGame(context.Background(), data.Game{
+		RequesterAddress: "0xaaa",
+		AccepterAddress:  "0xbbb",
+		StakeAmount:      99,
+		Status:           service.GameStatusStaked,
+		CreatedAt:        clock.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got %v", err)
+	}
+	below := payout(small, 1, 0)
+	below.StakeAmount = 99
+	if _, err := gameService.PayWinner(below); err != nil || sent != 1 {
+		t.Fatalf("Expected a stake below the threshold to be paid at once, got %v", err)
+	}
+
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now())
+	understated := payout(gameID, 1, 0)
+	understated.StakeAmount = 99
+	if _, err := gameService.PayWinner(understated); !errors.Is(err, service.ErrPayoutMismatch) || sent != 1 {
+		t.Errorf("Expected a payout understating the stake to be refused, got %v", err)
+	}
+	understated.GameID = ""
+	if _, err := gameService.PayWinner(understated); !errors.Is(err, service.ErrGameNotFound) || sent != 1 {
+		t.Errorf("Expected no game to be found for the understated stake, got %v", err)
+	}
+	resp, err := gameService.PayWinner(payout("", 1, 0))
+	if !errors.Is(err, service.ErrPayoutPendingApproval) || resp.Status != service.PayoutStatusPendingApproval || resp.PendingPayoutID == "" {
+		t.Fatalf("Expected the payout to wait for approval, got %+v, %v", resp, err)
+	}
+	if status := gameStatus(mockMongoClient, gameID); status != service.GameStatusPendingApproval {
+		t.Errorf("Expected the game to be pending approval, got %s", status)
+	}
+
+	if _, err := gameService.ApprovePendingPayout(resp.PendingPayoutID, ""); !errors.Is(err, service.ErrApproverUnverified) {
+		t.Errorf("Expected an anonymous approval to be refused, got %v", err)
+	}
+	first, err := gameService.ApprovePendingPayout(resp.PendingPayoutID, "alice")
+	if err != nil || len(first.Payout.Approvals) != 1 || first.Payout.Approvals[0].Admin != "alice" || sent != 1 {
+		t.Fatalf("Expected one approval recorded and nothing sent, got %+v, %v", first, err)
+	}
+	if _, err := gameService.ApprovePendingPayout(resp.PendingPayoutID, "alice"); !errors.Is(err, service.ErrAlreadyApproved) || sent != 1 {
+		t.Errorf("Expected a second approval by the same admin not to count, got %v", err)
+	}
+
+	clock.Advance(10 * time.Minute)
+	second, err := gameService.ApprovePendingPayout(resp.PendingPayoutID, "bob")
+	if err != nil || second.Payout.Status != service.PayoutStatusApproved || second.Payout.TransactionDigest != "payout-digest" || sent != 2 {
+		t.Fatalf("Expected the payout sent after the second approval, got %+v, %v", second, err)
+	}
+	if approval := second.Payout.Approvals[1]; approval.Admin != "bob" || !approval.ApprovedAt.Equal(clock.Now()) {
+		t.Errorf("Expected who approved and when to be recorded, got %+v", approval)
+	}
+	if status := gameStatus(mockMongoClient, gameID); status != service.GameStatusCompleted {
+		t.Errorf("Expected the game to be completed, got %s", status)
+	}
+	if _, err := gameService.ApprovePendingPayout(resp.PendingPayoutID, "carol"); !errors.Is(err, service.ErrPendingPayoutNotPending) || sent != 2 {
+		t.Errorf("Expected a sent payout not to be sent again, got %v", err)
+	}
+}
+
+func TestPayoutApproval_Expires(t *testing.T) {
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigurePayoutApproval(service.PayoutApprovalConfig{Threshold: 100, Required: 2, TTL: time.Hour})
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now())
+
+	resp, _ := gameService.PayWinner(payout(gameID, 1, 0))
+	gameService.ApprovePendingPayout(resp.PendingPayoutID, "alice")
+	clock.Advance(time.Hour)
+
+	if _, err := gameService.ApprovePendingPayout(resp.PendingPayoutID, "bob"); !errors.Is(err, service.ErrPendingPayoutExpired) {
+		t.Fatalf("Expected an approval after the deadline to be refused, got %v", err)
+	}
+	expired, _ := gameService.ListPendingPayouts(&request.PendingPayoutQuery{Status: service.PayoutStatusExpired})
+	if expired.Count != 1 || len(expired.Payouts[0].Approvals) != 1 {
+		t.Errorf("Expected the payout expired with its approval kept, got %+v", expired.Payouts)
+	}
+	if status := gameStatus(mockMongoClient, gameID); status != service.GameStatusStaked {
+		t.Errorf("Expected the game to be staked again, got %s", status)
+	}
+
+	other := stakedGame(t, mockMongoClient, "", clock.Now())
+	gameService.PayWinner(payout(other, 1, 0))
+	clock.Advance(2 * time.Hour)
+	if n, err := gameService.ExpirePendingPayouts(context.Background(), clock.Now()); err != nil || n != 1 {
+		t.Errorf("Expected the sweep to expire 1 payout, got %d, %v", n, err)
+	}
+}
+
+func TestPayoutApproval_FailedSendCanBeRetried(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigurePayoutApproval(service.PayoutApprovalConfig{Threshold: 100, Required: 2, TTL: time.Hour})
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		return "", errors.New("insufficient gas")
+	}
+
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now().Add(-2*time.Hour))
+	resp, _ := gameService.PayWinner(payout(gameID, 1, 0))
+	gameService.ApprovePendingPayout(resp.PendingPayoutID, "alice")
+	if _, err := gameService.ApprovePendingPayout(resp.PendingPayoutID, "bob"); err == nil {
+		t.Fatalf("Expected the failed transaction to be reported")
+	}
+	if status := gameStatus(mockMongoClient, gameID); status != service.GameStatusPendingApproval {
+		t.Errorf("Expected the game pending approval again, got %s", status)
+	}
+	pending, _ := gameService.ListPendingPayouts(&request.PendingPayoutQuery{})
+	if pending.Count != 1 || !strings.Contains(pending.Payouts[0].LastError, "insufficient gas") {
+		t.Fatalf("Expected the payout still pending with the error, got %+v", pending.Payouts)
+	}
+
+	// The refund sweep cannot take the game while the payout is sent
+	var refunded error
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		_, refunded = gameService.RefundStuckGame(gameID, "ops", &request.RefundGameRequest{})
+		return "payout-digest", nil
+	}
+	retried, err := gameService.ApprovePendingPayout(resp.PendingPayoutID, "alice")
+	if !errors.Is(refunded, service.ErrGameNotRefundable) {
+		t.Errorf("Expected the refund refused mid-payout, got %v", refunded)
+	}
+	if err != nil || retried.Payout.Status != service.PayoutStatusApproved || retried.Payout.LastError != "" {
+		t.Errorf("Expected an approver to retry the send, got %+v, %v", retried, err)
+	}
+}
+
+func TestPayoutApproval_Routes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	gameService.ConfigurePayoutApproval(service.PayoutApprovalConfig{Threshold: 100, Required: 2, TTL: time.Hour})
+	router := routes.SetupRoutes(gameService, &config.Config{
+		Environment: "test",
+		AdminAPIKey: "admin-secret",
+		AdminKeys:   []string{"alice:alice-key", "bob:bob-key"},
+		JWTSecret:   liveTestSecret,
+	})
+	approve := func(key, id string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/admin/payouts/pending/"+id+"/approve", bytes.NewBufferString(""))
+		req.Header.Set("X-Admin-Key", key)
+		req.Header.Set("X-Admin-Actor", "bob")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	recordGame(t, mockMongoClient, "0x1234567890abcdef1234567890abcdef12345678", "0xabcdef1234567890abcdef1234567890abcdef12")
+	body := `{"requester_address":"0x1234567890abcdef1234567890abcdef12345678","accepter_address":"0xabcdef1234567890abcdef1234567890abcdef12","stake_amount":100,"requester_score":50,"accepter_score":1}`
+	w := tokenRequest(router, "POST", "/api/v1/games/pay_winner", liveToken(t, middleware.RoleGameServer, "arena-1"), body)
+	var paid response.PayWinnerResponse
+	if w.Code != http.StatusAccepted || json.Unmarshal(w.Body.Bytes(), &paid) != nil || paid.PendingPayoutID == "" {
+		t.Fatalf("Expected 202 with the pending payout, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := approve("admin-secret", paid.PendingPayoutID); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for the shared key, got %d", w.Code)
+	}
+	if w := approve("alice-key", paid.PendingPayoutID); w.Code != http.StatusOK {
+		t.Errorf("Expected alice's approval to be recorded, got %d %s", w.Code, w.Body.String())
+	}
+	if w := approve("alice-key", paid.PendingPayoutID); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 when alice approves twice, got %d", w.Code)
+	}
+
+	var pending response.PendingPayoutListResponse
+	w = adminRequest(router, "GET", "/admin/payouts/pending", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &pending) != nil || pending.Count != 1 || len(pending.Payouts[0].Approvals) != 1 {
+		t.Errorf("Expected one pending payout with one approval, got %d %s", w.Code, w.Body.String())
+	}
+
+	var sent response.PendingPayoutResponse
+	w = approve("bob-key", paid.PendingPayoutID)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &sent) != nil || sent.Payout.Status != service.PayoutStatusApproved {
+		t.Errorf("Expected bob's approval to send the payout, got %d %s", w.Code, w.Body.String())
+	}
+
+	audit, _ := gameService.ListAdminAuditLog(&request.AdminAuditQuery{Actor: "alice"})
+	if audit.Count != 2 {
+		t.Errorf("Expected alice's requests audited under her key's name, got %+v", audit.Entries)
+	}
+	if w := approve("alice-key", "nope"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown payout, got %d", w.Code)
+	}
+}
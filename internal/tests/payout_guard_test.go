@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func stakedGame(t *testing.T, mongoClient *mocks.MockMongoClient, gameType string, createdAt time.Time) string {
+	gameID, err := mongoClient.CreateGame(context.Background(), data.Game{
+		RequesterAddress: "0xaaa",
+		AccepterAddress:  "0xbbb",
+		StakeAmount:      100,
+		GameType:         gameType,
+		Status:           service.GameStatusStaked,
+		CreatedAt:        createdAt,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got %v", err)
+	}
+	return gameID
+}
+
+// stakedChallenge creates a challenge game between 0xaaa and 0xbbb that was
+// opened at createdAt and accepted and staked at stakedAt.
+func stakedChallenge(t *testing.T, mongoClient *mocks.MockMongoClient, createdAt, stakedAt time.Time) string {
+	gameID, err := mongoClient.CreateGame(context.Background(), data.Game{
+		RequesterAddress: "0xaaa",
+		AccepterAddress:  "0xbbb",
+		StakeAmount:      100,
+		Status:           service.GameStatusStaked,
+		CreatedAt:        createdAt,
+		StakedAt:         &stakedAt,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got %v", err)
+	}
+	return gameID
+}
+
+func gameStatus(mongoClient *mocks.MockMongoClient, gameID string) string {
+	raw, _ := mongoClient.GetGame(context.Background(), gameID)
+	game, _ := raw.(data.Game)
+	return game.Status
+}
+
+func payout(gameID string, requesterScore, accepterScore uint64) *request.PayWinnerRequest {
+	return &request.PayWinnerRequest{
+		GameID:           gameID,
+		RequesterAddress: "0xaaa",
+		AccepterAddress:  "0xbbb",
+		RequesterScore:   requesterScore,
+		AccepterScore:    accepterScore,
+		StakeAmount:      100,
+		GameServer:       "game_server:arena-1",
+	}
+}
+
+func TestPayoutGuard_HoldsSuspiciousPayouts(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	sent := 0
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		sent++
+		return "payout-digest", nil
+	}
+	gameService.ConfigurePayoutGuard(service.PayoutGuardConfig{
+		MaxScore:        100,
+		MaxScores:       map[string]uint64{"chess": 1},
+		MinGameDuration: 5 * time.Minute,
+	})
+	gameService.UpsertGameType("chess", &request.GameTypeRequest{}, "ops")
+	tenMinutesAgo := clock.Now().Add(-10 * time.Minute)
+
+	chess := stakedGame(t, mockMongoClient, "chess", tenMinutesAgo)
+	if _, err := gameService.PayWinner(payout(chess, 1, 0)); err != nil || sent != 1 {
+		t.Fatalf("Expected a plausible payout to be sent, got %v", err)
+	}
+
+	if _, err := gameService.PayWinner(payout("", 1, 0)); !errors.Is(err, service.ErrGameNotFound) || sent != 1 {
+		t.Errorf("Expected a payout without a staked game to be refused, got %v", err)
+	}
+
+	chess = stakedGame(t, mockMongoClient, "chess", tenMinutesAgo)
+	resp, err := gameService.PayWinner(payout(chess, 3, 0))
+	if !errors.Is(err, service.ErrPayoutHeld) || len(resp.Violations) != 1 || resp.Violations[0] != service.PayoutViolationScore {
+		t.Fatalf("Expected an impossible chess score to be held, got %+v, %v", resp, err)
+	}
+	if status := gameStatus(mockMongoClient, chess); status != service.GameStatusHeldForReview {
+		t.Errorf("Expected the game to be held for review, got %s", status)
+	}
+	if _, err := gameService.PayWinner(payout(chess, 1, 0)); !errors.Is(err, service.ErrGameNotStaked) {
+		t.Errorf("Expected a second payout for the held game to be refused, got %v", err)
+	}
+
+	if _, err := gameService.RejectHeldPayout(resp.HeldPayoutID, "ops", &request.ReviewPayoutRequest{}); !errors.Is(err, service.ErrInvalidPayoutReview) {
+		t.Errorf("Expected a rejection without a note to be refused, got %v", err)
+	}
+	held, _ := gameService.ListHeldPayouts(&request.HeldPayoutQuery{})
+	for _, payout := range held.Payouts {
+		if payout.GameID == chess {
+			if _, err := gameService.RejectHeldPayout(payout.ID.Hex(), "ops", &request.ReviewPayoutRequest{Note: "score injected"}); err != nil {
+				t.Fatalf("Expected no error rejecting, got %v", err)
+			}
+		}
+	}
+	if status := gameStatus(mockMongoClient, chess); status != service.GameStatusStaked {
+		t.Errorf("Expected the rejected payout's game to be staked again, got %s", status)
+	}
+	if _, err := gameService.PayWinner(payout(chess, 1, 0)); err != nil || sent != 2 {
+		t.Errorf("Expected the corrected result to be paid, got %v", err)
+	}
+
+	quick := stakedGame(t, mockMongoClient, "", clock.Now().Add(-time.Minute))
+	resp, err = gameService.PayWinner(payout(quick, 50, 40))
+	if !errors.Is(err, service.ErrPayoutHeld) || resp.Violations[0] != service.PayoutViolationDuration {
+		t.Fatalf("Expected a one-minute game to be held, got %+v, %v", resp, err)
+	}
+	approved, err := gameService.ApproveHeldPayout(resp.HeldPayoutID, "ops", &request.ReviewPayoutRequest{Note: "tournament final"})
+	if err != nil || approved.Payout.Status != service.PayoutStatusApproved || approved.Payout.TransactionDigest != "payout-digest" || sent != 3 {
+		t.Fatalf("Expected the approved payout to be sent, got %+v, %v", approved, err)
+	}
+	if status := gameStatus(mockMongoClient, quick); status != service.GameStatusCompleted {
+		t.Errorf("Expected the approved game to be completed, got %s", status)
+	}
+	if _, err := gameService.ApproveHeldPayout(resp.HeldPayoutID, "ops", &request.ReviewPayoutRequest{}); !errors.Is(err, service.ErrHeldPayoutNotPending) {
+		t.Errorf("Expected a second review to be refused, got %v", err)
+	}
+}
+
+func TestPayoutGuard_MinDurationCountsFromTheStake(t *testing.T) {
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigurePayoutGuard(service.PayoutGuardConfig{MinGameDuration: 5 * time.Minute})
+
+	// Opened hours ago but only accepted and staked a minute ago
+	challenge := stakedChallenge(t, mockMongoClient, clock.Now().Add(-3*time.Hour), clock.Now().Add(-time.Minute))
+	resp, err := gameService.PayWinner(payout(challenge, 1, 0))
+	if !errors.Is(err, service.ErrPayoutHeld) || len(resp.Violations) != 1 || resp.Violations[0] != service.PayoutViolationDuration {
+		t.Fatalf("Expected a challenge staked a minute ago to be held, got %+v, %v", resp, err)
+	}
+
+	played := stakedChallenge(t, mockMongoClient, clock.Now().Add(-3*time.Hour), clock.Now().Add(-10*time.Minute))
+	if _, err := gameService.PayWinner(payout(played, 1, 0)); err != nil {
+		t.Errorf("Expected a challenge staked ten minutes ago to be paid, got %v", err)
+	}
+}
+
+func TestPayoutGuard_FailedApprovalIsHeldAgain(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigurePayoutGuard(service.PayoutGuardConfig{MaxScore: 10})
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now())
+
+	resp, _ := gameService.PayWinner(payout(gameID, 11, 0))
+	sending := ""
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		sending = gameStatus(mockMongoClient, gameID)
+		return "", errors.New("insufficient gas")
+	}
+	if _, err := gameService.ApproveHeldPayout(resp.HeldPayoutID, "ops", &request.ReviewPayoutRequest{}); err == nil {
+		t.Fatalf("Expected the failed transaction to be reported")
+	}
+	held, _ := gameService.ListHeldPayouts(&request.HeldPayoutQuery{})
+	if held.Count != 1 || !strings.Contains(held.Payouts[0].LastError, "insufficient gas") {
+		t.Errorf("Expected the payout back in review with the error, got %+v", held.Payouts)
+	}
+	if status := gameStatus(mockMongoClient, gameID); status != service.GameStatusHeldForReview || sending != service.GameStatusSettling {
+		t.Errorf("Expected the game settling while sent and held again after, got %s then %s", sending, status)
+	}
+}
+
+func TestPayoutGuard_DailyCapPerGameServer(t *testing.T) {
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigurePayoutGuard(service.PayoutGuardConfig{DailyCap: 300})
+
+	if _, err := gameService.PayWinner(payout(stakedGame(t, mockMongoClient, "", clock.Now()), 1, 0)); err != nil {
+		t.Fatalf("Expected the first payout within the cap, got %v", err)
+	}
+	resp, err := gameService.PayWinner(payout(stakedGame(t, mockMongoClient, "", clock.Now()), 1, 0))
+	if !errors.Is(err, service.ErrPayoutHeld) || resp.Violations[0] != service.PayoutViolationDailyCap {
+		t.Errorf("Expected the second payout to pass the cap and be held, got %+v, %v", resp, err)
+	}
+	other := payout(stakedGame(t, mockMongoClient, "", clock.Now()), 1, 0)
+	other.GameServer = "game_server:arena-2"
+	if _, err := gameService.PayWinner(other); err != nil {
+		t.Errorf("Expected another game server to have its own cap, got %v", err)
+	}
+}
+
+func TestPayoutGuard_Routes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	gameService.ConfigurePayoutGuard(service.PayoutGuardConfig{MaxScore: 10})
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret", JWTSecret: liveTestSecret})
+
+	recordGame(t, mockMongoClient, "0x1234567890abcdef1234567890abcdef12345678", "0xabcdef1234567890abcdef1234567890abcdef12")
+	body := `{"requester_address":"0x1234567890abcdef1234567890abcdef12345678","accepter_address":"0xabcdef1234567890abcdef1234567890abcdef12","stake_amount":100,"requester_score":50,"accepter_score":1}`
+	req := httptest.NewRequest("POST", "/api/v1/games/pay_winner", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+liveToken(t, middleware.RoleGameServer, "arena-1"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var paid response.PayWinnerResponse
+	if w.Code != http.StatusAccepted || json.Unmarshal(w.Body.Bytes(), &paid) != nil || paid.HeldPayoutID == "" {
+		t.Fatalf("Expected 202 with the held payout, got %d %s", w.Code, w.Body.String())
+	}
+
+	var held response.HeldPayoutListResponse
+	w = adminRequest(router, "GET", "/admin/payouts/held", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &held) != nil || held.Count != 1 || held.Payouts[0].GameServer != "game_server:arena-1" {
+		t.Errorf("Expected the held payout attributed to the game server, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := adminRequest(router, "POST", "/admin/payouts/held/"+paid.HeldPayoutID+"/reject", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a note, got %d", w.Code)
+	}
+	if w := adminRequest(router, "POST", "/admin/payouts/held/"+paid.HeldPayoutID+"/reject", `{"note":"score injected"}`); w.Code != http.StatusOK {
+		t.Errorf("Expected the payout to be rejected, got %d %s", w.Code, w.Body.String())
+	}
+	if w := adminRequest(router, "POST", "/admin/payouts/held/"+paid.HeldPayoutID+"/approve", ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a reviewed payout, got %d", w.Code)
+	}
+	if w := adminRequest(router, "POST", "/admin/payouts/held/nope/approve", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown payout, got %d", w.Code)
+	}
+}
@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+const testPrivateKey = "0101010101010101010101010101010101010101010101010101010101010101"
+
+// fakeSuiNode is a JSON-RPC server answering each method with a canned
+// result, or with an RPC error when the result is an error. It can also be
+// made to fail every request with an HTTP status.
+type fakeSuiNode struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	results map[string]interface{}
+	status  int
+	calls   map[string]int
+}
+
+func newFakeSuiNode(t *testing.T) *fakeSuiNode {
+	node := &fakeSuiNode{results: make(map[string]interface{}), calls: make(map[string]int)}
+	node.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req data.RPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Expected a JSON-RPC request, got %v", err)
+			return
+		}
+
+		node.mu.Lock()
+		node.calls[req.Method]++
+		result, ok := node.results[req.Method]
+		status := node.status
+		node.mu.Unlock()
+
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch {
+		case !ok:
+			resp["error"] = data.RPCError{Code: -32601, Message: "method not found: " + req.Method}
+		default:
+			if err, isErr := result.(error); isErr {
+				resp["error"] = data.RPCError{Code: -32000, Message: err.Error()}
+			} else {
+				resp["result"] = result
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(node.Close)
+	return node
+}
+
+func (n *fakeSuiNode) respond(method string, result interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.results[method] = result
+}
+
+// failWith makes every request answer with the HTTP status; zero restores
+// normal answers.
+func (n *fakeSuiNode) failWith(status int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.status = status
+}
+
+func (n *fakeSuiNode) callCount(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[method]
+}
+
+func poolObject(objectType string, fields map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"objectId": "0xpool",
+			"version":  "48213",
+			"type":     objectType,
+			"content": map[string]interface{}{
+				"dataType": "moveObject",
+				"type":     objectType,
+				"fields":   fields,
+			},
+		},
+	}
+}
+
+func TestDecodeStakePool(t *testing.T) {
+	pool, err := data.DecodeStakePool(poolObject("0x2a::game::StakePool", map[string]interface{}{
+		"balance":     map[string]interface{}{"type": "0x2::balance::Balance<0x2::sui::SUI>", "fields": map[string]interface{}{"value": "1250000000"}},
+		"fee_bps":     float64(1000),
+		"admin":       "0xadmin",
+		"paused":      true,
+		"total_games": "311",
+		"extra":       "kept",
+	}))
+	if err != nil {
+		t.Fatalf("Expected the pool to decode, got %v", err)
+	}
+	if pool.ObjectID != "0xpool" || pool.Version != 48213 || pool.Balance != 1250000000 || pool.FeeBps != 1000 ||
+		pool.Admin != "0xadmin" || !pool.Paused || pool.TotalGames != 311 || pool.Fields["extra"] != "kept" {
+		t.Errorf("Expected every field decoded, got %+v", pool)
+	}
+
+	invalid := []map[string]interface{}{
+		{"error": map[string]interface{}{"code": "notExists"}},
+		{"data": map[string]interface{}{"objectId": "0xpool", "content": map[string]interface{}{"dataType": "package"}}},
+		poolObject("0x2a::game::StakePool", map[string]interface{}{"fee_bps": "-5"}),
+		poolObject("0x2a::game::StakePool", map[string]interface{}{"paused": "no"}),
+	}
+	for i, object := range invalid {
+		if _, err := data.DecodeStakePool(object); err == nil {
+			t.Errorf("Expected case %d to be rejected", i)
+		}
+	}
+
+	if !data.IsModuleType("0x000000000000000000000000000000000000000000000000000000000000002a::game::StakePool<0x2::sui::SUI>", "0x2A", "game") {
+		t.Error("Expected short and long package addresses to match")
+	}
+	if data.IsModuleType("0x2a::other::StakePool", "0x2a", "game") || data.IsModuleType("0x2b::game::StakePool", "0x2a", "game") {
+		t.Error("Expected another module or package not to match")
+	}
+}
+
+func TestSuiClient_ValidatePoolConfig(t *testing.T) {
+	node := newFakeSuiNode(t)
+	client, err := data.NewSuiClient(node.URL, testPrivateKey, &data.Config{PackageID: "0x2a", ModuleName: "game", PoolID: "0xpool"})
+	if err != nil {
+		t.Fatalf("Expected a client, got %v", err)
+	}
+	ctx := context.Background()
+
+	node.respond("sui_getObject", poolObject("0x2a::game::StakePool", map[string]interface{}{"balance": "10", "fee_bps": "1000"}))
+	if err := client.ValidatePoolConfig(ctx); err != nil {
+		t.Errorf("Expected the pool to validate, got %v", err)
+	}
+
+	node.respond("sui_getObject", poolObject("0x2b::game::StakePool", map[string]interface{}{}))
+	if err := client.ValidatePoolConfig(ctx); err == nil || !strings.Contains(err.Error(), "0x2a::game") {
+		t.Errorf("Expected a pool from another package to be rejected, got %v", err)
+	}
+
+	node.respond("sui_getObject", map[string]interface{}{"error": map[string]interface{}{"code": "notExists", "object_id": "0xpool"}})
+	if err := client.ValidatePoolConfig(ctx); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a missing pool to be rejected, got %v", err)
+	}
+
+	unconfigured, _ := data.NewSuiClient(node.URL, testPrivateKey, &data.Config{PackageID: "0x2a", ModuleName: "game"})
+	if err := unconfigured.ValidatePoolConfig(ctx); err == nil {
+		t.Error("Expected a missing pool ID to be rejected")
+	}
+}
+
+func TestPoolRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuiClient := mocks.NewMockSuiClient()
+	gameService := service.NewGameService(mockSuiClient, mocks.NewMockMongoClient())
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test"})
+
+	mockSuiClient.SetCustomResponse("stake_pool", poolObject("0x2a::game::StakePool", map[string]interface{}{"balance": "42", "fee_bps": "250"}))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/pool", nil))
+	var resp response.PoolResponse
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &resp) != nil || resp.Pool.Balance != 42 || resp.Pool.FeeBps != 250 {
+		t.Errorf("Expected the decoded pool, got %d %s", w.Code, w.Body.String())
+	}
+
+	mockSuiClient.SetShouldFail(true)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/pool", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502 when the node fails, got %d", w.Code)
+	}
+}
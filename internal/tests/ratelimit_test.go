@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/ratelimit"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	clock := newFakeClock()
+	store := ratelimit.NewMemoryStoreWithClock(clock.Now)
+	limit := ratelimit.PerMinute(2)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if result, _ := store.Take(ctx, "ip:1.2.3.4", limit); !result.Allowed || result.Remaining != 1-i {
+			t.Fatalf("Expected request %d to be allowed, got %+v", i+1, result)
+		}
+	}
+	denied, _ := store.Take(ctx, "ip:1.2.3.4", limit)
+	if denied.Allowed || denied.RetryAfter != 30*time.Second || denied.Reset != time.Minute {
+		t.Errorf("Expected a denial with a 30s wait for the next token, got %+v", denied)
+	}
+	if other, _ := store.Take(ctx, "ip:5.6.7.8", limit); !other.Allowed {
+		t.Error("Expected another key to have its own budget")
+	}
+
+	clock.Advance(30 * time.Second)
+	if result, _ := store.Take(ctx, "ip:1.2.3.4", limit); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Expected one token to have refilled, got %+v", result)
+	}
+}
+
+func TestMongoStore_SlidingWindowSharedAcrossReplicas(t *testing.T) {
+	clock := newFakeClock()
+	collection := mocks.NewMockMongoClient().GetDatabase("jollfi_games").Collection(ratelimit.MongoCollection)
+	replicaA := ratelimit.NewMongoStoreWithClock(collection, clock.Now)
+	replicaB := ratelimit.NewMongoStoreWithClock(collection, clock.Now)
+	limit := ratelimit.PerMinute(3)
+	ctx := context.Background()
+
+	replicaA.Take(ctx, "writes:ip:1.2.3.4", limit)
+	replicaA.Take(ctx, "writes:ip:1.2.3.4", limit)
+	if result, err := replicaB.Take(ctx, "writes:ip:1.2.3.4", limit); err != nil || !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("Expected the third request to use up the shared budget, got %+v, %v", result, err)
+	}
+	for i := 0; i < 2; i++ {
+		if result, _ := replicaB.Take(ctx, "writes:ip:1.2.3.4", limit); result.Allowed {
+			t.Fatalf("Expected the fourth request to be denied on either replica, got %+v", result)
+		}
+	}
+
+	// Half way into the next window the previous one counts for half: 1.5 + 1
+	clock.Advance(90 * time.Second)
+	if result, _ := replicaA.Take(ctx, "writes:ip:1.2.3.4", limit); !result.Allowed {
+		t.Fatalf("Expected denied requests not to use up budget, got %+v", result)
+	}
+	denied, _ := replicaA.Take(ctx, "writes:ip:1.2.3.4", limit)
+	if denied.Allowed || denied.RetryAfter != 10*time.Second {
+		t.Errorf("Expected a denial until another sixth of the window slides out, got %+v", denied)
+	}
+	clock.Advance(10 * time.Second)
+	if result, _ := replicaB.Take(ctx, "writes:ip:1.2.3.4", limit); !result.Allowed {
+		t.Errorf("Expected a request to fit after Retry-After, got %+v", result)
+	}
+}
+
+func TestRateLimit_RouteGroupsAndHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{Environment: "test", JWTSecret: liveTestSecret, RateLimit: 100, RateLimitWrites: 1}
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigureRateLimit(service.RateLimitBackendMongo)
+	// A second replica sharing the same database
+	otherService := service.NewGameService(mocks.NewMockSuiClient(), mockMongoClient)
+	otherService.ConfigureMatchmaking(testMatchmakingConfig(), clock)
+	otherService.ConfigureRateLimit(service.RateLimitBackendMongo)
+	replicas := []*gin.Engine{routes.SetupRoutes(gameService, cfg), routes.SetupRoutes(otherService, cfg)}
+
+	send := func(replica int, method, path, token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:40000"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		replicas[replica].ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(0, "POST", "/api/v1/challenges", "")
+	if w.Code == http.StatusTooManyRequests || w.Header().Get("RateLimit-Limit") != "1" || w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("Expected the first write to pass with the writes budget, got %d %v", w.Code, w.Header())
+	}
+	w = send(1, "POST", "/api/v1/matchmaking/queue", "")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" || w.Header().Get("RateLimit-Policy") != "1;w=60" {
+		t.Errorf("Expected the other replica to deny the second write, got %d %v", w.Code, w.Header())
+	}
+	if w := send(1, "GET", "/api/v1/leaderboard", ""); w.Code == http.StatusTooManyRequests || w.Header().Get("RateLimit-Limit") != "100" {
+		t.Errorf("Expected reads to count against the default budget, got %d %v", w.Code, w.Header())
+	}
+	if w := send(0, "GET", "/livez", ""); w.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("Expected probes not to be limited, got %v", w.Header())
+	}
+
+	player := liveToken(t, middleware.RolePlayer, "0x1111111111111111111111111111111111111111")
+	if w := send(0, "POST", "/api/v1/challenges", player); w.Code == http.StatusTooManyRequests {
+		t.Error("Expected a signed-in player to have their own budget")
+	}
+	if w := send(1, "POST", "/api/v1/challenges", player); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected the player's budget to be shared across replicas, got %d", w.Code)
+	}
+	forged := liveToken(t, middleware.RolePlayer, "0x2222222222222222222222222222222222222222") + "x"
+	if w := send(0, "POST", "/api/v1/challenges", forged); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected an invalid token to fall back to the IP budget, got %d", w.Code)
+	}
+
+	// With Mongo down each replica counts on its own instead of failing
+	mockMongoClient.SetPingError(errors.New("connection refused"))
+	gameService.CheckDependencies(context.Background())
+	if w := send(0, "POST", "/api/v1/challenges", ""); w.Code == http.StatusTooManyRequests {
+		t.Error("Expected the local fallback to start with a fresh budget")
+	}
+	if w := send(0, "POST", "/api/v1/challenges", ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected the local fallback to enforce the budget, got %d", w.Code)
+	}
+}
+
+func TestRateLimitKey_APIKeyIsHashed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/api/v1/pool", nil)
+	c.Request.RemoteAddr = "10.0.0.1:40000"
+	keyFunc := middleware.RateLimitKey("", "partner-secret", middleware.RateLimitKeyAPIKey, middleware.RateLimitKeyIP)
+
+	if key := keyFunc(c); key != "ip:10.0.0.1" {
+		t.Errorf("Expected the IP without an API key, got %s", key)
+	}
+	c.Request.Header.Set("X-API-Key", "made-up-key")
+	if key := keyFunc(c); key != "ip:10.0.0.1" {
+		t.Errorf("Expected an unknown API key not to get its own budget, got %s", key)
+	}
+	c.Request.Header.Set("X-API-Key", "partner-secret")
+	if key := keyFunc(c); key == "" || key[:8] != "api_key:" || len(key) != len("api_key:")+16 {
+		t.Errorf("Expected a hashed API key, got %s", key)
+	}
+}
+
+func TestRateLimit_ForwardedForNeedsATrustedProxy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	send := func(router *gin.Engine, forwardedFor string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/api/v1/challenges", nil)
+		req.RemoteAddr = "10.0.0.1:40000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	gameService, _, _, _ := newReadinessService(t)
+	direct := routes.SetupRoutes(gameService, &config.Config{Environment: "test", RateLimit: 100, RateLimitWrites: 1})
+	send(direct, "1.1.1.1")
+	if w := send(direct, "2.2.2.2"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected a spoofed X-Forwarded-For not to reset the budget, got %d", w.Code)
+	}
+
+	gameService, _, _, _ = newReadinessService(t)
+	proxied := routes.SetupRoutes(gameService, &config.Config{Environment: "test", RateLimit: 100, RateLimitWrites: 1, TrustedProxies: []string{"10.0.0.0/8"}})
+	send(proxied, "1.1.1.1")
+	if w := send(proxied, "2.2.2.2"); w.Code == http.StatusTooManyRequests {
+		t.Error("Expected clients behind a trusted proxy to have their own budgets")
+	}
+}
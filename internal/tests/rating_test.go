@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"jollfi-gaming-api/internal/rating"
+)
+
+func TestRating_Update_GlickmanExample(t *testing.T) {
+	// Worked example from the Glicko-2 paper
+	player := rating.Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	results := []rating.Result{
+		{Opponent: rating.Rating{Rating: 1400, Deviation: 30, Volatility: 0.06}, Score: rating.Win},
+		{Opponent: rating.Rating{Rating: 1550, Deviation: 100, Volatility: 0.06}, Score: rating.Loss},
+		{Opponent: rating.Rating{Rating: 1700, Deviation: 300, Volatility: 0.06}, Score: rating.Loss},
+	}
+
+	got := rating.Update(player, results)
+
+	if math.Abs(got.Rating-1464.06) > 0.01 {
+		t.Errorf("Expected rating 1464.06, got %.4f", got.Rating)
+	}
+	if math.Abs(got.Deviation-151.52) > 0.01 {
+		t.Errorf("Expected deviation 151.52, got %.4f", got.Deviation)
+	}
+	if math.Abs(got.Volatility-0.05999) > 0.00001 {
+		t.Errorf("Expected volatility 0.05999, got %.6f", got.Volatility)
+	}
+}
+
+func TestRating_Update_DrawBetweenEqualsKeepsRating(t *testing.T) {
+	player := rating.Default()
+
+	got := rating.Update(player, []rating.Result{{Opponent: rating.Default(), Score: rating.Draw}})
+
+	if math.Abs(got.Rating-rating.DefaultRating) > 0.0001 {
+		t.Errorf("Expected rating to stay at %.0f after a draw between equals, got %.4f", rating.DefaultRating, got.Rating)
+	}
+	if got.Deviation >= player.Deviation {
+		t.Errorf("Expected deviation to shrink after a game, got %.4f", got.Deviation)
+	}
+}
+
+func TestRating_Update_NoGamesGrowsDeviation(t *testing.T) {
+	player := rating.Rating{Rating: 1700, Deviation: 50, Volatility: 0.06}
+
+	got := rating.Update(player, nil)
+
+	if got.Rating != player.Rating {
+		t.Errorf("Expected rating unchanged, got %.4f", got.Rating)
+	}
+	if got.Deviation <= player.Deviation {
+		t.Errorf("Expected deviation to grow while inactive, got %.4f", got.Deviation)
+	}
+}
+
+func TestRating_ExpectedScore(t *testing.T) {
+	strong := rating.Rating{Rating: 1800, Deviation: 50, Volatility: 0.06}
+	weak := rating.Rating{Rating: 1400, Deviation: 50, Volatility: 0.06}
+
+	if e := rating.ExpectedScore(rating.Default(), rating.Default()); math.Abs(e-0.5) > 0.0001 {
+		t.Errorf("Expected 0.5 between equal players, got %.4f", e)
+	}
+	if e := rating.ExpectedScore(strong, weak); e <= 0.8 {
+		t.Errorf("Expected strong player to be a clear favourite, got %.4f", e)
+	}
+	if sum := rating.ExpectedScore(strong, weak) + rating.ExpectedScore(weak, strong); math.Abs(sum-1) > 0.0001 {
+		t.Errorf("Expected complementary scores to sum to 1, got %.4f", sum)
+	}
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func newReadinessService(t *testing.T) (*service.GameService, *mocks.MockSuiClient, *mocks.MockMongoClient, *fakeClock) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	clock := newFakeClock()
+	gameService.ConfigureMatchmaking(testMatchmakingConfig(), clock)
+	gameService.ConfigureReadiness(service.ReadinessConfig{
+		CacheTTL:         2 * time.Second,
+		Timeout:          time.Second,
+		MaxCheckpointAge: time.Minute,
+	})
+	mockSuiClient.LatestCheckpointFunc = func(ctx context.Context) (*models.Checkpoint, error) {
+		return &models.Checkpoint{SequenceNumber: 1000, Timestamp: clock.Now().Add(-5 * time.Second)}, nil
+	}
+	return gameService, mockSuiClient, mockMongoClient, clock
+}
+
+func TestGameService_Readiness_ChecksEachDependency(t *testing.T) {
+	gameService, mockSuiClient, _, clock := newReadinessService(t)
+
+	ready := gameService.Readiness()
+	if !ready.Success || ready.Status != service.StatusReady || len(ready.Checks) != 4 {
+		t.Fatalf("Expected all four checks to pass, got %+v", ready)
+	}
+	for name, check := range ready.Checks {
+		if check.Status != service.DependencyOK || check.LatencyMs < 0 {
+			t.Errorf("Expected %s to be ok with a latency, got %+v", name, check)
+		}
+	}
+	if ready.Checks["sui"].Detail["checkpoint"] != uint64(1000) {
+		t.Errorf("Expected the checkpoint in the detail, got %+v", ready.Checks["sui"])
+	}
+
+	mockSuiClient.SetShouldFail(true)
+	if cached := gameService.Readiness(); cached != ready {
+		t.Error("Expected the result to be cached within the TTL")
+	}
+
+	clock.Advance(2 * time.Second)
+	notReady := gameService.Readiness()
+	if notReady.Success || notReady.Status != service.StatusNotReady {
+		t.Fatalf("Expected not ready once the chain fails, got %+v", notReady)
+	}
+	if notReady.Checks["mongo"].Status != service.DependencyOK {
+		t.Errorf("Expected Mongo to stay ok, got %+v", notReady.Checks["mongo"])
+	}
+	for _, name := range []string{"gas", "pool"} {
+		if check := notReady.Checks[name]; check.Status != service.DependencyFail || check.Error == "" {
+			t.Errorf("Expected %s to fail with an error, got %+v", name, check)
+		}
+	}
+}
+
+func TestGameService_Readiness_StaleCheckpointAndLowGas(t *testing.T) {
+	gameService, mockSuiClient, _, clock := newReadinessService(t)
+	gameService.ConfigureReadiness(service.ReadinessConfig{
+		Timeout:          time.Second,
+		MaxCheckpointAge: time.Minute,
+		MinGasBalance:    2000000,
+	})
+	mockSuiClient.LatestCheckpointFunc = func(ctx context.Context) (*models.Checkpoint, error) {
+		return &models.Checkpoint{SequenceNumber: 990, Timestamp: clock.Now().Add(-2 * time.Minute)}, nil
+	}
+
+	result := gameService.Readiness()
+	if result.Success {
+		t.Fatalf("Expected not ready, got %+v", result)
+	}
+	if sui := result.Checks["sui"]; sui.Status != service.DependencyFail || sui.Detail["age_seconds"] != float64(120) {
+		t.Errorf("Expected a stale checkpoint to fail with its age, got %+v", sui)
+	}
+	if gas := result.Checks["gas"]; gas.Status != service.DependencyFail || gas.Detail["balance"] != uint64(1000000) {
+		t.Errorf("Expected the low balance to fail, got %+v", gas)
+	}
+	if pool := result.Checks["pool"]; pool.Status != service.DependencyOK {
+		t.Errorf("Expected the pool to be reachable, got %+v", pool)
+	}
+}
+
+func TestProbeRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test"})
+
+	probe := func(path string) (int, response.ReadinessResponse) {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var body response.ReadinessResponse
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+
+	if code, _ := probe("/livez"); code != http.StatusOK {
+		t.Errorf("Expected /livez to be 200, got %d", code)
+	}
+	if code, body := probe("/readyz"); code != http.StatusOK || body.Status != service.StatusReady {
+		t.Errorf("Expected /readyz to be 200 and ready, got %d %+v", code, body)
+	}
+
+	mockMongoClient.Close()
+	clock.Advance(2 * time.Second)
+	code, body := probe("/readyz")
+	if code != http.StatusServiceUnavailable || body.Checks["mongo"].Status != service.DependencyFail {
+		t.Errorf("Expected /readyz to be 503 with Mongo failing, got %d %+v", code, body)
+	}
+	if code, _ := probe("/livez"); code != http.StatusOK {
+		t.Errorf("Expected /livez to stay 200 while Mongo is down, got %d", code)
+	}
+}
+
+func TestSuiClient_LatestCheckpoint(t *testing.T) {
+	node := newFakeSuiNode(t)
+	node.respond("sui_getLatestCheckpointSequenceNumber", "183402117")
+	node.respond("sui_getCheckpoint", map[string]interface{}{"sequenceNumber": "183402117", "timestampMs": "1748347200000"})
+	client := newResilientSuiClient(t, node.URL, data.DefaultRPCConfig())
+
+	checkpoint, err := client.LatestCheckpoint(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if checkpoint.SequenceNumber != 183402117 || !checkpoint.Timestamp.Equal(time.UnixMilli(1748347200000)) {
+		t.Errorf("Expected the decoded checkpoint, got %+v", checkpoint)
+	}
+}
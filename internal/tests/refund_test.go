@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/events"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func pendingTransactionTypes(t *testing.T, gameService *service.GameService) []string {
+	pending, err := gameService.ListPendingTransactions()
+	if err != nil {
+		t.Fatalf("Expected pending transactions, got %v", err)
+	}
+	var types []string
+	for _, tx := range pending.Transactions {
+		types = append(types, tx.Type)
+	}
+	return types
+}
+
+func TestRefunds_Draws(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	gameService.UpsertGameType("chess", &request.GameTypeRequest{TieHandling: models.TieHandlingRefund}, "ops")
+	paid, refunded := 0, 0
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		paid++
+		return "payout-digest", nil
+	}
+	mockSuiClient.ExternalRefundFunc = func(requesterAddress, accepterAddress string, stakeAmount uint64, ctx context.Context) (string, error) {
+		refunded++
+		return "refund-digest", nil
+	}
+
+	// Untyped draws go through the contract unless REFUND_DRAWS is on
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now())
+	if resp, err := gameService.PayWinner(payout(gameID, 5, 5)); err != nil || resp.Status == service.GameStatusRefunded || paid != 1 {
+		t.Fatalf("Expected the draw settled by the contract, got %+v, %v", resp, err)
+	}
+
+	gameID = stakedGame(t, mockMongoClient, "chess", clock.Now())
+	resp, err := gameService.PayWinner(payout(gameID, 5, 5))
+	if err != nil || resp.Status != service.GameStatusRefunded || resp.TransactionDigest != "refund-digest" || refunded != 1 || paid != 1 {
+		t.Fatalf("Expected the chess draw refunded, got %+v, %v", resp, err)
+	}
+	raw, _ := mockMongoClient.GetGame(context.Background(), gameID)
+	game, _ := raw.(data.Game)
+	if game.Status != service.GameStatusRefunded || game.RefundReason != service.RefundReasonDraw || game.Winner != "" {
+		t.Errorf("Expected the game refunded as a draw, got %+v", game)
+	}
+	history, _ := gameService.GetGameHistory("0xaaa")
+	if history.Count != 2 || history.Games[0].Refunded == history.Games[1].Refunded {
+		t.Errorf("Expected one refunded draw in game history, got %+v", history.Games)
+	}
+
+	// A decisive chess game is still paid out
+	gameID = stakedGame(t, mockMongoClient, "chess", clock.Now())
+	if _, err := gameService.PayWinner(payout(gameID, 6, 5)); err != nil || paid != 2 {
+		t.Errorf("Expected a chess win paid out, got %v", err)
+	}
+
+	gameService.ConfigureRefunds(service.RefundConfig{Draws: true})
+	gameID = stakedGame(t, mockMongoClient, "", clock.Now())
+	if resp, err := gameService.PayWinner(payout(gameID, 0, 0)); err != nil || resp.TransactionDigest != "refund-digest" || refunded != 2 {
+		t.Fatalf("Expected the untyped draw refunded through the contract, got %+v, %v", resp, err)
+	}
+
+	refunds := 0
+	for _, txType := range pendingTransactionTypes(t, gameService) {
+		if txType == service.TransactionTypeRefund {
+			refunds++
+		}
+	}
+	if refunds != 2 {
+		t.Errorf("Expected 2 refund transactions recorded, got %d", refunds)
+	}
+}
+
+func TestRefunds_StuckGames(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigureRefunds(service.RefundConfig{StuckAfter: time.Hour})
+	var published []string
+	gameService.Events().Subscribe(func(event events.Event) { published = append(published, event.Type) })
+
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now())
+	if _, err := gameService.RefundStuckGame(gameID, "ops", &request.RefundGameRequest{}); !errors.Is(err, service.ErrRefundTooEarly) {
+		t.Errorf("Expected a fresh game to be refused, got %v", err)
+	}
+	challenge := stakedChallenge(t, mockMongoClient, clock.Now().Add(-20*time.Hour), clock.Now().Add(-time.Minute))
+	if _, err := gameService.RefundStuckGame(challenge, "ops", &request.RefundGameRequest{}); !errors.Is(err, service.ErrRefundTooEarly) {
+		t.Errorf("Expected a challenge opened long ago but staked a minute ago to be refused, got %v", err)
+	}
+	if _, err := gameService.RefundStuckGame("000000000000000000000000", "ops", &request.RefundGameRequest{}); !errors.Is(err, service.ErrGameNotFound) {
+		t.Errorf("Expected an unknown game to be refused, got %v", err)
+	}
+
+	clock.Advance(2 * time.Hour)
+	mockSuiClient.ExternalRefundFunc = func(requesterAddress, accepterAddress string, stakeAmount uint64, ctx context.Context) (string, error) {
+		return "", errors.New("pool empty")
+	}
+	if _, err := gameService.RefundStuckGame(gameID, "ops", &request.RefundGameRequest{}); err == nil {
+		t.Fatal("Expected the failed refund to be reported")
+	}
+	if status := gameStatus(mockMongoClient, gameID); status != service.GameStatusStaked {
+		t.Fatalf("Expected the game back in staked after a failed refund, got %s", status)
+	}
+
+	mockSuiClient.ExternalRefundFunc = nil
+	resp, err := gameService.RefundStuckGame(gameID, "ops", &request.RefundGameRequest{Reason: "server crashed"})
+	if err != nil || resp.Game.Status != service.GameStatusRefunded || resp.Game.RefundReason != "server crashed" || resp.Game.TransactionDigest == "" {
+		t.Fatalf("Expected the game refunded, got %+v, %v", resp, err)
+	}
+	if _, err := gameService.RefundStuckGame(gameID, "ops", &request.RefundGameRequest{}); !errors.Is(err, service.ErrGameNotRefundable) {
+		t.Errorf("Expected a refunded game to be refused, got %v", err)
+	}
+	if len(published) != 1 || published[0] != events.GameRefunded {
+		t.Errorf("Expected one game.refunded event, got %v", published)
+	}
+	if types := pendingTransactionTypes(t, gameService); len(types) != 1 || types[0] != service.TransactionTypeRefund {
+		t.Errorf("Expected the refund recorded as its own transaction type, got %v", types)
+	}
+}
+
+func TestRefunds_SettledGamesAreNotPaidAgain(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	paid := 0
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		paid++
+		return "payout-digest", nil
+	}
+
+	refunded := stakedGame(t, mockMongoClient, "", clock.Now().Add(-2*time.Hour))
+	if _, err := gameService.RefundStuckGame(refunded, "ops", &request.RefundGameRequest{}); err != nil {
+		t.Fatalf("Expected the game refunded, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := gameService.PayWinner(payout(refunded, 1, 0)); !errors.Is(err, service.ErrGameNotStaked) {
+			t.Errorf("Expected a payout of a refunded game to be refused, got %v", err)
+		}
+	}
+
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now())
+	understated := payout(gameID, 1, 0)
+	understated.StakeAmount = 1
+	if _, err := gameService.PayWinner(understated); !errors.Is(err, service.ErrPayoutMismatch) {
+		t.Errorf("Expected a payout for another stake to be refused, got %v", err)
+	}
+	stranger := payout(gameID, 1, 0)
+	stranger.AccepterAddress = "0xccc"
+	if _, err := gameService.PayWinner(stranger); !errors.Is(err, service.ErrPayoutMismatch) {
+		t.Errorf("Expected a payout to other players to be refused, got %v", err)
+	}
+	if _, err := gameService.PayWinner(payout(gameID, 1, 0)); err != nil {
+		t.Fatalf("Expected the payout sent, got %v", err)
+	}
+	if _, err := gameService.PayWinner(payout(gameID, 1, 0)); !errors.Is(err, service.ErrGameNotStaked) {
+		t.Errorf("Expected a second payout of the game to be refused, got %v", err)
+	}
+	if paid != 1 || gameStatus(mockMongoClient, refunded) != service.GameStatusRefunded || gameStatus(mockMongoClient, gameID) != service.GameStatusCompleted {
+		t.Errorf("Expected exactly one payout sent, got %d", paid)
+	}
+
+	// A failed payout leaves the game staked for the next attempt
+	failed := stakedGame(t, mockMongoClient, "", clock.Now())
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		return "", errors.New("rpc down")
+	}
+	if _, err := gameService.PayWinner(payout(failed, 1, 0)); err == nil || gameStatus(mockMongoClient, failed) != service.GameStatusStaked {
+		t.Errorf("Expected the game back in staked after a failed payout, got %s, %v", gameStatus(mockMongoClient, failed), err)
+	}
+}
+
+func TestRefunds_Route(t *testing.T) {
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret"})
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now().Add(-2*time.Hour))
+
+	if w := adminRequest(router, "POST", "/admin/games/000000000000000000000000/refund", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown game, got %d", w.Code)
+	}
+	var refunded response.RefundGameResponse
+	w := adminRequest(router, "POST", "/admin/games/"+gameID+"/refund", `{"reason":"abandoned by both players"}`)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &refunded) != nil || refunded.Game.Status != service.GameStatusRefunded {
+		t.Fatalf("Expected the game refunded, got %d %s", w.Code, w.Body.String())
+	}
+	if w := adminRequest(router, "POST", "/admin/games/"+gameID+"/refund", ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 refunding it again, got %d", w.Code)
+	}
+}
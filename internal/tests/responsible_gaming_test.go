@@ -0,0 +1,222 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func limit(n uint64) *uint64 {
+	return &n
+}
+
+func TestResponsibleGaming_LimitsRefuseStakes(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	stakes := mockMongoClient.GetDatabase("jollfi_games").Collection("stakes")
+	payouts := mockMongoClient.GetDatabase("jollfi_games").Collection("pay_winners")
+	ctx := context.Background()
+	submitted := 0
+	mockSuiClient.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		submitted++
+		return "digest", nil
+	}
+	stakes.InsertOne(ctx, models.Stake{RequesterAddress: "0xaaa", AccepterAddress: "0xccc", StakeAmount: 300, Timestamp: clock.Now().Add(-2 * time.Hour).Unix()})
+	stakes.InsertOne(ctx, models.Stake{RequesterAddress: "0xccc", AccepterAddress: "0xaaa", StakeAmount: 400, Timestamp: clock.Now().Add(-3 * 24 * time.Hour).Unix()})
+	stakes.InsertOne(ctx, models.Stake{RequesterAddress: "0xaaa", AccepterAddress: "0xccc", StakeAmount: 500, Timestamp: clock.Now().Add(-40 * 24 * time.Hour).Unix()})
+	payouts.InsertOne(ctx, models.PayWinner{RequesterAddress: "0xaaa", AccepterAddress: "0xccc", Winner: "0xccc", StakeAmount: 300, Timestamp: clock.Now().Add(-2 * time.Hour).Unix()})
+
+	limits, err := gameService.GetStakeLimits("0xaaa")
+	if err != nil || limits.Usage.Daily != 300 || limits.Usage.Weekly != 700 || limits.Usage.Monthly != 700 || limits.Usage.NetLoss != 300 {
+		t.Fatalf("Expected usage of 300/700/700 and a net loss of 300, got %+v, %v", limits.Usage, err)
+	}
+
+	cases := []struct {
+		set  request.SetStakeLimitsRequest
+		code string
+	}{
+		{request.SetStakeLimitsRequest{MaxStake: limit(99)}, service.StakeCodeMaxStake},
+		{request.SetStakeLimitsRequest{MaxStake: limit(0), Daily: limit(399)}, service.StakeCodeDailyLimit},
+		{request.SetStakeLimitsRequest{Daily: limit(400), Weekly: limit(799)}, service.StakeCodeWeeklyLimit},
+		{request.SetStakeLimitsRequest{Weekly: limit(800), Monthly: limit(799)}, service.StakeCodeMonthlyLimit},
+		{request.SetStakeLimitsRequest{Monthly: limit(800), NetLoss: limit(399)}, service.StakeCodeNetLossLimit},
+	}
+	for _, tc := range cases {
+		// Start each case from scratch so loosened limits take effect at once
+		mockMongoClient.GetDatabase("jollfi_games").Collection("users").DeleteOne(ctx, bson.M{"address": "0xaaa"})
+		if _, err := gameService.SetStakeLimits("0xaaa", &tc.set); err != nil {
+			t.Fatalf("Expected no error setting limits, got %v", err)
+		}
+		resp, err := gameService.StakeGame(stakeRequest())
+		if !errors.Is(err, service.ErrStakeRefused) || resp.Code != tc.code {
+			t.Errorf("Expected the stake to be refused with %s, got %+v, %v", tc.code, resp, err)
+		}
+	}
+	if submitted != 0 {
+		t.Errorf("Expected no refused stake to reach the chain, got %d", submitted)
+	}
+
+	mockMongoClient.GetDatabase("jollfi_games").Collection("users").DeleteOne(ctx, bson.M{"address": "0xaaa"})
+	if _, err := gameService.SetStakeLimits("0xaaa", &request.SetStakeLimitsRequest{MaxStake: limit(100), Daily: limit(400), Weekly: limit(800), Monthly: limit(800), NetLoss: limit(400)}); err != nil {
+		t.Fatalf("Expected no error setting limits, got %v", err)
+	}
+	if resp, err := gameService.StakeGame(stakeRequest()); err != nil {
+		t.Errorf("Expected a stake that exactly reaches every limit to go through, got %+v, %v", resp, err)
+	}
+
+	gameService.ConfigureResponsibleGaming(service.ResponsibleGamingConfig{
+		Limits:      models.StakeLimits{MaxStake: 50},
+		LoosenDelay: 24 * time.Hour,
+	})
+	if resp, err := gameService.StakeGame(stakeRequest()); resp.Code != service.StakeCodeMaxStake {
+		t.Errorf("Expected the operator's limit to apply to players without their own, got %+v, %v", resp, err)
+	}
+}
+
+func TestResponsibleGaming_LooseningIsDelayed(t *testing.T) {
+	gameService, _, _, clock := newReadinessService(t)
+
+	if _, err := gameService.SetStakeLimits("0xaaa", &request.SetStakeLimitsRequest{MaxStake: limit(50)}); err != nil {
+		t.Fatalf("Expected no error setting limits, got %v", err)
+	}
+	resp, err := gameService.SetStakeLimits("0xaaa", &request.SetStakeLimitsRequest{MaxStake: limit(0), Daily: limit(1000)})
+	if err != nil || resp.Effective.MaxStake != 50 || resp.Effective.Daily != 1000 || resp.Controls.Pending == nil {
+		t.Fatalf("Expected the new daily limit at once and the removal pending, got %+v, %v", resp, err)
+	}
+	if resp, _ := gameService.StakeGame(stakeRequest()); resp.Code != service.StakeCodeMaxStake {
+		t.Errorf("Expected the old limit to hold until the delay passes, got %+v", resp)
+	}
+
+	clock.Advance(25 * time.Hour)
+	if resp, err := gameService.StakeGame(stakeRequest()); err != nil {
+		t.Errorf("Expected the loosened limit to apply after the delay, got %+v, %v", resp, err)
+	}
+	resp, _ = gameService.GetStakeLimits("0xaaa")
+	if resp.Controls.Pending != nil || resp.Effective.MaxStake != 0 || resp.Effective.Daily != 1000 {
+		t.Errorf("Expected the pending limits to have replaced the old ones, got %+v", resp.Controls)
+	}
+}
+
+func TestResponsibleGaming_Breaks(t *testing.T) {
+	gameService, _, _, clock := newReadinessService(t)
+
+	if _, err := gameService.StartCoolingOff("0xbbb", &request.BreakRequest{Days: 43}); !errors.Is(err, service.ErrInvalidLimits) {
+		t.Errorf("Expected a cooling-off period over 42 days to be rejected, got %v", err)
+	}
+	if _, err := gameService.SelfExclude("0xbbb", &request.BreakRequest{Days: 30}); !errors.Is(err, service.ErrInvalidLimits) {
+		t.Errorf("Expected a self-exclusion under 180 days to be rejected, got %v", err)
+	}
+
+	if _, err := gameService.StartCoolingOff("0xbbb", &request.BreakRequest{Days: 7}); err != nil {
+		t.Fatalf("Expected no error cooling off, got %v", err)
+	}
+	resp, _ := gameService.StartCoolingOff("0xbbb", &request.BreakRequest{Days: 1})
+	if !resp.Controls.CoolingOffUntil.Equal(clock.Now().Add(7 * 24 * time.Hour)) {
+		t.Errorf("Expected a shorter break not to cut the running one short, got %v", resp.Controls.CoolingOffUntil)
+	}
+	if resp, err := gameService.StakeGame(stakeRequest()); !errors.Is(err, service.ErrStakeRefused) || resp.Code != service.StakeCodeCoolingOff {
+		t.Errorf("Expected the accepter's cooling-off to refuse the stake, got %+v, %v", resp, err)
+	}
+
+	clock.Advance(8 * 24 * time.Hour)
+	if _, err := gameService.StakeGame(stakeRequest()); err != nil {
+		t.Errorf("Expected stakes to be accepted after the cooling-off period, got %v", err)
+	}
+
+	if _, err := gameService.SelfExclude("0xbbb", &request.BreakRequest{Days: 180}); err != nil {
+		t.Fatalf("Expected no error self-excluding, got %v", err)
+	}
+	clock.Advance(179 * 24 * time.Hour)
+	if resp, _ := gameService.StakeGame(stakeRequest()); resp.Code != service.StakeCodeSelfExcluded {
+		t.Errorf("Expected the self-exclusion to refuse the stake, got %+v", resp)
+	}
+}
+
+func TestResponsibleGaming_AddressCaseCannotBypassControls(t *testing.T) {
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	stakes := mockMongoClient.GetDatabase("jollfi_games").Collection("stakes")
+	stakes.InsertOne(context.Background(), models.Stake{RequesterAddress: "0xAAA", AccepterAddress: "0xccc", StakeAmount: 300, Timestamp: clock.Now().Add(-time.Hour).Unix()})
+
+	if _, err := gameService.SetStakeLimits("0xAaA", &request.SetStakeLimitsRequest{Daily: limit(399)}); err != nil {
+		t.Fatalf("Expected no error setting limits, got %v", err)
+	}
+	if resp, err := gameService.StakeGame(stakeRequest()); !errors.Is(err, service.ErrStakeRefused) || resp.Code != service.StakeCodeDailyLimit {
+		t.Errorf("Expected limits set in mixed case to count stakes made in any case, got %+v, %v", resp, err)
+	}
+
+	if _, err := gameService.SelfExclude("0xBBB", &request.BreakRequest{Days: 180}); err != nil {
+		t.Fatalf("Expected no error self-excluding, got %v", err)
+	}
+	req := stakeRequest()
+	req.RequesterAddress = "0xccc"
+	if resp, err := gameService.StakeGame(req); !errors.Is(err, service.ErrStakeRefused) || resp.Code != service.StakeCodeSelfExcluded {
+		t.Errorf("Expected a self-exclusion under 0xBBB to refuse 0xbbb, got %+v, %v", resp, err)
+	}
+	if resp, _ := gameService.GetStakeLimits("0xbbb"); resp == nil || resp.Controls.ExcludedUntil == nil {
+		t.Errorf("Expected the self-exclusion to show under any case, got %+v", resp)
+	}
+}
+
+func TestResponsibleGaming_Routes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", JWTSecret: liveTestSecret})
+	address := "0x1234567890abcdef1234567890abcdef12345678"
+
+	send := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send("GET", "/api/v1/players/"+address+"/limits", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", w.Code)
+	}
+	if w := send("PUT", "/api/v1/players/"+address+"/limits", liveToken(t, middleware.RolePlayer, "0xother"), `{"daily":10}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another player's limits, got %d", w.Code)
+	}
+	if w := send("PUT", "/api/v1/players/"+address+"/limits", liveToken(t, middleware.RoleGameServer, address), `{"daily":10}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a game server token, got %d", w.Code)
+	}
+
+	token := liveToken(t, middleware.RolePlayer, strings.ToUpper(address))
+	var limits response.PlayerLimitsResponse
+	w := send("PUT", "/api/v1/players/"+address+"/limits", token, `{"daily":10}`)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &limits) != nil || limits.Effective.Daily != 10 {
+		t.Errorf("Expected the player to set their own limit, got %d %s", w.Code, w.Body.String())
+	}
+	if w := send("POST", "/api/v1/players/"+address+"/self_exclusion", token, `{"days":30}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a self-exclusion that is too short, got %d", w.Code)
+	}
+	if w := send("POST", "/api/v1/players/"+address+"/cooling_off", token, `{"days":2}`); w.Code != http.StatusOK {
+		t.Errorf("Expected the cooling-off period to start, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = send("POST", "/api/v1/games/stake", "", `{"requester_coin_id":"0x123","accepter_coin_id":"0x456","requester_address":"`+address+`","accepter_address":"0xabcdef1234567890abcdef1234567890abcdef12","stake_amount":5}`)
+	var stake response.StakeResponse
+	if w.Code != http.StatusForbidden || json.Unmarshal(w.Body.Bytes(), &stake) != nil || stake.Code != service.StakeCodeCoolingOff {
+		t.Errorf("Expected the stake to be refused with 403 cooling_off, got %d %s", w.Code, w.Body.String())
+	}
+}
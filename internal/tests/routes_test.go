@@ -3,10 +3,15 @@ package tests
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"jollfi-gaming-api/internal/config"
 	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/middleware"
 	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/ratelimit"
 	"jollfi-gaming-api/internal/routes"
 	"jollfi-gaming-api/internal/service"
 	"net/http"
@@ -31,6 +36,7 @@ func createTestRouter() (*gin.Engine, service.GameServiceInterface) {
 		EnableCORS:    true,
 		RateLimit:     100,
 		APIKey:        "",
+		JWTSecret:     liveTestSecret,
 	}
 
 	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
@@ -329,7 +335,11 @@ func TestStakeGameRoute_ValidationErrors(t *testing.T) {
 }
 
 func TestPayWinnerRoute_Success(t *testing.T) {
-	router, _ := createTestRouter()
+	gin.SetMode(gin.TestMode)
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mocks.NewMockSuiClient(), mockMongoClient)
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", JWTSecret: liveTestSecret})
+	recordGame(t, mockMongoClient, "0x1234567890abcdef1234567890abcdef12345678", "0xabcdef1234567890abcdef1234567890abcdef12")
 
 	payReq := request.PayWinnerRequest{
 		RequesterAddress: "0x1234567890abcdef1234567890abcdef12345678",
@@ -338,12 +348,13 @@ func TestPayWinnerRoute_Success(t *testing.T) {
 	}
 
 	jsonData, _ := json.Marshal(payReq)
-	req, _ := http.NewRequest("POST", "/api/v1/games/pay_winner", bytes.NewBuffer(jsonData))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
+	if w := tokenRequest(router, "POST", "/api/v1/games/pay_winner", "", string(jsonData)); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a token, got %d", w.Code)
+	}
+	if w := tokenRequest(router, "POST", "/api/v1/games/pay_winner", liveToken(t, middleware.RolePlayer, payReq.RequesterAddress), string(jsonData)); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a player token, got %d", w.Code)
+	}
+	w := tokenRequest(router, "POST", "/api/v1/games/pay_winner", liveToken(t, middleware.RoleGameServer, "arena-1"), string(jsonData))
 	if w.Code != http.StatusOK {
 		t.Errorf("Expected status 200, got %d", w.Code)
 		t.Logf("Response body: %s", w.Body.String())
@@ -397,11 +408,7 @@ func TestPayWinnerRoute_ValidationErrors(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			jsonData, _ := json.Marshal(tc.request)
-			req, _ := http.NewRequest("POST", "/api/v1/games/pay_winner", bytes.NewBuffer(jsonData))
-			req.Header.Set("Content-Type", "application/json")
-			w := httptest.NewRecorder()
-
-			router.ServeHTTP(w, req)
+			w := tokenRequest(router, "POST", "/api/v1/games/pay_winner", liveToken(t, middleware.RoleGameServer, "arena-1"), string(jsonData))
 
 			if w.Code != http.StatusBadRequest {
 				t.Errorf("Expected status 400, got %d", w.Code)
@@ -527,6 +534,403 @@ func TestGetGameHistoryRoute_InvalidAddress(t *testing.T) {
 	}
 }
 
+// stubGameService overrides individual GameServiceInterface methods so route
+// handlers can be tested without going through the real service.
+type stubGameService struct {
+	service.GameServiceInterface
+	playerProfile func(address string) (*response.PlayerProfileResponse, error)
+	playerRating  func(address string) (*response.PlayerRatingResponse, error)
+	leaderboard   func(query *request.LeaderboardQuery) (*response.LeaderboardResponse, error)
+	accept        func(id string, req *request.AcceptChallengeRequest) (*response.StakeResponse, error)
+}
+
+func (s *stubGameService) GetPlayerProfile(address string) (*response.PlayerProfileResponse, error) {
+	return s.playerProfile(address)
+}
+
+func (s *stubGameService) GetPlayerRating(address string) (*response.PlayerRatingResponse, error) {
+	return s.playerRating(address)
+}
+
+func (s *stubGameService) GetLeaderboard(query *request.LeaderboardQuery) (*response.LeaderboardResponse, error) {
+	return s.leaderboard(query)
+}
+
+func (s *stubGameService) AcceptChallenge(id string, req *request.AcceptChallengeRequest) (*response.StakeResponse, error) {
+	return s.accept(id, req)
+}
+
+// KillSwitch reports the switch as released so guarded routes reach the stub.
+func (s *stubGameService) KillSwitch() models.KillSwitch {
+	return models.KillSwitch{}
+}
+
+// DependencyAvailable reports every dependency as up.
+func (s *stubGameService) DependencyAvailable(name string) bool {
+	return true
+}
+
+// RateLimitStore counts in memory, as the real service does by default.
+func (s *stubGameService) RateLimitStore() ratelimit.Store {
+	return ratelimit.NewMemoryStore()
+}
+
+func createStubRouter(stub *stubGameService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		Environment: "test",
+		RateLimit:   100,
+		JWTSecret:   liveTestSecret,
+	}
+	return routes.SetupRoutes(stub, cfg)
+}
+
+func TestGetPlayerProfileRoute_Success(t *testing.T) {
+	validAddress := "0x1234567890abcdef1234567890abcdef12345678"
+	router := createStubRouter(&stubGameService{
+		playerProfile: func(address string) (*response.PlayerProfileResponse, error) {
+			if address != validAddress {
+				t.Errorf("Expected address %s, got %s", validAddress, address)
+			}
+			return &response.PlayerProfileResponse{
+				Success: true,
+				Profile: &models.PlayerProfile{
+					Address:          address,
+					Wins:             3,
+					NetProfit:        -250,
+					LongestWinStreak: 2,
+					HeadToHead:       []models.HeadToHeadRecord{{Opponent: "0xabc", Games: 4}},
+				},
+			}, nil
+		},
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/players/"+validAddress, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp response.PlayerProfileResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Profile == nil || resp.Profile.NetProfit != -250 || resp.Profile.Wins != 3 {
+		t.Errorf("Expected profile to be passed through, got %+v", resp.Profile)
+	}
+	if len(resp.Profile.HeadToHead) != 1 || resp.Profile.HeadToHead[0].Opponent != "0xabc" {
+		t.Errorf("Expected head-to-head record, got %+v", resp.Profile.HeadToHead)
+	}
+}
+
+func TestGetPlayerProfileRoute_InvalidAddress(t *testing.T) {
+	router, _ := createTestRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/players/invalid_address", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Invalid address format") {
+		t.Errorf("Expected invalid address format error, got %s", w.Body.String())
+	}
+}
+
+func TestGetPlayerProfileRoute_ServiceError(t *testing.T) {
+	router := createStubRouter(&stubGameService{
+		playerProfile: func(address string) (*response.PlayerProfileResponse, error) {
+			return &response.PlayerProfileResponse{Success: false, Error: "Failed to fetch player stats"}, fmt.Errorf("db down")
+		},
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/players/0x1234567890abcdef1234567890abcdef12345678", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+}
+
+func TestGetPlayerRatingRoute_Success(t *testing.T) {
+	router := createStubRouter(&stubGameService{
+		playerRating: func(address string) (*response.PlayerRatingResponse, error) {
+			return &response.PlayerRatingResponse{
+				Success: true,
+				Address: address,
+				Rating:  &models.PlayerRating{Rating: 1662.3, Deviation: 290.3, Volatility: 0.06, Games: 1},
+				History: []models.RatingHistoryEntry{{Opponent: "0xabc", Result: "win", RatingBefore: 1500, RatingAfter: 1662.3}},
+			}, nil
+		},
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/players/0x1234567890abcdef1234567890abcdef12345678/rating", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp response.PlayerRatingResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Rating == nil || resp.Rating.Games != 1 || len(resp.History) != 1 || resp.History[0].Result != "win" {
+		t.Errorf("Expected rating with one win in history, got %+v", resp)
+	}
+}
+
+func TestGetLeaderboardRoute_Success(t *testing.T) {
+	router := createStubRouter(&stubGameService{
+		leaderboard: func(query *request.LeaderboardQuery) (*response.LeaderboardResponse, error) {
+			if query.Metric != "profit" || query.Period != "month" || query.Page != 2 || query.Limit != 10 {
+				t.Errorf("Expected profit/month page 2 limit 10, got %+v", query)
+			}
+			return &response.LeaderboardResponse{
+				Success: true,
+				Metric:  query.Metric,
+				Period:  query.Period,
+				Entries: []models.LeaderboardEntry{{Address: "0xabc", Rank: 11, Profit: 500}},
+			}, nil
+		},
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?metric=profit&period=month&page=2&limit=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp response.LeaderboardResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Entries) != 1 || resp.Entries[0].Rank != 11 {
+		t.Errorf("Expected one entry at rank 11, got %+v", resp.Entries)
+	}
+}
+
+func TestGetLeaderboardRoute_InvalidQuery(t *testing.T) {
+	router, _ := createTestRouter()
+
+	for _, query := range []string{"metric=kills", "period=year", "limit=500", "address=bad"} {
+		req, _ := http.NewRequest("GET", "/api/v1/leaderboard?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestChallengeRoutes_CreateListCancel(t *testing.T) {
+	router, _ := createTestRouter()
+	requester := "0x1234567890abcdef1234567890abcdef12345678"
+	requesterToken := liveToken(t, middleware.RolePlayer, requester)
+
+	body := `{"requester_coin_id":"0xcoin1","stake_amount":100}`
+	if w := tokenRequest(router, "POST", "/api/v1/challenges", "", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 creating without a token, got %d", w.Code)
+	}
+	w := tokenRequest(router, "POST", "/api/v1/challenges", requesterToken, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 creating challenge, got %d: %s", w.Code, w.Body.String())
+	}
+	var created response.ChallengeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || created.Challenge == nil {
+		t.Fatalf("Failed to parse challenge: %v", err)
+	}
+	if created.Challenge.RequesterAddress != requester {
+		t.Errorf("Expected the token subject as requester, got %s", created.Challenge.RequesterAddress)
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/challenges", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), created.Challenge.ID) {
+		t.Errorf("Expected open challenge %s to be listed, got %s", created.Challenge.ID, w.Body.String())
+	}
+
+	cancel := func(token string) int {
+		// A requester_address in the body is ignored; only the token counts.
+		body := `{"requester_address":"` + requester + `"}`
+		return tokenRequest(router, "POST", "/api/v1/challenges/"+created.Challenge.ID+"/cancel", token, body).Code
+	}
+	if code := cancel(""); code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 cancelling without a token, got %d", code)
+	}
+	if code := cancel(liveToken(t, middleware.RolePlayer, "0xabcdef1234567890abcdef1234567890abcdef12")); code != http.StatusForbidden {
+		t.Errorf("Expected status 403 cancelling someone else's challenge, got %d", code)
+	}
+	if code := cancel(requesterToken); code != http.StatusOK {
+		t.Errorf("Expected status 200 cancelling, got %d", code)
+	}
+	if code := cancel(requesterToken); code != http.StatusConflict {
+		t.Errorf("Expected status 409 cancelling twice, got %d", code)
+	}
+}
+
+func TestChallengeRoutes_InvalidCreate(t *testing.T) {
+	router, _ := createTestRouter()
+	requester := "0x1234567890abcdef1234567890abcdef12345678"
+
+	cases := []struct {
+		subject string
+		body    string
+	}{
+		{requester, `{"stake_amount":100}`},
+		{"bad", `{"requester_coin_id":"0xcoin1","stake_amount":100}`},
+		{requester, `{"requester_coin_id":"0xcoin1","stake_amount":100,"accepter_address":"bad"}`},
+		{requester, `{"requester_coin_id":"0xcoin1","stake_amount":100,"accepter_address":"` + requester + `"}`},
+	}
+	for _, tc := range cases {
+		w := tokenRequest(router, "POST", "/api/v1/challenges", liveToken(t, middleware.RolePlayer, tc.subject), tc.body)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s as %s, got %d", tc.body, tc.subject, w.Code)
+		}
+	}
+
+	gameServer := liveToken(t, middleware.RoleGameServer, "server-1")
+	if w := tokenRequest(router, "POST", "/api/v1/challenges", gameServer, `{"requester_coin_id":"0xcoin1","stake_amount":100}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 creating with a game server token, got %d", w.Code)
+	}
+}
+
+func TestAcceptChallengeRoute_ErrorMapping(t *testing.T) {
+	accepter := "0xabcdef1234567890abcdef1234567890abcdef12"
+	cases := map[error]int{
+		service.ErrChallengeNotFound:  http.StatusNotFound,
+		service.ErrChallengeNotOpen:   http.StatusConflict,
+		service.ErrChallengeForbidden: http.StatusForbidden,
+		fmt.Errorf("rpc down"):        http.StatusInternalServerError,
+	}
+	for serviceErr, expected := range cases {
+		router := createStubRouter(&stubGameService{
+			accept: func(id string, req *request.AcceptChallengeRequest) (*response.StakeResponse, error) {
+				if req.AccepterAddress != accepter {
+					t.Errorf("Expected the token subject as accepter, got %s", req.AccepterAddress)
+				}
+				return &response.StakeResponse{Success: false, Error: serviceErr.Error()}, serviceErr
+			},
+		})
+		body := `{"accepter_address":"0x1234567890abcdef1234567890abcdef12345678","accepter_coin_id":"0xcoin2"}`
+		w := tokenRequest(router, "POST", "/api/v1/challenges/abc/accept", liveToken(t, middleware.RolePlayer, accepter), body)
+		if w.Code != expected {
+			t.Errorf("Expected status %d for %v, got %d", expected, serviceErr, w.Code)
+		}
+	}
+
+	router := createStubRouter(&stubGameService{})
+	body := `{"accepter_coin_id":"0xcoin2"}`
+	if w := tokenRequest(router, "POST", "/api/v1/challenges/abc/accept", "", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 accepting without a token, got %d", w.Code)
+	}
+}
+
+func TestQueueRoutes_JoinAndLeaveWithToken(t *testing.T) {
+	router, _ := createTestRouter()
+	player := "0x1234567890abcdef1234567890abcdef12345678"
+	playerToken := liveToken(t, middleware.RolePlayer, player)
+
+	body := `{"address":"0xabcdef1234567890abcdef1234567890abcdef12","coin_id":"0xcoin1","stake_amount":100}`
+	if w := tokenRequest(router, "POST", "/api/v1/matchmaking/queue", "", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 joining without a token, got %d", w.Code)
+	}
+	w := tokenRequest(router, "POST", "/api/v1/matchmaking/queue", playerToken, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 joining, got %d: %s", w.Code, w.Body.String())
+	}
+	var joined response.QueueTicketResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &joined); err != nil || joined.Ticket == nil || joined.Ticket.Address != player {
+		t.Fatalf("Expected a ticket for the token subject, got %s", w.Body.String())
+	}
+	if w := tokenRequest(router, "POST", "/api/v1/matchmaking/queue", playerToken, body); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 joining twice, got %d", w.Code)
+	}
+
+	leave := func(token string) int {
+		// The ticket's address is public, so naming it in the body proves nothing
+		return tokenRequest(router, "POST", "/api/v1/matchmaking/queue/"+joined.Ticket.ID.Hex()+"/leave", token, `{"address":"`+player+`"}`).Code
+	}
+	if code := leave(""); code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 leaving without a token, got %d", code)
+	}
+	if code := leave(liveToken(t, middleware.RolePlayer, "0xabcdef1234567890abcdef1234567890abcdef12")); code != http.StatusForbidden {
+		t.Errorf("Expected status 403 leaving someone else's ticket, got %d", code)
+	}
+	if code := leave(playerToken); code != http.StatusOK {
+		t.Errorf("Expected status 200 leaving, got %d", code)
+	}
+}
+
+func TestAdminWebhookRoutes_RequireAdminKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	gameService := service.NewGameService(mocks.NewMockSuiClient(), mocks.NewMockMongoClient())
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret"})
+
+	register := func(adminKey, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/admin/webhooks", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if adminKey != "" {
+			req.Header.Set("X-Admin-Key", adminKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	valid := `{"url":"https://games.example.com/hooks","events":["game.settled"]}`
+	if w := register("", valid); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without admin key, got %d", w.Code)
+	}
+	if w := register("wrong", valid); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 with wrong admin key, got %d", w.Code)
+	}
+	if w := register("admin-secret", `{"url":"ftp://example.com"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a non-http url, got %d", w.Code)
+	}
+
+	w := register("admin-secret", valid)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 registering, got %d: %s", w.Code, w.Body.String())
+	}
+	var created response.WebhookResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || created.Webhook == nil || created.Webhook.Secret == "" {
+		t.Fatalf("Expected the generated secret in the response, got %s", w.Body.String())
+	}
+
+	req, _ := http.NewRequest("GET", "/admin/webhooks/deliveries?status=dead_letter", nil)
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 listing deliveries, got %d", w.Code)
+	}
+
+	req, _ = http.NewRequest("POST", "/admin/webhooks/deliveries/665f1c2e8b3a4d0012345678/redeliver", nil)
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 redelivering an unknown delivery, got %d", w.Code)
+	}
+
+	disabled := routes.SetupRoutes(gameService, &config.Config{Environment: "test"})
+	req, _ = http.NewRequest("GET", "/admin/webhooks", nil)
+	req.Header.Set("X-Admin-Key", "")
+	w = httptest.NewRecorder()
+	disabled.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 when no admin key is configured, got %d", w.Code)
+	}
+}
+
 func TestGetGameStatsRoute(t *testing.T) {
 	router, _ := createTestRouter()
 
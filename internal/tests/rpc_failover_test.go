@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"jollfi-gaming-api/internal/breaker"
+	"jollfi-gaming-api/internal/data"
+)
+
+func failoverRPCConfig() data.RPCConfig {
+	return data.RPCConfig{
+		Timeout:          time.Second,
+		MaxCheckpointLag: 20,
+		Breaker:          breaker.Config{FailureThreshold: 3, OpenTimeout: time.Minute},
+	}
+}
+
+func TestSuiClient_ReadsFailOver(t *testing.T) {
+	primary, backup := newFakeSuiNode(t), newFakeSuiNode(t)
+	primary.failWith(http.StatusServiceUnavailable)
+	backup.respond("suix_getBalance", map[string]interface{}{"totalBalance": "7000"})
+
+	client := newResilientSuiClient(t, primary.URL, data.DefaultRPCConfig())
+	client.ConfigureRPC(failoverRPCConfig(), primary.URL, backup.URL)
+
+	balance, err := client.GetBalance(context.Background())
+	if err != nil || balance != 7000 {
+		t.Fatalf("Expected the backup to answer, got %d, %v", balance, err)
+	}
+	if primary.callCount("suix_getBalance") != 1 || backup.callCount("suix_getBalance") != 1 {
+		t.Errorf("Expected one attempt on each endpoint, got %d and %d", primary.callCount("suix_getBalance"), backup.callCount("suix_getBalance"))
+	}
+
+	client.GetBalance(context.Background())
+	if n := primary.callCount("suix_getBalance"); n != 1 {
+		t.Errorf("Expected the failing endpoint to be ranked last, got %d calls", n)
+	}
+	statuses := client.RPCStatus()
+	if len(statuses) != 2 || statuses[0].ErrorRate == 0 || statuses[1].ErrorRate != 0 || statuses[0].Score <= statuses[1].Score {
+		t.Errorf("Expected the failing endpoint to score worse, got %+v", statuses)
+	}
+}
+
+func TestSuiClient_PinsTransactionsToHealthiestEndpoint(t *testing.T) {
+	lagging, current := newFakeSuiNode(t), newFakeSuiNode(t)
+	lagging.respond("sui_getLatestCheckpointSequenceNumber", "1000")
+	current.respond("sui_getLatestCheckpointSequenceNumber", "1050")
+
+	client := newResilientSuiClient(t, lagging.URL, data.DefaultRPCConfig())
+	client.ConfigureRPC(failoverRPCConfig(), lagging.URL, current.URL)
+	if statuses := client.RPCStatus(); !statuses[0].Pinned {
+		t.Fatalf("Expected the first endpoint to be pinned before any health check, got %+v", statuses)
+	}
+
+	ctx := context.Background()
+	client.RefreshEndpoints(ctx)
+	statuses := client.RPCStatus()
+	if statuses[0].Healthy || statuses[0].CheckpointLag != 50 || statuses[0].Pinned {
+		t.Errorf("Expected the lagging endpoint to be unhealthy and unpinned, got %+v", statuses[0])
+	}
+	if !statuses[1].Healthy || statuses[1].Checkpoint != 1050 || !statuses[1].Pinned {
+		t.Errorf("Expected the current endpoint to be healthy and pinned, got %+v", statuses[1])
+	}
+
+	client.ExecuteTransactionBlock(ctx, []byte("tx"))
+	if lagging.callCount("sui_executeTransactionBlock") != 0 || current.callCount("sui_executeTransactionBlock") != 1 {
+		t.Errorf("Expected the transaction on the pinned endpoint only, got %d and %d",
+			lagging.callCount("sui_executeTransactionBlock"), current.callCount("sui_executeTransactionBlock"))
+	}
+
+	// Catching up does not move the pin while the pinned endpoint stays healthy
+	lagging.respond("sui_getLatestCheckpointSequenceNumber", "1050")
+	client.RefreshEndpoints(ctx)
+	if statuses := client.RPCStatus(); !statuses[0].Healthy || !statuses[1].Pinned {
+		t.Errorf("Expected the pin to stay put, got %+v", statuses)
+	}
+
+	// Losing the pinned endpoint moves the pin
+	current.failWith(http.StatusBadGateway)
+	for i := 0; i < 3; i++ {
+		client.RefreshEndpoints(ctx)
+	}
+	if statuses := client.RPCStatus(); statuses[1].Breaker != string(breaker.Open) || !statuses[0].Pinned {
+		t.Errorf("Expected the pin to move off the failed endpoint, got %+v", statuses)
+	}
+	client.ExecuteTransactionBlock(ctx, []byte("tx"))
+	if n := lagging.callCount("sui_executeTransactionBlock"); n != 1 {
+		t.Errorf("Expected the next transaction on the new pin, got %d", n)
+	}
+}
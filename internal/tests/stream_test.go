@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/events"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+	"jollfi-gaming-api/internal/stream"
+)
+
+func streamEvent(eventType string, addresses ...string) events.Event {
+	return events.Event{Type: eventType, Addresses: addresses}
+}
+
+func TestStreamHub_FiltersByAddress(t *testing.T) {
+	hub := stream.NewHub(10)
+	alice := hub.Subscribe("0xaaa", "")
+	defer alice.Close()
+
+	hub.Publish(streamEvent(events.StakeCompleted, "0xbbb", "0xccc"))
+	hub.Publish(streamEvent(events.GameSettled, "0xaaa", "0xbbb"))
+
+	select {
+	case msg := <-alice.C:
+		if msg.Event.Type != events.GameSettled {
+			t.Errorf("Expected only the game involving 0xaaa, got %s", msg.Event.Type)
+		}
+	default:
+		t.Fatal("Expected a message for 0xaaa")
+	}
+	select {
+	case msg := <-alice.C:
+		t.Errorf("Expected no further messages, got %+v", msg)
+	default:
+	}
+
+	alice.Close()
+	alice.Close()
+	if hub.Subscribers() != 0 {
+		t.Errorf("Expected closed subscription to be removed, got %d", hub.Subscribers())
+	}
+}
+
+func TestStreamHub_ResumesFromLastEventID(t *testing.T) {
+	hub := stream.NewHub(3)
+	first := hub.Subscribe("0xaaa", "")
+	for i := 0; i < 2; i++ {
+		hub.Publish(streamEvent(events.StakeCompleted, "0xaaa"))
+	}
+	seen := <-first.C
+	first.Close()
+
+	hub.Publish(streamEvent(events.GameSettled, "0xaaa"))
+	hub.Publish(streamEvent(events.GameSettled, "0xbbb"))
+
+	resumed := hub.Subscribe("0xaaa", seen.ID)
+	defer resumed.Close()
+	if len(resumed.Replay) != 2 || resumed.Replay[1].Event.Type != events.GameSettled {
+		t.Fatalf("Expected the 2 missed messages for 0xaaa, got %+v", resumed.Replay)
+	}
+
+	// An ID from another run replays everything buffered for the address
+	evicted := hub.Subscribe("0xaaa", "0-0")
+	defer evicted.Close()
+	if len(evicted.Replay) != 2 {
+		t.Errorf("Expected an unknown ID to replay the buffer for 0xaaa, got %d", len(evicted.Replay))
+	}
+
+	fresh := hub.Subscribe("0xaaa", "")
+	defer fresh.Close()
+	if len(fresh.Replay) != 0 {
+		t.Errorf("Expected no replay without Last-Event-ID, got %d", len(fresh.Replay))
+	}
+}
+
+func TestStreamHub_DropsSlowSubscriber(t *testing.T) {
+	hub := stream.NewHub(0)
+	slow := hub.Subscribe("0xaaa", "")
+	for i := 0; i < 100; i++ {
+		hub.Publish(streamEvent(events.StakeCompleted, "0xaaa"))
+	}
+	select {
+	case <-slow.Done:
+	default:
+		t.Fatal("Expected a subscriber that stopped reading to be dropped")
+	}
+	if hub.Subscribers() != 0 {
+		t.Errorf("Expected dropped subscriber to be removed, got %d", hub.Subscribers())
+	}
+}
+
+func TestGameStreamRoute_PushesEventsForAddress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuiClient := mocks.NewMockSuiClient()
+	gameService := service.NewGameService(mockSuiClient, mocks.NewMockMongoClient())
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", StreamHeartbeatInterval: 1})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	requester := "0x1234567890abcdef1234567890abcdef12345678"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/v1/games/stream?address="+requester, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected stream to open, got %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("Expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readLine := func() string {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Stream ended early: %v", err)
+		}
+		return strings.TrimRight(line, "\n")
+	}
+
+	// The heartbeat proves the handler is subscribed and idle
+	for readLine() != ": heartbeat" {
+	}
+	stake := stakeRequest()
+	stake.RequesterAddress = requester
+	if _, err := gameService.StakeGame(stake); err != nil {
+		t.Fatalf("Expected no error staking, got %v", err)
+	}
+
+	var id, eventType, data string
+	for data == "" {
+		line := readLine()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	if id == "" || eventType != events.StakeCompleted {
+		t.Errorf("Expected an identified stake.completed event, got id %q type %q", id, eventType)
+	}
+	var event events.Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil || event.Data["transaction_digest"] == "" {
+		t.Errorf("Expected the event as JSON data, got %s", data)
+	}
+
+	bad := httptest.NewRecorder()
+	router.ServeHTTP(bad, httptest.NewRequest("GET", "/api/v1/games/stream?address=nope", nil))
+	if bad.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid address, got %d", bad.Code)
+	}
+}
@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+)
+
+func timeoutSettlement(holder, policy string) service.TimeoutSettlementConfig {
+	return service.TimeoutSettlementConfig{
+		After:    time.Hour,
+		Policy:   policy,
+		Holder:   holder,
+		LeaseTTL: 3 * time.Minute,
+	}
+}
+
+func TestTimeoutSettlement_RefundsAbandonedGames(t *testing.T) {
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	ctx := context.Background()
+	if settled, err := gameService.SettleTimedOutGames(ctx); err != nil || settled != 0 {
+		t.Fatalf("Expected nothing settled while the scheduler is off, got %d, %v", settled, err)
+	}
+	gameService.ConfigureTimeoutSettlement(timeoutSettlement("a", service.TimeoutPolicyRefund))
+
+	abandoned := stakedGame(t, mockMongoClient, "", clock.Now().Add(-2*time.Hour))
+	fresh := stakedGame(t, mockMongoClient, "", clock.Now().Add(-30*time.Minute))
+	if settled, err := gameService.SettleTimedOutGames(ctx); err != nil || settled != 1 {
+		t.Fatalf("Expected the abandoned game settled, got %d, %v", settled, err)
+	}
+	raw, _ := mockMongoClient.GetGame(ctx, abandoned)
+	if game, _ := raw.(data.Game); game.Status != service.GameStatusRefunded || game.RefundReason != service.RefundReasonTimeout {
+		t.Errorf("Expected the abandoned game refunded for a timeout, got %+v", game)
+	}
+	if status := gameStatus(mockMongoClient, fresh); status != service.GameStatusStaked {
+		t.Errorf("Expected the fresh game left staked, got %s", status)
+	}
+
+	logged, err := gameService.ListTimeoutSettlements(&request.TimeoutSettlementQuery{})
+	if err != nil || logged.Count != 1 {
+		t.Fatalf("Expected one logged settlement, got %+v, %v", logged, err)
+	}
+	record := logged.Settlements[0]
+	if record.GameID != abandoned || record.Action != service.TimeoutActionRefund || record.Status != service.GameStatusRefunded ||
+		record.Reason == "" || record.TransactionDigest == "" {
+		t.Errorf("Expected the refund logged with its reason, got %+v", record)
+	}
+}
+
+func TestTimeoutSettlement_AwardsTheOnlyReporter(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigureTimeoutSettlement(timeoutSettlement("a", service.TimeoutPolicyAwardReporter))
+	var paidScores [2]uint64
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		paidScores = [2]uint64{requesterScore, accepterScore}
+		return "award-digest", nil
+	}
+	ctx := context.Background()
+	staked := clock.Now().Add(-2 * time.Hour)
+
+	awarded := stakedGame(t, mockMongoClient, "", staked)
+	if _, err := gameService.ReportScore(awarded, &request.ScoreReportRequest{Address: "0xccc", Score: 3}); !errors.Is(err, service.ErrMatchForbidden) {
+		t.Errorf("Expected a report from a stranger to be refused, got %v", err)
+	}
+	if _, err := gameService.ReportScore("000000000000000000000000", &request.ScoreReportRequest{Address: "0xbbb"}); !errors.Is(err, service.ErrGameNotFound) {
+		t.Errorf("Expected a report on an unknown game to be refused, got %v", err)
+	}
+	resp, err := gameService.ReportScore(awarded, &request.ScoreReportRequest{Address: "0xbbb", Score: 7})
+	if err != nil || resp.Game.AccepterReport == nil || resp.Game.AccepterReport.Score != 7 {
+		t.Fatalf("Expected the accepter's score recorded, got %+v, %v", resp, err)
+	}
+
+	contested := stakedGame(t, mockMongoClient, "", staked)
+	gameService.ReportScore(contested, &request.ScoreReportRequest{Address: "0xaaa", Score: 4})
+	gameService.ReportScore(contested, &request.ScoreReportRequest{Address: "0xbbb", Score: 9})
+	silent := stakedGame(t, mockMongoClient, "", staked)
+
+	if settled, err := gameService.SettleTimedOutGames(ctx); err != nil || settled != 3 {
+		t.Fatalf("Expected all three games settled, got %d, %v", settled, err)
+	}
+	raw, _ := mockMongoClient.GetGame(ctx, awarded)
+	if game, _ := raw.(data.Game); game.Status != service.GameStatusCompleted || game.Winner != "0xbbb" || paidScores != [2]uint64{0, 7} {
+		t.Errorf("Expected the game awarded to the only reporter, got %+v paid %v", game, paidScores)
+	}
+	for _, gameID := range []string{contested, silent} {
+		if status := gameStatus(mockMongoClient, gameID); status != service.GameStatusRefunded {
+			t.Errorf("Expected game %s refunded, got %s", gameID, status)
+		}
+	}
+	if _, err := gameService.ReportScore(silent, &request.ScoreReportRequest{Address: "0xaaa", Score: 1}); !errors.Is(err, service.ErrMatchNotLive) {
+		t.Errorf("Expected a report on a settled game to be refused, got %v", err)
+	}
+
+	logged, _ := gameService.ListTimeoutSettlements(&request.TimeoutSettlementQuery{GameID: awarded})
+	if logged.Count != 1 || logged.Settlements[0].Action != service.TimeoutActionAward || logged.Settlements[0].Winner != "0xbbb" {
+		t.Errorf("Expected the award logged, got %+v", logged.Settlements)
+	}
+}
+
+func TestTimeoutSettlement_DeadlineCountsFromTheStake(t *testing.T) {
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigureTimeoutSettlement(timeoutSettlement("a", service.TimeoutPolicyAwardReporter))
+	ctx := context.Background()
+
+	// Opened a day ago, accepted a minute ago, and reported by one player only
+	gameID := stakedChallenge(t, mockMongoClient, clock.Now().Add(-23*time.Hour), clock.Now().Add(-time.Minute))
+	gameService.ReportScore(gameID, &request.ScoreReportRequest{Address: "0xbbb", Score: 7})
+	if settled, err := gameService.SettleTimedOutGames(ctx); err != nil || settled != 0 {
+		t.Fatalf("Expected a just-staked challenge left for the opponent to report, got %d, %v", settled, err)
+	}
+	if status := gameStatus(mockMongoClient, gameID); status != service.GameStatusStaked {
+		t.Errorf("Expected the game still staked, got %s", status)
+	}
+
+	clock.Advance(time.Hour)
+	if settled, err := gameService.SettleTimedOutGames(ctx); err != nil || settled != 1 {
+		t.Fatalf("Expected the game awarded an hour after staking, got %d, %v", settled, err)
+	}
+	logged, _ := gameService.ListTimeoutSettlements(&request.TimeoutSettlementQuery{GameID: gameID})
+	if logged.Count != 1 || !logged.Settlements[0].StakedAt.Equal(clock.Now().Add(-time.Hour-time.Minute)) {
+		t.Errorf("Expected the settlement to log the stake time, got %+v", logged.Settlements)
+	}
+}
+
+func TestTimeoutSettlement_AwardClaimsTheGame(t *testing.T) {
+	gameService, mockSuiClient, mockMongoClient, clock := newReadinessService(t)
+	gameService.ConfigureTimeoutSettlement(timeoutSettlement("a", service.TimeoutPolicyAwardReporter))
+	gameService.ConfigureRefunds(service.RefundConfig{})
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now().Add(-2*time.Hour))
+	gameService.ReportScore(gameID, &request.ScoreReportRequest{Address: "0xaaa", Score: 3})
+
+	// A game server result and a refund arrive while the award is being sent
+	paid := 0
+	var raced, refunded error
+	mockSuiClient.ExternalPayWinnerFunc = func(requesterAddress, accepterAddress string, requesterScore, accepterScore, stakeAmount uint64, ctx context.Context) (string, error) {
+		paid++
+		if paid == 1 {
+			_, raced = gameService.PayWinner(payout(gameID, 1, 5))
+			_, refunded = gameService.RefundStuckGame(gameID, "ops", &request.RefundGameRequest{})
+		}
+		return "award-digest", nil
+	}
+	if settled, err := gameService.SettleTimedOutGames(context.Background()); err != nil || settled != 1 {
+		t.Fatalf("Expected the game awarded, got %d, %v", settled, err)
+	}
+	if !errors.Is(raced, service.ErrGameNotStaked) || !errors.Is(refunded, service.ErrGameNotRefundable) || paid != 1 {
+		t.Errorf("Expected the game paid once, got %d payouts, %v and %v", paid, raced, refunded)
+	}
+	if status := gameStatus(mockMongoClient, gameID); status != service.GameStatusCompleted {
+		t.Errorf("Expected the game completed, got %s", status)
+	}
+}
+
+func TestTimeoutSettlement_OnlyTheLeaderSettles(t *testing.T) {
+	mockMongoClient := mocks.NewMockMongoClient()
+	clock := newFakeClock()
+	replica := func(holder string) *service.GameService {
+		gameService := service.NewGameService(mocks.NewMockSuiClient(), mockMongoClient)
+		gameService.ConfigureMatchmaking(testMatchmakingConfig(), clock)
+		gameService.ConfigureTimeoutSettlement(timeoutSettlement(holder, service.TimeoutPolicyRefund))
+		return gameService
+	}
+	replicaA, replicaB := replica("a"), replica("b")
+	ctx := context.Background()
+
+	stakedGame(t, mockMongoClient, "", clock.Now().Add(-2*time.Hour))
+	if settled, _ := replicaA.SettleTimedOutGames(ctx); settled != 1 {
+		t.Fatalf("Expected the first replica to lead and settle, got %d", settled)
+	}
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now().Add(-2*time.Hour))
+	if settled, _ := replicaB.SettleTimedOutGames(ctx); settled != 0 || gameStatus(mockMongoClient, gameID) != service.GameStatusStaked {
+		t.Fatalf("Expected the second replica to stand by, got %d settled", settled)
+	}
+
+	// The leader stops renewing; once its lease expires the other takes over
+	clock.Advance(4 * time.Minute)
+	if settled, _ := replicaB.SettleTimedOutGames(ctx); settled != 1 {
+		t.Errorf("Expected the second replica to take over, got %d", settled)
+	}
+	if settled, _ := replicaA.SettleTimedOutGames(ctx); settled != 0 {
+		t.Errorf("Expected the old leader to stand by, got %d", settled)
+	}
+}
+
+func TestTimeoutSettlement_Routes(t *testing.T) {
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret", JWTSecret: liveTestSecret})
+	gameID := stakedGame(t, mockMongoClient, "", clock.Now())
+	report := "/api/v1/games/" + gameID + "/report"
+
+	if w := tokenRequest(router, "POST", report, "", `{"score":3}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", w.Code)
+	}
+	if w := tokenRequest(router, "POST", report, liveToken(t, middleware.RoleGameServer, "0xaaa"), `{"score":3}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a game server token, got %d", w.Code)
+	}
+	if w := tokenRequest(router, "POST", report, liveToken(t, middleware.RolePlayer, "0xccc"), `{"score":3}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a stranger's report, got %d", w.Code)
+	}
+	// The body cannot name another player
+	var reported response.ScoreReportResponse
+	w := tokenRequest(router, "POST", report, liveToken(t, middleware.RolePlayer, "0xAAA"), `{"address":"0xbbb","score":3}`)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &reported) != nil || reported.Game.RequesterReport.Score != 3 || reported.Game.AccepterReport != nil {
+		t.Fatalf("Expected the report recorded for the token's player, got %d %s", w.Code, w.Body.String())
+	}
+
+	var logged response.TimeoutSettlementListResponse
+	w = adminRequest(router, "GET", "/admin/games/timeouts?limit=10", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &logged) != nil || logged.Count != 0 {
+		t.Errorf("Expected an empty settlement log, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,368 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jollfi-gaming-api/internal/config"
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/dto/response"
+	"jollfi-gaming-api/internal/middleware"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/routes"
+	"jollfi-gaming-api/internal/service"
+	"jollfi-gaming-api/internal/tournament"
+)
+
+func TestTournament_SingleEliminationBracket(t *testing.T) {
+	matches, err := tournament.Bracket(models.TournamentFormatSingleElimination, []string{"s1", "s2", "s3", "s4", "s5"})
+	if err != nil || len(matches) != 7 {
+		t.Fatalf("Expected 7 matches for 5 players padded to 8, got %d, %v", len(matches), err)
+	}
+	expected := []struct {
+		requester, accepter, status string
+	}{
+		{"s1", "", models.TournamentMatchBye},
+		{"s4", "s5", models.TournamentMatchReady},
+		{"s2", "", models.TournamentMatchBye},
+		{"s3", "", models.TournamentMatchBye},
+		{"s1", "", models.TournamentMatchWaiting},
+		{"s2", "s3", models.TournamentMatchReady},
+		{"", "", models.TournamentMatchWaiting},
+	}
+	for i, want := range expected {
+		got := matches[i]
+		if got.RequesterAddress != want.requester || got.AccepterAddress != want.accepter || got.Status != want.status {
+			t.Errorf("Match %d: expected %s vs %s (%s), got %s vs %s (%s)", i+1, want.requester, want.accepter, want.status, got.RequesterAddress, got.AccepterAddress, got.Status)
+		}
+	}
+	if matches[1].NextMatch != 5 || matches[1].NextSlot != tournament.SlotAccepter || matches[6].NextMatch != 0 || matches[6].Round != 3 {
+		t.Errorf("Expected match 2 to feed the accepter side of match 5 and match 7 to be the final, got %+v", matches)
+	}
+
+	matches[1].Status = models.TournamentMatchComplete
+	matches[1].Winner = "s5"
+	if next := tournament.Advance(matches, matches[1]); next.Number != 5 || next.AccepterAddress != "s5" || next.Status != models.TournamentMatchReady {
+		t.Errorf("Expected s5 to advance into a ready match 5, got %+v", next)
+	}
+
+	if _, err := tournament.Bracket(models.TournamentFormatSingleElimination, []string{"s1"}); err == nil {
+		t.Errorf("Expected a one-player bracket to be refused")
+	}
+	if _, err := tournament.Bracket("swiss", []string{"s1", "s2"}); err == nil {
+		t.Errorf("Expected an unknown format to be refused")
+	}
+}
+
+func TestTournament_RoundRobinAndStandings(t *testing.T) {
+	players := []string{"p1", "p2", "p3", "p4"}
+	matches, err := tournament.Bracket(models.TournamentFormatRoundRobin, players)
+	if err != nil || len(matches) != 6 {
+		t.Fatalf("Expected 6 matches for 4 players, got %d, %v", len(matches), err)
+	}
+	pairs := make(map[[2]string]bool)
+	perRound := make(map[int]map[string]bool)
+	for _, match := range matches {
+		a, b := match.RequesterAddress, match.AccepterAddress
+		if a > b {
+			a, b = b, a
+		}
+		if pairs[[2]string{a, b}] {
+			t.Errorf("Expected %s and %s to meet once", a, b)
+		}
+		pairs[[2]string{a, b}] = true
+		if perRound[match.Round] == nil {
+			perRound[match.Round] = make(map[string]bool)
+		}
+		if perRound[match.Round][a] || perRound[match.Round][b] {
+			t.Errorf("Expected each player to play once in round %d", match.Round)
+		}
+		perRound[match.Round][a], perRound[match.Round][b] = true, true
+	}
+	if odd, _ := tournament.Bracket(models.TournamentFormatRoundRobin, players[:3]); len(odd) != 3 {
+		t.Errorf("Expected 3 matches for 3 players, got %d", len(odd))
+	}
+
+	score := func(n uint64) *uint64 { return &n }
+	played := []models.TournamentMatch{
+		{RequesterAddress: "p1", AccepterAddress: "p2", RequesterScore: score(3), AccepterScore: score(1), Winner: "p1", Status: models.TournamentMatchComplete},
+		{RequesterAddress: "p3", AccepterAddress: "p4", RequesterScore: score(2), AccepterScore: score(2), Status: models.TournamentMatchComplete},
+		{RequesterAddress: "p2", AccepterAddress: "p3", RequesterScore: score(5), AccepterScore: score(0), Winner: "p2", Status: models.TournamentMatchComplete},
+		{RequesterAddress: "p1", AccepterAddress: "p4", Status: models.TournamentMatchStaked},
+	}
+	standings := tournament.Standings(players, played, models.ScoreDirectionHigher)
+	if standings[0].Address != "p2" || standings[0].Points != 3 || standings[1].Address != "p1" {
+		t.Errorf("Expected p2 ahead of p1 on score difference, got %+v", standings)
+	}
+	if standings[3].Address != "p3" || standings[3].Draws != 1 || standings[3].Losses != 1 || standings[3].Played != 2 {
+		t.Errorf("Expected p3 last with a draw and a loss, got %+v", standings[3])
+	}
+	if tournament.Finished(played) {
+		t.Errorf("Expected a staked match to keep the tournament running")
+	}
+}
+
+func registerPlayers(t *testing.T, gameService *service.GameService, clock *fakeClock, id string, addresses ...string) {
+	for _, address := range addresses {
+		if _, err := gameService.RegisterForTournament(id, &request.TournamentEntryRequest{Address: address, CoinID: "coin-" + address}); err != nil {
+			t.Fatalf("Expected %s to register, got %v", address, err)
+		}
+		clock.Advance(time.Second)
+	}
+}
+
+func tournamentMatch(t *testing.T, gameService *service.GameService, id string, number int) models.TournamentMatch {
+	resp, err := gameService.GetTournament(id)
+	if err != nil || len(resp.Matches) < number {
+		t.Fatalf("Expected match %d, got %+v, %v", number, resp, err)
+	}
+	return resp.Matches[number-1]
+}
+
+func TestTournament_SingleEliminationFlow(t *testing.T) {
+	gameService, _, _, clock := newReadinessService(t)
+	created, err := gameService.CreateTournament(&request.CreateTournamentRequest{
+		Name: "Friday cup", Format: models.TournamentFormatSingleElimination, EntryStake: 100, Capacity: 3,
+	}, "ops")
+	if err != nil {
+		t.Fatalf("Expected no error creating, got %v", err)
+	}
+	id := created.Tournament.ID.Hex()
+
+	registerPlayers(t, gameService, clock, id, "0xaaa", "0xbbb")
+	if _, err := gameService.RegisterForTournament(id, &request.TournamentEntryRequest{Address: "0xAAA", CoinID: "again"}); !errors.Is(err, service.ErrAlreadyRegistered) {
+		t.Errorf("Expected a second registration to be refused, got %v", err)
+	}
+	gameService.AddBlocklistEntry(&request.BlocklistEntryRequest{Address: "0xddd", Reason: "fraud"}, "ops")
+	if _, err := gameService.RegisterForTournament(id, &request.TournamentEntryRequest{Address: "0xddd", CoinID: "coin"}); !errors.Is(err, service.ErrAddressBlocked) {
+		t.Errorf("Expected a blocked address to be refused, got %v", err)
+	}
+	registerPlayers(t, gameService, clock, id, "0xccc")
+	if _, err := gameService.RegisterForTournament(id, &request.TournamentEntryRequest{Address: "0xeee", CoinID: "coin"}); !errors.Is(err, service.ErrTournamentFull) {
+		t.Errorf("Expected a full tournament to refuse players, got %v", err)
+	}
+
+	started, err := gameService.StartTournament(id, "ops")
+	if err != nil || started.Tournament.Status != models.TournamentStatusInProgress || len(started.Matches) != 3 {
+		t.Fatalf("Expected the tournament started with 3 matches, got %+v, %v", started, err)
+	}
+	if bye := started.Matches[0]; bye.Status != models.TournamentMatchBye || bye.Winner != "0xaaa" {
+		t.Errorf("Expected the top seed to get a bye, got %+v", bye)
+	}
+	semi := started.Matches[1]
+	if semi.Status != models.TournamentMatchStaked || semi.GameID == "" || started.PrizePool != 200 {
+		t.Fatalf("Expected the semi-final staked with the registered coins, got %+v (pool %d)", semi, started.PrizePool)
+	}
+	if _, err := gameService.StartTournament(id, "ops"); !errors.Is(err, service.ErrTournamentNotOpen) {
+		t.Errorf("Expected a second start to be refused, got %v", err)
+	}
+
+	if _, err := gameService.ReportTournamentResult(id, 2, &request.TournamentResultRequest{RequesterScore: 2, AccepterScore: 2}, "game_server:arena-1"); !errors.Is(err, service.ErrInvalidTournament) {
+		t.Errorf("Expected a knockout draw to be refused, got %v", err)
+	}
+	if _, err := gameService.ReportTournamentResult(id, 2, &request.TournamentResultRequest{RequesterScore: 3, AccepterScore: 1}, "game_server:arena-1"); err != nil {
+		t.Fatalf("Expected the semi-final to be paid, got %v", err)
+	}
+	if _, err := gameService.ReportTournamentResult(id, 2, &request.TournamentResultRequest{RequesterScore: 3, AccepterScore: 1}, "game_server:arena-1"); !errors.Is(err, service.ErrTournamentMatchNotStaked) {
+		t.Errorf("Expected a settled match not to be paid twice, got %v", err)
+	}
+
+	final := tournamentMatch(t, gameService, id, 3)
+	if final.AccepterAddress != "" || final.Status != models.TournamentMatchWaiting {
+		t.Errorf("Expected the final to wait for the tournament worker, got %+v", final)
+	}
+	if advanced, err := gameService.AdvanceTournaments(context.Background()); err != nil || advanced != 1 {
+		t.Fatalf("Expected the semi-final to be advanced, got %d, %v", advanced, err)
+	}
+	if advanced, _ := gameService.AdvanceTournaments(context.Background()); advanced != 0 {
+		t.Errorf("Expected nothing left to advance, got %d", advanced)
+	}
+	final = tournamentMatch(t, gameService, id, 3)
+	if final.RequesterAddress != "0xaaa" || final.AccepterAddress != "0xbbb" || final.Status != models.TournamentMatchReady {
+		t.Fatalf("Expected 0xbbb to meet 0xaaa in a final waiting for coins, got %+v", final)
+	}
+	if _, err := gameService.ReportTournamentResult(id, 3, &request.TournamentResultRequest{RequesterScore: 1}, "game_server:arena-1"); !errors.Is(err, service.ErrTournamentMatchNotStaked) {
+		t.Errorf("Expected an unstaked final to be refused, got %v", err)
+	}
+	if _, err := gameService.SetTournamentCoin(id, &request.TournamentEntryRequest{Address: "0xfff", CoinID: "coin"}); !errors.Is(err, service.ErrNotRegistered) {
+		t.Errorf("Expected an unregistered address to be refused, got %v", err)
+	}
+	if _, err := gameService.SetTournamentCoin(id, &request.TournamentEntryRequest{Address: "0xbbb", CoinID: "final-coin"}); err != nil {
+		t.Fatalf("Expected no error staging a coin, got %v", err)
+	}
+	if final = tournamentMatch(t, gameService, id, 3); final.Status != models.TournamentMatchStaked {
+		t.Fatalf("Expected the final staked once both coins were in, got %+v", final)
+	}
+
+	if _, err := gameService.ReportTournamentResult(id, 3, &request.TournamentResultRequest{RequesterScore: 0, AccepterScore: 2}, "game_server:arena-1"); err != nil {
+		t.Fatalf("Expected the final to be paid, got %v", err)
+	}
+	gameService.AdvanceTournaments(context.Background())
+	done, _ := gameService.GetTournament(id)
+	if done.Tournament.Status != models.TournamentStatusCompleted || done.Tournament.Winner != "0xbbb" || done.PrizePool != 400 {
+		t.Errorf("Expected 0xbbb to win the completed tournament, got %+v (pool %d)", done.Tournament, done.PrizePool)
+	}
+}
+
+func TestTournament_FailedStartReopensRegistration(t *testing.T) {
+	gameService, _, mockMongoClient, clock := newReadinessService(t)
+	created, _ := gameService.CreateTournament(&request.CreateTournamentRequest{
+		Name: "Friday cup", Format: models.TournamentFormatRoundRobin, EntryStake: 100, Capacity: 4,
+	}, "ops")
+	id := created.Tournament.ID.Hex()
+	registerPlayers(t, gameService, clock, id, "0xaaa", "0xbbb", "0xccc")
+
+	matches := mockMongoClient.GetDatabase("jollfi_games").Collection("tournament_matches").(*mocks.MockCollection)
+	matches.FailInsertsAfter(1, errors.New("write failed"))
+	if _, err := gameService.StartTournament(id, "ops"); err == nil {
+		t.Fatalf("Expected the start to fail when the bracket cannot be recorded")
+	}
+	resp, _ := gameService.GetTournament(id)
+	if resp.Tournament.Status != models.TournamentStatusRegistering || resp.Tournament.StartedAt != nil || len(resp.Matches) != 0 {
+		t.Fatalf("Expected registration reopened with no matches left behind, got %+v with %d matches", resp.Tournament, len(resp.Matches))
+	}
+
+	matches.FailInsertsAfter(0, nil)
+	started, err := gameService.StartTournament(id, "ops")
+	if err != nil || started.Tournament.Status != models.TournamentStatusInProgress || len(started.Matches) != 3 {
+		t.Errorf("Expected the retried start to record the full bracket, got %+v, %v", started, err)
+	}
+}
+
+func TestTournament_RoundRobinWaitsForCoinsAndReviews(t *testing.T) {
+	gameService, _, _, clock := newReadinessService(t)
+	gameService.ConfigurePayoutGuard(service.PayoutGuardConfig{MaxScore: 10})
+	gameService.UpsertGameType("darts", &request.GameTypeRequest{}, "ops")
+	created, _ := gameService.CreateTournament(&request.CreateTournamentRequest{
+		Name: "League", Format: models.TournamentFormatRoundRobin, EntryStake: 50, Capacity: 8, GameType: "darts",
+	}, "ops")
+	id := created.Tournament.ID.Hex()
+	registerPlayers(t, gameService, clock, id, "0x111", "0x222", "0x333")
+
+	started, err := gameService.StartTournament(id, "ops")
+	if err != nil || len(started.Matches) != 3 {
+		t.Fatalf("Expected 3 matches, got %+v, %v", started, err)
+	}
+	staked := 0
+	for _, match := range started.Matches {
+		if match.Status == models.TournamentMatchStaked {
+			staked++
+		}
+	}
+	if staked != 1 {
+		t.Fatalf("Expected one staked match while each player has a single coin, got %d", staked)
+	}
+	first := started.Matches[0]
+	if _, err := gameService.ReportTournamentResult(id, first.Number, &request.TournamentResultRequest{RequesterScore: 2, AccepterScore: 2}, "game_server:arena-1"); err != nil {
+		t.Fatalf("Expected a round robin draw to be paid, got %v", err)
+	}
+
+	gameService.SetTournamentCoin(id, &request.TournamentEntryRequest{Address: "0x333", CoinID: "coin-2"})
+	second := tournamentMatch(t, gameService, id, 2)
+	if second.Status != models.TournamentMatchStaked {
+		t.Fatalf("Expected the second match staked with the new coin, got %+v", second)
+	}
+	resp, err := gameService.ReportTournamentResult(id, 2, &request.TournamentResultRequest{RequesterScore: 50}, "game_server:arena-1")
+	if !errors.Is(err, service.ErrPayoutHeld) {
+		t.Fatalf("Expected an implausible score to be held, got %+v, %v", resp, err)
+	}
+	if _, err := gameService.ReportTournamentResult(id, 2, &request.TournamentResultRequest{RequesterScore: 5}, "game_server:arena-1"); !errors.Is(err, service.ErrTournamentMatchNotStaked) {
+		t.Errorf("Expected a held match not to take another result, got %v", err)
+	}
+	if _, err := gameService.ApproveHeldPayout(resp.HeldPayoutID, "ops", &request.ReviewPayoutRequest{}); err != nil {
+		t.Fatalf("Expected no error approving, got %v", err)
+	}
+	if second = tournamentMatch(t, gameService, id, 2); second.Status != models.TournamentMatchComplete || second.Winner != second.RequesterAddress {
+		t.Fatalf("Expected the approved payout to complete the match, got %+v", second)
+	}
+
+	for _, address := range []string{"0x111", "0x222", "0x333"} {
+		gameService.SetTournamentCoin(id, &request.TournamentEntryRequest{Address: address, CoinID: "coin-3-" + address})
+	}
+	third := tournamentMatch(t, gameService, id, 3)
+	if _, err := gameService.ReportTournamentResult(id, 3, &request.TournamentResultRequest{RequesterScore: 1}, "game_server:arena-1"); err != nil {
+		t.Fatalf("Expected the last match to be paid, got %v", err)
+	}
+	gameService.AdvanceTournaments(context.Background())
+	done, _ := gameService.GetTournament(id)
+	if done.Tournament.Status != models.TournamentStatusCompleted || done.Tournament.Winner != second.RequesterAddress || done.Standings[0].Points != 4 {
+		t.Errorf("Expected %s to top the table on 4 points, got %+v %+v", second.RequesterAddress, done.Tournament, done.Standings)
+	}
+	if done.Standings[1].Address != third.RequesterAddress {
+		t.Errorf("Expected the last match's winner second, got %+v", done.Standings)
+	}
+}
+
+func TestTournament_Routes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	router := routes.SetupRoutes(gameService, &config.Config{Environment: "test", AdminAPIKey: "admin-secret", JWTSecret: liveTestSecret})
+
+	if w := adminRequest(router, "POST", "/admin/tournaments", `{"name":"Cup","format":"swiss","entry_stake":10,"capacity":4}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown format, got %d", w.Code)
+	}
+	var created response.TournamentResponse
+	w := adminRequest(router, "POST", "/admin/tournaments", `{"name":"Cup","format":"single_elimination","entry_stake":10,"capacity":4}`)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &created) != nil || created.Tournament.CreatedBy != "ops@jollfi" {
+		t.Fatalf("Expected the tournament created, got %d %s", w.Code, w.Body.String())
+	}
+	id := created.Tournament.ID.Hex()
+
+	players := []string{"0x1234567890abcdef1234567890abcdef12345678", "0xabcdef1234567890abcdef1234567890abcdef12"}
+	if w := tokenRequest(router, "POST", "/api/v1/tournaments/"+id+"/register", "", `{"coin_id":"0xc01"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 registering without a token, got %d", w.Code)
+	}
+	for _, address := range players {
+		// An address in the body is ignored; the token's subject registers.
+		body := `{"address":"` + players[0] + `","coin_id":"0xc01"}`
+		if w := tokenRequest(router, "POST", "/api/v1/tournaments/"+id+"/register", liveToken(t, middleware.RolePlayer, address), body); w.Code != http.StatusOK {
+			t.Fatalf("Expected %s to register, got %d %s", address, w.Code, w.Body.String())
+		}
+	}
+	if w := tokenRequest(router, "POST", "/api/v1/tournaments/"+id+"/register", liveToken(t, middleware.RolePlayer, "bad"), `{"coin_id":"0xc01"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid address, got %d", w.Code)
+	}
+	if w := tokenRequest(router, "POST", "/api/v1/tournaments/"+id+"/coin", liveToken(t, middleware.RoleGameServer, "arena-1"), `{"coin_id":"0xc02"}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 staging a coin with a game server token, got %d", w.Code)
+	}
+	if w := adminRequest(router, "POST", "/admin/tournaments/"+id+"/start", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected the tournament to start, got %d %s", w.Code, w.Body.String())
+	}
+	if w := adminRequest(router, "POST", "/admin/tournaments/"+id+"/cancel", ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 cancelling a started tournament, got %d", w.Code)
+	}
+
+	var listed response.TournamentListResponse
+	w = adminRequest(router, "GET", "/api/v1/tournaments?status=in_progress", "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &listed) != nil || listed.Count != 1 {
+		t.Errorf("Expected the running tournament listed, got %d %s", w.Code, w.Body.String())
+	}
+
+	gameServer := liveToken(t, middleware.RoleGameServer, "arena-1")
+	if w := tokenRequest(router, "POST", "/api/v1/tournaments/"+id+"/matches/1/result", liveToken(t, middleware.RolePlayer, players[0]), `{"requester_score":3,"accepter_score":1}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a player reporting their own result, got %d", w.Code)
+	}
+	if w := tokenRequest(router, "POST", "/api/v1/tournaments/"+id+"/matches/x/result", gameServer, `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid match number, got %d", w.Code)
+	}
+	if w := tokenRequest(router, "POST", "/api/v1/tournaments/"+id+"/matches/1/result", gameServer, `{"requester_score":3,"accepter_score":1}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected the final to be paid, got %d %s", w.Code, w.Body.String())
+	}
+	gameService.AdvanceTournaments(context.Background())
+	var done response.TournamentResponse
+	w = adminRequest(router, "GET", "/api/v1/tournaments/"+id, "")
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &done) != nil || done.Tournament.Winner != players[0] {
+		t.Errorf("Expected the first seed to win, got %d %s", w.Code, w.Body.String())
+	}
+	if w := adminRequest(router, "GET", "/api/v1/tournaments/nope", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown tournament, got %d", w.Code)
+	}
+}
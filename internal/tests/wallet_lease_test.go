@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"jollfi-gaming-api/internal/data"
+	"jollfi-gaming-api/internal/lease"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/service"
+)
+
+func TestLease_FencingTokens(t *testing.T) {
+	clock := newFakeClock()
+	collection := mocks.NewMockMongoClient().GetDatabase("jollfi_games").Collection(lease.Collection)
+	config := lease.Config{TTL: 30 * time.Second, Now: clock.Now}
+	replicaA := lease.New(collection, "wallet:0xop", "a", config)
+	replicaB := lease.New(collection, "wallet:0xop", "b", config)
+	ctx := context.Background()
+
+	grantA, err := replicaA.TryAcquire(ctx)
+	if err != nil || grantA.Token != 1 || grantA.Holder != "a" {
+		t.Fatalf("Expected the first grant to get token 1, got %+v, %v", grantA, err)
+	}
+	if _, err := replicaB.TryAcquire(ctx); err != lease.ErrHeld {
+		t.Errorf("Expected the held lease to be refused, got %v", err)
+	}
+
+	clock.Advance(31 * time.Second)
+	grantB, err := replicaB.TryAcquire(ctx)
+	if err != nil || grantB.Token != 2 {
+		t.Fatalf("Expected the expired lease to be taken over with token 2, got %+v, %v", grantB, err)
+	}
+	if err := replicaA.Renew(ctx, grantA); err != lease.ErrLost {
+		t.Errorf("Expected the stale holder to find its lease lost, got %v", err)
+	}
+	replicaA.Release(ctx, grantA)
+	if err := replicaB.Renew(ctx, grantB); err != nil {
+		t.Errorf("Expected releasing a lost grant not to affect the new holder, got %v", err)
+	}
+
+	replicaB.Release(ctx, grantB)
+	if grant, err := replicaA.TryAcquire(ctx); err != nil || grant.Token != 3 {
+		t.Errorf("Expected a released lease to be free at once, got %+v, %v", grant, err)
+	}
+}
+
+func TestSuiClient_TransactionsCheckFence(t *testing.T) {
+	node := newFakeSuiNode(t)
+	client := newResilientSuiClient(t, node.URL, data.DefaultRPCConfig())
+
+	fenced := lease.WithFence(context.Background(), func(ctx context.Context) error {
+		return lease.ErrLost
+	})
+	if _, err := client.ExecuteTransactionBlock(fenced, []byte("tx")); !errors.Is(err, lease.ErrLost) {
+		t.Errorf("Expected the failed fence to stop the transaction, got %v", err)
+	}
+	if n := node.callCount("sui_executeTransactionBlock"); n != 0 {
+		t.Errorf("Expected nothing sent to the node, got %d calls", n)
+	}
+	client.GetBalance(fenced)
+	if n := node.callCount("suix_getBalance"); n != 1 {
+		t.Errorf("Expected reads not to be fenced, got %d calls", n)
+	}
+}
+
+func TestGameService_WalletLeaseSerializesReplicas(t *testing.T) {
+	clock := newFakeClock()
+	mockMongoClient := mocks.NewMockMongoClient()
+	newReplica := func(holder string) (*service.GameService, *mocks.MockSuiClient) {
+		mockSuiClient := mocks.NewMockSuiClient()
+		gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+		gameService.ConfigureMatchmaking(testMatchmakingConfig(), clock)
+		gameService.ConfigureWalletLease(service.WalletLeaseConfig{
+			Name:   "wallet:0xop",
+			Holder: holder,
+			TTL:    30 * time.Second,
+			Wait:   100 * time.Millisecond,
+		})
+		return gameService, mockSuiClient
+	}
+	replicaA, suiA := newReplica("a")
+	replicaB, suiB := newReplica("b")
+
+	submitting, resume := make(chan struct{}), make(chan struct{})
+	suiA.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		close(submitting)
+		<-resume
+		// What the real client does right before sui_executeTransactionBlock
+		if err := lease.CheckFence(ctx); err != nil {
+			return "", err
+		}
+		return "digest-a", nil
+	}
+	suiB.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		return "digest-b", lease.CheckFence(ctx)
+	}
+
+	stakeA := make(chan error, 1)
+	go func() {
+		_, err := replicaA.StakeGame(stakeRequest())
+		stakeA <- err
+	}()
+	<-submitting
+
+	if _, err := replicaB.StakeGame(stakeRequest()); !errors.Is(err, service.ErrWalletBusy) {
+		t.Fatalf("Expected the other replica to wait and give up, got %v", err)
+	}
+
+	// Replica A stalls past the TTL and replica B takes the wallet over
+	clock.Advance(31 * time.Second)
+	if resp, err := replicaB.StakeGame(stakeRequest()); err != nil || resp.TransactionDigest != "digest-b" {
+		t.Fatalf("Expected the expired lease to be taken over, got %+v, %v", resp, err)
+	}
+	close(resume)
+	if err := <-stakeA; !errors.Is(err, lease.ErrLost) {
+		t.Errorf("Expected the stalled replica's fence to stop its transaction, got %v", err)
+	}
+}
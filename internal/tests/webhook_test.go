@@ -0,0 +1,313 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"jollfi-gaming-api/internal/dto/request"
+	"jollfi-gaming-api/internal/events"
+	"jollfi-gaming-api/internal/mocks"
+	"jollfi-gaming-api/internal/models"
+	"jollfi-gaming-api/internal/service"
+	"jollfi-gaming-api/internal/webhook"
+)
+
+// webhookReceiver is an httptest server that checks signatures and answers
+// with a configurable status code.
+type webhookReceiver struct {
+	*httptest.Server
+	t      *testing.T
+	secret string
+
+	mu       sync.Mutex
+	status   int
+	received []events.Event
+}
+
+func newWebhookReceiver(t *testing.T, secret string) *webhookReceiver {
+	r := &webhookReceiver{t: t, secret: secret, status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		timestamp, err := strconv.ParseInt(req.Header.Get(webhook.TimestampHeader), 10, 64)
+		if err != nil {
+			t.Errorf("Expected a unix timestamp header, got %q", req.Header.Get(webhook.TimestampHeader))
+		}
+		if !webhook.Verify(r.secret, req.Header.Get(webhook.SignatureHeader), timestamp, body) {
+			t.Errorf("Expected a valid signature, got %q", req.Header.Get(webhook.SignatureHeader))
+		}
+		var event events.Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("Expected a JSON event body, got %v", err)
+		}
+		if req.Header.Get(webhook.EventHeader) != event.Type {
+			t.Errorf("Expected event header %s, got %s", event.Type, req.Header.Get(webhook.EventHeader))
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.received = append(r.received, event)
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *webhookReceiver) respondWith(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) events() []events.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]events.Event(nil), r.received...)
+}
+
+func stakeRequest() *request.StakeRequest {
+	return &request.StakeRequest{
+		RequesterCoinID:  "0xcoin_a",
+		AccepterCoinID:   "0xcoin_b",
+		RequesterAddress: "0xaaa",
+		AccepterAddress:  "0xbbb",
+		StakeAmount:      100,
+	}
+}
+
+func TestWebhook_SignAndBackoff(t *testing.T) {
+	body := []byte(`{"type":"game.settled"}`)
+	signature := webhook.Sign("secret", 1700000000, body)
+	if !webhook.Verify("secret", signature, 1700000000, body) {
+		t.Error("Expected signature to verify")
+	}
+	if webhook.Verify("other", signature, 1700000000, body) || webhook.Verify("secret", signature, 1700000001, body) {
+		t.Error("Expected signature to be bound to the secret and timestamp")
+	}
+
+	cfg := webhook.Config{BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+	expected := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, want := range expected {
+		if got := cfg.Backoff(i + 1); got != want {
+			t.Errorf("Expected backoff %v after attempt %d, got %v", want, i+1, got)
+		}
+	}
+}
+
+func TestGameService_Webhooks_DeliversSignedEvent(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	receiver := newWebhookReceiver(t, "whsec_test")
+
+	registered, err := gameService.RegisterWebhook(&request.RegisterWebhookRequest{
+		URL:    receiver.URL,
+		Events: []string{events.StakeCompleted},
+		Secret: "whsec_test",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error registering, got %v", err)
+	}
+	if _, err := gameService.RegisterWebhook(&request.RegisterWebhookRequest{URL: receiver.URL, Events: []string{"game.unknown"}}); err != service.ErrInvalidWebhook {
+		t.Errorf("Expected unknown events to be rejected, got %v", err)
+	}
+
+	if _, err := gameService.StakeGame(stakeRequest()); err != nil {
+		t.Fatalf("Expected no error staking, got %v", err)
+	}
+	if _, err := gameService.PayWinner(&request.PayWinnerRequest{RequesterAddress: "0xaaa", AccepterAddress: "0xbbb", RequesterScore: 3, AccepterScore: 1, StakeAmount: 100}); err != nil {
+		t.Fatalf("Expected no error paying winner, got %v", err)
+	}
+
+	delivered, err := gameService.DispatchWebhooks(context.Background())
+	if err != nil || delivered != 1 {
+		t.Fatalf("Expected only the subscribed stake event to be delivered, got %d, %v", delivered, err)
+	}
+	received := receiver.events()
+	if len(received) != 1 || received[0].Type != events.StakeCompleted || received[0].GameID == "" {
+		t.Fatalf("Expected one stake.completed event with a game_id, got %+v", received)
+	}
+
+	log, err := gameService.ListWebhookDeliveries(&request.WebhookDeliveryQuery{EndpointID: registered.Webhook.ID.Hex()})
+	if err != nil || log.Count != 1 {
+		t.Fatalf("Expected one logged delivery, got %+v, %v", log, err)
+	}
+	if d := log.Deliveries[0]; d.Status != models.DeliveryStatusDelivered || d.Attempts != 1 || d.LastStatusCode != http.StatusOK {
+		t.Errorf("Expected delivered on first attempt, got %+v", d)
+	}
+
+	listed, _ := gameService.ListWebhooks()
+	if listed.Count != 1 || listed.Webhooks[0].Secret != "" {
+		t.Errorf("Expected secrets to be hidden when listing, got %+v", listed.Webhooks)
+	}
+}
+
+func TestGameService_Webhooks_SignsEachAttemptWhenSent(t *testing.T) {
+	gameService, _, _, clock := newReadinessService(t)
+	var mu sync.Mutex
+	var stamps []int64
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		timestamp, _ := strconv.ParseInt(req.Header.Get(webhook.TimestampHeader), 10, 64)
+		mu.Lock()
+		stamps = append(stamps, timestamp)
+		mu.Unlock()
+		// Each receiver takes long enough to outlast a signature tolerance
+		clock.Advance(10 * time.Minute)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+	gameService.RegisterWebhook(&request.RegisterWebhookRequest{URL: slow.URL + "/a", Secret: "whsec_a"})
+	gameService.RegisterWebhook(&request.RegisterWebhookRequest{URL: slow.URL + "/b", Secret: "whsec_b"})
+
+	if _, err := gameService.StakeGame(stakeRequest()); err != nil {
+		t.Fatalf("Expected no error staking, got %v", err)
+	}
+	if delivered, err := gameService.DispatchWebhooks(context.Background()); err != nil || delivered != 2 {
+		t.Fatalf("Expected both deliveries in one batch, got %d, %v", delivered, err)
+	}
+	if len(stamps) != 2 || stamps[1]-stamps[0] != int64((10*time.Minute).Seconds()) {
+		t.Errorf("Expected the second attempt to be stamped when it was sent, got %v", stamps)
+	}
+}
+
+func TestGameService_Webhooks_RetriesThenDeadLetters(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+	clock := newFakeClock()
+	gameService.ConfigureMatchmaking(testMatchmakingConfig(), clock)
+	gameService.ConfigureWebhooks(webhook.Config{
+		MaxAttempts: 3,
+		BaseDelay:   10 * time.Second,
+		MaxDelay:    time.Minute,
+		Timeout:     time.Second,
+	}, &http.Client{})
+
+	receiver := newWebhookReceiver(t, "whsec_test")
+	receiver.respondWith(http.StatusInternalServerError)
+	gameService.RegisterWebhook(&request.RegisterWebhookRequest{URL: receiver.URL, Secret: "whsec_test"})
+
+	if _, err := gameService.StakeGame(stakeRequest()); err != nil {
+		t.Fatalf("Expected no error staking, got %v", err)
+	}
+
+	ctx := context.Background()
+	dispatch := func() {
+		if _, err := gameService.DispatchWebhooks(ctx); err != nil {
+			t.Fatalf("Expected no dispatch error, got %v", err)
+		}
+	}
+	delivery := func() models.WebhookDelivery {
+		log, err := gameService.ListWebhookDeliveries(&request.WebhookDeliveryQuery{})
+		if err != nil || log.Count != 1 {
+			t.Fatalf("Expected one delivery, got %+v, %v", log, err)
+		}
+		return log.Deliveries[0]
+	}
+
+	dispatch()
+	if d := delivery(); d.Status != models.DeliveryStatusPending || d.Attempts != 1 || !d.NextAttemptAt.Equal(clock.Now().Add(10*time.Second)) {
+		t.Fatalf("Expected a retry scheduled 10s out, got %+v", d)
+	}
+
+	dispatch()
+	if n := len(receiver.events()); n != 1 {
+		t.Errorf("Expected no attempt before the backoff elapsed, got %d attempts", n)
+	}
+
+	clock.Advance(10 * time.Second)
+	dispatch()
+	if d := delivery(); d.Attempts != 2 || !d.NextAttemptAt.Equal(clock.Now().Add(20*time.Second)) {
+		t.Fatalf("Expected the backoff to double, got %+v", d)
+	}
+
+	clock.Advance(20 * time.Second)
+	dispatch()
+	dead := delivery()
+	if dead.Status != models.DeliveryStatusDeadLetter || dead.Attempts != 3 || dead.LastStatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected dead letter after 3 attempts, got %+v", dead)
+	}
+
+	clock.Advance(time.Hour)
+	dispatch()
+	if n := len(receiver.events()); n != 3 {
+		t.Errorf("Expected dead-lettered deliveries not to be retried, got %d attempts", n)
+	}
+
+	receiver.respondWith(http.StatusNoContent)
+	resp, err := gameService.RedeliverWebhook(dead.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error redelivering, got %v", err)
+	}
+	if resp.Delivery.Status != models.DeliveryStatusDelivered || resp.Delivery.Attempts != 1 {
+		t.Errorf("Expected manual redelivery to succeed, got %+v", resp.Delivery)
+	}
+	if _, err := gameService.RedeliverWebhook("665f1c2e8b3a4d0012345678"); err != service.ErrDeliveryNotFound {
+		t.Errorf("Expected unknown delivery to be not found, got %v", err)
+	}
+}
+
+func TestGameService_ConfirmTransactions_PublishesOutcome(t *testing.T) {
+	mockSuiClient := mocks.NewMockSuiClient()
+	mockMongoClient := mocks.NewMockMongoClient()
+	gameService := service.NewGameService(mockSuiClient, mockMongoClient)
+
+	digests := []string{"tx_ok", "tx_bad", "tx_unknown"}
+	mockSuiClient.ExternalStakeFunc = func(requesterCoinID, accepterCoinID string, amount uint64, ctx context.Context) (string, error) {
+		digest := digests[0]
+		digests = digests[1:]
+		return digest, nil
+	}
+	mockSuiClient.AddTransaction("tx_ok", map[string]interface{}{
+		"digest":     "tx_ok",
+		"checkpoint": "4242",
+		"effects":    map[string]interface{}{"status": map[string]interface{}{"status": "success"}},
+	})
+	mockSuiClient.AddTransaction("tx_bad", map[string]interface{}{
+		"digest":  "tx_bad",
+		"effects": map[string]interface{}{"status": map[string]interface{}{"status": "failure", "error": "InsufficientGas"}},
+	})
+	mockSuiClient.AddTransaction("tx_unknown", map[string]interface{}{"digest": "tx_unknown"})
+
+	var published []events.Event
+	gameService.Events().Subscribe(func(e events.Event) {
+		if e.Type == events.TransactionConfirmed || e.Type == events.TransactionFailed {
+			published = append(published, e)
+		}
+	})
+
+	for range digests {
+		if _, err := gameService.StakeGame(stakeRequest()); err != nil {
+			t.Fatalf("Expected no error staking, got %v", err)
+		}
+	}
+
+	settled, err := gameService.ConfirmTransactions(context.Background())
+	if err != nil || settled != 2 {
+		t.Fatalf("Expected 2 settled transactions, got %d, %v", settled, err)
+	}
+	byDigest := map[string]events.Event{}
+	for _, e := range published {
+		byDigest[e.Data["transaction_digest"].(string)] = e
+	}
+	if e := byDigest["tx_ok"]; e.Type != events.TransactionConfirmed || e.Data["checkpoint"] != uint64(4242) || e.GameID == "" {
+		t.Errorf("Expected tx_ok confirmed at checkpoint 4242, got %+v", e)
+	}
+	if e := byDigest["tx_bad"]; e.Type != events.TransactionFailed || e.Data["error"] != "InsufficientGas" {
+		t.Errorf("Expected tx_bad failed with its error, got %+v", e)
+	}
+
+	pending, _ := mockMongoClient.GetPendingTransactions(context.Background())
+	if len(pending) != 1 {
+		t.Errorf("Expected the unresolved transaction to stay pending, got %d", len(pending))
+	}
+	if settled, _ := gameService.ConfirmTransactions(context.Background()); settled != 0 {
+		t.Errorf("Expected settled transactions not to be published twice, got %d", settled)
+	}
+}
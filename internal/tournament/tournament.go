@@ -0,0 +1,240 @@
+// Package tournament lays out brackets and totals standings. Like the
+// matchmaking engine it is pure; registration, staking and payouts live in
+// the service layer.
+package tournament
+
+import (
+	"fmt"
+	"sort"
+
+	"jollfi-gaming-api/internal/models"
+)
+
+const (
+	SlotRequester = "requester"
+	SlotAccepter  = "accepter"
+
+	pointsWin  = 3
+	pointsDraw = 1
+)
+
+// Bracket returns every match of a tournament between players, who must be
+// ordered by seed, best first. Single elimination brackets are padded to a
+// power of two with byes for the top seeds, which are resolved straight
+// away. Round robin pairs every player with every other once.
+func Bracket(format string, players []string) ([]models.TournamentMatch, error) {
+	if len(players) < 2 {
+		return nil, fmt.Errorf("a tournament needs at least 2 players, got %d", len(players))
+	}
+	switch format {
+	case models.TournamentFormatSingleElimination:
+		return singleElimination(players), nil
+	case models.TournamentFormatRoundRobin:
+		return roundRobin(players), nil
+	default:
+		return nil, fmt.Errorf("unknown tournament format %q", format)
+	}
+}
+
+func singleElimination(players []string) []models.TournamentMatch {
+	size := 2
+	for size < len(players) {
+		size *= 2
+	}
+	order := seedOrder(size)
+
+	var matches []models.TournamentMatch
+	roundStart, round := 1, 1
+	for width := size / 2; width >= 1; width /= 2 {
+		for i := 0; i < width; i++ {
+			match := models.TournamentMatch{
+				Number: roundStart + i,
+				Round:  round,
+				Status: models.TournamentMatchWaiting,
+			}
+			if width > 1 {
+				match.NextMatch = roundStart + width + i/2
+				match.NextSlot = SlotRequester
+				if i%2 == 1 {
+					match.NextSlot = SlotAccepter
+				}
+			}
+			if round == 1 {
+				match.RequesterAddress = seeded(players, order[2*i])
+				match.AccepterAddress = seeded(players, order[2*i+1])
+			}
+			matches = append(matches, match)
+		}
+		roundStart += width
+		round++
+	}
+
+	for i := range matches {
+		match := &matches[i]
+		if match.Round != 1 {
+			break
+		}
+		if match.AccepterAddress == "" {
+			match.Status = models.TournamentMatchBye
+			match.Winner = match.RequesterAddress
+			Advance(matches, *match)
+		} else {
+			match.Status = models.TournamentMatchReady
+		}
+	}
+	return matches
+}
+
+// seedOrder returns the seeds of a bracket of size in slot order, so that
+// seeds 1 and 2 can only meet in the final.
+func seedOrder(size int) []int {
+	order := []int{1, 2}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+func seeded(players []string, seed int) string {
+	if seed > len(players) {
+		return ""
+	}
+	return players[seed-1]
+}
+
+// Advance moves the winner of a completed single elimination match into its
+// next match, which becomes ready once both its players are known. It returns
+// the next match, or nil after the final.
+func Advance(matches []models.TournamentMatch, completed models.TournamentMatch) *models.TournamentMatch {
+	if completed.NextMatch == 0 || completed.NextMatch > len(matches) {
+		return nil
+	}
+	next := &matches[completed.NextMatch-1]
+	if completed.NextSlot == SlotAccepter {
+		next.AccepterAddress = completed.Winner
+	} else {
+		next.RequesterAddress = completed.Winner
+	}
+	if next.Status == models.TournamentMatchWaiting && next.RequesterAddress != "" && next.AccepterAddress != "" {
+		next.Status = models.TournamentMatchReady
+	}
+	return next
+}
+
+// roundRobin schedules every pairing with the circle method, so each player
+// plays at most once per round.
+func roundRobin(players []string) []models.TournamentMatch {
+	circle := append([]string(nil), players...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, "")
+	}
+	n := len(circle)
+
+	var matches []models.TournamentMatch
+	for round := 1; round < n; round++ {
+		for i := 0; i < n/2; i++ {
+			home, away := circle[i], circle[n-1-i]
+			if home == "" || away == "" {
+				continue
+			}
+			if round%2 == 0 && i == 0 {
+				home, away = away, home
+			}
+			matches = append(matches, models.TournamentMatch{
+				Number:           len(matches) + 1,
+				Round:            round,
+				RequesterAddress: home,
+				AccepterAddress:  away,
+				Status:           models.TournamentMatchReady,
+			})
+		}
+		// Keep the first player fixed and rotate the rest one place
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+	return matches
+}
+
+// Standings totals completed matches for players, best first: by points,
+// then score difference, then seed. In games where the lower score wins,
+// scoreDirection is models.ScoreDirectionLower and the difference counts the
+// other way.
+func Standings(players []string, matches []models.TournamentMatch, scoreDirection string) []models.TournamentStanding {
+	index := make(map[string]int, len(players))
+	standings := make([]models.TournamentStanding, len(players))
+	for i, address := range players {
+		index[address] = i
+		standings[i].Address = address
+	}
+
+	record := func(address string, scored, conceded uint64, winner string) {
+		i, ok := index[address]
+		if !ok {
+			return
+		}
+		s := &standings[i]
+		s.Played++
+		s.ScoreFor += scored
+		s.ScoreAgainst += conceded
+		switch winner {
+		case "":
+			s.Draws++
+			s.Points += pointsDraw
+		case address:
+			s.Wins++
+			s.Points += pointsWin
+		default:
+			s.Losses++
+		}
+	}
+	for _, match := range matches {
+		if match.Status != models.TournamentMatchComplete || match.RequesterScore == nil || match.AccepterScore == nil {
+			continue
+		}
+		record(match.RequesterAddress, *match.RequesterScore, *match.AccepterScore, match.Winner)
+		record(match.AccepterAddress, *match.AccepterScore, *match.RequesterScore, match.Winner)
+	}
+
+	sort.SliceStable(standings, func(a, b int) bool {
+		sa, sb := standings[a], standings[b]
+		if sa.Points != sb.Points {
+			return sa.Points > sb.Points
+		}
+		if scoreDirection == models.ScoreDirectionLower {
+			return scoreDifference(sa) < scoreDifference(sb)
+		}
+		return scoreDifference(sa) > scoreDifference(sb)
+	})
+	return standings
+}
+
+func scoreDifference(s models.TournamentStanding) int64 {
+	return int64(s.ScoreFor) - int64(s.ScoreAgainst)
+}
+
+// Finished reports whether every match has been played or was a bye.
+func Finished(matches []models.TournamentMatch) bool {
+	for _, match := range matches {
+		if match.Status != models.TournamentMatchComplete && match.Status != models.TournamentMatchBye {
+			return false
+		}
+	}
+	return true
+}
+
+// Champion returns the winner of a finished tournament: the winner of the
+// single elimination final, or the top of the round robin standings.
+func Champion(format string, players []string, matches []models.TournamentMatch, scoreDirection string) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	if format == models.TournamentFormatSingleElimination {
+		return matches[len(matches)-1].Winner
+	}
+	return Standings(players, matches, scoreDirection)[0].Address
+}
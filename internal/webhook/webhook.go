@@ -0,0 +1,84 @@
+// Package webhook holds the pieces of outbound webhook delivery that do not
+// touch storage: payload signing and the retry schedule. Receivers verify a
+// delivery by recomputing the HMAC over "<timestamp>.<body>".
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Jollfi-Signature"
+	TimestampHeader = "X-Jollfi-Timestamp"
+	EventHeader     = "X-Jollfi-Event"
+	DeliveryHeader  = "X-Jollfi-Delivery"
+
+	signaturePrefix = "sha256="
+)
+
+// Config controls delivery timeouts and the retry schedule.
+type Config struct {
+	// MaxAttempts is the number of attempts before a delivery is dead-lettered.
+	MaxAttempts int
+	// BaseDelay is the wait after the first failure; it doubles per attempt
+	// up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Timeout   time.Duration
+}
+
+// DefaultConfig returns the settings used when none are configured.
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts: 8,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    6 * time.Hour,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// Backoff returns how long to wait after the given failed attempt (1-based).
+func (c Config) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := c.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if c.MaxDelay > 0 && delay >= c.MaxDelay {
+			return c.MaxDelay
+		}
+	}
+	if c.MaxDelay > 0 && delay > c.MaxDelay {
+		return c.MaxDelay
+	}
+	return delay
+}
+
+// Sign returns the signature header value for body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches body sent at timestamp.
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}